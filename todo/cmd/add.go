@@ -5,6 +5,10 @@ package cmd
 
 import (
 	"fmt"
+	"os"
+	"strings"
+	"time"
+	"todo/hooks"
 	"todo/taskdata"
 
 	"github.com/spf13/cobra"
@@ -30,6 +34,37 @@ func addRun(cmd *cobra.Command, args []string) {
 	// Get flags
 	dueDate, _ := cmd.Flags().GetString("due")
 	priority, _ := cmd.Flags().GetString("priority")
+	if !cmd.Flags().Changed("priority") {
+		priority = appConfig.DefaultPriority
+	}
+	note, _ := cmd.Flags().GetString("note")
+	wait, _ := cmd.Flags().GetString("wait")
+	dueTime, _ := cmd.Flags().GetString("time")
+	tags, _ := cmd.Flags().GetStringSlice("tag")
+	depends, _ := cmd.Flags().GetIntSlice("depends")
+	assignee, _ := cmd.Flags().GetString("assign")
+	assignee = resolveAssignee(assignee)
+	project, _ := cmd.Flags().GetString("project")
+	project = strings.TrimSpace(project)
+
+	dueDate, err := taskdata.ResolveDate(dueDate, time.Now())
+	if err != nil {
+		fmt.Printf("Invalid due date: %v\n", err)
+		return
+	}
+	wait, err = taskdata.ResolveDate(wait, time.Now())
+	if err != nil {
+		fmt.Printf("Invalid wait date: %v\n", err)
+		return
+	}
+	if err := taskdata.ValidateTime(dueTime); err != nil {
+		fmt.Printf("Invalid due time: %v\n", err)
+		return
+	}
+	if dueTime != "" && dueDate == "" {
+		fmt.Println("Please give a due date (--due) with --time.")
+		return
+	}
 
 	// Load existing tasks
 	store, err := taskdata.LoadTasks()
@@ -44,6 +79,8 @@ func addRun(cmd *cobra.Command, args []string) {
 	}
 
 	// Add each task
+	runner := taskHooks()
+	var added []hooks.Change
 	successCount := 0
 	for _, taskDesc := range args {
 		if taskDesc == "" {
@@ -58,12 +95,73 @@ func addRun(cmd *cobra.Command, args []string) {
 			continue
 		}
 
+		if note != "" {
+			updateTaskNotes(store, task.ID, note)
+			task.Notes = note
+		}
+		if dueTime != "" {
+			updateTaskDueTime(store, task.ID, dueTime)
+			task.DueTime = dueTime
+		}
+		if wait != "" {
+			updateTaskWaitUntil(store, task.ID, wait)
+			task.WaitUntil = wait
+		}
+		for _, tag := range tags {
+			task.Tags = addTags(task.Tags, tag)
+		}
+		for _, id := range depends {
+			if err := validateDependency(store, task.ID, id); err != nil {
+				fmt.Printf("Warning: skipping dependency for '%s': %v\n", taskDesc, err)
+				continue
+			}
+			task.DependsOn = addDependency(task.DependsOn, id)
+		}
+		updateTaskTags(store, task.ID, task.Tags)
+		updateTaskDependsOn(store, task.ID, task.DependsOn)
+		if assignee != "" {
+			assignTask(store, task.ID, assignee)
+			task.Assignee = assignee
+		}
+		if project != "" {
+			updateTaskProject(store, task.ID, project)
+			task.Project = project
+		}
+
+		// Let the pre-add hook veto or rewrite the task
+		hooked, ok := runPreHook(os.Stdout, runner, hooks.Change{Event: hooks.Add, Task: *findTaskByID(store, task.ID)})
+		if !ok {
+			deleteTaskByID(store, task.ID)
+			continue
+		}
+		*findTaskByID(store, task.ID) = hooked
+		*task = hooked
+		added = append(added, hooks.Change{Event: hooks.Add, Task: hooked})
+
 		// Display success message
 		fmt.Printf("✓ Added task #%d: %s\n", task.ID, task.Description)
 		if task.DueDate != "" {
-			fmt.Printf("  Due date: %s\n", task.DueDate)
+			fmt.Printf("  Due date: %s\n", strings.TrimSpace(task.DueDate+" "+task.DueTime))
 		}
 		fmt.Printf("  Priority: %s\n", task.Priority)
+		if task.Notes != "" {
+			fmt.Printf("  Notes: %s\n", task.Notes)
+		}
+		if task.WaitUntil != "" {
+			fmt.Printf("  Waiting until: %s\n", task.WaitUntil)
+		}
+		if len(task.Tags) > 0 {
+			fmt.Printf("  Tags: %s\n", formatTags(task.Tags))
+		}
+		if task.Project != "" {
+			fmt.Printf("  Project: %s\n", task.Project)
+		}
+		if len(task.DependsOn) > 0 {
+			fmt.Printf("  Depends on: %s\n", formatDependencies(task.DependsOn))
+		}
+		if task.Assignee != "" {
+			fmt.Printf("  Assigned to: %s\n", task.Assignee)
+		}
 		fmt.Printf("  Status: %s\n", func() string {
 			if task.Completed {
 				return "Completed"
@@ -82,6 +180,7 @@ func addRun(cmd *cobra.Command, args []string) {
 			return
 		}
 		fmt.Printf("Successfully added %d task(s) and saved to file.\n", successCount)
+		runPostHooks(os.Stdout, runner, added)
 	}
 } // Add the add command to the root command
 // This allows the add command to be executed as a subcommand of the main todo command
@@ -92,6 +191,21 @@ func init() {
 	rootCmd.AddCommand(addCmd)
 
 	// Here you will define your flags and configuration settings.
-	addCmd.Flags().StringP("due", "d", "", "Due date for the task (format: YYYY-MM-DD)")
-	addCmd.Flags().StringP("priority", "p", "normal", "Priority level of the task (low, normal, high)")
+	addCmd.Flags().StringP("due", "d", "", "Due date for the task (YYYY-MM-DD, or today, tomorrow, friday, +3d...)")
+	addCmd.Flags().StringP("priority", "p", "normal", "Priority level of the task (low, normal, high, or priorities.levels; default from defaults.priority)")
+	addCmd.Flags().StringP("note", "n", "", "Notes attached to the task (searchable)")
+	addCmd.Flags().String("time", "", "Time of day the task is due (format: HH:MM, needs --due)")
+	addCmd.Flags().String("wait", "", "Defer the task until a date (YYYY-MM-DD, or today, tomorrow, friday, +3d...)")
+	addCmd.Flags().StringSliceP("tag", "t", nil, "Tags for the task (comma-separated)")
+	addCmd.Flags().String("project", "", "Project of the task, e.g. work.reports")
+	addCmd.Flags().IntSlice("depends", nil, "IDs of tasks that must be completed first")
+	addCmd.Flags().String("assign", "", "Assign the task to someone ('me' for yourself)")
+
+	addCmd.RegisterFlagCompletionFunc("due", completeDates)
+	addCmd.RegisterFlagCompletionFunc("wait", completeDates)
+	addCmd.RegisterFlagCompletionFunc("priority", completePriorities)
+	addCmd.RegisterFlagCompletionFunc("tag", completeTags)
+	addCmd.RegisterFlagCompletionFunc("project", completeProjects)
+	addCmd.RegisterFlagCompletionFunc("depends", completeDependencies)
+	addCmd.RegisterFlagCompletionFunc("assign", completeAssignees)
 }
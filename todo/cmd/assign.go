@@ -0,0 +1,116 @@
+/*
+Copyright © 2025 Smart Todo CLI
+*/
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"todo/taskdata"
+
+	"github.com/spf13/cobra"
+)
+
+// showEveryone makes the smart views include tasks assigned to others
+var showEveryone bool
+
+// historyCmd represents the history command
+var historyCmd = &cobra.Command{
+	Use:   "history <task_id_or_name>",
+	Short: "Show who created a task and how it was reassigned",
+	Long: `Show a task's creator, current assignee and recorded changes, such as
+reassignments made with 'todo mark <id> --assign'.
+
+Examples:
+  todo history 5
+  todo history "deploy"`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		store, err := taskdata.LoadTasks()
+		if err != nil {
+			fmt.Printf("Error loading tasks: %v\n", err)
+			return
+		}
+		task := resolveTask(store, args[0])
+		if task == nil {
+			return
+		}
+
+		fmt.Printf("📜 History of #%d: %s\n", task.ID, task.Description)
+		fmt.Println(strings.Repeat("=", 50))
+		fmt.Printf("  Created %s by %s\n", formatTimestamp(task.CreatedAt), orNobody(task.Creator))
+		fmt.Printf("  Assigned to %s\n", orNobody(task.Assignee))
+		if len(task.History) == 0 {
+			fmt.Println("\n  No recorded changes.")
+			return
+		}
+
+		fmt.Println()
+		for _, change := range task.History {
+			fmt.Printf("  %s  %s: %s → %s", formatTimestamp(change.At), change.Field, orNobody(change.From), orNobody(change.To))
+			if change.By != "" {
+				fmt.Printf(" (by %s)", change.By)
+			}
+			fmt.Println()
+		}
+	},
+}
+
+// resolveAssignee turns an --assign value into a name: "me" is the local
+// user and "none" unassigns
+func resolveAssignee(value string) string {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "me":
+		return taskdata.Identity()
+	case "none":
+		return ""
+	}
+	return strings.TrimSpace(value)
+}
+
+// assignTask assigns a stored task, recording the change in its history
+func assignTask(store *taskdata.TaskStore, id int, assignee string) bool {
+	for i := range store.Tasks {
+		if store.Tasks[i].ID == id {
+			return store.Tasks[i].Assign(assignee, time.Now())
+		}
+	}
+	return false
+}
+
+// tasksForCurrentUser returns a copy of the store with only the local
+// user's tasks (see Task.IsMine), or the store itself with --everyone
+func tasksForCurrentUser(store *taskdata.TaskStore) *taskdata.TaskStore {
+	if showEveryone {
+		return store
+	}
+	mine := *store
+	mine.Tasks = nil
+	for _, task := range store.Tasks {
+		if task.IsMine() {
+			mine.Tasks = append(mine.Tasks, task)
+		}
+	}
+	return &mine
+}
+
+func formatTimestamp(timestamp string) string {
+	at, err := time.Parse(taskdata.TimestampFormat, timestamp)
+	if err != nil {
+		return "at an unknown time"
+	}
+	return at.Local().Format(appConfig.DateFormat + " 15:04")
+}
+
+func orNobody(name string) string {
+	if name == "" {
+		return "nobody"
+	}
+	return name
+}
+
+func init() {
+	rootCmd.AddCommand(historyCmd)
+	historyCmd.ValidArgsFunction = completeAnyTask
+}
@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"slices"
+	"testing"
+	"todo/taskdata"
+)
+
+func TestAssigneeFilters(t *testing.T) {
+	taskdata.SetIdentity("alice")
+	t.Cleanup(func() { taskdata.SetIdentity("") })
+
+	store := &taskdata.TaskStore{Tasks: []taskdata.Task{
+		{ID: 1, Description: "Write report", Assignee: "Alice"},
+		{ID: 2, Description: "Review report", Assignee: "bob"},
+		{ID: 3, Description: "Book room"},
+	}}
+
+	tests := []struct {
+		assignee string
+		want     []int
+	}{
+		{"", []int{1, 2, 3}},
+		{"me", []int{1}},
+		{"ME", []int{1}},
+		{"bob", []int{2}},
+		{" Bob ", []int{2}},
+		{"none", []int{3}},
+		{"carol", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.assignee, func(t *testing.T) {
+			var ids []int
+			for _, task := range filterTasks(store.Tasks, filterOptions{timeFilter: "all", assignee: tt.assignee}) {
+				ids = append(ids, task.ID)
+			}
+			if !slices.Equal(ids, tt.want) {
+				t.Errorf("tasks %v, want %v", ids, tt.want)
+			}
+		})
+	}
+
+	for everyone, want := range map[bool][]int{false: {1, 3}, true: {1, 2, 3}} {
+		showEveryone = everyone
+		var ids []int
+		for _, task := range tasksForCurrentUser(store).Tasks {
+			ids = append(ids, task.ID)
+		}
+		if !slices.Equal(ids, want) {
+			t.Errorf("everyone %v: smart view tasks %v, want %v", everyone, ids, want)
+		}
+	}
+	showEveryone = false
+	if len(store.Tasks) != 3 {
+		t.Error("tasksForCurrentUser changed the store")
+	}
+}
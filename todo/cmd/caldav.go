@@ -0,0 +1,900 @@
+/*
+Copyright © 2025 Smart Todo CLI
+*/
+package cmd
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"todo/taskdata"
+	"todo/transfer"
+
+	"github.com/spf13/cobra"
+)
+
+// caldavCmd represents the caldav command
+var caldavCmd = &cobra.Command{
+	Use:   "caldav",
+	Short: "Serve the tasks as a CalDAV task list",
+	Long: `Serve the tasks as a CalDAV VTODO collection for calendar and reminder apps.
+
+Add a CalDAV account in the client with the server address, any user name
+and the token as password. Clients discover the task list through
+/.well-known/caldav; it lives at /calendars/tasks/.
+
+Clients can list, create, edit, complete and delete tasks. Changes made in
+the CLI show up on the next sync: the server supports sync tokens
+(RFC 6578) and collection ctags, so clients only fetch what changed; a
+client whose token predates the last 10000 changes syncs everything again.
+Every task has an ETag, and a client that edits a task that was changed in
+the meantime gets 412 Precondition Failed and refetches it instead of
+overwriting the other change.
+
+Examples:
+  todo caldav                                  # http://127.0.0.1:5232
+  todo caldav --addr 127.0.0.1:5233 --token s3cret`,
+	Args: cobra.NoArgs,
+	Run:  caldavRun,
+}
+
+// CalDAV resource paths
+const (
+	davPrincipalPath = "/principal/"
+	davHomePath      = "/calendars/"
+	davTasksPath     = "/calendars/tasks/"
+)
+
+// XML namespaces used in WebDAV bodies
+const (
+	nsDAV    = "DAV:"
+	nsCalDAV = "urn:ietf:params:xml:ns:caldav"
+	nsCS     = "http://calendarserver.org/ns/"
+)
+
+var davPrefixes = map[string]string{nsDAV: "d", nsCalDAV: "c", nsCS: "cs"}
+
+// davServer serves the task store over CalDAV. It keeps a log of which
+// objects changed at each sync-token sequence number, built by comparing
+// the ETags of the store whenever tasks.json changes.
+type davServer struct {
+	mu       sync.Mutex
+	epoch    string            // Identifies this server run in sync tokens
+	seq      int               // Current sync sequence number
+	etags    map[string]string // Object name to ETag at seq
+	changes  map[string]int    // Object name to the seq of its last change
+	oldest   int               // Tokens before this seq are no longer valid
+	revision string            // Store revision etags was built from
+}
+
+// maxDAVChanges bounds the change log. Once more objects than this have
+// changed, the oldest entries are dropped and clients holding tokens from
+// before them start over with a full sync.
+const maxDAVChanges = 10000
+
+// davResourceKind tells the resources of the server apart
+type davResourceKind int
+
+const (
+	davPrincipal davResourceKind = iota
+	davHome
+	davCalendar
+	davObject
+)
+
+// davResource is something a PROPFIND or REPORT response describes
+type davResource struct {
+	kind davResourceKind
+	href string
+	task taskdata.Task // davObject only
+}
+
+// davRequest is the parsed body of a PROPFIND or REPORT request
+type davRequest struct {
+	root      xml.Name
+	props     []xml.Name
+	allProps  bool
+	hrefs     []string // calendar-multiget
+	syncToken string   // sync-collection
+	// calendar-query: a component other than VTODO was asked for, or only
+	// tasks without COMPLETED
+	otherComponent bool
+	pendingOnly    bool
+}
+
+// caldavInfo is kept in Task.Extra["caldav"] for tasks a client created
+// under a resource name other than <UID>.ics
+type caldavInfo struct {
+	Name string `json:"name"`
+}
+
+func caldavRun(cmd *cobra.Command, args []string) {
+	addr, _ := cmd.Flags().GetString("addr")
+	token, generated := serverToken(cmd)
+
+	dav := newDAVServer()
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           logRequests(dav.handler(token)),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		return
+	}
+
+	fmt.Printf("📆 Serving %s as CalDAV on http://%s%s\n", taskdata.GetDataFilePath(), listener.Addr(), davTasksPath)
+	if generated {
+		fmt.Printf("🔑 Password: %s (any user name)\n", token)
+	}
+
+	if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		fmt.Printf("❌ %v\n", err)
+	}
+}
+
+func newDAVServer() *davServer {
+	return &davServer{epoch: strconv.FormatInt(time.Now().UnixNano(), 36), changes: map[string]int{}}
+}
+
+func (s *davServer) handler(token string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/.well-known/caldav" {
+			http.Redirect(w, r, davPrincipalPath, http.StatusMovedPermanently)
+			return
+		}
+		if !davAuthorized(r, token) {
+			w.Header().Set("WWW-Authenticate", `Basic realm="todo"`)
+			http.Error(w, "missing or invalid credentials", http.StatusUnauthorized)
+			return
+		}
+
+		w.Header().Set("DAV", "1, 3, calendar-access")
+		switch r.Method {
+		case "OPTIONS":
+			w.Header().Set("Allow", "OPTIONS, GET, HEAD, PUT, DELETE, PROPFIND, REPORT")
+			w.WriteHeader(http.StatusOK)
+		case "PROPFIND":
+			s.propfind(w, r)
+		case "REPORT":
+			s.report(w, r)
+		case "GET", "HEAD":
+			s.get(w, r)
+		case "PUT":
+			s.put(w, r)
+		case "DELETE":
+			s.delete(w, r)
+		default:
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+}
+
+// davAuthorized accepts the token as a Basic password or a Bearer token
+func davAuthorized(r *http.Request, token string) bool {
+	given := ""
+	if _, password, ok := r.BasicAuth(); ok {
+		given = password
+	} else if bearer, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+		given = bearer
+	}
+	return subtle.ConstantTimeCompare([]byte(given), []byte(token)) == 1
+}
+
+// load reads the store and brings the change log up to date with it
+func (s *davServer) load() (*taskdata.TaskStore, error) {
+	store, err := taskdata.LoadTasks()
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.etags != nil && store.Revision() == s.revision {
+		return store, nil
+	}
+
+	etags := map[string]string{}
+	for _, task := range store.Tasks {
+		etags[caldavName(task)] = taskETag(task)
+	}
+	if s.etags != nil {
+		s.seq++
+		for name, etag := range etags {
+			if s.etags[name] != etag {
+				s.changes[name] = s.seq
+			}
+		}
+		for name := range s.etags {
+			if _, exists := etags[name]; !exists {
+				s.changes[name] = s.seq
+			}
+		}
+		s.compact()
+	}
+	s.etags = etags
+	s.revision = store.Revision()
+	return store, nil
+}
+
+// compact drops the oldest entries of the change log once it holds more
+// than maxDAVChanges objects
+func (s *davServer) compact() {
+	for len(s.changes) > maxDAVChanges {
+		oldest := s.seq
+		for _, seq := range s.changes {
+			oldest = min(oldest, seq)
+		}
+		for name, seq := range s.changes {
+			if seq == oldest {
+				delete(s.changes, name)
+			}
+		}
+		s.oldest = oldest
+	}
+}
+
+// syncToken returns the token for the current state of the change log
+func (s *davServer) syncToken() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return fmt.Sprintf("http://todo/sync/%s-%d", s.epoch, s.seq)
+}
+
+// changedSince returns the names of objects changed after a sync token, or
+// false if the token was not issued by this server run or is older than
+// the change log
+func (s *davServer) changedSince(token string) ([]string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rest, ok := strings.CutPrefix(token, "http://todo/sync/"+s.epoch+"-")
+	if !ok {
+		return nil, false
+	}
+	seq, err := strconv.Atoi(rest)
+	if err != nil || seq < s.oldest || seq > s.seq {
+		return nil, false
+	}
+
+	var names []string
+	for name, changed := range s.changes {
+		if changed > seq {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names, true
+}
+
+// caldavName returns the resource name of a task in the collection
+func caldavName(task taskdata.Task) string {
+	var info caldavInfo
+	if raw, exists := task.Extra["caldav"]; exists && json.Unmarshal(raw, &info) == nil && info.Name != "" {
+		return info.Name
+	}
+	return task.UID + ".ics"
+}
+
+// objectName returns the resource name in a task URL, or "" for other paths
+func objectName(urlPath string) string {
+	name, ok := strings.CutPrefix(urlPath, davTasksPath)
+	if !ok || name == "" || strings.Contains(name, "/") {
+		return ""
+	}
+	return name
+}
+
+// findTaskByName returns the stored task with the given resource name
+func findTaskByName(store *taskdata.TaskStore, name string) *taskdata.Task {
+	for i := range store.Tasks {
+		if caldavName(store.Tasks[i]) == name {
+			return &store.Tasks[i]
+		}
+	}
+	return nil
+}
+
+func (s *davServer) propfind(w http.ResponseWriter, r *http.Request) {
+	request, err := parseDAVRequest(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	store, err := s.load()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	depth := r.Header.Get("Depth")
+	urlPath := r.URL.Path
+	if !strings.HasSuffix(urlPath, "/") && objectName(urlPath) == "" {
+		urlPath += "/"
+	}
+
+	var resources []davResource
+	switch urlPath {
+	case "/", davPrincipalPath:
+		resources = append(resources, davResource{kind: davPrincipal, href: urlPath})
+	case davHomePath:
+		resources = append(resources, davResource{kind: davHome, href: davHomePath})
+		if depth != "0" {
+			resources = append(resources, davResource{kind: davCalendar, href: davTasksPath})
+		}
+	case davTasksPath:
+		resources = append(resources, davResource{kind: davCalendar, href: davTasksPath})
+		if depth != "0" {
+			for _, task := range store.Tasks {
+				resources = append(resources, objectResource(task))
+			}
+		}
+	default:
+		task := findTaskByName(store, objectName(urlPath))
+		if task == nil {
+			http.NotFound(w, r)
+			return
+		}
+		resources = append(resources, objectResource(*task))
+	}
+
+	s.writeMultistatus(w, request, resources, nil, false)
+}
+
+func objectResource(task taskdata.Task) davResource {
+	return davResource{kind: davObject, href: davTasksPath + caldavName(task), task: task}
+}
+
+func (s *davServer) report(w http.ResponseWriter, r *http.Request) {
+	request, err := parseDAVRequest(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	store, err := s.load()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	var resources []davResource
+	var missing []string
+	switch request.root {
+	case xml.Name{Space: nsCalDAV, Local: "calendar-query"}:
+		if !request.otherComponent {
+			for _, task := range store.Tasks {
+				if !request.pendingOnly || !task.Completed {
+					resources = append(resources, objectResource(task))
+				}
+			}
+		}
+		s.writeMultistatus(w, request, resources, nil, false)
+
+	case xml.Name{Space: nsCalDAV, Local: "calendar-multiget"}:
+		for _, href := range request.hrefs {
+			if task := findTaskByName(store, objectName(href)); task != nil {
+				resources = append(resources, objectResource(*task))
+			} else {
+				missing = append(missing, href)
+			}
+		}
+		s.writeMultistatus(w, request, resources, missing, false)
+
+	case xml.Name{Space: nsDAV, Local: "sync-collection"}:
+		if request.syncToken == "" {
+			for _, task := range store.Tasks {
+				resources = append(resources, objectResource(task))
+			}
+			s.writeMultistatus(w, request, resources, nil, true)
+			return
+		}
+
+		names, ok := s.changedSince(request.syncToken)
+		if !ok {
+			// The client starts over with an empty token
+			writeDAVXML(w, http.StatusForbidden, `<d:error xmlns:d="DAV:"><d:valid-sync-token/></d:error>`)
+			return
+		}
+		for _, name := range names {
+			if task := findTaskByName(store, name); task != nil {
+				resources = append(resources, objectResource(*task))
+			} else {
+				missing = append(missing, davTasksPath+name)
+			}
+		}
+		s.writeMultistatus(w, request, resources, missing, true)
+
+	default:
+		http.Error(w, "unsupported report", http.StatusForbidden)
+	}
+}
+
+func (s *davServer) get(w http.ResponseWriter, r *http.Request) {
+	store, err := s.load()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// The whole collection as one calendar
+	tasks := store.Tasks
+	if name := objectName(r.URL.Path); name != "" {
+		task := findTaskByName(store, name)
+		if task == nil {
+			http.NotFound(w, r)
+			return
+		}
+		tasks = []taskdata.Task{*task}
+		w.Header().Set("ETag", taskETag(*task))
+	} else if strings.TrimSuffix(r.URL.Path, "/")+"/" != davTasksPath {
+		http.NotFound(w, r)
+		return
+	}
+
+	data, err := caldavData(tasks)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	if r.Method == "GET" {
+		w.Write(data)
+	}
+}
+
+// put creates or replaces a task from a client's VTODO
+func (s *davServer) put(w http.ResponseWriter, r *http.Request) {
+	name := objectName(r.URL.Path)
+	if name == "" {
+		http.Error(w, "tasks can only be written inside "+davTasksPath, http.StatusForbidden)
+		return
+	}
+	if contentType := r.Header.Get("Content-Type"); contentType != "" {
+		if mediaType, _, err := mime.ParseMediaType(contentType); err != nil || mediaType != "text/calendar" {
+			http.Error(w, "tasks must be sent as text/calendar", http.StatusUnsupportedMediaType)
+			return
+		}
+	}
+
+	ics, err := transfer.Lookup("ics")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	result, err := ics.Import(io.LimitReader(r.Body, 1<<20), transfer.Options{})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if len(result.Errors) > 0 {
+		http.Error(w, result.Errors[0].Err.Error(), http.StatusBadRequest)
+		return
+	}
+	if len(result.Tasks) != 1 {
+		writeDAVXML(w, http.StatusForbidden, `<d:error xmlns:d="DAV:" xmlns:c="urn:ietf:params:xml:ns:caldav"><c:supported-calendar-component/></d:error>`)
+		return
+	}
+	incoming := result.Tasks[0]
+
+	created := false
+	store, err := taskdata.UpdateTasks(func(store *taskdata.TaskStore) error {
+		existing := findTaskByName(store, name)
+		if err := checkDAVPreconditions(r, existing); err != nil {
+			return err
+		}
+
+		if existing == nil {
+			if incoming.UID != "" && store.FindByUID(incoming.UID) != nil {
+				return apiErrorf(http.StatusForbidden, "a task with UID %s already exists", incoming.UID)
+			}
+			if incoming.Priority == "" {
+				incoming.Priority = appConfig.DefaultPriority
+			}
+			priority, err := taskdata.NormalizePriority(incoming.Priority)
+			if err != nil {
+				return apiErrorf(http.StatusBadRequest, "%v", err)
+			}
+			incoming.Priority = priority
+			task, err := store.ImportTask(incoming)
+			if err != nil {
+				return apiErrorf(http.StatusBadRequest, "%v", err)
+			}
+			stored := findTaskByID(store, task.ID)
+			if caldavName(*stored) != name {
+				setCaldavName(stored, name)
+			}
+			created = true
+			return nil
+		}
+
+		return applyCaldavTask(existing, incoming)
+	})
+	if err != nil {
+		writeDAVError(w, err)
+		return
+	}
+
+	// The ETag of the task as saved, with its new modification time
+	w.Header().Set("ETag", taskETag(*findTaskByName(store, name)))
+	if created {
+		w.WriteHeader(http.StatusCreated)
+	} else {
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func (s *davServer) delete(w http.ResponseWriter, r *http.Request) {
+	name := objectName(r.URL.Path)
+	if name == "" {
+		http.Error(w, "only tasks can be deleted", http.StatusForbidden)
+		return
+	}
+
+	_, err := taskdata.UpdateTasks(func(store *taskdata.TaskStore) error {
+		task := findTaskByName(store, name)
+		if task == nil {
+			return apiErrorf(http.StatusNotFound, "%s not found", name)
+		}
+		if err := checkDAVPreconditions(r, task); err != nil {
+			return err
+		}
+		deleteTaskByID(store, task.ID)
+		return nil
+	})
+	if err != nil {
+		writeDAVError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// checkDAVPreconditions applies If-Match and If-None-Match to the current
+// version of a task, nil if it does not exist yet
+func checkDAVPreconditions(r *http.Request, task *taskdata.Task) error {
+	if r.Header.Get("If-None-Match") == "*" && task != nil {
+		return apiErrorf(http.StatusPreconditionFailed, "the task already exists")
+	}
+	if r.Header.Get("If-Match") != "" {
+		if task == nil {
+			return apiErrorf(http.StatusPreconditionFailed, "the task no longer exists")
+		}
+		return checkIfMatch(r, *task)
+	}
+	return nil
+}
+
+// applyCaldavTask copies the fields a calendar client edits onto a task,
+// keeping its ID, dependencies and other local fields
+func applyCaldavTask(task *taskdata.Task, incoming taskdata.Task) error {
+	if strings.TrimSpace(incoming.Description) == "" {
+		return apiErrorf(http.StatusBadRequest, "task description cannot be empty")
+	}
+	for _, date := range []string{incoming.DueDate, incoming.WaitUntil} {
+		if err := taskdata.ValidateDate(date); err != nil {
+			return apiErrorf(http.StatusBadRequest, "%v", err)
+		}
+	}
+	if err := taskdata.ValidateTime(incoming.DueTime); err != nil {
+		return apiErrorf(http.StatusBadRequest, "%v", err)
+	}
+	if incoming.DueTime != "" && incoming.DueDate == "" {
+		return apiErrorf(http.StatusBadRequest, "a due time needs a due date")
+	}
+	if incoming.Priority != "" {
+		priority, err := taskdata.NormalizePriority(incoming.Priority)
+		if err != nil {
+			return apiErrorf(http.StatusBadRequest, "%v", err)
+		}
+		incoming.Priority = priority
+	}
+
+	task.Description = incoming.Description
+	task.Notes = incoming.Notes
+	task.DueDate = incoming.DueDate
+	task.DueTime = incoming.DueTime
+	task.WaitUntil = incoming.WaitUntil
+	task.Tags = incoming.Tags
+	task.Project = incoming.Project
+	task.Contexts = incoming.Contexts
+	if incoming.Priority != "" {
+		task.Priority = incoming.Priority
+	}
+
+	if incoming.Completed != task.Completed {
+		task.Completed = incoming.Completed
+		task.CompletedAt = ""
+		if task.Completed {
+			task.CompletedAt = incoming.CompletedAt
+			if task.CompletedAt == "" {
+				task.CompletedAt = time.Now().Format(taskdata.TimestampFormat)
+			}
+		}
+	}
+	return nil
+}
+
+func setCaldavName(task *taskdata.Task, name string) {
+	data, err := json.Marshal(caldavInfo{Name: name})
+	if err != nil {
+		return
+	}
+	if task.Extra == nil {
+		task.Extra = map[string]json.RawMessage{}
+	}
+	task.Extra["caldav"] = data
+}
+
+// caldavData renders tasks as a calendar of VTODOs
+func caldavData(tasks []taskdata.Task) ([]byte, error) {
+	ics, err := transfer.Lookup("ics")
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := ics.Export(&buf, tasks, transfer.Options{TodosOnly: true}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// parseDAVRequest reads the requested properties and the parts of REPORT
+// bodies the server supports. An empty body asks for all properties.
+func parseDAVRequest(body io.Reader) (davRequest, error) {
+	var request davRequest
+	decoder := xml.NewDecoder(io.LimitReader(body, 1<<20))
+
+	var stack []xml.Name
+	var compFilters []string
+	propFilter := ""
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return request, fmt.Errorf("invalid XML body: %v", err)
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			parent := xml.Name{}
+			if len(stack) > 0 {
+				parent = stack[len(stack)-1]
+			} else {
+				request.root = t.Name
+			}
+			stack = append(stack, t.Name)
+
+			switch {
+			case parent == xml.Name{Space: nsDAV, Local: "prop"} && len(stack) == 3:
+				request.props = append(request.props, t.Name)
+			case t.Name == xml.Name{Space: nsDAV, Local: "allprop"}:
+				request.allProps = true
+			case t.Name == xml.Name{Space: nsCalDAV, Local: "comp-filter"}:
+				compFilters = append(compFilters, davAttr(t, "name"))
+			case t.Name == xml.Name{Space: nsCalDAV, Local: "prop-filter"}:
+				propFilter = strings.ToUpper(davAttr(t, "name"))
+			case t.Name == xml.Name{Space: nsCalDAV, Local: "is-not-defined"} && propFilter == "COMPLETED":
+				request.pendingOnly = true
+			}
+		case xml.EndElement:
+			if t.Name == (xml.Name{Space: nsCalDAV, Local: "prop-filter"}) {
+				propFilter = ""
+			}
+			stack = stack[:len(stack)-1]
+		case xml.CharData:
+			if len(stack) == 0 {
+				continue
+			}
+			text := strings.TrimSpace(string(t))
+			switch stack[len(stack)-1] {
+			case xml.Name{Space: nsDAV, Local: "href"}:
+				request.hrefs = append(request.hrefs, text)
+			case xml.Name{Space: nsDAV, Local: "sync-token"}:
+				request.syncToken = text
+			}
+		}
+	}
+
+	for _, name := range compFilters {
+		if name != "VCALENDAR" && name != "VTODO" {
+			request.otherComponent = true
+		}
+	}
+	if request.root.Local == "" {
+		request.allProps = true
+	}
+	return request, nil
+}
+
+func davAttr(element xml.StartElement, name string) string {
+	for _, attr := range element.Attr {
+		if attr.Name.Local == name {
+			return attr.Value
+		}
+	}
+	return ""
+}
+
+// defaultDAVProps are returned for allprop and empty PROPFIND bodies
+var defaultDAVProps = []xml.Name{
+	{Space: nsDAV, Local: "resourcetype"},
+	{Space: nsDAV, Local: "displayname"},
+	{Space: nsDAV, Local: "getetag"},
+	{Space: nsDAV, Local: "getcontenttype"},
+	{Space: nsDAV, Local: "sync-token"},
+	{Space: nsCS, Local: "getctag"},
+}
+
+// writeMultistatus describes resources with the requested properties;
+// missing hrefs get a 404 response
+func (s *davServer) writeMultistatus(w http.ResponseWriter, request davRequest, resources []davResource, missing []string, withToken bool) {
+	props := request.props
+	if request.allProps || len(props) == 0 {
+		props = defaultDAVProps
+	}
+
+	var out strings.Builder
+	out.WriteString(`<?xml version="1.0" encoding="utf-8"?>` + "\n")
+	out.WriteString(`<d:multistatus xmlns:d="DAV:" xmlns:c="urn:ietf:params:xml:ns:caldav" xmlns:cs="http://calendarserver.org/ns/">` + "\n")
+
+	for _, resource := range resources {
+		var found, notFound strings.Builder
+		for _, name := range props {
+			value, ok := s.propValue(resource, name)
+			if !ok {
+				notFound.WriteString(davElement(name, ""))
+				continue
+			}
+			found.WriteString(davElement(name, value))
+		}
+
+		out.WriteString("<d:response><d:href>" + davEscape(resource.href) + "</d:href>")
+		if found.Len() > 0 {
+			out.WriteString("<d:propstat><d:prop>" + found.String() + "</d:prop><d:status>HTTP/1.1 200 OK</d:status></d:propstat>")
+		}
+		if notFound.Len() > 0 {
+			out.WriteString("<d:propstat><d:prop>" + notFound.String() + "</d:prop><d:status>HTTP/1.1 404 Not Found</d:status></d:propstat>")
+		}
+		out.WriteString("</d:response>\n")
+	}
+	for _, href := range missing {
+		out.WriteString("<d:response><d:href>" + davEscape(href) + "</d:href><d:status>HTTP/1.1 404 Not Found</d:status></d:response>\n")
+	}
+	if withToken {
+		out.WriteString("<d:sync-token>" + davEscape(s.syncToken()) + "</d:sync-token>\n")
+	}
+	out.WriteString("</d:multistatus>\n")
+
+	writeDAVXML(w, http.StatusMultiStatus, out.String())
+}
+
+// propValue returns the XML content of a property, false if the resource
+// does not have it
+func (s *davServer) propValue(resource davResource, name xml.Name) (string, bool) {
+	isCollection := resource.kind != davObject
+
+	switch name {
+	case xml.Name{Space: nsDAV, Local: "resourcetype"}:
+		switch resource.kind {
+		case davPrincipal:
+			return "<d:collection/><d:principal/>", true
+		case davHome:
+			return "<d:collection/>", true
+		case davCalendar:
+			return "<d:collection/><c:calendar/>", true
+		}
+		return "", true
+	case xml.Name{Space: nsDAV, Local: "displayname"}:
+		switch resource.kind {
+		case davPrincipal:
+			return "todo", true
+		case davCalendar:
+			return "Tasks", true
+		case davObject:
+			return davEscape(resource.task.Description), true
+		}
+	case xml.Name{Space: nsDAV, Local: "current-user-principal"}, xml.Name{Space: nsDAV, Local: "principal-URL"},
+		xml.Name{Space: nsDAV, Local: "owner"}:
+		return "<d:href>" + davPrincipalPath + "</d:href>", true
+	case xml.Name{Space: nsCalDAV, Local: "calendar-home-set"}:
+		return "<d:href>" + davHomePath + "</d:href>", true
+	case xml.Name{Space: nsDAV, Local: "current-user-privilege-set"}:
+		return "<d:privilege><d:read/></d:privilege><d:privilege><d:write/></d:privilege>" +
+			"<d:privilege><d:write-content/></d:privilege><d:privilege><d:bind/></d:privilege>" +
+			"<d:privilege><d:unbind/></d:privilege>", true
+	case xml.Name{Space: nsCalDAV, Local: "supported-calendar-component-set"}:
+		if resource.kind == davCalendar {
+			return `<c:comp name="VTODO"/>`, true
+		}
+	case xml.Name{Space: nsDAV, Local: "supported-report-set"}:
+		if resource.kind == davCalendar {
+			return "<d:supported-report><d:report><c:calendar-query/></d:report></d:supported-report>" +
+				"<d:supported-report><d:report><c:calendar-multiget/></d:report></d:supported-report>" +
+				"<d:supported-report><d:report><d:sync-collection/></d:report></d:supported-report>", true
+		}
+	case xml.Name{Space: nsDAV, Local: "sync-token"}, xml.Name{Space: nsCS, Local: "getctag"}:
+		if resource.kind == davCalendar {
+			return davEscape(s.syncToken()), true
+		}
+	case xml.Name{Space: nsDAV, Local: "getetag"}:
+		if !isCollection {
+			return davEscape(taskETag(resource.task)), true
+		}
+	case xml.Name{Space: nsDAV, Local: "getcontenttype"}:
+		if !isCollection {
+			return "text/calendar; charset=utf-8; component=vtodo", true
+		}
+	case xml.Name{Space: nsCalDAV, Local: "calendar-data"}:
+		if !isCollection {
+			data, err := caldavData([]taskdata.Task{resource.task})
+			if err == nil {
+				return davEscape(string(data)), true
+			}
+		}
+	}
+	return "", false
+}
+
+// davElement writes a property element, declaring unknown namespaces inline
+func davElement(name xml.Name, value string) string {
+	tag := name.Local
+	declaration := ""
+	if prefix, known := davPrefixes[name.Space]; known {
+		tag = prefix + ":" + name.Local
+	} else if name.Space != "" {
+		tag = "x:" + name.Local
+		declaration = ` xmlns:x="` + davEscape(name.Space) + `"`
+	}
+	if value == "" {
+		return "<" + tag + declaration + "/>"
+	}
+	return "<" + tag + declaration + ">" + value + "</" + tag + ">"
+}
+
+func davEscape(text string) string {
+	var buf strings.Builder
+	xml.EscapeText(&buf, []byte(text))
+	return buf.String()
+}
+
+func writeDAVXML(w http.ResponseWriter, status int, body string) {
+	w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+	w.WriteHeader(status)
+	io.WriteString(w, body)
+}
+
+// writeDAVError reports an error from a change with its HTTP status
+func writeDAVError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	var apiErr apiError
+	switch {
+	case errors.As(err, &apiErr):
+		status = apiErr.status
+	case errors.Is(err, taskdata.ErrStoreChanged):
+		status = http.StatusConflict
+	}
+	http.Error(w, err.Error(), status)
+}
+
+func init() {
+	rootCmd.AddCommand(caldavCmd)
+
+	caldavCmd.Flags().String("addr", "127.0.0.1:5232", "Address to listen on")
+	caldavCmd.Flags().String("token", "", "Password clients must send (default $TODO_API_TOKEN or a random token)")
+}
@@ -0,0 +1,469 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+	"todo/taskdata"
+)
+
+const davTestToken = "s3cret"
+
+// newDAVTestServer serves a CalDAV collection backed by a tasks file in a
+// temporary directory
+func newDAVTestServer(t *testing.T) (*httptest.Server, *davServer) {
+	t.Helper()
+	taskdata.SetDataFilePath(filepath.Join(t.TempDir(), "tasks.json"))
+	t.Cleanup(func() { taskdata.SetDataFilePath("") })
+
+	dav := newDAVServer()
+	server := httptest.NewServer(dav.handler(davTestToken))
+	t.Cleanup(server.Close)
+	return server, dav
+}
+
+func davDo(t *testing.T, server *httptest.Server, method, path, body string, headers map[string]string) (*http.Response, string) {
+	t.Helper()
+	req, err := http.NewRequest(method, server.URL+path, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.SetBasicAuth("me", davTestToken)
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	resp, err := server.Client().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp, string(data)
+}
+
+func vtodo(uid, extra string) string {
+	return "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nPRODID:-//test//EN\r\nBEGIN:VTODO\r\n" +
+		"UID:" + uid + "\r\nSUMMARY:Buy milk\r\n" + extra +
+		"END:VTODO\r\nEND:VCALENDAR\r\n"
+}
+
+var calendarType = map[string]string{"Content-Type": "text/calendar; charset=utf-8"}
+
+// addDAVTestTask adds a task and returns it as saved
+func addDAVTestTask(t *testing.T, description string) taskdata.Task {
+	t.Helper()
+	var id int
+	store, err := taskdata.UpdateTasks(func(store *taskdata.TaskStore) error {
+		task, err := store.AddTask(description, "", "normal")
+		if err != nil {
+			return err
+		}
+		id = task.ID
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return *findTaskByID(store, id)
+}
+
+func TestCaldavPropfindListsTasks(t *testing.T) {
+	server, _ := newDAVTestServer(t)
+	task := addDAVTestTask(t, "Write report")
+
+	resp, body := davDo(t, server, "PROPFIND", davTasksPath, `<?xml version="1.0"?>
+<d:propfind xmlns:d="DAV:"><d:prop><d:getetag/><d:resourcetype/></d:prop></d:propfind>`,
+		map[string]string{"Depth": "1"})
+
+	if resp.StatusCode != http.StatusMultiStatus {
+		t.Fatalf("status = %d, want 207\n%s", resp.StatusCode, body)
+	}
+	if !strings.Contains(body, davTasksPath+task.UID+".ics") {
+		t.Errorf("response does not list %s.ics:\n%s", task.UID, body)
+	}
+	if !strings.Contains(body, "getetag") || !strings.Contains(body, "calendar") {
+		t.Errorf("response lacks the requested properties:\n%s", body)
+	}
+
+	resp, _ = davDo(t, server, "PROPFIND", davTasksPath+"missing.ics", "", map[string]string{"Depth": "0"})
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("PROPFIND of a missing task: status = %d, want 404", resp.StatusCode)
+	}
+}
+
+func TestCaldavPutCreatesAndUpdates(t *testing.T) {
+	server, _ := newDAVTestServer(t)
+
+	resp, body := davDo(t, server, "PUT", davTasksPath+"milk.ics", vtodo("milk-1", "PRIORITY:1\r\nDUE;VALUE=DATE:20261102\r\n"), calendarType)
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("create: status = %d, want 201\n%s", resp.StatusCode, body)
+	}
+	etag := resp.Header.Get("ETag")
+
+	store, err := taskdata.LoadTasks()
+	if err != nil {
+		t.Fatal(err)
+	}
+	task := store.FindByUID("milk-1")
+	if task == nil {
+		t.Fatal("the task was not stored")
+	}
+	if task.Description != "Buy milk" || task.DueDate != "2026-11-02" || task.Priority != taskdata.HighestPriority() {
+		t.Errorf("stored task = %+v", *task)
+	}
+
+	// Stored under the client's resource name
+	resp, _ = davDo(t, server, "GET", davTasksPath+"milk.ics", "", nil)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("GET: status = %d, want 200", resp.StatusCode)
+	}
+
+	resp, body = davDo(t, server, "PUT", davTasksPath+"milk.ics", vtodo("milk-1", "STATUS:COMPLETED\r\n"),
+		map[string]string{"Content-Type": "text/calendar", "If-Match": etag})
+	if resp.StatusCode != http.StatusNoContent {
+		t.Fatalf("update: status = %d, want 204\n%s", resp.StatusCode, body)
+	}
+
+	// The ETag used above is out of date now
+	resp, _ = davDo(t, server, "PUT", davTasksPath+"milk.ics", vtodo("milk-1", ""),
+		map[string]string{"Content-Type": "text/calendar", "If-Match": etag})
+	if resp.StatusCode != http.StatusPreconditionFailed {
+		t.Errorf("stale update: status = %d, want 412", resp.StatusCode)
+	}
+
+	store, err = taskdata.LoadTasks()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if task := store.FindByUID("milk-1"); task == nil || !task.Completed {
+		t.Errorf("the update was not applied: %+v", task)
+	}
+}
+
+func TestCaldavPutRejectsInvalidInput(t *testing.T) {
+	server, _ := newDAVTestServer(t)
+
+	tests := []struct {
+		name    string
+		body    string
+		headers map[string]string
+		status  int
+	}{
+		{"priority out of range", vtodo("a", "PRIORITY:12\r\n"), calendarType, http.StatusBadRequest},
+		{"invalid due date", vtodo("b", "DUE;VALUE=DATE:2026-13-45\r\n"), calendarType, http.StatusBadRequest},
+		{"not a calendar", `{"description": "Buy milk"}`, map[string]string{"Content-Type": "application/json"}, http.StatusUnsupportedMediaType},
+		{"no VTODO", "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nEND:VCALENDAR\r\n", calendarType, http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, body := davDo(t, server, "PUT", davTasksPath+"x.ics", tt.body, tt.headers)
+			if resp.StatusCode != tt.status {
+				t.Errorf("status = %d, want %d\n%s", resp.StatusCode, tt.status, body)
+			}
+		})
+	}
+
+	store, err := taskdata.LoadTasks()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(store.Tasks) != 0 {
+		t.Errorf("invalid PUTs stored %d task(s)", len(store.Tasks))
+	}
+}
+
+func TestApplyCaldavTaskValidates(t *testing.T) {
+	tests := []struct {
+		name     string
+		incoming taskdata.Task
+	}{
+		{"unknown priority", taskdata.Task{Description: "x", Priority: "urgent"}},
+		{"invalid due time", taskdata.Task{Description: "x", DueDate: "2026-11-02", DueTime: "25:00"}},
+		{"due time without date", taskdata.Task{Description: "x", DueTime: "10:00"}},
+		{"empty description", taskdata.Task{Description: " "}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := taskdata.Task{Description: "before", Priority: "normal"}
+			err := applyCaldavTask(&task, tt.incoming)
+			var apiErr apiError
+			if !errors.As(err, &apiErr) || apiErr.status != http.StatusBadRequest {
+				t.Fatalf("err = %v, want a 400 error", err)
+			}
+			if task.Description != "before" {
+				t.Errorf("the task was changed despite the error: %+v", task)
+			}
+		})
+	}
+}
+
+func TestCaldavDelete(t *testing.T) {
+	server, _ := newDAVTestServer(t)
+	task := addDAVTestTask(t, "Old task")
+	path := davTasksPath + task.UID + ".ics"
+
+	resp, _ := davDo(t, server, "DELETE", path, "", map[string]string{"If-Match": `"stale"`})
+	if resp.StatusCode != http.StatusPreconditionFailed {
+		t.Errorf("stale delete: status = %d, want 412", resp.StatusCode)
+	}
+
+	resp, _ = davDo(t, server, "DELETE", path, "", map[string]string{"If-Match": taskETag(task)})
+	if resp.StatusCode != http.StatusNoContent {
+		t.Fatalf("delete: status = %d, want 204", resp.StatusCode)
+	}
+
+	resp, _ = davDo(t, server, "DELETE", path, "", nil)
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("second delete: status = %d, want 404", resp.StatusCode)
+	}
+}
+
+var syncTokenPattern = regexp.MustCompile(`<d:sync-token>([^<]*)</d:sync-token>`)
+
+func syncCollection(t *testing.T, server *httptest.Server, token string) (int, string) {
+	t.Helper()
+	resp, body := davDo(t, server, "REPORT", davTasksPath, fmt.Sprintf(`<?xml version="1.0"?>
+<d:sync-collection xmlns:d="DAV:"><d:sync-token>%s</d:sync-token><d:prop><d:getetag/></d:prop></d:sync-collection>`, token), nil)
+	return resp.StatusCode, body
+}
+
+func TestCaldavSyncCollectionReportsChanges(t *testing.T) {
+	server, _ := newDAVTestServer(t)
+	kept := addDAVTestTask(t, "Kept")
+
+	status, body := syncCollection(t, server, "")
+	if status != http.StatusMultiStatus {
+		t.Fatalf("initial sync: status = %d\n%s", status, body)
+	}
+	m := syncTokenPattern.FindStringSubmatch(body)
+	if m == nil {
+		t.Fatalf("no sync token in:\n%s", body)
+	}
+	token := m[1]
+
+	added := addDAVTestTask(t, "Added after the first sync")
+
+	status, body = syncCollection(t, server, token)
+	if status != http.StatusMultiStatus {
+		t.Fatalf("incremental sync: status = %d\n%s", status, body)
+	}
+	if !strings.Contains(body, added.UID+".ics") {
+		t.Errorf("the new task is not reported:\n%s", body)
+	}
+	if strings.Contains(body, kept.UID+".ics") {
+		t.Errorf("the unchanged task is reported:\n%s", body)
+	}
+
+	status, _ = syncCollection(t, server, "http://todo/sync/other-1")
+	if status != http.StatusForbidden {
+		t.Errorf("foreign token: status = %d, want 403", status)
+	}
+}
+
+func TestDAVChangeLogIsCompacted(t *testing.T) {
+	dav := newDAVServer()
+	dav.seq = maxDAVChanges + 2
+	for i := range maxDAVChanges + 1 {
+		dav.changes[fmt.Sprintf("task-%d.ics", i)] = i + 1
+	}
+	dav.compact()
+
+	if len(dav.changes) > maxDAVChanges {
+		t.Fatalf("%d changes kept, want at most %d", len(dav.changes), maxDAVChanges)
+	}
+	if _, ok := dav.changedSince(fmt.Sprintf("http://todo/sync/%s-0", dav.epoch)); ok {
+		t.Error("a token older than the change log is still accepted")
+	}
+	names, ok := dav.changedSince(fmt.Sprintf("http://todo/sync/%s-%d", dav.epoch, maxDAVChanges))
+	if !ok || len(names) != 1 || names[0] != fmt.Sprintf("task-%d.ics", maxDAVChanges) {
+		t.Errorf("changedSince = %v, %v; want the last change", names, ok)
+	}
+}
+
+func TestCaldavAuthentication(t *testing.T) {
+	server, _ := newDAVTestServer(t)
+	client := server.Client()
+	client.CheckRedirect = func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }
+
+	tests := []struct {
+		name   string
+		path   string
+		auth   func(r *http.Request)
+		want   int
+		header string
+	}{
+		{"no credentials", davTasksPath, func(r *http.Request) {}, http.StatusUnauthorized, "WWW-Authenticate"},
+		{"wrong password", davTasksPath, func(r *http.Request) { r.SetBasicAuth("me", "nope") }, http.StatusUnauthorized, "WWW-Authenticate"},
+		{"basic password", davTasksPath, func(r *http.Request) { r.SetBasicAuth("anyone", davTestToken) }, http.StatusOK, "DAV"},
+		{"bearer token", davTasksPath, func(r *http.Request) { r.Header.Set("Authorization", "Bearer "+davTestToken) }, http.StatusOK, "DAV"},
+		{"well-known redirect needs none", "/.well-known/caldav", func(r *http.Request) {}, http.StatusMovedPermanently, "Location"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest("OPTIONS", server.URL+tt.path, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			tt.auth(req)
+			resp, err := client.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+			if resp.StatusCode != tt.want || resp.Header.Get(tt.header) == "" {
+				t.Errorf("status = %d, %s = %q, want %d with the header", resp.StatusCode, tt.header, resp.Header.Get(tt.header), tt.want)
+			}
+		})
+	}
+}
+
+func TestCaldavDiscovery(t *testing.T) {
+	server, _ := newDAVTestServer(t)
+	props := `<?xml version="1.0"?>
+<d:propfind xmlns:d="DAV:" xmlns:c="urn:ietf:params:xml:ns:caldav"><d:prop>
+<d:current-user-principal/><c:calendar-home-set/><d:resourcetype/><c:supported-calendar-component-set/><x:unknown xmlns:x="urn:example"/>
+</d:prop></d:propfind>`
+
+	tests := []struct {
+		path  string
+		depth string
+		want  []string
+	}{
+		{"/", "0", []string{"<d:href>" + davPrincipalPath + "</d:href>", "<d:principal/>"}},
+		{davPrincipalPath, "0", []string{"<d:href>" + davHomePath + "</d:href>"}},
+		{davHomePath, "1", []string{"<d:href>" + davTasksPath + "</d:href>", "<c:calendar/>", `<c:comp name="VTODO"/>`}},
+		{"/calendars/tasks", "0", []string{"<c:calendar/>", "404"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			resp, body := davDo(t, server, "PROPFIND", tt.path, props, map[string]string{"Depth": tt.depth})
+			if resp.StatusCode != http.StatusMultiStatus {
+				t.Fatalf("status = %d, want 207\n%s", resp.StatusCode, body)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(body, want) {
+					t.Errorf("response lacks %s:\n%s", want, body)
+				}
+			}
+		})
+	}
+}
+
+func TestCaldavCalendarQuery(t *testing.T) {
+	server, _ := newDAVTestServer(t)
+	pending := addDAVTestTask(t, "Write report")
+	done := addDAVTestTask(t, "Call mom")
+	if _, err := taskdata.UpdateTasks(func(store *taskdata.TaskStore) error {
+		return updateTaskCompletion(store, done.ID, true)
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	query := func(filter string) string {
+		return `<?xml version="1.0"?>
+<c:calendar-query xmlns:d="DAV:" xmlns:c="urn:ietf:params:xml:ns:caldav">
+<d:prop><d:getetag/><c:calendar-data/></d:prop>
+<c:filter><c:comp-filter name="VCALENDAR">` + filter + `</c:comp-filter></c:filter>
+</c:calendar-query>`
+	}
+	tests := []struct {
+		name string
+		body string
+		want []string
+	}{
+		{"all tasks", query(`<c:comp-filter name="VTODO"/>`), []string{pending.UID, done.UID}},
+		{"pending tasks", query(`<c:comp-filter name="VTODO"><c:prop-filter name="COMPLETED"><c:is-not-defined/></c:prop-filter></c:comp-filter>`), []string{pending.UID}},
+		{"events", query(`<c:comp-filter name="VEVENT"/>`), nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, body := davDo(t, server, "REPORT", davTasksPath, tt.body, map[string]string{"Depth": "1"})
+			if resp.StatusCode != http.StatusMultiStatus {
+				t.Fatalf("status = %d, want 207\n%s", resp.StatusCode, body)
+			}
+			for _, uid := range []string{pending.UID, done.UID} {
+				listed := strings.Contains(body, davTasksPath+uid+".ics")
+				if want := slices.Contains(tt.want, uid); listed != want {
+					t.Errorf("%s listed = %v, want %v:\n%s", uid, listed, want, body)
+				}
+			}
+			if len(tt.want) > 0 && !strings.Contains(body, "BEGIN:VTODO") {
+				t.Errorf("response lacks calendar data:\n%s", body)
+			}
+		})
+	}
+}
+
+func TestCaldavMultiget(t *testing.T) {
+	server, _ := newDAVTestServer(t)
+	task := addDAVTestTask(t, "Write report")
+
+	resp, body := davDo(t, server, "REPORT", davTasksPath, `<?xml version="1.0"?>
+<c:calendar-multiget xmlns:d="DAV:" xmlns:c="urn:ietf:params:xml:ns:caldav">
+<d:prop><d:getetag/></d:prop>
+<d:href>`+davTasksPath+task.UID+`.ics</d:href>
+<d:href>`+davTasksPath+`gone.ics</d:href>
+</c:calendar-multiget>`, nil)
+	if resp.StatusCode != http.StatusMultiStatus {
+		t.Fatalf("status = %d, want 207\n%s", resp.StatusCode, body)
+	}
+	if !strings.Contains(body, davEscape(taskETag(task))) {
+		t.Errorf("response lacks the task's ETag:\n%s", body)
+	}
+	if !regexp.MustCompile(`gone\.ics</d:href>\s*<d:status>[^<]*404`).MatchString(body) {
+		t.Errorf("the missing task is not reported as 404:\n%s", body)
+	}
+
+	resp, _ = davDo(t, server, "REPORT", davTasksPath, `<d:expand-property xmlns:d="DAV:"/>`, nil)
+	if resp.StatusCode != http.StatusForbidden {
+		t.Errorf("unsupported report: status = %d, want 403", resp.StatusCode)
+	}
+	resp, _ = davDo(t, server, "REPORT", davTasksPath, `<d:broken`, nil)
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("invalid XML: status = %d, want 400", resp.StatusCode)
+	}
+}
+
+func TestCaldavGet(t *testing.T) {
+	server, _ := newDAVTestServer(t)
+	report := addDAVTestTask(t, "Write report")
+	addDAVTestTask(t, "Call mom")
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		want   int
+		count  int
+	}{
+		{"one task", "GET", davTasksPath + report.UID + ".ics", http.StatusOK, 1},
+		{"the collection", "GET", davTasksPath, http.StatusOK, 2},
+		{"head", "HEAD", davTasksPath + report.UID + ".ics", http.StatusOK, 0},
+		{"missing task", "GET", davTasksPath + "gone.ics", http.StatusNotFound, 0},
+		{"outside the collection", "GET", davHomePath, http.StatusNotFound, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, body := davDo(t, server, tt.method, tt.path, "", nil)
+			if resp.StatusCode != tt.want {
+				t.Fatalf("status = %d, want %d", resp.StatusCode, tt.want)
+			}
+			if count := strings.Count(body, "BEGIN:VTODO"); count != tt.count {
+				t.Errorf("%d VTODOs, want %d:\n%s", count, tt.count, body)
+			}
+			if tt.count == 1 && resp.Header.Get("ETag") != taskETag(report) {
+				t.Errorf("ETag = %q, want %q", resp.Header.Get("ETag"), taskETag(report))
+			}
+		})
+	}
+}
@@ -0,0 +1,219 @@
+/*
+Copyright © 2025 Smart Todo CLI
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"todo/config"
+	"todo/taskdata"
+
+	"github.com/spf13/cobra"
+)
+
+// completionCmd represents the completion command
+var completionCmd = &cobra.Command{
+	Use:   "completion bash|zsh|fish|powershell",
+	Short: "Generate the shell completion script",
+	Long: `Print a completion script for your shell. Besides commands and flags it
+completes task IDs (with their descriptions) for mark, delete and the other
+commands that take a task, priorities, relative due dates, and existing
+tags, projects, assignees, lists and reports from your tasks.
+
+Examples:
+  # Bash (needs the bash-completion package)
+  source <(todo completion bash)
+  todo completion bash > ~/.local/share/bash-completion/completions/todo
+
+  # Zsh
+  todo completion zsh > "${fpath[1]}/_todo"
+
+  # Fish
+  todo completion fish > ~/.config/fish/completions/todo.fish`,
+	ValidArgs:             []string{"bash", "zsh", "fish", "powershell"},
+	Args:                  cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
+	DisableFlagsInUseLine: true,
+	Run:                   completionRun,
+}
+
+func completionRun(cmd *cobra.Command, args []string) {
+	var err error
+	switch args[0] {
+	case "bash":
+		err = rootCmd.GenBashCompletionV2(os.Stdout, true)
+	case "zsh":
+		err = rootCmd.GenZshCompletion(os.Stdout)
+	case "fish":
+		err = rootCmd.GenFishCompletion(os.Stdout, true)
+	case "powershell":
+		err = rootCmd.GenPowerShellCompletionWithDesc(os.Stdout)
+	}
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+	}
+}
+
+// completeTasks completes the first argument with the IDs of the tasks
+// match accepts, described by their descriptions
+func completeTasks(match func(task taskdata.Task) bool) cobra.CompletionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return taskCompletions(match), cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// completePendingTasks completes pending task IDs
+var completePendingTasks = completeTasks(func(task taskdata.Task) bool { return !task.Completed })
+
+// completeAnyTask completes the IDs of all tasks
+var completeAnyTask = completeTasks(func(task taskdata.Task) bool { return true })
+
+func taskCompletions(match func(task taskdata.Task) bool) []cobra.Completion {
+	store, err := taskdata.LoadTasks()
+	if err != nil {
+		return nil
+	}
+	var completions []cobra.Completion
+	for _, task := range store.Tasks {
+		if match(task) {
+			completions = append(completions, cobra.CompletionWithDesc(strconv.Itoa(task.ID), task.Description))
+		}
+	}
+	return completions
+}
+
+// completeMarkTasks completes pending tasks, or completed ones with --undone
+func completeMarkTasks(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+	if undone, _ := cmd.Flags().GetBool("undone"); undone {
+		return completeTasks(func(task taskdata.Task) bool { return task.Completed })(cmd, args, toComplete)
+	}
+	return completePendingTasks(cmd, args, toComplete)
+}
+
+// completeDependencies completes task IDs for --depends, which takes a
+// comma-separated list
+func completeDependencies(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+	return commaList(toComplete, taskCompletions(func(task taskdata.Task) bool { return !task.Completed })), cobra.ShellCompDirectiveNoFileComp
+}
+
+func completePriorities(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+	return taskdata.Priorities(), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeDates completes the relative date keywords, showing the date
+// each one stands for today
+func completeDates(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+	now := time.Now()
+	var completions []cobra.Completion
+	for _, keyword := range taskdata.DateKeywords {
+		date, _ := taskdata.ResolveDate(keyword.Name, now)
+		completions = append(completions, cobra.CompletionWithDesc(keyword.Name, fmt.Sprintf("%s (%s)", keyword.Description, date)))
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeTags completes existing tags; --tag takes a comma-separated list
+func completeTags(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+	return commaList(toComplete, storeValues(func(task taskdata.Task) []string { return task.Tags })), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeProjects completes existing projects and their parents, so
+// "work" is offered next to "work.reports"
+func completeProjects(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+	return storeValues(func(task taskdata.Task) []string {
+		var projects []string
+		for i, r := range task.Project {
+			if r == '.' {
+				projects = append(projects, task.Project[:i])
+			}
+		}
+		return append(projects, task.Project)
+	}), cobra.ShellCompDirectiveNoFileComp
+}
+
+func completeAssignees(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+	return storeValues(func(task taskdata.Task) []string { return []string{task.Assignee} }), cobra.ShellCompDirectiveNoFileComp
+}
+
+func completeListNames(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+	names, _ := taskdata.ListNames()
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeFirstArg completes only the first argument with complete
+func completeFirstArg(complete cobra.CompletionFunc) cobra.CompletionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return complete(cmd, args, toComplete)
+	}
+}
+
+func completeReportNames(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+	var completions []cobra.Completion
+	for _, name := range appConfig.ReportNames() {
+		completions = append(completions, cobra.CompletionWithDesc(name, appConfig.Reports[name].Description))
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
+func completeSettingKeys(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+	var completions []cobra.Completion
+	for _, setting := range config.Settings() {
+		completions = append(completions, cobra.CompletionWithDesc(setting.Key, setting.Description))
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
+// storeValues returns the distinct, sorted non-empty values that values
+// finds in the tasks
+func storeValues(values func(task taskdata.Task) []string) []string {
+	store, err := taskdata.LoadTasks()
+	if err != nil {
+		return nil
+	}
+	seen := map[string]bool{}
+	var found []string
+	for _, task := range store.Tasks {
+		for _, value := range values(task) {
+			if value != "" && !seen[value] {
+				seen[value] = true
+				found = append(found, value)
+			}
+		}
+	}
+	sort.Strings(found)
+	return found
+}
+
+// commaList completes the last item of a comma-separated list, keeping the
+// items typed before it and leaving them out of the completions
+func commaList(toComplete string, completions []cobra.Completion) []cobra.Completion {
+	i := strings.LastIndex(toComplete, ",")
+	if i < 0 {
+		return completions
+	}
+	prefix := toComplete[:i+1]
+	typed := strings.Split(toComplete[:i], ",")
+	var prefixed []cobra.Completion
+	for _, completion := range completions {
+		value, _, _ := strings.Cut(completion, "\t")
+		if !slices.Contains(typed, value) {
+			prefixed = append(prefixed, prefix+completion)
+		}
+	}
+	return prefixed
+}
+
+func init() {
+	rootCmd.AddCommand(completionCmd)
+}
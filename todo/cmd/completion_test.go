@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"slices"
+	"testing"
+
+	"todo/taskdata"
+)
+
+// useCompletionStore saves tasks to a temporary file for completions to read
+func useCompletionStore(t *testing.T) {
+	t.Helper()
+	useAPIStore(t)
+	store := &taskdata.TaskStore{NextID: 5, Tasks: []taskdata.Task{
+		{ID: 1, UID: "a", Description: "Write report", Project: "work.reports.q3", Tags: []string{"work", "urgent"}, Assignee: "bob"},
+		{ID: 2, UID: "b", Description: "Book room", Project: "work", Tags: []string{"work"}},
+		{ID: 3, UID: "c", Description: "Buy milk", Project: "home", Tags: []string{"shopping"}, Completed: true},
+		{ID: 4, UID: "d", Description: "Call mum", Assignee: "alice"},
+	}}
+	if err := store.SaveTasks(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCompleteTasks(t *testing.T) {
+	useCompletionStore(t)
+	tests := []struct {
+		name     string
+		complete func() []string
+		want     []string
+	}{
+		{"pending", func() []string { got, _ := completePendingTasks(nil, nil, ""); return got },
+			[]string{"1\tWrite report", "2\tBook room", "4\tCall mum"}},
+		{"any", func() []string { got, _ := completeAnyTask(nil, nil, ""); return got },
+			[]string{"1\tWrite report", "2\tBook room", "3\tBuy milk", "4\tCall mum"}},
+		{"only the first argument", func() []string { got, _ := completeAnyTask(nil, []string{"1"}, ""); return got }, nil},
+		{"dependencies", func() []string { got, _ := completeDependencies(nil, nil, ""); return got },
+			[]string{"1\tWrite report", "2\tBook room", "4\tCall mum"}},
+		{"more dependencies", func() []string { got, _ := completeDependencies(nil, nil, "1,4,"); return got },
+			[]string{"1,4,2\tBook room"}},
+	}
+	for _, tt := range tests {
+		if got := tt.complete(); !slices.Equal(got, tt.want) {
+			t.Errorf("%s: %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCompleteStoreValues(t *testing.T) {
+	useCompletionStore(t)
+	tests := []struct {
+		name       string
+		complete   func(toComplete string) []string
+		toComplete string
+		want       []string
+	}{
+		{"projects and their parents", func(s string) []string { got, _ := completeProjects(nil, nil, s); return got }, "",
+			[]string{"home", "work", "work.reports", "work.reports.q3"}},
+		{"tags", func(s string) []string { got, _ := completeTags(nil, nil, s); return got }, "",
+			[]string{"shopping", "urgent", "work"}},
+		{"more tags", func(s string) []string { got, _ := completeTags(nil, nil, s); return got }, "work,",
+			[]string{"work,shopping", "work,urgent"}},
+		{"assignees", func(s string) []string { got, _ := completeAssignees(nil, nil, s); return got }, "",
+			[]string{"alice", "bob"}},
+	}
+	for _, tt := range tests {
+		if got := tt.complete(tt.toComplete); !slices.Equal(got, tt.want) {
+			t.Errorf("%s: %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCommaList(t *testing.T) {
+	completions := []string{"1\tWrite report", "2\tBook room", "12\tCall mum"}
+	tests := []struct {
+		toComplete string
+		want       []string
+	}{
+		{"", completions},
+		{"1", completions},
+		{"1,", []string{"1,2\tBook room", "1,12\tCall mum"}},
+		{"1,2,", []string{"1,2,12\tCall mum"}},
+		{"1,2,12,", nil},
+		{"7,1", []string{"7,1\tWrite report", "7,2\tBook room", "7,12\tCall mum"}},
+	}
+	for _, tt := range tests {
+		if got := commaList(tt.toComplete, completions); !slices.Equal(got, tt.want) {
+			t.Errorf("commaList(%q) = %q, want %q", tt.toComplete, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+/*
+Copyright © 2025 Smart Todo CLI
+*/
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"todo/config"
+
+	"github.com/spf13/cobra"
+)
+
+// configCmd represents the config command
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "View and change configuration settings",
+	Long: `View and change the settings stored in ~/.config/todo/config.toml.
+
+Settings control defaults such as the data file, the priority used by
+'todo add', the due-soon window, the week range, the date format, color,
+the default list filter and the thresholds used by the smart heuristics.
+
+Examples:
+  todo config list                          # Show all settings
+  todo config get dates.due_soon_days       # Show one setting
+  todo config set dates.due_soon_days 5     # Change a setting
+  todo config set dates.week_start monday   # Weeks start on Monday
+  todo config set report.standup.filter "status:pending due:soon"
+  todo config unset dates.due_soon_days     # Restore the default
+  todo config path                          # Show the config file location`,
+	Run: func(cmd *cobra.Command, args []string) {
+		displayConfigList()
+	},
+}
+
+var configListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List all settings and their current values",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		displayConfigList()
+	},
+}
+
+var configGetCmd = &cobra.Command{
+	Use:   "get <key>",
+	Short: "Show the value of a setting",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		value, err := appConfig.Get(args[0])
+		if err != nil {
+			fmt.Printf("❌ %v\n", err)
+			return
+		}
+		fmt.Println(value)
+	},
+}
+
+var configSetCmd = &cobra.Command{
+	Use:   "set <key> <value>",
+	Short: "Change a setting in the config file",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := config.Set(args[0], args[1]); err != nil {
+			fmt.Printf("❌ %v\n", err)
+			return
+		}
+		fmt.Printf("✅ Set %s = %s\n", args[0], args[1])
+	},
+}
+
+var configUnsetCmd = &cobra.Command{
+	Use:   "unset <key>",
+	Short: "Remove a setting so its default applies again",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := config.Unset(args[0]); err != nil {
+			fmt.Printf("❌ %v\n", err)
+			return
+		}
+		fmt.Printf("✅ Unset %s\n", args[0])
+	},
+}
+
+var configPathCmd = &cobra.Command{
+	Use:   "path",
+	Short: "Show the config file location",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		fmt.Println(config.Path())
+	},
+}
+
+func displayConfigList() {
+	fmt.Println("⚙️  Configuration")
+	fmt.Println(strings.Repeat("=", 50))
+	fmt.Printf("File: %s\n\n", config.Path())
+
+	for _, setting := range config.Settings() {
+		value, _ := appConfig.Get(setting.Key)
+		source := "default"
+		if appConfig.IsSet(setting.Key) {
+			source = "config"
+		}
+		if value == "" {
+			value = `""`
+		}
+		fmt.Printf("%-28s %s (%s)\n", setting.Key, value, source)
+		fmt.Printf("%-28s 💭 %s\n", "", setting.Description)
+	}
+
+	fmt.Printf("\n💡 Reports are listed with 'todo report'\n")
+}
+
+func init() {
+	rootCmd.AddCommand(configCmd)
+	configCmd.AddCommand(configListCmd)
+	configCmd.AddCommand(configGetCmd)
+	configCmd.AddCommand(configSetCmd)
+	configCmd.AddCommand(configUnsetCmd)
+	configCmd.AddCommand(configPathCmd)
+
+	configGetCmd.ValidArgsFunction = completeFirstArg(completeSettingKeys)
+	configSetCmd.ValidArgsFunction = completeFirstArg(completeSettingKeys)
+	configUnsetCmd.ValidArgsFunction = completeFirstArg(completeSettingKeys)
+}
@@ -0,0 +1,216 @@
+/*
+Copyright © 2025 Smart Todo CLI
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+	"todo/reminder"
+	"todo/taskdata"
+
+	"github.com/spf13/cobra"
+)
+
+// daemonCmd represents the daemon command
+var daemonCmd = &cobra.Command{
+	Use:   "daemon",
+	Short: "Send reminders before tasks are due",
+	Long: `Watch the tasks and send reminders at set offsets before each task's due
+time. Tasks due on a day without a time are due at reminders.all_day_time.
+
+Offsets come from reminders.offsets (default 1d, 1h and 0m, i.e. a day
+before, an hour before and when due). Each reminder fires once; if the
+daemon was not running, reminders up to 12 hours late are still sent, and
+when several offsets have passed only the latest fires. Changing a task's
+due date re-arms its reminders. What was sent is kept next to the data
+file (tasks.reminders.json); the tasks themselves are only read.
+
+Notifiers (reminders.notifiers or --notify):
+  desktop   Desktop notification through notify-send (D-Bus)
+  bell      Terminal bell and a line on the daemon's output
+  command   Run reminders.command with the reminder as JSON on stdin and
+            TODO_ID, TODO_DESCRIPTION, TODO_DUE and TODO_MESSAGE set
+  webhook   POST the reminder as JSON to reminders.webhook
+
+Use 'todo snooze <task>' to be reminded again later.
+
+Examples:
+  todo daemon                          # Run with the configured notifiers
+  todo daemon --notify bell            # Only ring the terminal bell
+  todo daemon --once                   # Check once, e.g. from cron
+  todo config set reminders.offsets 2h,15m`,
+	Args: cobra.NoArgs,
+	Run:  daemonRun,
+}
+
+// snoozeCmd represents the snooze command
+var snoozeCmd = &cobra.Command{
+	Use:   "snooze <task_id_or_name>",
+	Short: "Postpone a task's reminders",
+	Long: `Hold back a task's reminders and remind again when the snooze runs out.
+
+Examples:
+  todo snooze 5              # Remind again in 10 minutes
+  todo snooze 5 --for 2h     # Remind again in 2 hours
+  todo snooze 5 --for 1d     # Remind again tomorrow`,
+	Args: cobra.ExactArgs(1),
+	Run:  snoozeRun,
+}
+
+func daemonRun(cmd *cobra.Command, args []string) {
+	interval, _ := cmd.Flags().GetDuration("interval")
+	once, _ := cmd.Flags().GetBool("once")
+	names := appConfig.Reminders.Notifiers
+	if cmd.Flags().Changed("notify") {
+		names, _ = cmd.Flags().GetStringSlice("notify")
+	}
+
+	var offsets []time.Duration
+	for _, offset := range appConfig.Reminders.Offsets {
+		d, err := reminder.ParseOffset(offset)
+		if err != nil {
+			fmt.Printf("❌ reminders.offsets: %v\n", err)
+			return
+		}
+		offsets = append(offsets, d)
+	}
+
+	var notifiers []reminder.Notifier
+	opts := reminder.Options{Command: appConfig.Reminders.Command, Webhook: appConfig.Reminders.Webhook}
+	for _, name := range names {
+		notifier, err := reminder.NewNotifier(name, opts)
+		if err != nil {
+			fmt.Printf("⚠️  Skipping %s notifier: %v\n", name, err)
+			continue
+		}
+		notifiers = append(notifiers, notifier)
+	}
+	if len(notifiers) == 0 {
+		fmt.Println("❌ No notifier is available. Set reminders.notifiers or use --notify bell")
+		return
+	}
+
+	var shown []string
+	for _, offset := range offsets {
+		shown = append(shown, reminder.FormatOffset(offset))
+	}
+	if !once {
+		fmt.Printf("⏰ Watching %s: reminding %s before due, checking every %s (Ctrl+C to stop)\n",
+			taskdata.GetDataFilePath(), strings.Join(shown, ", "), interval)
+	}
+
+	for {
+		checkReminders(offsets, notifiers)
+		if once {
+			return
+		}
+		time.Sleep(interval)
+	}
+}
+
+// checkReminders records the reminders that are due in the reminder
+// state file, then sends them, so each one is sent at most once even with
+// several daemons. The tasks themselves are only read.
+func checkReminders(offsets []time.Duration, notifiers []reminder.Notifier) {
+	now := time.Now()
+	store, err := taskdata.LoadTasks()
+	if err != nil {
+		fmt.Printf("⚠️  %s: %v\n", now.Format("15:04"), err)
+		return
+	}
+
+	var fired []reminder.Reminder
+	err = updateReminderStates(store, func(states *reminder.States) {
+		states.Prune(store.Tasks)
+		for _, task := range store.Tasks {
+			if r := states.Check(task, offsets, appConfig.Reminders.AllDayTime, now); r != nil {
+				fired = append(fired, *r)
+			}
+		}
+	})
+	if err != nil {
+		fmt.Printf("⚠️  %s: %v\n", now.Format("15:04"), err)
+		return
+	}
+
+	for _, r := range fired {
+		for _, notifier := range notifiers {
+			if err := notifier.Notify(r, now); err != nil {
+				fmt.Printf("⚠️  #%d: %v\n", r.Task.ID, err)
+			}
+		}
+	}
+}
+
+// updateReminderStates applies update to the reminder state of the store's
+// list and saves it, holding the state file's lock
+func updateReminderStates(store *taskdata.TaskStore, update func(states *reminder.States)) error {
+	path := reminder.StatePath(taskdata.GetDataFilePath())
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create data directory: %v", err)
+	}
+	unlock, err := taskdata.LockFile(path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	states, err := reminder.LoadStates(path, store.Tasks)
+	if err != nil {
+		return err
+	}
+	update(states)
+	return states.Save()
+}
+
+func snoozeRun(cmd *cobra.Command, args []string) {
+	period, _ := cmd.Flags().GetString("for")
+	duration, err := reminder.ParseOffset(period)
+	if err != nil || duration == 0 {
+		fmt.Printf("❌ Invalid snooze period '%s'. Use e.g. 10m, 2h or 1d\n", period)
+		return
+	}
+	until := time.Now().Add(duration)
+
+	store, err := taskdata.LoadTasks()
+	if err != nil {
+		fmt.Printf("❌ Error loading tasks: %v\n", err)
+		return
+	}
+	task := resolveTask(store, args[0])
+	if task == nil {
+		return
+	}
+	if task.Completed {
+		fmt.Printf("❌ Task #%d is already completed\n", task.ID)
+		return
+	}
+	if task.DueDate == "" {
+		fmt.Printf("❌ Task #%d has no due date, so it has no reminders\n", task.ID)
+		return
+	}
+
+	err = updateReminderStates(store, func(states *reminder.States) {
+		states.Snooze(*task, until)
+	})
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		return
+	}
+	fmt.Printf("😴 Snoozed #%d %s until %s\n", task.ID, task.Description, until.Format(appConfig.DateFormat+" 15:04"))
+}
+
+func init() {
+	rootCmd.AddCommand(daemonCmd)
+	rootCmd.AddCommand(snoozeCmd)
+
+	daemonCmd.Flags().Duration("interval", 30*time.Second, "How often to check the tasks")
+	daemonCmd.Flags().Bool("once", false, "Check once and exit")
+	daemonCmd.Flags().StringSlice("notify", nil, "Notifiers to use instead of reminders.notifiers")
+	snoozeCmd.Flags().String("for", "10m", "How long to snooze (e.g. 10m, 2h, 1d)")
+	snoozeCmd.ValidArgsFunction = completePendingTasks
+}
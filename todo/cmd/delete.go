@@ -5,10 +5,10 @@ package cmd
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
 	"os"
 	"sort"
-	"strconv"
 	"strings"
 	"time"
 	"todo/taskdata"
@@ -110,9 +110,9 @@ func deleteRun(cmd *cobra.Command, args []string) {
 		return
 	}
 
-	// Handle direct deletion by ID or name (enhanced with fuzzy matching)
+	// Handle direct deletion by ID or name (ranked fuzzy matching)
 	if len(args) > 0 {
-		deleteByIDOrNameSmart(store, args[0], force)
+		deleteByIDOrName(store, args[0], force)
 		return
 	}
 }
@@ -220,10 +220,10 @@ func getUltraSmartSuggestions(store *taskdata.TaskStore) []SmartSuggestion {
 
 	// 4. Low priority tasks without dates (low impact)
 	lowImpactTasks := getLowImpactTasks(store.Tasks)
-	if len(lowImpactTasks) > 3 {
+	if limit := appConfig.Smart.LowImpactLimit; len(lowImpactTasks) > limit {
 		suggestions = append(suggestions, SmartSuggestion{
 			Category: "Low-Impact Tasks",
-			Tasks:    lowImpactTasks[:3], // Only suggest top 3
+			Tasks:    lowImpactTasks[:min(limit, appConfig.Smart.SuggestionsPerList)], // Only suggest the top few
 			Score:    60,
 			Reason:   "Low priority tasks without deadlines. Consider if they're still needed.",
 			Impact:   "low",
@@ -249,11 +249,14 @@ func getUltraSmartSuggestions(store *taskdata.TaskStore) []SmartSuggestion {
 			Category: "Ancient Low Priority Tasks",
 			Tasks:    ancientTasks,
 			Score:    80,
-			Reason:   "Low priority tasks overdue by more than a month. Likely no longer relevant.",
+			Reason:   fmt.Sprintf("Low priority tasks overdue by more than %d days. Likely no longer relevant.", appConfig.Smart.AncientDays),
 			Impact:   "low",
 		})
 	}
 
+	// 7. Categories contributed by plugins
+	suggestions = append(suggestions, pluginSuggestions(store)...)
+
 	// Sort by score (highest impact first)
 	sort.Slice(suggestions, func(i, j int) bool {
 		return suggestions[i].Score > suggestions[j].Score
@@ -299,67 +302,25 @@ func showSpecificSuggestions(store *taskdata.TaskStore, completed, overdue, old,
 }
 
 func deleteByIDOrName(store *taskdata.TaskStore, identifier string, force bool) {
-	// Try to parse as ID first
-	if id, err := strconv.Atoi(identifier); err == nil {
-		task := findTaskByID(store.Tasks, id)
-		if task == nil {
-			fmt.Printf("❌ Task with ID %d not found.\n", id)
-			return
-		}
-
-		if !force && !confirmDeletion(fmt.Sprintf("Delete task #%d: %s", task.ID, task.Description)) {
-			fmt.Println("Deletion cancelled.")
-			return
-		}
-
-		if deleteTaskByID(store, id) {
-			fmt.Printf("✅ Deleted task #%d: %s\n", task.ID, task.Description)
-			store.SaveTasks()
-		}
+	task := resolveTask(store, identifier)
+	if task == nil {
 		return
 	}
 
-	// Search by name (partial match)
-	matches := findTasksByName(store.Tasks, identifier)
-	if len(matches) == 0 {
-		fmt.Printf("❌ No tasks found matching '%s'.\n", identifier)
+	if !force && !confirmDeletion(fmt.Sprintf("Delete task #%d: %s", task.ID, task.Description)) {
+		fmt.Println("Deletion cancelled.")
 		return
 	}
 
-	if len(matches) == 1 {
-		task := matches[0]
-		if !force && !confirmDeletion(fmt.Sprintf("Delete task #%d: %s", task.ID, task.Description)) {
-			fmt.Println("Deletion cancelled.")
+	id, description := task.ID, task.Description
+	if deleteTaskByID(store, id) {
+		if err := saveTasks(store); err != nil {
+			if !errors.Is(err, errVetoed) {
+				fmt.Printf("❌ Error saving changes: %v\n", err)
+			}
 			return
 		}
-
-		if deleteTaskByID(store, task.ID) {
-			fmt.Printf("✅ Deleted task #%d: %s\n", task.ID, task.Description)
-			store.SaveTasks()
-		}
-		return
-	}
-
-	// Multiple matches - show options
-	fmt.Printf("🔍 Multiple tasks found matching '%s':\n", identifier)
-	for i, task := range matches {
-		fmt.Printf("  %d. #%d: %s\n", i+1, task.ID, task.Description)
-	}
-
-	fmt.Print("\nEnter the number to delete (or 0 to cancel): ")
-	reader := bufio.NewReader(os.Stdin)
-	input, _ := reader.ReadString('\n')
-	choice, err := strconv.Atoi(strings.TrimSpace(input))
-
-	if err != nil || choice < 1 || choice > len(matches) {
-		fmt.Println("Deletion cancelled.")
-		return
-	}
-
-	selectedTask := matches[choice-1]
-	if deleteTaskByID(store, selectedTask.ID) {
-		fmt.Printf("✅ Deleted task #%d: %s\n", selectedTask.ID, selectedTask.Description)
-		store.SaveTasks()
+		fmt.Printf("✅ Deleted task #%d: %s\n", id, description)
 	}
 }
 
@@ -409,34 +370,13 @@ func getOldCompletedTasks(tasks []taskdata.Task) []taskdata.Task {
 func getCompletedHighPriorityTasks(tasks []taskdata.Task) []taskdata.Task {
 	var completed []taskdata.Task
 	for _, task := range tasks {
-		if task.Completed && task.Priority == "high" {
+		if task.Completed && isHighPriority(task) {
 			completed = append(completed, task)
 		}
 	}
 	return completed
 }
 
-func findTaskByID(tasks []taskdata.Task, id int) *taskdata.Task {
-	for _, task := range tasks {
-		if task.ID == id {
-			return &task
-		}
-	}
-	return nil
-}
-
-func findTasksByName(tasks []taskdata.Task, name string) []taskdata.Task {
-	var matches []taskdata.Task
-	searchTerm := strings.ToLower(name)
-
-	for _, task := range tasks {
-		if strings.Contains(strings.ToLower(task.Description), searchTerm) {
-			matches = append(matches, task)
-		}
-	}
-	return matches
-}
-
 func deleteTaskByID(store *taskdata.TaskStore, id int) bool {
 	for i, task := range store.Tasks {
 		if task.ID == id {
@@ -457,7 +397,12 @@ func deleteTasks(store *taskdata.TaskStore, tasks []taskdata.Task) {
 	}
 
 	if deleted > 0 {
-		store.SaveTasks()
+		if err := saveTasks(store); err != nil {
+			if !errors.Is(err, errVetoed) {
+				fmt.Printf("❌ Error saving changes: %v\n", err)
+			}
+			return
+		}
 		fmt.Printf("✅ Successfully deleted %d task(s).\n", deleted)
 	}
 }
@@ -470,15 +415,7 @@ func displayTaskForDeletion(task taskdata.Task) {
 	}
 
 	// Priority icon
-	priorityIcon := ""
-	switch task.Priority {
-	case "high":
-		priorityIcon = "🔴"
-	case "normal":
-		priorityIcon = "🟡"
-	case "low":
-		priorityIcon = "🟢"
-	}
+	priorityIcon := priorityIcon(task.Priority)
 
 	// Format due date with overdue indication
 	dueDateStr := ""
@@ -487,9 +424,9 @@ func displayTaskForDeletion(task taskdata.Task) {
 		if err == nil {
 			now := time.Now()
 			if dueDate.Before(now) && !task.Completed {
-				dueDateStr = fmt.Sprintf(" ⚠️  Overdue (%s)", task.DueDate)
+				dueDateStr = fmt.Sprintf(" ⚠️  Overdue (%s)", formatDate(task.DueDate))
 			} else {
-				dueDateStr = fmt.Sprintf(" 📅 %s", task.DueDate)
+				dueDateStr = fmt.Sprintf(" 📅 %s", formatDate(task.DueDate))
 			}
 		}
 	}
@@ -514,7 +451,7 @@ func confirmDeletion(message string) bool {
 func getOverdueHighPriorityTasks(tasks []taskdata.Task, now time.Time) []taskdata.Task {
 	var overdue []taskdata.Task
 	for _, task := range tasks {
-		if !task.Completed && task.Priority == "high" && task.DueDate != "" {
+		if !task.Completed && isHighPriority(task) && task.DueDate != "" {
 			dueDate, err := time.Parse("2006-01-02", task.DueDate)
 			if err == nil {
 				today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
@@ -551,8 +488,7 @@ func findDuplicateTasks(tasks []taskdata.Task) []taskdata.Task {
 		}
 
 		// Normalize description for comparison
-		normalized := strings.ToLower(strings.TrimSpace(task.Description))
-		normalized = strings.ReplaceAll(normalized, "  ", " ") // Remove double spaces
+		normalized := taskdata.NormalizeDescription(task.Description)
 
 		if existing, exists := seen[normalized]; exists {
 			// Found a duplicate - prefer to keep the one with due date or higher priority
@@ -580,14 +516,13 @@ func shouldKeepExisting(existing, new taskdata.Task) bool {
 	}
 
 	// Keep higher priority
-	priorityOrder := map[string]int{"high": 3, "normal": 2, "low": 1}
-	return priorityOrder[existing.Priority] >= priorityOrder[new.Priority]
+	return taskdata.PriorityRank(existing.Priority) >= taskdata.PriorityRank(new.Priority)
 }
 
 func getLowImpactTasks(tasks []taskdata.Task) []taskdata.Task {
 	var lowImpact []taskdata.Task
 	for _, task := range tasks {
-		if !task.Completed && task.Priority == "low" && task.DueDate == "" {
+		if !task.Completed && isLowPriority(task) && task.DueDate == "" {
 			lowImpact = append(lowImpact, task)
 		}
 	}
@@ -596,7 +531,7 @@ func getLowImpactTasks(tasks []taskdata.Task) []taskdata.Task {
 
 func getVagueTasks(tasks []taskdata.Task) []taskdata.Task {
 	var vague []taskdata.Task
-	vagueKeywords := []string{"stuff", "things", "misc", "todo", "remember", "check", "fix", "update"}
+	vagueKeywords := appConfig.Smart.VagueKeywords
 
 	for _, task := range tasks {
 		if task.Completed {
@@ -604,7 +539,7 @@ func getVagueTasks(tasks []taskdata.Task) []taskdata.Task {
 		}
 
 		desc := strings.ToLower(task.Description)
-		if len(desc) < 10 { // Very short descriptions
+		if len(desc) < appConfig.Smart.VagueMinLength { // Very short descriptions
 			vague = append(vague, task)
 			continue
 		}
@@ -621,10 +556,10 @@ func getVagueTasks(tasks []taskdata.Task) []taskdata.Task {
 
 func getAncientLowPriorityTasks(tasks []taskdata.Task, now time.Time) []taskdata.Task {
 	var ancient []taskdata.Task
-	monthAgo := now.AddDate(0, -1, 0)
+	monthAgo := now.AddDate(0, 0, -appConfig.Smart.AncientDays)
 
 	for _, task := range tasks {
-		if !task.Completed && task.Priority == "low" && task.DueDate != "" {
+		if !task.Completed && isLowPriority(task) && task.DueDate != "" {
 			dueDate, err := time.Parse("2006-01-02", task.DueDate)
 			if err == nil && dueDate.Before(monthAgo) {
 				ancient = append(ancient, task)
@@ -642,15 +577,7 @@ func displayTaskForDeletionSmart(task taskdata.Task, impact string) {
 	}
 
 	// Priority icon
-	priorityIcon := ""
-	switch task.Priority {
-	case "high":
-		priorityIcon = "🔴"
-	case "normal":
-		priorityIcon = "🟡"
-	case "low":
-		priorityIcon = "🟢"
-	}
+	priorityIcon := priorityIcon(task.Priority)
 
 	// Impact icon
 	impactIcon := ""
@@ -673,9 +600,9 @@ func displayTaskForDeletionSmart(task taskdata.Task, impact string) {
 			taskDate := time.Date(dueDate.Year(), dueDate.Month(), dueDate.Day(), 0, 0, 0, 0, dueDate.Location())
 
 			if taskDate.Before(today) && !task.Completed {
-				dueDateStr = fmt.Sprintf(" ⚠️  Overdue (%s)", task.DueDate)
+				dueDateStr = fmt.Sprintf(" ⚠️  Overdue (%s)", formatDate(task.DueDate))
 			} else {
-				dueDateStr = fmt.Sprintf(" 📅 %s", task.DueDate)
+				dueDateStr = fmt.Sprintf(" 📅 %s", formatDate(task.DueDate))
 			}
 		}
 	}
@@ -700,132 +627,6 @@ func confirmDeletionSmart(category, reason string, count int) bool {
 	return response == "y" || response == "yes"
 }
 
-func deleteByIDOrNameSmart(store *taskdata.TaskStore, identifier string, force bool) {
-	// Enhanced fuzzy matching for names
-	if _, err := strconv.Atoi(identifier); err == nil {
-		// Handle ID deletion (same as before)
-		deleteByIDOrName(store, identifier, force)
-		return
-	}
-
-	// Fuzzy search by name with similarity scoring
-	matches := findTasksByNameFuzzy(store.Tasks, identifier)
-	if len(matches) == 0 {
-		fmt.Printf("❌ No tasks found matching '%s'.\n", identifier)
-		fmt.Printf("💡 Try: \n")
-		fmt.Printf("   - Using partial words\n")
-		fmt.Printf("   - Checking task IDs with 'todo list -a'\n")
-		fmt.Printf("   - Using 'todo delete --smart' for smart suggestions\n")
-		return
-	}
-
-	if len(matches) == 1 {
-		task := matches[0]
-		if !force && !confirmDeletion(fmt.Sprintf("Delete task #%d: %s", task.ID, task.Description)) {
-			fmt.Println("Deletion cancelled.")
-			return
-		}
-
-		if deleteTaskByID(store, task.ID) {
-			fmt.Printf("✅ Deleted task #%d: %s\n", task.ID, task.Description)
-			store.SaveTasks()
-		}
-		return
-	}
-
-	// Show smart-ranked matches
-	fmt.Printf("🔍 Found %d similar tasks (ranked by relevance):\n", len(matches))
-	for i, task := range matches {
-		score := calculateSimilarityScore(task.Description, identifier)
-		fmt.Printf("  %d. #%d: %s (%.0f%% match)\n", i+1, task.ID, task.Description, score*100)
-	}
-
-	fmt.Print("\nEnter the number to delete (0 to cancel): ")
-	reader := bufio.NewReader(os.Stdin)
-	input, _ := reader.ReadString('\n')
-	choice, err := strconv.Atoi(strings.TrimSpace(input))
-
-	if err != nil || choice < 1 || choice > len(matches) {
-		fmt.Println("Deletion cancelled.")
-		return
-	}
-
-	selectedTask := matches[choice-1]
-	if deleteTaskByID(store, selectedTask.ID) {
-		fmt.Printf("✅ Deleted task #%d: %s\n", selectedTask.ID, selectedTask.Description)
-		store.SaveTasks()
-	}
-}
-
-func findTasksByNameFuzzy(tasks []taskdata.Task, name string) []taskdata.Task {
-	var matches []taskdata.Task
-	searchTerm := strings.ToLower(name)
-
-	// Collect all potential matches with scores
-	type taskMatch struct {
-		task  taskdata.Task
-		score float64
-	}
-	var candidates []taskMatch
-
-	for _, task := range tasks {
-		score := calculateSimilarityScore(task.Description, searchTerm)
-		if score > 0.3 { // 30% similarity threshold
-			candidates = append(candidates, taskMatch{task, score})
-		}
-	}
-
-	// Sort by score (highest first)
-	sort.Slice(candidates, func(i, j int) bool {
-		return candidates[i].score > candidates[j].score
-	})
-
-	// Return top matches
-	for _, candidate := range candidates {
-		matches = append(matches, candidate.task)
-		if len(matches) >= 5 { // Limit to top 5 matches
-			break
-		}
-	}
-
-	return matches
-}
-
-func calculateSimilarityScore(text, pattern string) float64 {
-	text = strings.ToLower(text)
-	pattern = strings.ToLower(pattern)
-
-	// Exact match
-	if text == pattern {
-		return 1.0
-	}
-
-	// Contains match
-	if strings.Contains(text, pattern) {
-		return 0.8
-	}
-
-	// Word boundary match
-	words := strings.Fields(text)
-	patternWords := strings.Fields(pattern)
-
-	matches := 0
-	for _, pw := range patternWords {
-		for _, tw := range words {
-			if strings.HasPrefix(tw, pw) || strings.Contains(tw, pw) {
-				matches++
-				break
-			}
-		}
-	}
-
-	if len(patternWords) > 0 {
-		return float64(matches) / float64(len(patternWords)) * 0.6
-	}
-
-	return 0.0
-}
-
 // Smart and advanced modes
 func showSmartDeleteSuggestions(store *taskdata.TaskStore, interactive, force bool) {
 	fmt.Println("� Smart-Powered Deletion Assistant")
@@ -931,7 +732,7 @@ func groupTasksForBatch(tasks []taskdata.Task) map[string][]taskdata.Task {
 	for _, task := range tasks {
 		if task.Completed {
 			groups["Completed Tasks"] = append(groups["Completed Tasks"], task)
-		} else if task.Priority == "low" && task.DueDate == "" {
+		} else if isLowPriority(task) && task.DueDate == "" {
 			groups["Low Priority Tasks Without Dates"] = append(groups["Low Priority Tasks Without Dates"], task)
 		}
 	}
@@ -1005,4 +806,6 @@ func init() {
 	// Control flags
 	deleteCmd.Flags().BoolP("interactive", "i", false, "Interactive mode with category-by-category confirmation")
 	deleteCmd.Flags().BoolP("force", "f", false, "Force deletion without confirmation")
+
+	deleteCmd.ValidArgsFunction = completePendingTasks
 }
@@ -0,0 +1,112 @@
+/*
+Copyright © 2025 Smart Todo CLI
+*/
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+	"todo/taskdata"
+	"todo/transfer"
+
+	"github.com/spf13/cobra"
+)
+
+// exportCmd represents the export command
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export tasks in another tool's file format",
+	Long: `Export tasks to standard output or a file.
+
+The format is taken from --format, or guessed from the --output extension.
+Use --filter with report filter terms to export a subset.
+
+Formats:
+  todotxt    todo.txt lines: (A)/(B)/(C) priorities, +project, @context,
+             due:YYYY-MM-DD, t:YYYY-MM-DD (wait until), uid: and x for completed
+  csv        One row per task with a header row of task fields
+  ics        iCalendar: VTODO per task, or VEVENT when it has a due time
+  taskwarrior  JSON for 'task import', including attributes kept from Taskwarrior
+  markdown   - [ ] / - [x] checklists in sections by due date (or --group priority)
+
+Examples:
+  todo export --format todotxt                 # Print todo.txt lines
+  todo export -o todo.txt                      # Write to a file
+  todo export -f todotxt --filter status:pending
+  todo export -o report.csv --filter "status:pending due:week"
+  todo export -o tasks.ics                     # Subscribe from a calendar app
+  todo export -f taskwarrior | task import     # Back to Taskwarrior
+  todo export -f markdown --group priority     # Checklist for a PR description`,
+	Args: cobra.NoArgs,
+	Run:  exportRun,
+}
+
+func exportRun(cmd *cobra.Command, args []string) {
+	formatName, _ := cmd.Flags().GetString("format")
+	output, _ := cmd.Flags().GetString("output")
+	filterExpr, _ := cmd.Flags().GetString("filter")
+	group, _ := cmd.Flags().GetString("group")
+
+	if group != "due" && group != "priority" {
+		fmt.Printf("❌ Invalid group '%s' (use due or priority)\n", group)
+		return
+	}
+
+	format, err := resolveFormat(formatName, output)
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		return
+	}
+
+	filter, err := parseReportFilter(filterExpr)
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		return
+	}
+
+	store, err := taskdata.LoadTasks()
+	if err != nil {
+		fmt.Printf("Error loading tasks: %v\n", err)
+		return
+	}
+
+	now := time.Now()
+	var tasks []taskdata.Task
+	for _, task := range store.Tasks {
+		if filter.matches(task, now) {
+			tasks = append(tasks, task)
+		}
+	}
+
+	var w io.Writer = os.Stdout
+	if output != "" && output != "-" {
+		file, err := os.Create(output)
+		if err != nil {
+			fmt.Printf("❌ Failed to create %s: %v\n", output, err)
+			return
+		}
+		defer file.Close()
+		w = file
+	}
+
+	if err := format.Export(w, tasks, transfer.Options{Store: store, Group: group, DefaultPriority: appConfig.DefaultPriority}); err != nil {
+		fmt.Printf("❌ Export failed: %v\n", err)
+		return
+	}
+
+	if w != os.Stdout {
+		fmt.Printf("✅ Exported %d task(s) to %s (%s)\n", len(tasks), output, format.Name())
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(exportCmd)
+
+	exportCmd.Flags().StringP("format", "f", "", "File format ("+strings.Join(transfer.Names(), ", ")+")")
+	exportCmd.Flags().StringP("output", "o", "", "Write to a file instead of standard output")
+	exportCmd.Flags().String("group", "due", "Section Markdown checklists by due or priority")
+	exportCmd.Flags().String("filter", "", "Only export tasks matching report filter terms (e.g. status:pending)")
+}
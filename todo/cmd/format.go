@@ -0,0 +1,96 @@
+/*
+Copyright © 2025 Smart Todo CLI
+*/
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"todo/taskdata"
+)
+
+// priorityIcon returns the marker shown next to a task for its priority.
+// The most important level is red, the least important green and any levels
+// in between yellow. Plain text is used when color is disabled.
+func priorityIcon(priority string) string {
+	rank := taskdata.PriorityRank(priority)
+	if rank == 0 {
+		return ""
+	}
+
+	if !appConfig.Color {
+		return "[" + priority + "]"
+	}
+
+	switch rank {
+	case len(taskdata.Priorities()):
+		return "🔴"
+	case 1:
+		return "🟢"
+	}
+	return "🟡"
+}
+
+// isHighPriority reports whether a task has the most important priority level
+func isHighPriority(task taskdata.Task) bool {
+	return taskdata.PriorityRank(task.Priority) == len(taskdata.Priorities())
+}
+
+// isLowPriority reports whether a task has the least important priority level
+func isLowPriority(task taskdata.Task) bool {
+	return taskdata.PriorityRank(task.Priority) == 1
+}
+
+// priorityList describes the configured priority levels for help and errors
+func priorityList() string {
+	return strings.Join(taskdata.Priorities(), ", ")
+}
+
+// capitalize upper-cases the first letter of s, such as a priority level
+// or an action name at the start of a message
+func capitalize(s string) string {
+	runes := []rune(s)
+	if len(runes) == 0 {
+		return s
+	}
+	return strings.ToUpper(string(runes[:1])) + string(runes[1:])
+}
+
+// formatDate renders a stored YYYY-MM-DD date using the configured date format
+func formatDate(date string) string {
+	parsed, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return date
+	}
+	return parsed.Format(appConfig.DateFormat)
+}
+
+// formatTags renders tags for display, or "none"
+func formatTags(tags []string) string {
+	if len(tags) == 0 {
+		return "none"
+	}
+	return "+" + strings.Join(tags, " +")
+}
+
+// formatContexts renders contexts as "@phone @home"
+func formatContexts(contexts []string) string {
+	parts := make([]string, len(contexts))
+	for i, context := range contexts {
+		parts[i] = "@" + context
+	}
+	return strings.Join(parts, " ")
+}
+
+// formatDependencies renders dependency IDs for display, or "none"
+func formatDependencies(depends []int) string {
+	if len(depends) == 0 {
+		return "none"
+	}
+	ids := make([]string, len(depends))
+	for i, id := range depends {
+		ids[i] = fmt.Sprintf("#%d", id)
+	}
+	return strings.Join(ids, ", ")
+}
@@ -0,0 +1,305 @@
+/*
+Copyright © 2025 Smart Todo CLI
+*/
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+	"todo/gitlink"
+	"todo/taskdata"
+
+	"github.com/spf13/cobra"
+)
+
+// gitHookCmd represents the git-hook command
+var gitHookCmd = &cobra.Command{
+	Use:   "git-hook",
+	Short: "Complete tasks from git commit messages",
+	Long: `Install git hooks that complete tasks referenced in commit messages.
+
+Once installed, a commit whose message contains "closes todo#12" (also
+"fixes" or "resolves") or "todo: #12, #13" completes those tasks and records
+the commit on them. The commit-msg hook warns about references to unknown or
+already completed tasks; the post-commit hook completes the tasks. Use
+'todo git log 12' to see the commits linked to a task.
+
+Examples:
+  todo git-hook install              # Install into the current repository
+  todo git-hook install ~/src/app    # Install into another repository
+  todo git-hook uninstall            # Remove the hooks`,
+}
+
+var gitHookInstallCmd = &cobra.Command{
+	Use:   "install [repo]",
+	Short: "Install the commit-msg and post-commit hooks",
+	Args:  cobra.MaximumNArgs(1),
+	Run:   gitHookInstallRun,
+}
+
+var gitHookUninstallCmd = &cobra.Command{
+	Use:   "uninstall [repo]",
+	Short: "Remove the hooks installed by 'todo git-hook install'",
+	Args:  cobra.MaximumNArgs(1),
+	Run:   gitHookUninstallRun,
+}
+
+// gitHookCommitMsgCmd is run by the commit-msg hook with the message file
+var gitHookCommitMsgCmd = &cobra.Command{
+	Use:    "commit-msg <message-file>",
+	Args:   cobra.ExactArgs(1),
+	Hidden: true,
+	Run:    gitHookCommitMsgRun,
+}
+
+// gitHookPostCommitCmd is run by the post-commit hook
+var gitHookPostCommitCmd = &cobra.Command{
+	Use:    "post-commit",
+	Args:   cobra.NoArgs,
+	Hidden: true,
+	Run:    gitHookPostCommitRun,
+}
+
+// gitCmd represents the git command
+var gitCmd = &cobra.Command{
+	Use:   "git",
+	Short: "Show git commits linked to tasks",
+}
+
+var gitLogCmd = &cobra.Command{
+	Use:   "log <task_id_or_name>",
+	Short: "Show the commits that referenced a task",
+	Long: `Show the commits recorded on a task by the post-commit hook.
+
+Examples:
+  todo git log 12
+  todo git log "release notes"`,
+	Args: cobra.ExactArgs(1),
+	Run:  gitLogRun,
+}
+
+// gitInfo is kept in Task.Extra["git"] for tasks referenced by commits
+type gitInfo struct {
+	Commits []gitlink.Commit `json:"commits"`
+}
+
+func gitHookInstallRun(cmd *cobra.Command, args []string) {
+	force, _ := cmd.Flags().GetBool("force")
+
+	hooksDir, ok := repoHooksDir(args)
+	if !ok {
+		return
+	}
+
+	executable, err := os.Executable()
+	if err != nil {
+		fmt.Printf("❌ Cannot find the todo executable: %v\n", err)
+		return
+	}
+	command := []string{executable}
+	// Hooks use the same config file (and so the same task file)
+	if cfgFile != "" {
+		if path, err := filepath.Abs(cfgFile); err == nil {
+			command = append(command, "--config", path)
+		}
+	}
+
+	for _, hook := range gitlink.Hooks {
+		if err := gitlink.Install(hooksDir, hook, command, force); err != nil {
+			fmt.Printf("❌ %v\n", err)
+			return
+		}
+		fmt.Printf("✅ Installed %s\n", filepath.Join(hooksDir, hook))
+	}
+	fmt.Println("💡 Commit with \"closes todo#12\" or \"todo: #12\" to complete task #12")
+}
+
+func gitHookUninstallRun(cmd *cobra.Command, args []string) {
+	hooksDir, ok := repoHooksDir(args)
+	if !ok {
+		return
+	}
+
+	for _, hook := range gitlink.Hooks {
+		removed, err := gitlink.Uninstall(hooksDir, hook)
+		if err != nil {
+			fmt.Printf("⚠️  %v\n", err)
+			continue
+		}
+		if removed {
+			fmt.Printf("🗑️  Removed %s\n", filepath.Join(hooksDir, hook))
+		}
+	}
+}
+
+// repoHooksDir returns the hooks directory of the repository given in args,
+// or the current one
+func repoHooksDir(args []string) (string, bool) {
+	repo := "."
+	if len(args) > 0 {
+		repo = args[0]
+	}
+	hooksDir, err := gitlink.HooksDir(repo)
+	if err != nil {
+		fmt.Printf("❌ %s is not a git repository: %v\n", repo, err)
+		return "", false
+	}
+	return hooksDir, true
+}
+
+// gitHookCommitMsgRun warns about references to tasks that cannot be
+// completed. It never rejects the commit.
+func gitHookCommitMsgRun(cmd *cobra.Command, args []string) {
+	message, err := os.ReadFile(args[0])
+	if err != nil {
+		fmt.Printf("⚠️  todo: cannot read the commit message: %v\n", err)
+		return
+	}
+
+	ids := gitlink.References(string(message))
+	if len(ids) == 0 {
+		return
+	}
+
+	store, err := taskdata.LoadTasks()
+	if err != nil {
+		fmt.Printf("⚠️  todo: error loading tasks: %v\n", err)
+		return
+	}
+
+	for _, id := range ids {
+		task := findTaskByID(store, id)
+		switch {
+		case task == nil:
+			fmt.Printf("⚠️  todo: task #%d not found; the reference will be ignored\n", id)
+		case task.Completed:
+			fmt.Printf("ℹ️  todo: task #%d is already completed: %s\n", id, task.Description)
+		default:
+			fmt.Printf("🔗 todo: this commit will complete task #%d: %s\n", id, task.Description)
+		}
+	}
+}
+
+// gitHookPostCommitRun completes the tasks referenced by the new commit and
+// records the commit on them
+func gitHookPostCommitRun(cmd *cobra.Command, args []string) {
+	commit, message, err := gitlink.HeadCommit(".")
+	if err != nil {
+		fmt.Printf("⚠️  todo: %v\n", err)
+		return
+	}
+
+	ids := gitlink.References(message)
+	if len(ids) == 0 {
+		return
+	}
+
+	store, err := taskdata.LoadTasks()
+	if err != nil {
+		fmt.Printf("⚠️  todo: error loading tasks: %v\n", err)
+		return
+	}
+
+	for _, id := range ids {
+		task := findTaskByID(store, id)
+		if task == nil {
+			fmt.Printf("⚠️  todo: task #%d not found\n", id)
+			continue
+		}
+
+		linkCommit(task, commit)
+		if task.Completed {
+			fmt.Printf("🔗 Linked %s to completed task #%d: %s\n", commit.Short(), task.ID, task.Description)
+			continue
+		}
+		updateTaskCompletion(store, task.ID, true)
+		fmt.Printf("✅ todo: %s completed task #%d: %s\n", commit.Short(), task.ID, task.Description)
+	}
+
+	if err := saveTasks(store); err != nil {
+		fmt.Printf("❌ todo: error saving changes: %v\n", err)
+	}
+}
+
+func gitLogRun(cmd *cobra.Command, args []string) {
+	store, err := taskdata.LoadTasks()
+	if err != nil {
+		fmt.Printf("Error loading tasks: %v\n", err)
+		return
+	}
+
+	task := resolveTask(store, args[0])
+	if task == nil {
+		return
+	}
+
+	commits := getGitInfo(*task).Commits
+	fmt.Printf("🔗 Commits linked to task #%d: %s\n", task.ID, task.Description)
+	fmt.Println("==================================================")
+	if len(commits) == 0 {
+		fmt.Printf("No commits yet. Reference the task with \"closes todo#%d\" in a commit message.\n", task.ID)
+		return
+	}
+
+	for _, commit := range commits {
+		date := commit.Date
+		if t, err := time.Parse(time.RFC3339, commit.Date); err == nil {
+			date = t.Local().Format("2006-01-02 15:04")
+		}
+		fmt.Printf("  %s %s %s\n", commit.Short(), date, commit.Subject)
+		if commit.Author != "" || commit.Repo != "" {
+			fmt.Printf("           %s  %s\n", commit.Author, commit.Repo)
+		}
+	}
+}
+
+// findTaskByID returns the stored task with the given ID, or nil
+func findTaskByID(store *taskdata.TaskStore, id int) *taskdata.Task {
+	for i := range store.Tasks {
+		if store.Tasks[i].ID == id {
+			return &store.Tasks[i]
+		}
+	}
+	return nil
+}
+
+// linkCommit records a commit on a task unless it is already there
+func linkCommit(task *taskdata.Task, commit gitlink.Commit) {
+	info := getGitInfo(*task)
+	for _, linked := range info.Commits {
+		if linked.Hash == commit.Hash {
+			return
+		}
+	}
+	info.Commits = append(info.Commits, commit)
+
+	data, err := json.Marshal(info)
+	if err != nil {
+		return
+	}
+	if task.Extra == nil {
+		task.Extra = map[string]json.RawMessage{}
+	}
+	task.Extra["git"] = data
+}
+
+func getGitInfo(task taskdata.Task) gitInfo {
+	var info gitInfo
+	if raw, exists := task.Extra["git"]; exists {
+		json.Unmarshal(raw, &info)
+	}
+	return info
+}
+
+func init() {
+	rootCmd.AddCommand(gitHookCmd)
+	gitHookCmd.AddCommand(gitHookInstallCmd, gitHookUninstallCmd, gitHookCommitMsgCmd, gitHookPostCommitCmd)
+	rootCmd.AddCommand(gitCmd)
+	gitCmd.AddCommand(gitLogCmd)
+
+	gitHookInstallCmd.Flags().Bool("force", false, "Replace existing hooks not installed by todo")
+	gitLogCmd.ValidArgsFunction = completeAnyTask
+}
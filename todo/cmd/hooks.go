@@ -0,0 +1,149 @@
+/*
+Copyright © 2025 Smart Todo CLI
+*/
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"sort"
+	"todo/hooks"
+	"todo/taskdata"
+)
+
+// errVetoed is returned by saveTasks when pre-hooks vetoed every change
+var errVetoed = errors.New("every change was vetoed by a hook")
+
+// taskHooks returns the hooks configured in the hooks.* settings
+func taskHooks() hooks.Config {
+	h := appConfig.Hooks
+	return hooks.Config{
+		Pre: map[hooks.Event]string{
+			hooks.Add:      h.PreAdd,
+			hooks.Modify:   h.PreModify,
+			hooks.Complete: h.PreComplete,
+			hooks.Delete:   h.PreDelete,
+		},
+		On: map[hooks.Event]string{
+			hooks.Add:      h.OnAdd,
+			hooks.Modify:   h.OnModify,
+			hooks.Complete: h.OnComplete,
+			hooks.Delete:   h.OnDelete,
+		},
+	}
+}
+
+// saveTasks saves the store like SaveTasks, running the pre-hooks for every
+// change since it was loaded first and the post-hooks for the saved changes
+// afterwards. Vetoed changes are undone before saving and rewritten tasks
+// are saved as the hook returned them.
+func saveTasks(store *taskdata.TaskStore) error {
+	return saveWithHooks(store, taskHooks(), os.Stdout)
+}
+
+// saveTasksReporting is saveTasks telling w what the hooks did, and what
+// they printed themselves
+func saveTasksReporting(store *taskdata.TaskStore, w io.Writer) error {
+	runner := taskHooks()
+	runner.Output = w
+	return saveWithHooks(store, runner, w)
+}
+
+// saveWithHooks is saveTasks with the given hooks, reporting to w
+func saveWithHooks(store *taskdata.TaskStore, runner hooks.Config, w io.Writer) error {
+	if !runner.Enabled() {
+		return store.SaveTasks()
+	}
+
+	changes := store.Changes()
+	var accepted []hooks.Change
+	for _, c := range changes {
+		change := hooks.Change{Event: hooks.EventOf(c.Old, c.New), Old: c.Old}
+		if c.New != nil {
+			change.Task = *c.New
+		} else {
+			change.Task = *c.Old
+		}
+
+		task, ok := runPreHook(w, runner, change)
+		if !ok {
+			undoChange(store, change)
+			continue
+		}
+		if change.Event != hooks.Delete {
+			*findTaskByUID(store, task.UID) = task
+		}
+		change.Task = task
+		accepted = append(accepted, change)
+	}
+	if len(changes) > 0 && len(accepted) == 0 {
+		return errVetoed
+	}
+
+	if err := store.SaveTasks(); err != nil {
+		return err
+	}
+	runPostHooks(w, runner, accepted)
+	return nil
+}
+
+// runPreHook runs the pre-hook for a change, telling the user when it vetoes
+// or rewrites the change. It returns the task to save and whether to save it.
+func runPreHook(w io.Writer, runner hooks.Config, change hooks.Change) (taskdata.Task, bool) {
+	task, err := runner.RunPre(change)
+	if err != nil {
+		fmt.Fprintf(w, "🚫 Task #%d %s not %s: %v\n", change.Task.ID, change.Task.Description, pastTense(change.Event), err)
+		return change.Task, false
+	}
+	if !reflect.DeepEqual(task, change.Task) {
+		fmt.Fprintf(w, "🪝 The pre-%s hook rewrote task #%d: %s\n", change.Event, task.ID, task.Description)
+	}
+	return task, true
+}
+
+// runPostHooks runs the post-hooks for saved changes; failures are only
+// reported, as the changes are already saved
+func runPostHooks(w io.Writer, runner hooks.Config, changes []hooks.Change) {
+	for _, change := range changes {
+		if err := runner.RunOn(change); err != nil {
+			fmt.Fprintf(w, "⚠️  Task #%d: %v\n", change.Task.ID, err)
+		}
+	}
+}
+
+// undoChange restores a task to how it was loaded
+func undoChange(store *taskdata.TaskStore, change hooks.Change) {
+	switch change.Event {
+	case hooks.Add:
+		deleteTaskByID(store, change.Task.ID)
+	case hooks.Delete:
+		store.Tasks = append(store.Tasks, *change.Old)
+		sort.SliceStable(store.Tasks, func(i, j int) bool { return store.Tasks[i].ID < store.Tasks[j].ID })
+	default:
+		*findTaskByUID(store, change.Task.UID) = *change.Old
+	}
+}
+
+func findTaskByUID(store *taskdata.TaskStore, uid string) *taskdata.Task {
+	for i := range store.Tasks {
+		if store.Tasks[i].UID == uid {
+			return &store.Tasks[i]
+		}
+	}
+	return nil
+}
+
+func pastTense(event hooks.Event) string {
+	switch event {
+	case hooks.Add:
+		return "added"
+	case hooks.Complete:
+		return "completed"
+	case hooks.Delete:
+		return "deleted"
+	}
+	return "changed"
+}
@@ -0,0 +1,217 @@
+/*
+Copyright © 2025 Smart Todo CLI
+*/
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"todo/taskdata"
+	"todo/transfer"
+
+	"github.com/spf13/cobra"
+)
+
+// importCmd represents the import command
+var importCmd = &cobra.Command{
+	Use:   "import <file>",
+	Short: "Import tasks from another tool's file format",
+	Long: `Import tasks from a file written by another tool.
+
+The format is taken from --format, or guessed from the file extension.
+Use - as the file name to read from standard input.
+
+Every entry is validated like 'todo add' (dates as YYYY-MM-DD, priorities on
+the configured scale). Invalid entries are skipped and listed with their line
+or row number; use --dry-run to check a file before importing it.
+
+With --sync, entries that match an existing task (same UID, or the same
+description) update its completion state instead of being added again.
+
+Formats:
+  todotxt    todo.txt lines: (A)/(B)/(C) priorities, +project, @context,
+             due:YYYY-MM-DD, t:YYYY-MM-DD (wait until), uid: and x for completed
+  csv        Header row naming task fields (description, due_date, priority,
+             completed, notes, wait_until, created_at, completed_at, tags,
+             project, contexts); map other headers with --map
+  ics        iCalendar VTODOs; entries whose UID already exists are skipped
+  taskwarrior  'task export' JSON; deleted tasks are skipped, attributes
+             without a matching field are kept for exporting back
+  markdown   - [ ] / - [x] checklist items with inline (due 2026-11-02),
+             (wait 2026-11-01), !priority and #tag metadata
+
+Examples:
+  todo import todo.txt                     # Format from the extension
+  todo import --format todotxt done.txt    # Explicit format
+  cat todo.txt | todo import -f todotxt -  # Read from stdin
+  todo import tasks.csv --map "Title=description,Deadline=due_date"
+  todo import tasks.csv --dry-run          # Preview without saving
+  todo import calendar.ics                 # VTODOs from a calendar app
+  task export | todo import -f taskwarrior -
+  todo import --sync TODO.md               # Pick up items ticked off in a README`,
+	Args: cobra.ExactArgs(1),
+	Run:  importRun,
+}
+
+func importRun(cmd *cobra.Command, args []string) {
+	formatName, _ := cmd.Flags().GetString("format")
+	mappingSpec, _ := cmd.Flags().GetString("map")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	sync, _ := cmd.Flags().GetBool("sync")
+
+	mapping, err := transfer.ParseMapping(mappingSpec)
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		return
+	}
+
+	format, err := resolveFormat(formatName, args[0])
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		return
+	}
+
+	var input io.Reader = os.Stdin
+	if args[0] != "-" {
+		file, err := os.Open(args[0])
+		if err != nil {
+			fmt.Printf("❌ Failed to open %s: %v\n", args[0], err)
+			return
+		}
+		defer file.Close()
+		input = file
+	}
+
+	result, err := format.Import(input, transfer.Options{Mapping: mapping, DefaultPriority: appConfig.DefaultPriority})
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		return
+	}
+
+	store, err := taskdata.LoadTasks()
+	if err != nil {
+		fmt.Printf("Error loading tasks: %v\n", err)
+		return
+	}
+
+	if dryRun {
+		fmt.Printf("🔍 Dry run: importing from %s (%s)\n", args[0], format.Name())
+	} else {
+		fmt.Printf("📥 Importing from %s (%s)\n", args[0], format.Name())
+	}
+	fmt.Println(strings.Repeat("=", 50))
+
+	added, duplicates, synced := 0, 0, 0
+	for _, task := range result.Tasks {
+		if sync {
+			existing := store.FindByUID(task.UID)
+			if existing == nil {
+				existing = store.FindByDescription(task.Description)
+			}
+			if existing != nil {
+				if existing.Completed != task.Completed {
+					updateTaskCompletion(store, existing.ID, task.Completed)
+					displayTask(*existing)
+					synced++
+				} else {
+					duplicates++
+				}
+				continue
+			}
+		}
+
+		// Entries carrying a known UID were imported (or exported from here) before
+		if existing := store.FindByUID(task.UID); existing != nil {
+			duplicates++
+			continue
+		}
+		if task.Priority == "" {
+			task.Priority = appConfig.DefaultPriority
+		}
+		imported, err := store.ImportTask(task)
+		if err != nil {
+			fmt.Printf("  ❌ '%s': %v\n", task.Description, err)
+			continue
+		}
+		displayTask(*imported)
+		added++
+	}
+
+	linkImportedDependencies(store, result.Dependencies)
+
+	if synced > 0 {
+		fmt.Printf("\n🔄 Updated the completion state of %d existing task(s)\n", synced)
+	}
+	if duplicates > 0 {
+		fmt.Printf("\n⏭️  Skipped %d task(s) that already exist\n", duplicates)
+	}
+	displayImportErrors(result.Errors)
+
+	if dryRun {
+		fmt.Printf("\n🔍 Would import %d task(s), update %d, skip %d. Nothing was saved.\n",
+			added, synced, len(result.Errors)+len(result.Tasks)-added-duplicates-synced)
+		return
+	}
+	if added == 0 && synced == 0 {
+		fmt.Println("\nNo tasks imported.")
+		return
+	}
+	if err := store.SaveTasks(); err != nil {
+		fmt.Printf("Error saving tasks: %v\n", err)
+		return
+	}
+	fmt.Printf("\n✅ Imported %d task(s), updated %d\n", added, synced)
+}
+
+// linkImportedDependencies turns dependencies recorded by UID into task IDs
+func linkImportedDependencies(store *taskdata.TaskStore, dependencies map[string][]string) {
+	for uid, dependsOn := range dependencies {
+		task := store.FindByUID(uid)
+		if task == nil {
+			continue
+		}
+		for _, dependencyUID := range dependsOn {
+			dependency := store.FindByUID(dependencyUID)
+			if dependency == nil {
+				fmt.Printf("  ⚠️  #%d depends on %s, which was not imported; it is kept for export\n", task.ID, dependencyUID)
+				continue
+			}
+			task.DependsOn = addDependency(task.DependsOn, dependency.ID)
+		}
+	}
+}
+
+func displayImportErrors(errors []transfer.ImportError) {
+	if len(errors) == 0 {
+		return
+	}
+	fmt.Printf("\n⚠️  Skipped %d entry(s):\n", len(errors))
+	for _, e := range errors {
+		fmt.Printf("  %v\n", e)
+		if e.Text != "" {
+			fmt.Printf("     %s\n", e.Text)
+		}
+	}
+}
+
+// resolveFormat looks up --format, falling back to the file extension
+func resolveFormat(name, fileName string) (transfer.Format, error) {
+	if name != "" {
+		return transfer.Lookup(name)
+	}
+	if fileName == "" || fileName == "-" {
+		return nil, fmt.Errorf("please choose a format with --format (%s)", strings.Join(transfer.Names(), ", "))
+	}
+	return transfer.Detect(fileName)
+}
+
+func init() {
+	rootCmd.AddCommand(importCmd)
+
+	importCmd.Flags().StringP("format", "f", "", "File format ("+strings.Join(transfer.Names(), ", ")+")")
+	importCmd.Flags().String("map", "", "Map source columns onto task fields, e.g. \"Title=description,Deadline=due_date\"")
+	importCmd.Flags().Bool("sync", false, "Update the completion state of tasks that were imported before")
+	importCmd.Flags().Bool("dry-run", false, "Validate and preview the tasks without saving them")
+}
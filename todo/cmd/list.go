@@ -15,15 +15,16 @@ import (
 
 // listCmd represents the list command
 var listCmd = &cobra.Command{
-	Use:   "list",
+	Use:   "list [@report]",
 	Short: "List your tasks with smart filtering and insights",
 	Long: `Display your todo tasks with various filtering options and smart insights.
 
-By default, shows today's tasks. You can filter by:
+By default, shows today's tasks (configurable with defaults.list_filter). You can filter by:
 - Time period: today (default), week, month, all
-- Priority: low, normal, high (can use first letter: l, n, h)
+- Priority: low, normal, high (can use first letter: l, n, h), or your configured scale
 - Completion status: pending, completed, all
 - Smart filters: overdue, due-soon, no-date, productivity insights
+- Assignee: your tasks (--mine) or someone else's (--assignee)
 
 Examples:
   todo list                      # Show today's tasks with insights
@@ -34,14 +35,24 @@ Examples:
   todo list -p high --all        # Show all high priority tasks
   todo list --completed          # Show completed tasks
   todo list --overdue            # Show only overdue tasks
-  todo list --due-soon           # Show tasks due in next 3 days
+  todo list --due-soon           # Show tasks due soon (next 3 days by default)
   todo list --no-date            # Show tasks without due dates
   todo list --insights           # Show productivity insights
-  todo list --smart              # Smart view with recommendations`,
+  todo list --smart              # Smart view with recommendations (your tasks)
+  todo list --smart --everyone   # Smart view including tasks assigned to others
+  todo list --mine -a            # Show all tasks assigned to you
+  todo list --assignee bob -a    # Show all tasks assigned to bob
+  todo list @next                # Run a named report (see 'todo report')`,
 	Run: listRun,
 }
 
 func listRun(cmd *cobra.Command, args []string) {
+	// Named report (todo list @standup)
+	if len(args) > 0 && strings.HasPrefix(args[0], "@") {
+		runReportByName(appConfig, args[0])
+		return
+	}
+
 	// Load tasks
 	store, err := taskdata.LoadTasks()
 	if err != nil {
@@ -67,6 +78,11 @@ func listRun(cmd *cobra.Command, args []string) {
 	showInsights, _ := cmd.Flags().GetBool("insights")
 	showSmart, _ := cmd.Flags().GetBool("smart")
 	showStats, _ := cmd.Flags().GetBool("stats")
+	showMine, _ := cmd.Flags().GetBool("mine")
+	assignee, _ := cmd.Flags().GetString("assignee")
+	if showMine {
+		assignee = "me"
+	}
 
 	// Smart view mode
 	if showSmart {
@@ -95,6 +111,7 @@ func listRun(cmd *cobra.Command, args []string) {
 		showOverdue:   showOverdue,
 		showDueSoon:   showDueSoon,
 		showNoDate:    showNoDate,
+		assignee:      assignee,
 	})
 
 	if len(filteredTasks) == 0 {
@@ -119,6 +136,7 @@ type filterOptions struct {
 	showOverdue   bool
 	showDueSoon   bool
 	showNoDate    bool
+	assignee      string // "me" for the local user, "none" for unassigned tasks
 }
 
 func getTimeFilter(week, month, all bool) string {
@@ -131,7 +149,7 @@ func getTimeFilter(week, month, all bool) string {
 	if all {
 		return "all"
 	}
-	return "today"
+	return appConfig.DefaultListFilter
 }
 
 func filterTasks(tasks []taskdata.Task, opts filterOptions) []taskdata.Task {
@@ -162,6 +180,11 @@ func filterTasks(tasks []taskdata.Task, opts filterOptions) []taskdata.Task {
 			continue
 		}
 
+		// Assignee filter
+		if opts.assignee != "" && !matchesAssignee(task, opts.assignee) {
+			continue
+		}
+
 		// Completion status filter
 		if opts.showCompleted && !opts.showPending {
 			if !task.Completed {
@@ -183,10 +206,10 @@ func filterTasks(tasks []taskdata.Task, opts filterOptions) []taskdata.Task {
 			return !filtered[i].Completed
 		}
 
-		// Then by priority (high > normal > low)
-		priorityOrder := map[string]int{"high": 3, "normal": 2, "low": 1}
-		if priorityOrder[filtered[i].Priority] != priorityOrder[filtered[j].Priority] {
-			return priorityOrder[filtered[i].Priority] > priorityOrder[filtered[j].Priority]
+		// Then by priority (most important level first)
+		ri, rj := taskdata.PriorityRank(filtered[i].Priority), taskdata.PriorityRank(filtered[j].Priority)
+		if ri != rj {
+			return ri > rj
 		}
 
 		// Finally by due date (earlier dates first)
@@ -230,19 +253,20 @@ func matchesTimeFilter(task taskdata.Task, timeFilter string, now time.Time) boo
 }
 
 func matchesPriority(task taskdata.Task, priority string) bool {
-	priority = strings.ToLower(priority)
-
-	// Handle single letter shortcuts
-	switch priority {
-	case "h":
-		priority = "high"
-	case "n":
-		priority = "normal"
-	case "l":
-		priority = "low"
+	// Handles case and shortcuts such as h, n, l on the default scale
+	level, err := taskdata.NormalizePriority(priority)
+	if err != nil {
+		return false
 	}
 
-	return task.Priority == priority
+	return taskdata.PriorityRank(task.Priority) == taskdata.PriorityRank(level)
+}
+
+func matchesAssignee(task taskdata.Task, assignee string) bool {
+	if strings.EqualFold(assignee, "none") {
+		return task.Assignee == ""
+	}
+	return task.IsAssignedTo(resolveAssignee(assignee))
 }
 
 func isOverdue(task taskdata.Task, now time.Time) bool {
@@ -263,6 +287,11 @@ func isOverdue(task taskdata.Task, now time.Time) bool {
 }
 
 func isDueSoon(task taskdata.Task, now time.Time) bool {
+	// Due soon = within the configured number of days (default 3)
+	return isDueWithin(task, now, appConfig.DueSoonDays)
+}
+
+func isDueWithin(task taskdata.Task, now time.Time, days int) bool {
 	if task.Completed || task.DueDate == "" {
 		return false
 	}
@@ -272,9 +301,8 @@ func isDueSoon(task taskdata.Task, now time.Time) bool {
 		return false
 	}
 
-	// Due soon = within next 3 days
-	threeDaysFromNow := now.AddDate(0, 0, 3)
-	return !dueDate.Before(now) && !dueDate.After(threeDaysFromNow)
+	limit := now.AddDate(0, 0, days)
+	return !dueDate.Before(now) && !dueDate.After(limit)
 }
 
 func isSameDay(date1, date2 time.Time) bool {
@@ -284,9 +312,14 @@ func isSameDay(date1, date2 time.Time) bool {
 }
 
 func isInWeekRange(date, now time.Time) bool {
-	// Week range is today + 6 days (7 days total)
+	// Week range starts today (or on the configured weekday) and spans week_days days
 	startOfRange := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
-	endOfRange := startOfRange.AddDate(0, 0, 6)
+	if appConfig.WeekStart != "today" {
+		for !strings.EqualFold(startOfRange.Weekday().String(), appConfig.WeekStart) {
+			startOfRange = startOfRange.AddDate(0, 0, -1)
+		}
+	}
+	endOfRange := startOfRange.AddDate(0, 0, appConfig.WeekDays-1)
 	normalizedDate := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
 
 	return !normalizedDate.Before(startOfRange) && !normalizedDate.After(endOfRange)
@@ -302,7 +335,7 @@ func displayTasks(tasks []taskdata.Task, timeFilter string) {
 	// Display header
 	switch timeFilter {
 	case "today":
-		fmt.Printf("📅 Today's Tasks (%s)\n", time.Now().Format("2006-01-02"))
+		fmt.Printf("📅 Today's Tasks (%s)\n", time.Now().Format(appConfig.DateFormat))
 	case "week":
 		fmt.Printf("📅 This Week's Tasks\n")
 	case "month":
@@ -351,15 +384,7 @@ func displayTask(task taskdata.Task) {
 	}
 
 	// Priority icon
-	priorityIcon := ""
-	switch task.Priority {
-	case "high":
-		priorityIcon = "🔴"
-	case "normal":
-		priorityIcon = "🟡"
-	case "low":
-		priorityIcon = "🟢"
-	}
+	priorityIcon := priorityIcon(task.Priority)
 
 	// Format due date
 	dueDateStr := ""
@@ -370,75 +395,110 @@ func displayTask(task taskdata.Task) {
 			if isSameDay(dueDate, now) {
 				dueDateStr = " 📅 Today"
 			} else if dueDate.Before(now) && !task.Completed {
-				dueDateStr = fmt.Sprintf(" ⚠️  Overdue (%s)", task.DueDate)
+				dueDateStr = fmt.Sprintf(" ⚠️  Overdue (%s)", formatDate(task.DueDate))
 			} else {
-				dueDateStr = fmt.Sprintf(" 📅 %s", task.DueDate)
+				dueDateStr = fmt.Sprintf(" 📅 %s", formatDate(task.DueDate))
+			}
+			if task.DueTime != "" {
+				dueDateStr += " ⏰ " + task.DueTime
 			}
 		}
 	}
 
-	fmt.Printf("  %s %s #%d: %s%s\n",
+	// Tags
+	tagsStr := ""
+	if len(task.Tags) > 0 {
+		tagsStr = " 🏷️  " + formatTags(task.Tags)
+	}
+	if task.Project != "" {
+		tagsStr += " 📁 " + task.Project
+	}
+	if len(task.Contexts) > 0 {
+		tagsStr += " " + formatContexts(task.Contexts)
+	}
+	if task.Source != "" {
+		tagsStr += " 📄 " + task.Source
+	}
+	if task.Assignee != "" {
+		tagsStr += " 👤 " + task.Assignee
+	}
+
+	fmt.Printf("  %s %s #%d: %s%s%s\n",
 		status,
 		priorityIcon,
 		task.ID,
 		task.Description,
-		dueDateStr)
+		dueDateStr,
+		tagsStr)
 }
 
-func displaySmartView(store *taskdata.TaskStore) {
-	fmt.Println("🧠 Smart Task View")
-	fmt.Println(strings.Repeat("=", 50))
+// smartSection is one group of tasks in the smart view
+type smartSection struct {
+	Icon  string          `json:"icon"`
+	Title string          `json:"title"`
+	Tasks []taskdata.Task `json:"tasks"`
+}
 
-	now := time.Now()
+// smartView is the content of 'todo list --smart', shared with the web UI
+type smartView struct {
+	Sections        []smartSection `json:"sections"`
+	Recommendations []string       `json:"recommendations"`
+}
 
-	// Critical tasks (overdue + high priority)
-	criticalTasks := getCriticalTasks(store.Tasks, now)
-	if len(criticalTasks) > 0 {
-		fmt.Printf("\n🚨 Critical Tasks (%d)\n", len(criticalTasks))
-		fmt.Println(strings.Repeat("-", 30))
-		for _, task := range criticalTasks {
-			displayTask(task)
+// buildSmartView groups the tasks that need attention; empty groups are left out
+func buildSmartView(store *taskdata.TaskStore, now time.Time) smartView {
+	var view smartView
+	addSection := func(icon, title string, tasks []taskdata.Task) {
+		if len(tasks) > 0 {
+			view.Sections = append(view.Sections, smartSection{Icon: icon, Title: title, Tasks: tasks})
 		}
 	}
 
+	// Critical tasks (overdue + high priority)
+	addSection("🚨", "Critical Tasks", getCriticalTasks(store.Tasks, now))
+
 	// Today's focus
-	todayTasks := getTodayTasks(store.Tasks, now)
-	if len(todayTasks) > 0 {
-		fmt.Printf("\n🎯 Today's Focus (%d)\n", len(todayTasks))
-		fmt.Println(strings.Repeat("-", 30))
-		for _, task := range todayTasks {
-			displayTask(task)
-		}
-	}
+	addSection("🎯", "Today's Focus", getTodayTasks(store.Tasks, now))
 
 	// Due soon
-	dueSoonTasks := getDueSoonTasks(store.Tasks, now)
-	if len(dueSoonTasks) > 0 {
-		fmt.Printf("\n⏰ Due Soon (Next 3 Days) (%d)\n", len(dueSoonTasks))
-		fmt.Println(strings.Repeat("-", 30))
-		for _, task := range dueSoonTasks {
-			displayTask(task)
-		}
-	}
+	addSection("⏰", fmt.Sprintf("Due Soon (Next %d Days)", appConfig.DueSoonDays), getDueSoonTasks(store.Tasks, now))
 
 	// Quick wins (low priority, easy tasks)
 	quickWins := getQuickWins(store.Tasks)
-	if len(quickWins) > 0 && len(quickWins) <= 3 {
-		fmt.Printf("\n⚡ Quick Wins (%d)\n", len(quickWins))
+	if len(quickWins) <= appConfig.Smart.SuggestionsPerList {
+		addSection("⚡", "Quick Wins", quickWins)
+	}
+
+	view.Recommendations = getSmartRecommendations(store, now)
+	return view
+}
+
+func displaySmartView(store *taskdata.TaskStore) {
+	store = tasksForCurrentUser(store)
+	fmt.Println("🧠 Smart Task View")
+	fmt.Println(strings.Repeat("=", 50))
+
+	view := buildSmartView(store, time.Now())
+	for _, section := range view.Sections {
+		fmt.Printf("\n%s %s (%d)\n", section.Icon, section.Title, len(section.Tasks))
 		fmt.Println(strings.Repeat("-", 30))
-		for _, task := range quickWins {
+		for _, task := range section.Tasks {
 			displayTask(task)
 		}
 	}
 
 	// Show recommendations
-	showSmartRecommendations(store, now)
+	fmt.Printf("\n💡 Smart Recommendations\n")
+	fmt.Println(strings.Repeat("-", 30))
+	for _, recommendation := range view.Recommendations {
+		fmt.Printf("• %s\n", recommendation)
+	}
 }
 
 func getCriticalTasks(tasks []taskdata.Task, now time.Time) []taskdata.Task {
 	var critical []taskdata.Task
 	for _, task := range tasks {
-		if !task.Completed && (isOverdue(task, now) || (task.Priority == "high" && isDueSoon(task, now))) {
+		if !task.Completed && (isOverdue(task, now) || (isHighPriority(task) && isDueSoon(task, now))) {
 			critical = append(critical, task)
 		}
 	}
@@ -471,36 +531,42 @@ func getDueSoonTasks(tasks []taskdata.Task, now time.Time) []taskdata.Task {
 func getQuickWins(tasks []taskdata.Task) []taskdata.Task {
 	var quickWins []taskdata.Task
 	for _, task := range tasks {
-		if !task.Completed && task.Priority == "low" && task.DueDate == "" {
+		if !task.Completed && isLowPriority(task) && task.DueDate == "" {
 			quickWins = append(quickWins, task)
 		}
 	}
 	return quickWins
 }
 
-func showSmartRecommendations(store *taskdata.TaskStore, now time.Time) {
-	fmt.Printf("\n💡 Smart Recommendations\n")
-	fmt.Println(strings.Repeat("-", 30))
+func getSmartRecommendations(store *taskdata.TaskStore, now time.Time) []string {
+	var recommendations []string
 
 	overdueTasks := getOverdueTasksWithTime(store.Tasks, now)
 	if len(overdueTasks) > 0 {
-		fmt.Printf("• You have %d overdue task(s). Consider rescheduling or completing them.\n", len(overdueTasks))
+		recommendations = append(recommendations,
+			fmt.Sprintf("You have %d overdue task(s). Consider rescheduling or completing them.", len(overdueTasks)))
 	}
 
 	noDateTasks := getNoDateTasks(store.Tasks)
-	if len(noDateTasks) > 5 {
-		fmt.Printf("• You have %d tasks without due dates. Consider adding dates for better planning.\n", len(noDateTasks))
+	if len(noDateTasks) > appConfig.Smart.NoDateLimit {
+		recommendations = append(recommendations,
+			fmt.Sprintf("You have %d tasks without due dates. Consider adding dates for better planning.", len(noDateTasks)))
 	}
 
 	highPriorityCount := getHighPriorityPendingCount(store.Tasks)
-	if highPriorityCount > 3 {
-		fmt.Printf("• You have %d high-priority tasks. Consider focusing on top 3 first.\n", highPriorityCount)
+	if highPriorityCount > appConfig.Smart.HighPriorityLimit {
+		recommendations = append(recommendations,
+			fmt.Sprintf("You have %d high-priority tasks. Consider focusing on top %d first.",
+				highPriorityCount, appConfig.Smart.HighPriorityLimit))
 	}
 
 	completedToday := getCompletedTodayCount(store.Tasks, now)
 	if completedToday > 0 {
-		fmt.Printf("• Great job! You've completed %d task(s) today! 🎉\n", completedToday)
+		recommendations = append(recommendations,
+			fmt.Sprintf("Great job! You've completed %d task(s) today! 🎉", completedToday))
 	}
+
+	return recommendations
 }
 
 func getOverdueTasksWithTime(tasks []taskdata.Task, now time.Time) []taskdata.Task {
@@ -526,7 +592,7 @@ func getNoDateTasks(tasks []taskdata.Task) []taskdata.Task {
 func getHighPriorityPendingCount(tasks []taskdata.Task) int {
 	count := 0
 	for _, task := range tasks {
-		if !task.Completed && task.Priority == "high" {
+		if !task.Completed && isHighPriority(task) {
 			count++
 		}
 	}
@@ -539,60 +605,102 @@ func getCompletedTodayCount(tasks []taskdata.Task, now time.Time) int {
 	return 0
 }
 
-func displayInsights(store *taskdata.TaskStore) {
-	fmt.Println("📊 Task Insights")
-	fmt.Println(strings.Repeat("=", 50))
+// priorityCount is the number of pending tasks at one priority level
+type priorityCount struct {
+	Priority string `json:"priority"`
+	Count    int    `json:"count"`
+}
 
-	now := time.Now()
+// taskStatistics is the content of 'todo list --insights' and '--stats',
+// shared with the web UI
+type taskStatistics struct {
+	Total        int             `json:"total"`
+	Completed    int             `json:"completed"`
+	Pending      int             `json:"pending"`
+	Overdue      int             `json:"overdue"`
+	DueSoon      int             `json:"due_soon"`
+	NoDueDate    int             `json:"no_due_date"`
+	Priorities   []priorityCount `json:"priorities"` // Most important first
+	DueToday     int             `json:"due_today"`
+	DueThisWeek  int             `json:"due_this_week"`
+	DueThisMonth int             `json:"due_this_month"`
+}
 
-	// Task breakdown
-	total := len(store.Tasks)
-	completed := 0
-	pending := 0
-	overdue := 0
-	dueSoon := 0
-	noDate := 0
+func buildStatistics(store *taskdata.TaskStore, now time.Time) taskStatistics {
+	stats := taskStatistics{Total: len(store.Tasks)}
 
+	// Task breakdown
 	for _, task := range store.Tasks {
 		if task.Completed {
-			completed++
+			stats.Completed++
 		} else {
-			pending++
+			stats.Pending++
 			if isOverdue(task, now) {
-				overdue++
+				stats.Overdue++
 			} else if isDueSoon(task, now) {
-				dueSoon++
+				stats.DueSoon++
 			}
 			if task.DueDate == "" {
-				noDate++
+				stats.NoDueDate++
+			}
+		}
+	}
+
+	breakdown := getPriorityBreakdown(store.Tasks)
+	for _, level := range taskdata.Priorities() {
+		stats.Priorities = append(stats.Priorities, priorityCount{Priority: level, Count: breakdown[level]})
+	}
+
+	// Time-based analysis
+	for _, task := range store.Tasks {
+		if !task.Completed && task.DueDate != "" {
+			dueDate, err := time.Parse("2006-01-02", task.DueDate)
+			if err == nil {
+				if isSameDay(dueDate, now) {
+					stats.DueToday++
+				}
+				if isInWeekRange(dueDate, now) {
+					stats.DueThisWeek++
+				}
+				if isSameMonth(dueDate, now) {
+					stats.DueThisMonth++
+				}
 			}
 		}
 	}
 
+	return stats
+}
+
+func displayInsights(store *taskdata.TaskStore) {
+	fmt.Println("📊 Task Insights")
+	fmt.Println(strings.Repeat("=", 50))
+
+	stats := buildStatistics(store, time.Now())
+
 	// Display stats
 	fmt.Printf("\n📈 Task Overview\n")
 	fmt.Println(strings.Repeat("-", 30))
-	fmt.Printf("Total Tasks: %d\n", total)
-	fmt.Printf("Completed: %d (%.1f%%)\n", completed, float64(completed)/float64(total)*100)
-	fmt.Printf("Pending: %d (%.1f%%)\n", pending, float64(pending)/float64(total)*100)
+	fmt.Printf("Total Tasks: %d\n", stats.Total)
+	fmt.Printf("Completed: %d (%.1f%%)\n", stats.Completed, float64(stats.Completed)/float64(stats.Total)*100)
+	fmt.Printf("Pending: %d (%.1f%%)\n", stats.Pending, float64(stats.Pending)/float64(stats.Total)*100)
 
-	if overdue > 0 {
-		fmt.Printf("⚠️  Overdue: %d\n", overdue)
+	if stats.Overdue > 0 {
+		fmt.Printf("⚠️  Overdue: %d\n", stats.Overdue)
 	}
-	if dueSoon > 0 {
-		fmt.Printf("⏰ Due Soon: %d\n", dueSoon)
+	if stats.DueSoon > 0 {
+		fmt.Printf("⏰ Due Soon: %d\n", stats.DueSoon)
 	}
-	if noDate > 0 {
-		fmt.Printf("📝 No Due Date: %d\n", noDate)
+	if stats.NoDueDate > 0 {
+		fmt.Printf("📝 No Due Date: %d\n", stats.NoDueDate)
 	}
 
 	// Priority breakdown
 	fmt.Printf("\n🎯 Priority Breakdown\n")
 	fmt.Println(strings.Repeat("-", 30))
-	highCount, normalCount, lowCount := getPriorityBreakdown(store.Tasks)
-	fmt.Printf("🔴 High: %d\n", highCount)
-	fmt.Printf("🟡 Normal: %d\n", normalCount)
-	fmt.Printf("🟢 Low: %d\n", lowCount)
+	for _, level := range stats.Priorities {
+		fmt.Printf("%s %s: %d\n", priorityIcon(level.Priority), capitalize(level.Priority), level.Count)
+	}
 }
 
 func displayStatistics(store *taskdata.TaskStore) {
@@ -605,48 +713,23 @@ func displayStatistics(store *taskdata.TaskStore) {
 	fmt.Printf("\n📅 Time-based Analysis\n")
 	fmt.Println(strings.Repeat("-", 30))
 
-	now := time.Now()
-	todayCount := 0
-	thisWeekCount := 0
-	thisMonthCount := 0
-
-	for _, task := range store.Tasks {
-		if !task.Completed && task.DueDate != "" {
-			dueDate, err := time.Parse("2006-01-02", task.DueDate)
-			if err == nil {
-				if isSameDay(dueDate, now) {
-					todayCount++
-				}
-				if isInWeekRange(dueDate, now) {
-					thisWeekCount++
-				}
-				if isSameMonth(dueDate, now) {
-					thisMonthCount++
-				}
-			}
-		}
-	}
-
-	fmt.Printf("Due Today: %d\n", todayCount)
-	fmt.Printf("Due This Week: %d\n", thisWeekCount)
-	fmt.Printf("Due This Month: %d\n", thisMonthCount)
+	stats := buildStatistics(store, time.Now())
+	fmt.Printf("Due Today: %d\n", stats.DueToday)
+	fmt.Printf("Due This Week: %d\n", stats.DueThisWeek)
+	fmt.Printf("Due This Month: %d\n", stats.DueThisMonth)
 }
 
-func getPriorityBreakdown(tasks []taskdata.Task) (int, int, int) {
-	high, normal, low := 0, 0, 0
+// getPriorityBreakdown counts pending tasks per configured priority level
+func getPriorityBreakdown(tasks []taskdata.Task) map[string]int {
+	breakdown := make(map[string]int)
 	for _, task := range tasks {
 		if !task.Completed {
-			switch task.Priority {
-			case "high":
-				high++
-			case "normal":
-				normal++
-			case "low":
-				low++
+			if level, err := taskdata.NormalizePriority(task.Priority); err == nil {
+				breakdown[level]++
 			}
 		}
 	}
-	return high, normal, low
+	return breakdown
 }
 
 func showQuickInsights(store *taskdata.TaskStore, filteredTasks []taskdata.Task) {
@@ -690,7 +773,7 @@ func init() {
 	listCmd.Flags().BoolP("all", "a", false, "Show all tasks")
 
 	// Priority filter flag
-	listCmd.Flags().StringP("priority", "p", "", "Filter by priority (low/l, normal/n, high/h)")
+	listCmd.Flags().StringP("priority", "p", "", "Filter by priority (low/l, normal/n, high/h, or priorities.levels)")
 
 	// Completion status flags
 	listCmd.Flags().Bool("completed", false, "Show only completed tasks")
@@ -698,11 +781,24 @@ func init() {
 
 	// Smart filter flags
 	listCmd.Flags().Bool("overdue", false, "Show only overdue tasks")
-	listCmd.Flags().Bool("due-soon", false, "Show tasks due in next 3 days")
+	listCmd.Flags().Bool("due-soon", false, "Show tasks due soon (dates.due_soon_days, default 3)")
 	listCmd.Flags().Bool("no-date", false, "Show tasks without due dates")
 
 	// Analysis flags
 	listCmd.Flags().BoolP("insights", "i", false, "Show productivity insights")
 	listCmd.Flags().BoolP("smart", "s", false, "Smart view with recommendations")
 	listCmd.Flags().Bool("stats", false, "Show detailed statistics")
+	listCmd.Flags().Bool("mine", false, "Show only tasks assigned to you (see the user setting)")
+	listCmd.Flags().String("assignee", "", "Show only tasks assigned to someone ('none' for unassigned)")
+	listCmd.Flags().BoolVar(&showEveryone, "everyone", false, "Include tasks assigned to others in --smart")
+
+	listCmd.ValidArgsFunction = completeFirstArg(func(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+		reports, directive := completeReportNames(cmd, args, toComplete)
+		for i := range reports {
+			reports[i] = "@" + reports[i]
+		}
+		return reports, directive
+	})
+	listCmd.RegisterFlagCompletionFunc("priority", completePriorities)
+	listCmd.RegisterFlagCompletionFunc("assignee", completeAssignees)
 }
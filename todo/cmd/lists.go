@@ -0,0 +1,231 @@
+/*
+Copyright © 2025 Smart Todo CLI
+*/
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+	"todo/config"
+	"todo/taskdata"
+
+	"github.com/spf13/cobra"
+)
+
+// listCreateCmd represents the list-create command
+var listCreateCmd = &cobra.Command{
+	Use:   "list-create <name>",
+	Short: "Create a named task list",
+	Long: `Create a separate task list, for example for work, home or an on-call
+rotation. Every list has its own tasks and IDs.
+
+Examples:
+  todo list-create work    # Create the "work" list
+  todo use work            # Make it the current list
+  todo add "Deploy" --list oncall   # Use another list for one command`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		name := args[0]
+		if err := taskdata.CreateList(name); err != nil {
+			fmt.Printf("❌ %v\n", err)
+			return
+		}
+		fmt.Printf("✅ Created list '%s'\n", name)
+		fmt.Printf("💡 Switch to it with 'todo use %s' or use it once with --list %s\n", name, name)
+	},
+}
+
+// useCmd represents the use command
+var useCmd = &cobra.Command{
+	Use:   "use [name]",
+	Short: "Switch the current task list",
+	Long: `Make a task list the current one for all following commands. The choice
+is saved as current_list in the config file; --list overrides it for a
+single command. Without a name, show the current list.
+
+Examples:
+  todo use work       # Switch to the "work" list
+  todo use default    # Back to the default list
+  todo use            # Show the current list`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) == 0 {
+			fmt.Printf("📋 Current list: %s\n", taskdata.CurrentList())
+			return
+		}
+
+		name := args[0]
+		if err := taskdata.ValidateListName(name); err != nil {
+			fmt.Printf("❌ %v\n", err)
+			return
+		}
+		if !taskdata.ListExists(name) {
+			fmt.Printf("❌ List '%s' does not exist. Create it with 'todo list-create %s'\n", name, name)
+			return
+		}
+		if err := config.Set("current_list", name); err != nil {
+			fmt.Printf("❌ %v\n", err)
+			return
+		}
+		fmt.Printf("📋 Now using list '%s'\n", name)
+	},
+}
+
+// listsCmd represents the lists command
+var listsCmd = &cobra.Command{
+	Use:   "lists",
+	Short: "Show all task lists with their task counts",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		names, err := taskdata.ListNames()
+		if err != nil {
+			fmt.Printf("❌ %v\n", err)
+			return
+		}
+
+		fmt.Println("📋 Task Lists")
+		fmt.Println("==================================================")
+		now := time.Now()
+		for _, name := range names {
+			store, err := taskdata.LoadList(name)
+			if err != nil {
+				fmt.Printf("  ❌ %s: %v\n", name, err)
+				continue
+			}
+
+			var pending, completed, overdue int
+			for _, task := range store.Tasks {
+				switch {
+				case task.Completed:
+					completed++
+				default:
+					pending++
+					if isOverdue(task, now) {
+						overdue++
+					}
+				}
+			}
+
+			marker := "  "
+			if name == taskdata.CurrentList() {
+				marker = "▶ "
+			}
+			fmt.Printf("%s%-16s %3d pending, %3d completed", marker, name, pending, completed)
+			if overdue > 0 {
+				fmt.Printf(", 🔥 %d overdue", overdue)
+			}
+			fmt.Println()
+		}
+	},
+}
+
+// moveCmd represents the move command
+var moveCmd = &cobra.Command{
+	Use:   "move <task_id_or_name>... --to <list>",
+	Short: "Move tasks to another task list",
+	Long: `Move tasks from the current list (or --list) to another list. Moved tasks
+get the next free IDs in the target list and keep their UID, notes, tags
+and timestamps. Dependencies between lists are not kept.
+
+Examples:
+  todo move 5 --to home           # Move task #5 to the "home" list
+  todo move 3 4 --to work         # Move several tasks
+  todo move 2 --list work --to default`,
+	Args: cobra.MinimumNArgs(1),
+	Run:  moveRun,
+}
+
+func moveRun(cmd *cobra.Command, args []string) {
+	target, _ := cmd.Flags().GetString("to")
+	if err := taskdata.ValidateListName(target); err != nil {
+		fmt.Printf("❌ %v\n", err)
+		return
+	}
+	if !taskdata.ListExists(target) {
+		fmt.Printf("❌ List '%s' does not exist. Create it with 'todo list-create %s'\n", target, target)
+		return
+	}
+	if target == taskdata.CurrentList() {
+		fmt.Printf("❌ The tasks are already in '%s'\n", target)
+		return
+	}
+
+	// Resolve the tasks first: resolving may ask which task was meant, and
+	// no list may stay locked while waiting for an answer
+	current, err := taskdata.LoadTasks()
+	if err != nil {
+		fmt.Printf("Error loading tasks: %v\n", err)
+		return
+	}
+	var uids []string
+	for _, identifier := range args {
+		task := resolveTask(current, identifier)
+		if task == nil {
+			return
+		}
+		if !slices.Contains(uids, task.UID) {
+			uids = append(uids, task.UID)
+		}
+	}
+
+	var moved []string
+	err = taskdata.UpdateListPair(taskdata.CurrentList(), target, func(store, dest *taskdata.TaskStore) error {
+		var moving []taskdata.Task
+		for _, uid := range uids {
+			task := store.FindByUID(uid)
+			if task == nil {
+				return errors.New("a task was deleted by another process; nothing was moved")
+			}
+			moving = append(moving, *task)
+		}
+
+		for _, task := range moving {
+			if dest.FindByUID(task.UID) != nil {
+				return fmt.Errorf("task #%d is already in '%s'", task.ID, target)
+			}
+			task.DependsOn = nil
+			imported, err := dest.ImportTask(task)
+			if err != nil {
+				return fmt.Errorf("task #%d: %v", task.ID, err)
+			}
+			moved = append(moved, fmt.Sprintf("📦 #%d → %s #%d: %s", task.ID, target, imported.ID, task.Description))
+		}
+
+		// Drop the moved tasks, and dependencies on them
+		movedIDs := map[int]bool{}
+		for _, task := range moving {
+			movedIDs[task.ID] = true
+		}
+		store.Tasks = slices.DeleteFunc(store.Tasks, func(t taskdata.Task) bool { return movedIDs[t.ID] })
+		for i := range store.Tasks {
+			store.Tasks[i].DependsOn = slices.DeleteFunc(store.Tasks[i].DependsOn, func(id int) bool { return movedIDs[id] })
+		}
+		return nil
+	})
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		return
+	}
+	for _, line := range moved {
+		fmt.Println(line)
+	}
+	fmt.Printf("✅ Moved %d task(s) from '%s' to '%s'\n", len(moved), taskdata.CurrentList(), target)
+}
+
+func init() {
+	rootCmd.AddCommand(listCreateCmd)
+	rootCmd.AddCommand(useCmd)
+	rootCmd.AddCommand(listsCmd)
+	rootCmd.AddCommand(moveCmd)
+
+	moveCmd.Flags().String("to", "", "List to move the tasks to")
+	moveCmd.MarkFlagRequired("to")
+
+	useCmd.ValidArgsFunction = completeFirstArg(completeListNames)
+	moveCmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+		return taskCompletions(func(task taskdata.Task) bool { return !task.Completed }), cobra.ShellCompDirectiveNoFileComp
+	}
+	moveCmd.RegisterFlagCompletionFunc("to", completeListNames)
+}
@@ -5,8 +5,11 @@ package cmd
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
 	"os"
+	"slices"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -33,13 +36,20 @@ Examples:
   todo mark                      # Smart suggestions for completion
   todo mark 5                    # Mark task #5 as done
   todo mark 5 --undone           # Mark task #5 as not done
-  todo mark "buy groceries"      # Mark task by name as done
+  todo mark "buy groceries"      # Mark task by name as done (fuzzy, asks when ambiguous)
   todo mark --overdue            # Show overdue tasks for action
   todo mark --smart              # Smart-powered task analysis
   todo mark 5 --edit             # Edit task #5 properties
   todo mark 5 --due "2025-07-20" # Change due date
+  todo mark 5 --due friday       # Or a relative date: today, tomorrow, +3d...
   todo mark 5 --priority high    # Change priority
   todo mark 5 --desc "New desc"  # Change description
+  todo mark 5 --note "Call Bob"  # Change notes
+  todo mark 5 --wait 2025-08-01  # Defer until a date (see 'todo report waiting')
+  todo mark 5 --tag work,-home   # Add tag "work", remove tag "home"
+  todo mark 5 --project work     # Move task #5 to a project ('none' to clear)
+  todo mark 5 --depends 3        # Task #5 can't start before #3 is done
+  todo mark 5 --assign bob       # Reassign task #5 (see 'todo history 5')
   todo mark --batch              # Batch mark multiple tasks
   todo mark --cleanup            # Mark and suggest cleanup`,
 	Run: markRun,
@@ -68,6 +78,13 @@ func markRun(cmd *cobra.Command, args []string) {
 	newDue, _ := cmd.Flags().GetString("due")
 	newPriority, _ := cmd.Flags().GetString("priority")
 	newDesc, _ := cmd.Flags().GetString("desc")
+	newNote, _ := cmd.Flags().GetString("note")
+	newWait, _ := cmd.Flags().GetString("wait")
+	newTime, _ := cmd.Flags().GetString("time")
+	tagChanges, _ := cmd.Flags().GetStringSlice("tag")
+	dependsChanges, _ := cmd.Flags().GetIntSlice("depends")
+	newAssignee, _ := cmd.Flags().GetString("assign")
+	newProject, _ := cmd.Flags().GetString("project")
 	force, _ := cmd.Flags().GetBool("force")
 
 	// Smart mode - smart-powered analysis
@@ -104,8 +121,20 @@ func markRun(cmd *cobra.Command, args []string) {
 	identifier := args[0]
 
 	// Check if we're editing properties
-	if editMode || newDue != "" || newPriority != "" || newDesc != "" {
-		editTaskProperties(store, identifier, newDue, newPriority, newDesc)
+	edits := taskEdits{
+		due:      newDue,
+		priority: newPriority,
+		desc:     newDesc,
+		note:     newNote,
+		wait:     newWait,
+		dueTime:  newTime,
+		tags:     tagChanges,
+		depends:  dependsChanges,
+		assign:   newAssignee,
+		project:  newProject,
+	}
+	if editMode || edits.any() {
+		editTaskProperties(store, identifier, edits)
 		return
 	}
 
@@ -146,7 +175,7 @@ func analyzeTaskPatterns(store *taskdata.TaskStore, now time.Time) {
 		if task.Completed {
 			completedTasks++
 		} else {
-			if task.Priority == "high" {
+			if isHighPriority(task) {
 				highPriorityPending++
 			}
 			if isTaskOverdue(task, now) {
@@ -186,13 +215,16 @@ func showProductivityInsights(store *taskdata.TaskStore, now time.Time) {
 
 	// Analyze task distribution
 	priorityDist := analyzePriorityDistribution(store)
-	fmt.Printf("Priority Distribution: High:%d, Normal:%d, Low:%d\n",
-		priorityDist["high"], priorityDist["normal"], priorityDist["low"])
+	var parts []string
+	for _, level := range taskdata.Priorities() {
+		parts = append(parts, fmt.Sprintf("%s:%d", capitalize(level), priorityDist[level]))
+	}
+	fmt.Printf("Priority Distribution: %s\n", strings.Join(parts, ", "))
 
 	// Time-based insights
 	upcomingDeadlines := getUpcomingDeadlines(store, now)
 	if len(upcomingDeadlines) > 0 {
-		fmt.Printf("📅 Upcoming Deadlines (%d tasks in next 7 days)\n", len(upcomingDeadlines))
+		fmt.Printf("📅 Upcoming Deadlines (%d tasks in next %d days)\n", len(upcomingDeadlines), appConfig.Smart.UpcomingDays)
 	}
 
 	// Suggest optimal focus
@@ -207,7 +239,7 @@ func showCompletionRecommendations(store *taskdata.TaskStore, now time.Time) {
 	quickWins := getQuickWinTasks(store)
 	if len(quickWins) > 0 {
 		fmt.Printf("⚡ Quick Wins (%d tasks):\n", len(quickWins))
-		for _, task := range quickWins[:min(3, len(quickWins))] {
+		for _, task := range quickWins[:min(appConfig.Smart.SuggestionsPerList, len(quickWins))] {
 			fmt.Printf("  • #%d: %s\n", task.ID, task.Description)
 		}
 	}
@@ -216,7 +248,7 @@ func showCompletionRecommendations(store *taskdata.TaskStore, now time.Time) {
 	highImpact := getHighImpactTasks(store, now)
 	if len(highImpact) > 0 {
 		fmt.Printf("🎯 High Impact (%d tasks):\n", len(highImpact))
-		for _, task := range highImpact[:min(3, len(highImpact))] {
+		for _, task := range highImpact[:min(appConfig.Smart.SuggestionsPerList, len(highImpact))] {
 			fmt.Printf("  • #%d: %s\n", task.ID, task.Description)
 		}
 	}
@@ -225,7 +257,7 @@ func showCompletionRecommendations(store *taskdata.TaskStore, now time.Time) {
 	overdue := getOverdueTasksForRecovery(store, now)
 	if len(overdue) > 0 {
 		fmt.Printf("🚨 Overdue Recovery (%d tasks):\n", len(overdue))
-		for _, task := range overdue[:min(3, len(overdue))] {
+		for _, task := range overdue[:min(appConfig.Smart.SuggestionsPerList, len(overdue))] {
 			fmt.Printf("  • #%d: %s (due %s)\n", task.ID, task.Description, task.DueDate)
 		}
 	}
@@ -355,7 +387,7 @@ func showMarkSuggestions(store *taskdata.TaskStore) {
 	highPriorityTasks := getHighPriorityPendingTasks(store)
 	if len(highPriorityTasks) > 0 {
 		fmt.Printf("\n🔴 High Priority (%d tasks):\n", len(highPriorityTasks))
-		for _, task := range highPriorityTasks[:min(3, len(highPriorityTasks))] {
+		for _, task := range highPriorityTasks[:min(appConfig.Smart.SuggestionsPerList, len(highPriorityTasks))] {
 			displayTaskForMarking(task)
 		}
 	}
@@ -364,7 +396,7 @@ func showMarkSuggestions(store *taskdata.TaskStore) {
 	quickWins := getQuickWinTasks(store)
 	if len(quickWins) > 0 {
 		fmt.Printf("\n⚡ Quick Wins (%d tasks):\n", len(quickWins))
-		for _, task := range quickWins[:min(3, len(quickWins))] {
+		for _, task := range quickWins[:min(appConfig.Smart.SuggestionsPerList, len(quickWins))] {
 			displayTaskForMarking(task)
 		}
 	}
@@ -373,7 +405,7 @@ func showMarkSuggestions(store *taskdata.TaskStore) {
 	overdue := getOverdueTasksForRecovery(store, now)
 	if len(overdue) > 0 {
 		fmt.Printf("\n⚠️  Overdue (%d tasks):\n", len(overdue))
-		for _, task := range overdue[:min(3, len(overdue))] {
+		for _, task := range overdue[:min(appConfig.Smart.SuggestionsPerList, len(overdue))] {
 			displayTaskForMarking(task)
 		}
 	}
@@ -382,12 +414,31 @@ func showMarkSuggestions(store *taskdata.TaskStore) {
 	fmt.Printf("💡 Use 'todo mark --smart' for detailed analysis\n")
 }
 
-func editTaskProperties(store *taskdata.TaskStore, identifier, newDue, newPriority, newDesc string) {
-	task := findTaskByIDOrName(store, identifier)
+// taskEdits holds the property changes requested with mark's edit flags
+type taskEdits struct {
+	due      string
+	priority string
+	desc     string
+	note     string
+	wait     string
+	dueTime  string
+	tags     []string // "-tag" removes a tag
+	depends  []int    // negative IDs remove a dependency
+	assign   string   // "me" for the local user, "none" unassigns
+	project  string   // "none" clears the project
+}
+
+func (e taskEdits) any() bool {
+	return e.due != "" || e.priority != "" || e.desc != "" || e.note != "" || e.wait != "" ||
+		e.dueTime != "" || len(e.tags) > 0 || len(e.depends) > 0 || e.assign != "" || e.project != ""
+}
+
+func editTaskProperties(store *taskdata.TaskStore, identifier string, edits taskEdits) {
+	task := resolveTask(store, identifier)
 	if task == nil {
-		fmt.Printf("❌ Task not found: %s\n", identifier)
 		return
 	}
+	newDue, newPriority, newDesc, newNote, newWait := edits.due, edits.priority, edits.desc, edits.note, edits.wait
 
 	fmt.Printf("📝 Editing Task #%d: %s\n", task.ID, task.Description)
 	fmt.Println(strings.Repeat("=", 40))
@@ -397,23 +448,43 @@ func editTaskProperties(store *taskdata.TaskStore, identifier, newDue, newPriori
 
 	// Update due date
 	if newDue != "" {
-		if err := taskdata.ValidateDate(newDue); err != nil {
+		due, err := taskdata.ResolveDate(newDue, time.Now())
+		if err != nil {
 			fmt.Printf("❌ Invalid due date: %v\n", err)
 			return
 		}
+		newDue = due
 		changes["Due Date"] = fmt.Sprintf("%s → %s", task.DueDate, newDue)
 		updateTaskDueDate(store, task.ID, newDue)
 		updated = true
 	}
 
+	// Update due time ("none" clears it)
+	if edits.dueTime != "" {
+		dueTime := edits.dueTime
+		if dueTime == "none" {
+			dueTime = ""
+		} else if err := taskdata.ValidateTime(dueTime); err != nil {
+			fmt.Printf("❌ Invalid due time: %v\n", err)
+			return
+		} else if task.DueDate == "" && newDue == "" {
+			fmt.Println("❌ The task has no due date; set one with --due")
+			return
+		}
+		changes["Due Time"] = fmt.Sprintf("%s → %s", task.DueTime, dueTime)
+		updateTaskDueTime(store, task.ID, dueTime)
+		updated = true
+	}
+
 	// Update priority
 	if newPriority != "" {
-		if err := taskdata.ValidatePriority(newPriority); err != nil {
+		level, err := taskdata.NormalizePriority(newPriority)
+		if err != nil {
 			fmt.Printf("❌ Invalid priority: %v\n", err)
 			return
 		}
-		changes["Priority"] = fmt.Sprintf("%s → %s", task.Priority, strings.ToLower(newPriority))
-		updateTaskPriority(store, task.ID, strings.ToLower(newPriority))
+		changes["Priority"] = fmt.Sprintf("%s → %s", task.Priority, level)
+		updateTaskPriority(store, task.ID, level)
 		updated = true
 	}
 
@@ -424,9 +495,87 @@ func editTaskProperties(store *taskdata.TaskStore, identifier, newDue, newPriori
 		updated = true
 	}
 
+	// Update wait date ("none" clears it)
+	if newWait != "" {
+		if newWait == "none" {
+			newWait = ""
+		} else if wait, err := taskdata.ResolveDate(newWait, time.Now()); err != nil {
+			fmt.Printf("❌ Invalid wait date: %v\n", err)
+			return
+		} else {
+			newWait = wait
+		}
+		changes["Wait Until"] = fmt.Sprintf("%s → %s", task.WaitUntil, newWait)
+		updateTaskWaitUntil(store, task.ID, newWait)
+		updated = true
+	}
+
+	// Update notes
+	if newNote != "" {
+		changes["Notes"] = fmt.Sprintf("%s → %s", task.Notes, newNote)
+		updateTaskNotes(store, task.ID, newNote)
+		updated = true
+	}
+
+	// Update tags ("-tag" removes)
+	if len(edits.tags) > 0 {
+		tags := task.Tags
+		for _, change := range edits.tags {
+			if tag, remove := strings.CutPrefix(change, "-"); remove {
+				tags = removeTag(tags, tag)
+			} else {
+				tags = addTags(tags, change)
+			}
+		}
+		changes["Tags"] = fmt.Sprintf("%s → %s", formatTags(task.Tags), formatTags(tags))
+		updateTaskTags(store, task.ID, tags)
+		updated = true
+	}
+
+	// Update dependencies (negative IDs remove)
+	if len(edits.depends) > 0 {
+		depends := task.DependsOn
+		for _, change := range edits.depends {
+			if change < 0 {
+				depends = removeDependency(depends, -change)
+				continue
+			}
+			if err := validateDependency(store, task.ID, change); err != nil {
+				fmt.Printf("❌ Invalid dependency: %v\n", err)
+				return
+			}
+			depends = addDependency(depends, change)
+		}
+		changes["Depends On"] = fmt.Sprintf("%s → %s", formatDependencies(task.DependsOn), formatDependencies(depends))
+		updateTaskDependsOn(store, task.ID, depends)
+		updated = true
+	}
+
+	// Update project ("none" clears it)
+	if edits.project != "" {
+		project := strings.TrimSpace(edits.project)
+		if project == "none" {
+			project = ""
+		}
+		changes["Project"] = fmt.Sprintf("%s → %s", task.Project, project)
+		updateTaskProject(store, task.ID, project)
+		updated = true
+	}
+
+	// Reassign, recording the change in the task's history
+	if edits.assign != "" {
+		assignee := resolveAssignee(edits.assign)
+		if assignTask(store, task.ID, assignee) {
+			changes["Assignee"] = fmt.Sprintf("%s → %s", orNobody(task.Assignee), orNobody(assignee))
+			updated = true
+		}
+	}
+
 	if updated {
-		if err := store.SaveTasks(); err != nil {
-			fmt.Printf("❌ Error saving changes: %v\n", err)
+		if err := saveTasks(store); err != nil {
+			if !errors.Is(err, errVetoed) {
+				fmt.Printf("❌ Error saving changes: %v\n", err)
+			}
 			return
 		}
 
@@ -435,14 +584,13 @@ func editTaskProperties(store *taskdata.TaskStore, identifier, newDue, newPriori
 			fmt.Printf("  %s: %s\n", field, change)
 		}
 	} else {
-		fmt.Println("ℹ️  No changes specified. Use --due, --priority, or --desc flags to edit.")
+		fmt.Println("ℹ️  No changes specified. Use --due, --time, --priority, --desc, --note, --wait, --tag, --project, --depends or --assign flags to edit.")
 	}
 }
 
 func markTask(store *taskdata.TaskStore, identifier string, undone, force bool) {
-	task := findTaskByIDOrName(store, identifier)
+	task := resolveTask(store, identifier)
 	if task == nil {
-		fmt.Printf("❌ Task not found: %s\n", identifier)
 		return
 	}
 
@@ -452,7 +600,7 @@ func markTask(store *taskdata.TaskStore, identifier string, undone, force bool)
 	}
 
 	if !force {
-		if !confirmAction(fmt.Sprintf("%s task #%d: %s", strings.Title(action), task.ID, task.Description)) {
+		if !confirmAction(fmt.Sprintf("%s task #%d: %s", capitalize(action), task.ID, task.Description)) {
 			fmt.Println("Operation cancelled.")
 			return
 		}
@@ -464,8 +612,10 @@ func markTask(store *taskdata.TaskStore, identifier string, undone, force bool)
 		return
 	}
 
-	if err := store.SaveTasks(); err != nil {
-		fmt.Printf("❌ Error saving changes: %v\n", err)
+	if err := saveTasks(store); err != nil {
+		if !errors.Is(err, errVetoed) {
+			fmt.Printf("❌ Error saving changes: %v\n", err)
+		}
 		return
 	}
 
@@ -528,18 +678,12 @@ func calculateTaskHealthScore(total, completed, overdue, highPriorityPending int
 }
 
 func analyzePriorityDistribution(store *taskdata.TaskStore) map[string]int {
-	dist := map[string]int{"high": 0, "normal": 0, "low": 0}
-	for _, task := range store.Tasks {
-		if !task.Completed {
-			dist[task.Priority]++
-		}
-	}
-	return dist
+	return getPriorityBreakdown(store.Tasks)
 }
 
 func getUpcomingDeadlines(store *taskdata.TaskStore, now time.Time) []taskdata.Task {
 	var upcoming []taskdata.Task
-	nextWeek := now.AddDate(0, 0, 7)
+	nextWeek := now.AddDate(0, 0, appConfig.Smart.UpcomingDays)
 
 	for _, task := range store.Tasks {
 		if !task.Completed && task.DueDate != "" {
@@ -553,6 +697,7 @@ func getUpcomingDeadlines(store *taskdata.TaskStore, now time.Time) []taskdata.T
 }
 
 func suggestOptimalFocus(store *taskdata.TaskStore, now time.Time) {
+	store = tasksForCurrentUser(store)
 	overdue := getOverdueTasksForRecovery(store, now)
 	today := getTodayTasksForCompletion(store, now)
 	highPriority := getHighPriorityPendingTasks(store)
@@ -566,12 +711,30 @@ func suggestOptimalFocus(store *taskdata.TaskStore, now time.Time) {
 	} else {
 		fmt.Println("🎯 Focus: Great job! Consider picking up some quick wins")
 	}
+
+	// Point at the most urgent tasks overall
+	ranked := getTasksByUrgency(store, now)
+	if len(ranked) > 0 {
+		fmt.Println("🔥 Most urgent:")
+		for _, task := range ranked[:min(appConfig.Smart.SuggestionsPerList, len(ranked))] {
+			fmt.Printf("  • #%d: %s (urgency %.1f)\n", task.ID, task.Description, store.Urgency(task, now))
+		}
+	}
+}
+
+// getTasksByUrgency returns pending tasks ordered by urgency, most urgent first
+func getTasksByUrgency(store *taskdata.TaskStore, now time.Time) []taskdata.Task {
+	pending := getPendingTasks(store)
+	sort.SliceStable(pending, func(i, j int) bool {
+		return store.Urgency(pending[i], now) > store.Urgency(pending[j], now)
+	})
+	return pending
 }
 
 func getQuickWinTasks(store *taskdata.TaskStore) []taskdata.Task {
 	var quickWins []taskdata.Task
 	for _, task := range store.Tasks {
-		if !task.Completed && task.Priority == "low" && task.DueDate == "" {
+		if !task.Completed && isLowPriority(task) && task.DueDate == "" {
 			quickWins = append(quickWins, task)
 		}
 	}
@@ -581,7 +744,7 @@ func getQuickWinTasks(store *taskdata.TaskStore) []taskdata.Task {
 func getHighImpactTasks(store *taskdata.TaskStore, now time.Time) []taskdata.Task {
 	var highImpact []taskdata.Task
 	for _, task := range store.Tasks {
-		if !task.Completed && task.Priority == "high" {
+		if !task.Completed && isHighPriority(task) {
 			highImpact = append(highImpact, task)
 		}
 	}
@@ -611,7 +774,7 @@ func getOldCompletedTasksForCleanup(store *taskdata.TaskStore) []taskdata.Task {
 func getStaleTasksForReview(store *taskdata.TaskStore, now time.Time) []taskdata.Task {
 	var stale []taskdata.Task
 	for _, task := range store.Tasks {
-		if !task.Completed && task.DueDate == "" && task.Priority == "low" {
+		if !task.Completed && task.DueDate == "" && isLowPriority(task) {
 			stale = append(stale, task)
 		}
 	}
@@ -659,10 +822,10 @@ func handleOverdueTaskActions(store *taskdata.TaskStore, overdueTasks []taskdata
 			updateTaskCompletion(store, task.ID, true)
 			fmt.Printf("✅ Marked task #%d as completed\n", task.ID)
 		case "r", "reschedule":
-			fmt.Print("New due date (YYYY-MM-DD): ")
+			fmt.Print("New due date (YYYY-MM-DD, today, friday, +3d...): ")
 			newDate, _ := reader.ReadString('\n')
-			newDate = strings.TrimSpace(newDate)
-			if err := taskdata.ValidateDate(newDate); err == nil {
+			newDate, err := taskdata.ResolveDate(strings.TrimSpace(newDate), time.Now())
+			if err == nil && newDate != "" {
 				updateTaskDueDate(store, task.ID, newDate)
 				fmt.Printf("📅 Rescheduled task #%d to %s\n", task.ID, newDate)
 			} else {
@@ -684,7 +847,7 @@ func handleOverdueTaskActions(store *taskdata.TaskStore, overdueTasks []taskdata
 		}
 	}
 
-	store.SaveTasks()
+	saveTasks(store)
 }
 
 func getPendingTasks(store *taskdata.TaskStore) []taskdata.Task {
@@ -705,12 +868,17 @@ func markAllTasks(store *taskdata.TaskStore, tasks []taskdata.Task, undone bool)
 		}
 	}
 
-	store.SaveTasks()
+	if err := saveTasks(store); err != nil {
+		if !errors.Is(err, errVetoed) {
+			fmt.Printf("❌ Error saving changes: %v\n", err)
+		}
+		return
+	}
 	action := "completed"
 	if undone {
 		action = "marked as incomplete"
 	}
-	fmt.Printf("✅ %s %d task(s)\n", strings.Title(action), count)
+	fmt.Printf("✅ %s %d task(s)\n", capitalize(action), count)
 }
 
 func markSelectedTasks(store *taskdata.TaskStore, tasks []taskdata.Task, input string, undone bool) {
@@ -727,12 +895,17 @@ func markSelectedTasks(store *taskdata.TaskStore, tasks []taskdata.Task, input s
 		}
 	}
 
-	store.SaveTasks()
+	if err := saveTasks(store); err != nil {
+		if !errors.Is(err, errVetoed) {
+			fmt.Printf("❌ Error saving changes: %v\n", err)
+		}
+		return
+	}
 	action := "completed"
 	if undone {
 		action = "marked as incomplete"
 	}
-	fmt.Printf("✅ %s %d task(s)\n", strings.Title(action), count)
+	fmt.Printf("✅ %s %d task(s)\n", capitalize(action), count)
 }
 
 func autoMarkObviousCompletions(store *taskdata.TaskStore) {
@@ -754,7 +927,7 @@ func getTodayTasksForCompletion(store *taskdata.TaskStore, now time.Time) []task
 func getHighPriorityPendingTasks(store *taskdata.TaskStore) []taskdata.Task {
 	var highPriority []taskdata.Task
 	for _, task := range store.Tasks {
-		if !task.Completed && task.Priority == "high" {
+		if !task.Completed && isHighPriority(task) {
 			highPriority = append(highPriority, task)
 		}
 	}
@@ -762,69 +935,167 @@ func getHighPriorityPendingTasks(store *taskdata.TaskStore) []taskdata.Task {
 }
 
 func displayTaskForMarking(task taskdata.Task) {
-	priorityIcon := ""
-	switch task.Priority {
-	case "high":
-		priorityIcon = "🔴"
-	case "normal":
-		priorityIcon = "🟡"
-	case "low":
-		priorityIcon = "🟢"
-	}
+	priorityIcon := priorityIcon(task.Priority)
 
 	dueDateStr := ""
 	if task.DueDate != "" {
-		dueDateStr = fmt.Sprintf(" (due: %s)", task.DueDate)
+		dueDateStr = fmt.Sprintf(" (due: %s)", formatDate(task.DueDate))
 	}
 
 	fmt.Printf("  %s #%d: %s%s\n", priorityIcon, task.ID, task.Description, dueDateStr)
 }
 
-func findTaskByIDOrName(store *taskdata.TaskStore, identifier string) *taskdata.Task {
-	// Try to parse as ID first
-	if id, err := strconv.Atoi(identifier); err == nil {
-		for _, task := range store.Tasks {
-			if task.ID == id {
-				return &task
-			}
+func updateTaskDueDate(store *taskdata.TaskStore, id int, newDue string) error {
+	for i, task := range store.Tasks {
+		if task.ID == id {
+			store.Tasks[i].DueDate = newDue
+			return nil
 		}
 	}
+	return fmt.Errorf("task not found")
+}
 
-	// Search by name (partial match)
-	searchTerm := strings.ToLower(identifier)
-	for _, task := range store.Tasks {
-		if strings.Contains(strings.ToLower(task.Description), searchTerm) {
-			return &task
+func updateTaskDueTime(store *taskdata.TaskStore, id int, newTime string) error {
+	for i, task := range store.Tasks {
+		if task.ID == id {
+			store.Tasks[i].DueTime = newTime
+			return nil
+		}
+	}
+	return fmt.Errorf("task not found")
+}
+
+func updateTaskPriority(store *taskdata.TaskStore, id int, newPriority string) error {
+	for i, task := range store.Tasks {
+		if task.ID == id {
+			store.Tasks[i].Priority = newPriority
+			return nil
+		}
+	}
+	return fmt.Errorf("task not found")
+}
+
+func updateTaskDescription(store *taskdata.TaskStore, id int, newDesc string) error {
+	for i, task := range store.Tasks {
+		if task.ID == id {
+			store.Tasks[i].Description = newDesc
+			return nil
+		}
+	}
+	return fmt.Errorf("task not found")
+}
+
+func updateTaskProject(store *taskdata.TaskStore, id int, project string) error {
+	for i, task := range store.Tasks {
+		if task.ID == id {
+			store.Tasks[i].Project = project
+			return nil
+		}
+	}
+	return fmt.Errorf("task not found")
+}
+
+func updateTaskWaitUntil(store *taskdata.TaskStore, id int, newWait string) error {
+	for i, task := range store.Tasks {
+		if task.ID == id {
+			store.Tasks[i].WaitUntil = newWait
+			return nil
+		}
+	}
+	return fmt.Errorf("task not found")
+}
+
+// addTags adds comma-separated tags, normalized to lowercase without a leading +
+func addTags(tags []string, input string) []string {
+	for _, tag := range strings.Split(input, ",") {
+		tag = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(tag), "+"))
+		if tag == "" || slices.Contains(tags, tag) {
+			continue
+		}
+		tags = append(tags, tag)
+	}
+	return tags
+}
+
+func removeTag(tags []string, tag string) []string {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	return slices.DeleteFunc(slices.Clone(tags), func(t string) bool { return t == tag })
+}
+
+func addDependency(depends []int, id int) []int {
+	if slices.Contains(depends, id) {
+		return depends
+	}
+	return append(depends, id)
+}
+
+func removeDependency(depends []int, id int) []int {
+	return slices.DeleteFunc(slices.Clone(depends), func(d int) bool { return d == id })
+}
+
+// validateDependency checks that a dependency exists and would not create a cycle
+func validateDependency(store *taskdata.TaskStore, taskID, dependsOn int) error {
+	if taskID == dependsOn {
+		return fmt.Errorf("task #%d cannot depend on itself", taskID)
+	}
+
+	var target *taskdata.Task
+	for i := range store.Tasks {
+		if store.Tasks[i].ID == dependsOn {
+			target = &store.Tasks[i]
+		}
+	}
+	if target == nil {
+		return fmt.Errorf("task #%d not found", dependsOn)
+	}
+
+	// Walk the dependency chain of the target looking for taskID
+	visited := map[int]bool{}
+	queue := append([]int(nil), target.DependsOn...)
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		if id == taskID {
+			return fmt.Errorf("task #%d already depends on #%d (circular dependency)", dependsOn, taskID)
+		}
+		if visited[id] {
+			continue
+		}
+		visited[id] = true
+		for _, task := range store.Tasks {
+			if task.ID == id {
+				queue = append(queue, task.DependsOn...)
+			}
 		}
 	}
 
 	return nil
 }
 
-func updateTaskDueDate(store *taskdata.TaskStore, id int, newDue string) error {
+func updateTaskTags(store *taskdata.TaskStore, id int, tags []string) error {
 	for i, task := range store.Tasks {
 		if task.ID == id {
-			store.Tasks[i].DueDate = newDue
+			store.Tasks[i].Tags = tags
 			return nil
 		}
 	}
 	return fmt.Errorf("task not found")
 }
 
-func updateTaskPriority(store *taskdata.TaskStore, id int, newPriority string) error {
+func updateTaskDependsOn(store *taskdata.TaskStore, id int, depends []int) error {
 	for i, task := range store.Tasks {
 		if task.ID == id {
-			store.Tasks[i].Priority = newPriority
+			store.Tasks[i].DependsOn = depends
 			return nil
 		}
 	}
 	return fmt.Errorf("task not found")
 }
 
-func updateTaskDescription(store *taskdata.TaskStore, id int, newDesc string) error {
+func updateTaskNotes(store *taskdata.TaskStore, id int, newNotes string) error {
 	for i, task := range store.Tasks {
 		if task.ID == id {
-			store.Tasks[i].Description = newDesc
+			store.Tasks[i].Notes = newNotes
 			return nil
 		}
 	}
@@ -835,6 +1106,10 @@ func updateTaskCompletion(store *taskdata.TaskStore, id int, completed bool) err
 	for i, task := range store.Tasks {
 		if task.ID == id {
 			store.Tasks[i].Completed = completed
+			store.Tasks[i].CompletedAt = ""
+			if completed {
+				store.Tasks[i].CompletedAt = time.Now().Format(taskdata.TimestampFormat)
+			}
 			return nil
 		}
 	}
@@ -871,7 +1146,7 @@ func showPostCompletionSuggestions(store *taskdata.TaskStore, completedTask *tas
 			}
 		}
 
-		if completed > 5 {
+		if completed > appConfig.Smart.CompletedCleanup {
 			fmt.Printf("   🧹 Consider running 'todo delete --completed' to clean up\n")
 		}
 	}
@@ -892,7 +1167,24 @@ func init() {
 
 	// Edit flags
 	markCmd.Flags().BoolP("edit", "e", false, "Edit task properties")
-	markCmd.Flags().String("due", "", "Change due date (YYYY-MM-DD)")
-	markCmd.Flags().StringP("priority", "p", "", "Change priority (low, normal, high)")
+	markCmd.Flags().String("due", "", "Change due date (YYYY-MM-DD, or today, tomorrow, friday, +3d...)")
+	markCmd.Flags().String("time", "", "Change due time (HH:MM, or 'none' to clear)")
+	markCmd.Flags().StringP("priority", "p", "", "Change priority (low, normal, high, or priorities.levels)")
 	markCmd.Flags().StringP("desc", "d", "", "Change task description")
+	markCmd.Flags().StringP("note", "n", "", "Change task notes")
+	markCmd.Flags().String("wait", "", "Defer task until a date (YYYY-MM-DD, today, friday, +3d..., or 'none' to clear)")
+	markCmd.Flags().StringSliceP("tag", "t", nil, "Add tags (prefix with - to remove, e.g. -work)")
+	markCmd.Flags().String("project", "", "Change the project, e.g. work.reports ('none' to clear)")
+	markCmd.Flags().IntSlice("depends", nil, "Add task IDs this task depends on (negative to remove)")
+	markCmd.Flags().String("assign", "", "Reassign the task ('me' for yourself, 'none' to unassign)")
+	markCmd.Flags().BoolVar(&showEveryone, "everyone", false, "Include tasks assigned to others in --smart analysis")
+
+	markCmd.ValidArgsFunction = completeMarkTasks
+	markCmd.RegisterFlagCompletionFunc("due", completeDates)
+	markCmd.RegisterFlagCompletionFunc("wait", completeDates)
+	markCmd.RegisterFlagCompletionFunc("priority", completePriorities)
+	markCmd.RegisterFlagCompletionFunc("tag", completeTags)
+	markCmd.RegisterFlagCompletionFunc("project", completeProjects)
+	markCmd.RegisterFlagCompletionFunc("depends", completeDependencies)
+	markCmd.RegisterFlagCompletionFunc("assign", completeAssignees)
 }
@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"testing"
+	"todo/taskdata"
+)
+
+func TestValidateDependency(t *testing.T) {
+	store := &taskdata.TaskStore{Tasks: []taskdata.Task{
+		{ID: 1, Description: "Collect numbers"},
+		{ID: 2, Description: "Write report", DependsOn: []int{1}},
+		{ID: 3, Description: "Send report", DependsOn: []int{2}},
+		{ID: 4, Description: "Unrelated"},
+	}}
+
+	tests := []struct {
+		name      string
+		task      int
+		dependsOn int
+		wantErr   bool
+	}{
+		{"independent task", 4, 1, false},
+		{"further down the chain", 3, 1, false},
+		{"itself", 1, 1, true},
+		{"missing task", 1, 99, true},
+		{"direct cycle", 1, 2, true},
+		{"cycle through a chain", 1, 3, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateDependency(store, tt.task, tt.dependsOn)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateDependency(#%d, #%d) = %v, want error %v", tt.task, tt.dependsOn, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,338 @@
+/*
+Copyright © 2025 Smart Todo CLI
+*/
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+	"todo/taskdata"
+
+	"github.com/spf13/cobra"
+)
+
+// matrixCmd represents the matrix command
+var matrixCmd = &cobra.Command{
+	Use:   "matrix",
+	Short: "Eisenhower matrix of pending tasks",
+	Long: `Classify pending tasks into the four Eisenhower quadrants.
+
+Importance comes from priority: tasks at or above matrix.important_priority
+(the highest level by default) are important. Urgency comes from the due date:
+overdue tasks and tasks due within matrix.urgent_days (3 by default) are urgent.
+
+Quadrants:
+  1. Do first     urgent and important
+  2. Schedule     important, not urgent
+  3. Delegate     urgent, not important
+  4. Eliminate    neither urgent nor important
+
+Examples:
+  todo matrix                    # 2x2 grid in the terminal
+  todo matrix --json             # Quadrants as JSON
+  todo matrix -q 2               # Only the Schedule quadrant
+  todo matrix --review           # Reprioritise tasks quadrant by quadrant
+  todo matrix --review -q 3      # Review only the Delegate quadrant`,
+	Args: cobra.NoArgs,
+	Run:  matrixRun,
+}
+
+// matrixCellWidth is the inner width of each grid cell
+const matrixCellWidth = 36
+
+// quadrant is one cell of the Eisenhower matrix
+type quadrant struct {
+	Number    int             `json:"quadrant"`
+	Name      string          `json:"name"`
+	Action    string          `json:"action"`
+	Urgent    bool            `json:"urgent"`
+	Important bool            `json:"important"`
+	Tasks     []taskdata.Task `json:"tasks"`
+}
+
+func matrixRun(cmd *cobra.Command, args []string) {
+	// Get flags
+	asJSON, _ := cmd.Flags().GetBool("json")
+	review, _ := cmd.Flags().GetBool("review")
+	only, _ := cmd.Flags().GetInt("quadrant")
+	limit, _ := cmd.Flags().GetInt("limit")
+
+	if only < 0 || only > 4 {
+		fmt.Println("❌ Quadrant must be between 1 and 4.")
+		return
+	}
+
+	// Load tasks
+	store, err := taskdata.LoadTasks()
+	if err != nil {
+		fmt.Printf("Error loading tasks: %v\n", err)
+		return
+	}
+
+	now := time.Now()
+	quadrants := buildMatrix(store, now)
+
+	if asJSON {
+		selected := quadrants[:]
+		if only > 0 {
+			selected = quadrants[only-1 : only]
+		}
+		data, err := json.MarshalIndent(selected, "", "  ")
+		if err != nil {
+			fmt.Printf("Error encoding matrix: %v\n", err)
+			return
+		}
+		fmt.Println(string(data))
+		return
+	}
+
+	if review {
+		reviewMatrix(store, quadrants, only)
+		return
+	}
+
+	if only > 0 {
+		q := quadrants[only-1]
+		fmt.Printf("%d. %s (%d) - %s\n", q.Number, strings.ToUpper(q.Name), len(q.Tasks), q.Action)
+		fmt.Println(strings.Repeat("=", 50))
+		for _, task := range q.Tasks {
+			displayTask(task)
+		}
+		return
+	}
+
+	displayMatrix(quadrants, limit)
+}
+
+// buildMatrix sorts pending tasks into the four quadrants, most urgent first
+func buildMatrix(store *taskdata.TaskStore, now time.Time) [4]quadrant {
+	quadrants := [4]quadrant{
+		{Number: 1, Name: "Do first", Action: "do it now", Urgent: true, Important: true},
+		{Number: 2, Name: "Schedule", Action: "plan a time for it", Important: true},
+		{Number: 3, Name: "Delegate", Action: "hand off or timebox it", Urgent: true},
+		{Number: 4, Name: "Eliminate", Action: "drop or defer it"},
+	}
+
+	for _, task := range getTasksByUrgency(store, now) {
+		q := &quadrants[quadrantIndex(task, now)]
+		q.Tasks = append(q.Tasks, task)
+	}
+
+	for i := range quadrants {
+		if quadrants[i].Tasks == nil {
+			quadrants[i].Tasks = []taskdata.Task{}
+		}
+	}
+
+	return quadrants
+}
+
+// quadrantIndex returns the zero-based quadrant for a task
+func quadrantIndex(task taskdata.Task, now time.Time) int {
+	urgent := isUrgent(task, now)
+	important := isImportant(task)
+
+	switch {
+	case urgent && important:
+		return 0
+	case important:
+		return 1
+	case urgent:
+		return 2
+	}
+	return 3
+}
+
+// isImportant reports whether a task's priority meets matrix.important_priority
+func isImportant(task taskdata.Task) bool {
+	threshold := taskdata.HighestPriority()
+	if appConfig.Matrix.ImportantPriority != "" {
+		if level, err := taskdata.NormalizePriority(appConfig.Matrix.ImportantPriority); err == nil {
+			threshold = level
+		}
+	}
+	return taskdata.PriorityRank(task.Priority) >= taskdata.PriorityRank(threshold)
+}
+
+// isUrgent reports whether a task is overdue or due within matrix.urgent_days
+func isUrgent(task taskdata.Task, now time.Time) bool {
+	return isOverdue(task, now) || isTaskDueToday(task, now) || isDueWithin(task, now, appConfig.Matrix.UrgentDays)
+}
+
+func displayMatrix(quadrants [4]quadrant, limit int) {
+	fmt.Println("🧭 Eisenhower Matrix")
+	fmt.Println(strings.Repeat("=", 50))
+	fmt.Println()
+
+	border := strings.Repeat("─", matrixCellWidth+2)
+	fmt.Printf("%-11s %-*s %s\n", "", matrixCellWidth+2, "   URGENT", "   NOT URGENT")
+	fmt.Printf("%-11s┌%s┬%s┐\n", "", border, border)
+	displayMatrixRow("IMPORTANT", quadrants[0], quadrants[1], limit)
+	fmt.Printf("%-11s├%s┼%s┤\n", "", border, border)
+	displayMatrixRow("NOT", quadrants[2], quadrants[3], limit)
+	fmt.Printf("%-11s└%s┴%s┘\n", "", border, border)
+
+	total := 0
+	for _, q := range quadrants {
+		total += len(q.Tasks)
+	}
+	fmt.Printf("\nTotal: %d pending tasks\n", total)
+	fmt.Printf("💡 Use 'todo matrix --review' to reprioritise tasks quadrant by quadrant\n")
+}
+
+func displayMatrixRow(label string, left, right quadrant, limit int) {
+	leftLines := matrixCellLines(left, limit)
+	rightLines := matrixCellLines(right, limit)
+
+	rows := max(len(leftLines), len(rightLines))
+	for i := 0; i < rows; i++ {
+		rowLabel := ""
+		switch {
+		case i == 1:
+			rowLabel = label
+		case i == 2 && label == "NOT":
+			rowLabel = "IMPORTANT"
+		}
+
+		var l, r string
+		if i < len(leftLines) {
+			l = leftLines[i]
+		}
+		if i < len(rightLines) {
+			r = rightLines[i]
+		}
+		fmt.Printf("%-11s│ %s │ %s │\n", rowLabel, padCell(l), padCell(r))
+	}
+}
+
+// matrixCellLines renders the title and tasks of a quadrant, one line each
+func matrixCellLines(q quadrant, limit int) []string {
+	lines := []string{
+		fmt.Sprintf("%d. %s (%d)", q.Number, strings.ToUpper(q.Name), len(q.Tasks)),
+		"",
+	}
+
+	shown := q.Tasks
+	if limit > 0 && len(shown) > limit {
+		shown = shown[:limit]
+	}
+
+	for _, task := range shown {
+		line := fmt.Sprintf("#%d %s", task.ID, task.Description)
+		if task.DueDate != "" {
+			line += " (" + formatDate(task.DueDate) + ")"
+		}
+		lines = append(lines, line)
+	}
+
+	if hidden := len(q.Tasks) - len(shown); hidden > 0 {
+		lines = append(lines, fmt.Sprintf("… and %d more", hidden))
+	}
+	if len(q.Tasks) == 0 {
+		lines = append(lines, "(none)")
+	}
+
+	return lines
+}
+
+// padCell truncates or pads text to the cell width
+func padCell(text string) string {
+	runes := []rune(text)
+	if len(runes) > matrixCellWidth {
+		return string(runes[:matrixCellWidth-1]) + "…"
+	}
+	return text + strings.Repeat(" ", matrixCellWidth-len(runes))
+}
+
+// reviewMatrix walks through quadrants and lets the user raise or lower priorities
+func reviewMatrix(store *taskdata.TaskStore, quadrants [4]quadrant, only int) {
+	fmt.Println("🧭 Matrix Review")
+	fmt.Println(strings.Repeat("=", 50))
+
+	reader := bufio.NewReader(os.Stdin)
+	changed := 0
+
+	for _, q := range quadrants {
+		if only > 0 && q.Number != only {
+			continue
+		}
+		if len(q.Tasks) == 0 {
+			continue
+		}
+
+		fmt.Printf("\n%d. %s (%d tasks) - %s\n", q.Number, strings.ToUpper(q.Name), len(q.Tasks), q.Action)
+		fmt.Println(strings.Repeat("-", 30))
+
+		for _, task := range q.Tasks {
+			displayTask(task)
+			fmt.Print("   Actions: (u)p priority, (d)own priority, (s)kip, (q)uit: ")
+
+			action, _ := reader.ReadString('\n')
+			action = strings.TrimSpace(strings.ToLower(action))
+
+			switch action {
+			case "u", "up", "d", "down":
+				newPriority, ok := shiftPriority(task.Priority, action == "u" || action == "up")
+				if !ok {
+					fmt.Printf("   ℹ️  Priority is already at the end of the scale\n")
+					continue
+				}
+				updateTaskPriority(store, task.ID, newPriority)
+				fmt.Printf("   ✅ #%d priority: %s → %s\n", task.ID, task.Priority, newPriority)
+				changed++
+			case "q", "quit":
+				saveMatrixReview(store, changed)
+				return
+			default:
+				fmt.Printf("   ⏭️  Skipped task #%d\n", task.ID)
+			}
+		}
+	}
+
+	saveMatrixReview(store, changed)
+}
+
+func saveMatrixReview(store *taskdata.TaskStore, changed int) {
+	if changed == 0 {
+		fmt.Println("\nNo priorities changed.")
+		return
+	}
+	if err := store.SaveTasks(); err != nil {
+		fmt.Printf("❌ Error saving changes: %v\n", err)
+		return
+	}
+	fmt.Printf("\n✅ Reprioritised %d task(s)\n", changed)
+}
+
+// shiftPriority moves a priority one level up (more important) or down the scale
+func shiftPriority(priority string, up bool) (string, bool) {
+	levels := taskdata.Priorities()
+	index := len(levels) - taskdata.PriorityRank(priority)
+	if index >= len(levels) {
+		// Unknown priority: start from the middle of the scale
+		return levels[len(levels)/2], true
+	}
+
+	if up {
+		index--
+	} else {
+		index++
+	}
+	if index < 0 || index >= len(levels) {
+		return priority, false
+	}
+	return levels[index], true
+}
+
+func init() {
+	rootCmd.AddCommand(matrixCmd)
+
+	matrixCmd.Flags().Bool("json", false, "Output the quadrants as JSON")
+	matrixCmd.Flags().Bool("review", false, "Interactively reprioritise tasks from each quadrant")
+	matrixCmd.Flags().IntP("quadrant", "q", 0, "Only show or review one quadrant (1-4)")
+	matrixCmd.Flags().IntP("limit", "n", 8, "Maximum tasks shown per quadrant in the grid (0 for all)")
+}
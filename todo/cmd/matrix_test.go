@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"slices"
+	"testing"
+	"time"
+	"todo/config"
+	"todo/taskdata"
+)
+
+func TestQuadrantIndex(t *testing.T) {
+	t.Cleanup(func() { appConfig = config.Default() })
+	appConfig.Matrix.UrgentDays = 3
+	now := time.Date(2025, time.July, 16, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		task taskdata.Task
+		want int
+	}{
+		{"important and overdue", taskdata.Task{Priority: "high", DueDate: "2025-07-10"}, 1},
+		{"important and due today", taskdata.Task{Priority: "high", DueDate: "2025-07-16"}, 1},
+		{"important and due in three days", taskdata.Task{Priority: "high", DueDate: "2025-07-19"}, 1},
+		{"important and due next week", taskdata.Task{Priority: "high", DueDate: "2025-07-23"}, 2},
+		{"important without a due date", taskdata.Task{Priority: "high"}, 2},
+		{"normal and overdue", taskdata.Task{Priority: "normal", DueDate: "2025-07-01"}, 3},
+		{"low and due tomorrow", taskdata.Task{Priority: "low", DueDate: "2025-07-17"}, 3},
+		{"normal without a due date", taskdata.Task{Priority: "normal"}, 4},
+		{"unknown priority", taskdata.Task{Priority: "someday", DueDate: "2025-08-01"}, 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := quadrantIndex(tt.task, now) + 1; got != tt.want {
+				t.Errorf("quadrant %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestImportantPriorityThreshold(t *testing.T) {
+	scale, err := taskdata.NewPriorityScale([]string{"p0", "p1", "p2", "p3"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	taskdata.SetPriorityScale(scale)
+	t.Cleanup(func() {
+		defaultScale, _ := taskdata.NewPriorityScale(taskdata.DefaultPriorityLevels, nil)
+		taskdata.SetPriorityScale(defaultScale)
+		appConfig = config.Default()
+	})
+
+	tests := []struct {
+		threshold string
+		priority  string
+		want      bool
+	}{
+		{"", "p0", true},
+		{"", "p1", false},
+		{"p1", "p0", true},
+		{"p1", "p1", true},
+		{"p1", "p2", false},
+		{"p3", "p3", true},
+		{"p1", "unknown", false},
+	}
+	for _, tt := range tests {
+		appConfig.Matrix.ImportantPriority = tt.threshold
+		if got := isImportant(taskdata.Task{Priority: tt.priority}); got != tt.want {
+			t.Errorf("threshold %q: isImportant(%s) = %v, want %v", tt.threshold, tt.priority, got, tt.want)
+		}
+	}
+}
+
+func TestBuildMatrix(t *testing.T) {
+	now := time.Date(2025, time.July, 16, 12, 0, 0, 0, time.UTC)
+	store := &taskdata.TaskStore{Tasks: []taskdata.Task{
+		{ID: 1, Description: "Plan the offsite", Priority: "high"},
+		{ID: 2, Description: "Fix production", Priority: "high", DueDate: "2025-07-15"},
+		{ID: 3, Description: "Done already", Priority: "high", DueDate: "2025-07-15", Completed: true},
+		{ID: 4, Description: "Reply to email", Priority: "normal", DueDate: "2025-07-16"},
+		{ID: 5, Description: "Tidy desk", Priority: "low"},
+		{ID: 6, Description: "Ship release", Priority: "high", DueDate: "2025-07-17"},
+	}}
+
+	quadrants := buildMatrix(store, now)
+	want := [4][]int{{2, 6}, {1}, {4}, {5}}
+	for i, q := range quadrants {
+		var ids []int
+		for _, task := range q.Tasks {
+			ids = append(ids, task.ID)
+		}
+		if !slices.Equal(ids, want[i]) {
+			t.Errorf("quadrant %d holds %v, want %v", q.Number, ids, want[i])
+		}
+	}
+}
+
+func TestShiftPriority(t *testing.T) {
+	tests := []struct {
+		priority string
+		up       bool
+		want     string
+		moved    bool
+	}{
+		{"normal", true, "high", true},
+		{"normal", false, "low", true},
+		{"high", true, "high", false},
+		{"low", false, "low", false},
+		{"someday", true, "normal", true},
+	}
+	for _, tt := range tests {
+		got, moved := shiftPriority(tt.priority, tt.up)
+		if got != tt.want || moved != tt.moved {
+			t.Errorf("shiftPriority(%q, up=%v) = %q, %v, want %q, %v", tt.priority, tt.up, got, moved, tt.want, tt.moved)
+		}
+	}
+}
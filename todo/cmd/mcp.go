@@ -0,0 +1,366 @@
+/*
+Copyright © 2025 Smart Todo CLI
+*/
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"strings"
+	"time"
+	"todo/mcp"
+	"todo/taskdata"
+
+	"github.com/spf13/cobra"
+)
+
+// mcpCmd represents the mcp command
+var mcpCmd = &cobra.Command{
+	Use:   "mcp",
+	Short: "Serve the tasks to assistants over the Model Context Protocol",
+	Long: `Speak the Model Context Protocol (MCP) over standard input and output, so
+local assistants and editors can read and change tasks through typed tools:
+
+  list_tasks        List tasks with filters, most urgent first
+  add_task          Add a task
+  complete_task     Complete a task
+  reschedule_task   Change a task's due date and time
+  get_insights      Statistics, smart view and recommendations
+
+Configure the client to run 'todo mcp' as a stdio server, for example:
+
+  {"mcpServers": {"todo": {"command": "todo", "args": ["mcp"]}}}
+
+Dates and priorities are checked like 'todo add'. Changes are saved under
+the same lock as other commands, so the CLI can be used at the same time.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		// Standard output carries the protocol; anything else goes to stderr
+		protocol := os.Stdout
+		os.Stdout = os.Stderr
+
+		server := &mcp.Server{Name: "todo", Version: Version, Tools: mcpTools()}
+		if err := server.Serve(os.Stdin, protocol); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		}
+	},
+}
+
+// mcpTask is a task as returned by the tools
+type mcpTask struct {
+	taskdata.Task
+	Urgency float64 `json:"urgency"`
+}
+
+func mcpTools() []mcp.Tool {
+	priorities := taskdata.Priorities()
+	dateSchema := map[string]any{
+		"type":        "string",
+		"pattern":     `^\d{4}-\d{2}-\d{2}$`,
+		"description": "Date as YYYY-MM-DD",
+	}
+	timeSchema := map[string]any{
+		"type":        "string",
+		"pattern":     `^\d{2}:\d{2}$`,
+		"description": "Time of day as HH:MM (24-hour); needs a due date",
+	}
+	prioritySchema := map[string]any{
+		"type":        "string",
+		"enum":        priorities,
+		"description": "Priority, most important first: " + strings.Join(priorities, ", "),
+	}
+	idSchema := map[string]any{"type": "integer", "minimum": 1, "description": "Task ID"}
+
+	return []mcp.Tool{
+		{
+			Name:        "list_tasks",
+			Description: "List tasks matching the given filters, most urgent first.",
+			InputSchema: objectSchema(map[string]any{
+				"status": map[string]any{
+					"type": "string", "enum": []string{"pending", "completed", "waiting", "all"},
+					"description": "Task status (default pending)",
+				},
+				"priority": prioritySchema,
+				"tag":      map[string]any{"type": "string", "description": "Only tasks with this tag"},
+				"project":  map[string]any{"type": "string", "description": "Only tasks in this project or its sub-projects"},
+				"due": map[string]any{
+					"type": "string", "enum": []string{"today", "week", "month", "overdue", "soon", "none", "any"},
+					"description": "Due date window",
+				},
+				"query": map[string]any{"type": "string", "description": "Fuzzy search in descriptions and notes"},
+				"limit": map[string]any{"type": "integer", "minimum": 1, "description": "Maximum number of tasks"},
+			}),
+			Handler: mcpListTasks,
+		},
+		{
+			Name:        "add_task",
+			Description: "Add a task. Returns the new task with its ID.",
+			InputSchema: objectSchema(map[string]any{
+				"description": map[string]any{"type": "string", "minLength": 1, "description": "What needs to be done"},
+				"due_date":    dateSchema,
+				"due_time":    timeSchema,
+				"priority":    prioritySchema,
+				"notes":       map[string]any{"type": "string"},
+				"tags":        map[string]any{"type": "array", "items": map[string]any{"type": "string"}},
+				"project":     map[string]any{"type": "string"},
+			}, "description"),
+			Handler: mcpAddTask,
+		},
+		{
+			Name:        "complete_task",
+			Description: "Mark a task as completed.",
+			InputSchema: objectSchema(map[string]any{"id": idSchema}, "id"),
+			Handler:     mcpCompleteTask,
+		},
+		{
+			Name:        "reschedule_task",
+			Description: "Change the due date, and optionally the time, of a task. An empty due_date removes both.",
+			InputSchema: objectSchema(map[string]any{
+				"id":       idSchema,
+				"due_date": dateSchema,
+				"due_time": timeSchema,
+			}, "id", "due_date"),
+			Handler: mcpRescheduleTask,
+		},
+		{
+			Name:        "get_insights",
+			Description: "Task statistics, the smart view (critical, today, due soon, quick wins) and recommendations.",
+			InputSchema: objectSchema(map[string]any{}),
+			Handler:     mcpGetInsights,
+		},
+	}
+}
+
+func objectSchema(properties map[string]any, required ...string) map[string]any {
+	schema := map[string]any{
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": false,
+	}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+// decodeArgs reads tool arguments, rejecting unknown fields
+func decodeArgs(args json.RawMessage, v any) error {
+	decoder := json.NewDecoder(bytes.NewReader(args))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		return fmt.Errorf("invalid arguments: %v", err)
+	}
+	return nil
+}
+
+func mcpListTasks(args json.RawMessage) (any, error) {
+	var params struct {
+		Status   string `json:"status"`
+		Priority string `json:"priority"`
+		Tag      string `json:"tag"`
+		Project  string `json:"project"`
+		Due      string `json:"due"`
+		Query    string `json:"query"`
+		Limit    int    `json:"limit"`
+	}
+	if err := decodeArgs(args, &params); err != nil {
+		return nil, err
+	}
+	if params.Priority != "" {
+		if err := taskdata.ValidatePriority(params.Priority); err != nil {
+			return nil, err
+		}
+	}
+	if params.Status == "" {
+		params.Status = "pending"
+	}
+
+	terms := []string{"status:" + params.Status, params.Query}
+	for key, value := range map[string]string{"priority": params.Priority, "tag": params.Tag, "project": params.Project, "due": params.Due} {
+		if value != "" {
+			terms = append(terms, key+":"+strings.ReplaceAll(value, " ", ""))
+		}
+	}
+	filter, err := parseReportFilter(strings.Join(terms, " "))
+	if err != nil {
+		return nil, err
+	}
+
+	store, err := taskdata.LoadTasks()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	var tasks []taskdata.Task
+	for _, task := range store.Tasks {
+		if filter.matches(task, now) {
+			tasks = append(tasks, task)
+		}
+	}
+	sortReportTasks(store, tasks, []reportSortKey{{field: "urgency", descending: true}, {field: "id"}}, now)
+
+	total := len(tasks)
+	if params.Limit > 0 && len(tasks) > params.Limit {
+		tasks = tasks[:params.Limit]
+	}
+
+	results := []mcpTask{}
+	for _, task := range tasks {
+		results = append(results, newMCPTask(store, task, now))
+	}
+	return map[string]any{"tasks": results, "total": total}, nil
+}
+
+func mcpAddTask(args json.RawMessage) (any, error) {
+	var params struct {
+		Description string   `json:"description"`
+		DueDate     string   `json:"due_date"`
+		DueTime     string   `json:"due_time"`
+		Priority    string   `json:"priority"`
+		Notes       string   `json:"notes"`
+		Tags        []string `json:"tags"`
+		Project     string   `json:"project"`
+	}
+	if err := decodeArgs(args, &params); err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(params.Description) == "" {
+		return nil, fmt.Errorf("description is required")
+	}
+	if params.Priority == "" {
+		params.Priority = appConfig.DefaultPriority
+	}
+	if err := taskdata.ValidatePriority(params.Priority); err != nil {
+		return nil, err
+	}
+	if err := taskdata.ValidateDate(params.DueDate); err != nil {
+		return nil, err
+	}
+
+	input := apiTaskInput{
+		DueTime: &params.DueTime,
+		Notes:   &params.Notes,
+		Tags:    &params.Tags,
+		Project: &params.Project,
+	}
+
+	var added taskdata.Task
+	store, err := taskdata.UpdateTasks(func(store *taskdata.TaskStore) error {
+		task, err := store.AddTask(strings.TrimSpace(params.Description), params.DueDate, params.Priority)
+		if err != nil {
+			return err
+		}
+		stored := findTaskByID(store, task.ID)
+		if err := applyTaskInput(store, stored, input); err != nil {
+			return err
+		}
+		added = *stored
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return newMCPTask(store, added, time.Now()), nil
+}
+
+func mcpCompleteTask(args json.RawMessage) (any, error) {
+	var params struct {
+		ID int `json:"id"`
+	}
+	if err := decodeArgs(args, &params); err != nil {
+		return nil, err
+	}
+
+	var completed taskdata.Task
+	store, err := taskdata.UpdateTasks(func(store *taskdata.TaskStore) error {
+		task := findTaskByID(store, params.ID)
+		if task == nil {
+			return fmt.Errorf("task #%d not found", params.ID)
+		}
+		if task.Completed {
+			return fmt.Errorf("task #%d is already completed", params.ID)
+		}
+		if err := updateTaskCompletion(store, task.ID, true); err != nil {
+			return err
+		}
+		completed = *task
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return newMCPTask(store, completed, time.Now()), nil
+}
+
+func mcpRescheduleTask(args json.RawMessage) (any, error) {
+	var params struct {
+		ID      int     `json:"id"`
+		DueDate *string `json:"due_date"`
+		DueTime *string `json:"due_time"` // Kept when left out
+	}
+	if err := decodeArgs(args, &params); err != nil {
+		return nil, err
+	}
+	if params.DueDate == nil {
+		return nil, fmt.Errorf("due_date is required (use \"\" to remove the due date)")
+	}
+	if err := taskdata.ValidateDate(*params.DueDate); err != nil {
+		return nil, err
+	}
+
+	var rescheduled taskdata.Task
+	store, err := taskdata.UpdateTasks(func(store *taskdata.TaskStore) error {
+		task := findTaskByID(store, params.ID)
+		if task == nil {
+			return fmt.Errorf("task #%d not found", params.ID)
+		}
+		if err := applyTaskInput(store, task, apiTaskInput{DueDate: params.DueDate, DueTime: params.DueTime}); err != nil {
+			return err
+		}
+		rescheduled = *task
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return newMCPTask(store, rescheduled, time.Now()), nil
+}
+
+func mcpGetInsights(args json.RawMessage) (any, error) {
+	var params struct{}
+	if err := decodeArgs(args, &params); err != nil {
+		return nil, err
+	}
+
+	store, err := taskdata.LoadTasks()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	view := buildSmartView(store, now)
+	sections := map[string][]int{}
+	for _, section := range view.Sections {
+		for _, task := range section.Tasks {
+			sections[section.Title] = append(sections[section.Title], task.ID)
+		}
+	}
+
+	return map[string]any{
+		"statistics":      buildStatistics(store, now),
+		"smart_view":      sections,
+		"recommendations": append([]string{}, view.Recommendations...),
+	}, nil
+}
+
+func newMCPTask(store *taskdata.TaskStore, task taskdata.Task, now time.Time) mcpTask {
+	return mcpTask{Task: task, Urgency: math.Round(store.Urgency(task, now)*100) / 100}
+}
+
+func init() {
+	rootCmd.AddCommand(mcpCmd)
+}
@@ -0,0 +1,151 @@
+package cmd
+
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+	"todo/taskdata"
+)
+
+// mcpCall runs one of the MCP tools with JSON arguments
+func mcpCall(t *testing.T, name, args string) (any, error) {
+	t.Helper()
+	for _, tool := range mcpTools() {
+		if tool.Name == name {
+			return tool.Handler(json.RawMessage(args))
+		}
+	}
+	t.Fatalf("no tool %s", name)
+	return nil, nil
+}
+
+// mcpListIDs returns the IDs list_tasks returns for args
+func mcpListIDs(t *testing.T, args string) []int {
+	t.Helper()
+	result, err := mcpCall(t, "list_tasks", args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []int
+	for _, task := range result.(map[string]any)["tasks"].([]mcpTask) {
+		ids = append(ids, task.ID)
+	}
+	return ids
+}
+
+func TestMCPTools(t *testing.T) {
+	useAPIStore(t)
+
+	added, err := mcpCall(t, "add_task", `{"description":"Write report","due_date":"2025-07-20","due_time":"09:30","priority":"high","tags":["Work"],"project":"reports"}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	task := added.(mcpTask)
+	if task.ID != 1 || task.DueTime != "09:30" || !slices.Equal(task.Tags, []string{"work"}) || task.Project != "reports" || task.Urgency == 0 {
+		t.Errorf("added %+v", task)
+	}
+	if _, err := mcpCall(t, "add_task", `{"description":"Buy milk"}`); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := mcpCall(t, "add_task", `{"description":"Call mom","priority":"low"}`); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		args string
+		want []int
+	}{
+		{`{}`, []int{1, 2, 3}},
+		{`{"priority":"high"}`, []int{1}},
+		{`{"tag":"work"}`, []int{1}},
+		{`{"project":"reports"}`, []int{1}},
+		{`{"query":"milk"}`, []int{2}},
+		{`{"due":"none"}`, []int{2, 3}},
+		{`{"limit":1}`, []int{1}},
+	}
+	for _, tt := range tests {
+		if got := mcpListIDs(t, tt.args); !slices.Equal(got, tt.want) {
+			t.Errorf("list_tasks %s = %v, want %v", tt.args, got, tt.want)
+		}
+	}
+
+	rescheduled, err := mcpCall(t, "reschedule_task", `{"id":1,"due_date":"2025-08-01"}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if task := rescheduled.(mcpTask); task.DueDate != "2025-08-01" || task.DueTime != "09:30" {
+		t.Errorf("rescheduled %s %s, want the time kept", task.DueDate, task.DueTime)
+	}
+	if rescheduled, err = mcpCall(t, "reschedule_task", `{"id":1,"due_date":""}`); err != nil {
+		t.Fatal(err)
+	}
+	if task := rescheduled.(mcpTask); task.DueDate != "" || task.DueTime != "" {
+		t.Errorf("removing the due date left %s %s", task.DueDate, task.DueTime)
+	}
+
+	completed, err := mcpCall(t, "complete_task", `{"id":2}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if task := completed.(mcpTask); !task.Completed || task.Urgency != 0 {
+		t.Errorf("completed %+v", task)
+	}
+	if got := mcpListIDs(t, `{"status":"completed"}`); !slices.Equal(got, []int{2}) {
+		t.Errorf("completed tasks %v", got)
+	}
+
+	insights, err := mcpCall(t, "get_insights", `{}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats := insights.(map[string]any)["statistics"].(taskStatistics); stats.Total != 3 || stats.Completed != 1 {
+		t.Errorf("statistics %+v", stats)
+	}
+}
+
+func TestMCPToolErrors(t *testing.T) {
+	useAPIStore(t)
+	addDAVTestTask(t, "Collect numbers")
+
+	tests := []struct {
+		tool string
+		args string
+		want string
+	}{
+		{"add_task", `{}`, "description is required"},
+		{"add_task", `{"description":"x","colour":"red"}`, "invalid arguments"},
+		{"add_task", `{"description":"x","priority":"extreme"}`, "invalid priority"},
+		{"add_task", `{"description":"x","due_date":"tomorrow"}`, "invalid date"},
+		{"add_task", `{"description":"x","due_time":"10:00"}`, "due_time needs a due_date"},
+		{"list_tasks", `{"status":"someday"}`, "status"},
+		{"list_tasks", `{"priority":"extreme"}`, "invalid priority"},
+		{"complete_task", `{"id":9}`, "task #9 not found"},
+		{"reschedule_task", `{"id":1}`, "due_date is required"},
+		{"reschedule_task", `{"id":1,"due_date":"2025-13-01"}`, "invalid date"},
+		{"get_insights", `{"verbose":true}`, "invalid arguments"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.tool+" "+tt.args, func(t *testing.T) {
+			_, err := mcpCall(t, tt.tool, tt.args)
+			if err == nil || !strings.Contains(strings.ToLower(err.Error()), strings.ToLower(tt.want)) {
+				t.Errorf("error %v, want %q", err, tt.want)
+			}
+		})
+	}
+
+	if _, err := mcpCall(t, "complete_task", `{"id":1}`); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := mcpCall(t, "complete_task", `{"id":1}`); err == nil || !strings.Contains(err.Error(), "already completed") {
+		t.Errorf("completing twice: %v", err)
+	}
+
+	store, err := taskdata.LoadTasks()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(store.Tasks) != 1 {
+		t.Errorf("a failed call added tasks: %+v", store.Tasks)
+	}
+}
@@ -0,0 +1,236 @@
+/*
+Copyright © 2025 Smart Todo CLI
+*/
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+	"todo/config"
+	"todo/plugin"
+	"todo/taskdata"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// pluginsCmd represents the plugins command
+var pluginsCmd = &cobra.Command{
+	Use:   "plugins",
+	Short: "List the plugins found on PATH",
+	Long: `List the plugins found on PATH. Any executable named todo-<name> on PATH
+runs as 'todo <name>', like git subcommands; built-in commands take
+precedence. Plugins get the tasks' location in their environment:
+TODO_DATA_FILE, TODO_LIST, TODO_CONFIG and TODO_BIN (this todo binary).
+
+Plugins listed in plugins.suggest also add their own categories to the
+'todo delete' suggestions. todo runs 'todo-<name> suggest' with the task
+store as JSON on stdin, and the plugin prints a JSON array such as:
+
+  [{"category": "Stale Tickets", "score": 75, "impact": "low",
+    "reason": "Closed in the tracker", "task_ids": [3, 8]}]
+
+Examples:
+  todo plugins                               # List plugins
+  todo config set plugins.suggest stale      # Ask todo-stale for suggestions
+  todo delete                                # Shows the plugin's suggestions`,
+	Args: cobra.NoArgs,
+	Run:  pluginsRun,
+}
+
+func pluginsRun(cmd *cobra.Command, args []string) {
+	plugins := plugin.Discover()
+	if len(plugins) == 0 {
+		fmt.Printf("No plugins found. Put an executable named %s<name> on PATH to add 'todo <name>'.\n", plugin.Prefix)
+		return
+	}
+
+	fmt.Println("🔌 Plugins")
+	fmt.Println("==================================================")
+	for _, p := range plugins {
+		note := ""
+		if isBuiltinCommand(p.Name) {
+			note = " (hidden by the built-in command)"
+		} else if slices.Contains(appConfig.Plugins.Suggest, p.Name) {
+			note = " (suggestions)"
+		}
+		fmt.Printf("  %-16s %s%s\n", p.Name, p.Path, note)
+	}
+}
+
+// allPluginsAdded records that every plugin on PATH has a command
+var allPluginsAdded bool
+
+// addPluginCommands adds the plugin commands a command line needs. PATH is
+// only searched when no built-in command matches: for the one plugin the
+// line names, or for all of them when it names no command, as for
+// 'todo --help' or when completing the command name.
+func addPluginCommands(args []string) {
+	if len(args) > 0 && (args[0] == cobra.ShellCompRequestCmd || args[0] == cobra.ShellCompNoDescRequestCmd) {
+		args = args[1:]
+		if len(args) <= 1 {
+			addAllPluginCommands()
+			return
+		}
+	}
+
+	name := commandName(args)
+	if name == "help" {
+		name = commandName(args[slices.Index(args, "help")+1:])
+	}
+	if name == "" {
+		addAllPluginCommands()
+		return
+	}
+	if isBuiltinCommand(name) {
+		return
+	}
+	if p, found := plugin.Find(name); found {
+		addPluginCommand(p)
+	}
+}
+
+// addAllPluginCommands adds a command for each plugin on PATH that does not
+// clash with a built-in command
+func addAllPluginCommands() {
+	if allPluginsAdded {
+		return
+	}
+	allPluginsAdded = true
+	for _, p := range plugin.Discover() {
+		if !isBuiltinCommand(p.Name) {
+			addPluginCommand(p)
+		}
+	}
+}
+
+func addPluginCommand(p plugin.Plugin) {
+	for _, c := range rootCmd.Commands() {
+		if c.Name() == p.Name {
+			return
+		}
+	}
+	if !rootCmd.ContainsGroup("plugins") {
+		rootCmd.AddGroup(&cobra.Group{ID: "plugins", Title: "Plugin Commands:"})
+	}
+	rootCmd.AddCommand(&cobra.Command{
+		Use:                p.Name,
+		Short:              "Plugin " + p.Path,
+		GroupID:            "plugins",
+		DisableFlagParsing: true,
+		Run: func(cmd *cobra.Command, args []string) {
+			runPlugin(p, args)
+		},
+	})
+}
+
+// commandName returns the first word of a command line that is neither a
+// global flag nor a flag's value
+func commandName(args []string) string {
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			if i+1 < len(args) {
+				return args[i+1]
+			}
+			return ""
+		}
+		if !strings.HasPrefix(arg, "-") || arg == "-" {
+			return arg
+		}
+
+		name, _, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		var flag *pflag.Flag
+		if strings.HasPrefix(arg, "--") {
+			flag = rootCmd.PersistentFlags().Lookup(name)
+		} else if len(name) == 1 {
+			flag = rootCmd.PersistentFlags().ShorthandLookup(name)
+		}
+		if flag != nil && flag.NoOptDefVal == "" && !hasValue {
+			i++ // Skip the flag's value
+		}
+	}
+	return ""
+}
+
+func isBuiltinCommand(name string) bool {
+	if name == "help" || name == "completion" {
+		return true
+	}
+	for _, c := range rootCmd.Commands() {
+		if c.GroupID != "plugins" && (c.Name() == name || c.HasAlias(name)) {
+			return true
+		}
+	}
+	return false
+}
+
+// pluginEnv tells plugins where the tasks are
+func pluginEnv() []string {
+	bin, _ := os.Executable()
+	return []string{
+		"TODO_BIN=" + bin,
+		"TODO_DATA_FILE=" + taskdata.GetDataFilePath(),
+		"TODO_LIST=" + taskdata.CurrentList(),
+		"TODO_CONFIG=" + config.Path(),
+	}
+}
+
+// runPlugin runs a plugin command, exiting with its exit status
+func runPlugin(p plugin.Plugin, args []string) {
+	flushShell()
+	err := p.Run(args, pluginEnv())
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		exit(exitErr.ExitCode())
+	}
+	if err != nil {
+		fmt.Printf("❌ Plugin %s: %v\n", p.Name, err)
+		exit(1)
+	}
+}
+
+// pluginSuggestions asks the plugins in plugins.suggest for suggestions
+func pluginSuggestions(store *taskdata.TaskStore) []SmartSuggestion {
+	var suggestions []SmartSuggestion
+	for _, name := range appConfig.Plugins.Suggest {
+		p, found := plugin.Find(name)
+		if !found {
+			fmt.Fprintf(os.Stderr, "⚠️  Plugin %s%s is not on PATH\n", plugin.Prefix, name)
+			continue
+		}
+		results, err := p.Suggest(store, pluginEnv())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "⚠️  Plugin %s: %v\n", name, err)
+			continue
+		}
+
+		for _, result := range results {
+			var tasks []taskdata.Task
+			for _, id := range result.TaskIDs {
+				if task := findTaskByID(store, id); task != nil {
+					tasks = append(tasks, *task)
+				}
+			}
+			if len(tasks) == 0 {
+				continue
+			}
+			suggestions = append(suggestions, SmartSuggestion{
+				Category: fmt.Sprintf("%s (%s)", result.Category, name),
+				Tasks:    tasks,
+				Score:    result.Score,
+				Reason:   result.Reason,
+				Impact:   result.Impact,
+			})
+		}
+	}
+	return suggestions
+}
+
+func init() {
+	rootCmd.AddCommand(pluginsCmd)
+}
@@ -0,0 +1,530 @@
+/*
+Copyright © 2025 Smart Todo CLI
+*/
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+	"todo/config"
+	"todo/search"
+	"todo/taskdata"
+
+	"github.com/spf13/cobra"
+)
+
+// reportCmd represents the report command
+var reportCmd = &cobra.Command{
+	Use:   "report [name]",
+	Short: "Run a saved view (named report)",
+	Long: `Run a named report: a saved combination of filter, sort, columns and format.
+
+Built-in reports:
+  next       Most important pending tasks to work on next
+  waiting    Tasks deferred with --wait until a later date
+  overdue    Pending tasks past their due date
+  recent     Tasks completed in the last 7 days
+
+Define your own in ~/.config/todo/config.toml:
+
+  [report.standup]
+  description = "High priority work due soon"
+  filter = "status:pending priority:high due:soon"
+  sort = "due+,priority-"
+  columns = ["id", "priority", "due", "description"]
+  format = "table"   # list, table or json
+  limit = 10
+
+Filter terms (space separated, all must match):
+  status:pending|completed|waiting|all    priority:<level>    tag:<tag>
+  project:<name>                          context:<name>
+  assignee:<name>|me|none
+  due:today|week|month|overdue|soon|none|any
+  completed:N  created:N                  (within the last N days)
+  any other word                          (fuzzy match on description/notes)
+
+Sort fields: id, priority, urgency, due, wait, description, created, completed
+(suffix + for ascending, - for descending).
+
+Columns: id, status, priority, urgency, due, wait, description, notes, tags,
+project, contexts, source, depends, assignee, created, completed, age
+
+Examples:
+  todo report                    # List available reports
+  todo report next               # Run the built-in next report
+  todo report standup            # Run a report from your config file
+  todo list @standup             # Same, through the list command`,
+	Args: cobra.MaximumNArgs(1),
+	Run:  reportRun,
+}
+
+func reportRun(cmd *cobra.Command, args []string) {
+	if len(args) == 0 {
+		displayReportList(appConfig)
+		return
+	}
+
+	runReportByName(appConfig, args[0])
+}
+
+func displayReportList(cfg *config.Config) {
+	fmt.Println("📋 Available Reports")
+	fmt.Println(strings.Repeat("=", 50))
+
+	for _, name := range cfg.ReportNames() {
+		report := cfg.Reports[name]
+		source := "config"
+		if report.BuiltIn {
+			source = "built-in"
+		}
+		fmt.Printf("  %-12s %s (%s)\n", name, report.Description, source)
+	}
+
+	fmt.Printf("\n💡 Run a report with 'todo report <name>' or 'todo list @<name>'\n")
+	fmt.Printf("💡 Define your own in %s\n", config.Path())
+}
+
+func runReportByName(cfg *config.Config, name string) {
+	report, err := cfg.Report(name)
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		return
+	}
+
+	store, err := taskdata.LoadTasks()
+	if err != nil {
+		fmt.Printf("Error loading tasks: %v\n", err)
+		return
+	}
+
+	if err := runReport(store, report); err != nil {
+		fmt.Printf("❌ Report '%s': %v\n", report.Name, err)
+	}
+}
+
+func runReport(store *taskdata.TaskStore, report config.Report) error {
+	filter, err := parseReportFilter(report.Filter)
+	if err != nil {
+		return err
+	}
+
+	sortKeys, err := parseReportSort(report.Sort)
+	if err != nil {
+		return err
+	}
+
+	columns := report.Columns
+	if len(columns) == 0 {
+		columns = []string{"id", "status", "priority", "due", "description"}
+	}
+	for _, column := range columns {
+		if !isValidReportColumn(column) {
+			return fmt.Errorf("unknown column '%s'", column)
+		}
+	}
+
+	now := time.Now()
+	var tasks []taskdata.Task
+	for _, task := range store.Tasks {
+		if filter.matches(task, now) {
+			tasks = append(tasks, task)
+		}
+	}
+
+	sortReportTasks(store, tasks, sortKeys, now)
+
+	if report.Limit > 0 && len(tasks) > report.Limit {
+		tasks = tasks[:report.Limit]
+	}
+
+	switch report.Format {
+	case "json":
+		if tasks == nil {
+			tasks = []taskdata.Task{}
+		}
+		data, err := json.MarshalIndent(tasks, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal tasks: %v", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	fmt.Printf("📋 Report: %s\n", report.Name)
+	if report.Description != "" {
+		fmt.Printf("   %s\n", report.Description)
+	}
+	fmt.Println(strings.Repeat("=", 50))
+
+	if len(tasks) == 0 {
+		fmt.Println("No tasks match this report.")
+		return nil
+	}
+
+	if report.Format == "table" {
+		displayReportTable(store, tasks, columns, now)
+	} else {
+		for _, task := range tasks {
+			displayTask(task)
+		}
+	}
+
+	fmt.Printf("\nTotal: %d tasks\n", len(tasks))
+	return nil
+}
+
+// reportFilter is a parsed report filter expression
+type reportFilter struct {
+	status          string
+	priority        string
+	due             string
+	completedWithin int
+	createdWithin   int
+	tags            []string
+	project         string
+	contexts        []string
+	assignee        string
+	terms           []string
+}
+
+func parseReportFilter(expr string) (reportFilter, error) {
+	filter := reportFilter{status: "all"}
+
+	for _, term := range strings.Fields(expr) {
+		key, value, hasValue := strings.Cut(term, ":")
+		if !hasValue {
+			filter.terms = append(filter.terms, term)
+			continue
+		}
+
+		key, value = strings.ToLower(key), strings.ToLower(value)
+		switch key {
+		case "status":
+			switch value {
+			case "pending", "completed", "waiting", "all":
+				filter.status = value
+			default:
+				return filter, fmt.Errorf("invalid status '%s' in filter", value)
+			}
+		case "priority":
+			if err := taskdata.ValidatePriority(value); err != nil {
+				return filter, err
+			}
+			filter.priority = value
+		case "tag":
+			filter.tags = append(filter.tags, value)
+		case "project":
+			filter.project = value
+		case "context":
+			filter.contexts = append(filter.contexts, strings.TrimPrefix(value, "@"))
+		case "assignee":
+			filter.assignee = value
+		case "due":
+			switch value {
+			case "today", "week", "month", "overdue", "soon", "none", "any":
+				filter.due = value
+			default:
+				return filter, fmt.Errorf("invalid due filter '%s'", value)
+			}
+		case "completed", "created":
+			days, err := strconv.Atoi(value)
+			if err != nil || days < 0 {
+				return filter, fmt.Errorf("invalid number of days '%s' for %s", value, key)
+			}
+			if key == "completed" {
+				filter.completedWithin = days
+			} else {
+				filter.createdWithin = days
+			}
+		default:
+			return filter, fmt.Errorf("unknown filter term '%s'", term)
+		}
+	}
+
+	return filter, nil
+}
+
+func (f reportFilter) matches(task taskdata.Task, now time.Time) bool {
+	switch f.status {
+	case "pending":
+		if task.Completed || task.IsWaiting(now) {
+			return false
+		}
+	case "completed":
+		if !task.Completed {
+			return false
+		}
+	case "waiting":
+		if !task.IsWaiting(now) {
+			return false
+		}
+	}
+
+	if f.priority != "" && !matchesPriority(task, f.priority) {
+		return false
+	}
+
+	if f.due != "" && !matchesDueFilter(task, f.due, now) {
+		return false
+	}
+
+	if f.completedWithin > 0 && !isWithinDays(task.CompletedAt, f.completedWithin, now) {
+		return false
+	}
+
+	if f.createdWithin > 0 && !isWithinDays(task.CreatedAt, f.createdWithin, now) {
+		return false
+	}
+
+	for _, tag := range f.tags {
+		if !slices.Contains(task.Tags, tag) {
+			return false
+		}
+	}
+
+	// project:work also matches sub-projects such as work.reports
+	if f.project != "" {
+		project := strings.ToLower(task.Project)
+		if project != f.project && !strings.HasPrefix(project, f.project+".") {
+			return false
+		}
+	}
+
+	for _, context := range f.contexts {
+		if !slices.ContainsFunc(task.Contexts, func(c string) bool { return strings.EqualFold(c, context) }) {
+			return false
+		}
+	}
+
+	if f.assignee != "" && !matchesAssignee(task, f.assignee) {
+		return false
+	}
+
+	for _, term := range f.terms {
+		score := search.Score(task.Description, term)
+		if notesScore := search.Score(task.Notes, term); notesScore > score {
+			score = notesScore
+		}
+		if score < search.DefaultThreshold {
+			return false
+		}
+	}
+
+	return true
+}
+
+func matchesDueFilter(task taskdata.Task, due string, now time.Time) bool {
+	switch due {
+	case "none":
+		return task.DueDate == ""
+	case "any":
+		return task.DueDate != ""
+	case "overdue":
+		return isOverdue(task, now)
+	case "soon":
+		return isDueSoon(task, now)
+	}
+
+	if task.DueDate == "" {
+		return false
+	}
+	return matchesTimeFilter(task, due, now)
+}
+
+// isWithinDays reports whether an RFC 3339 timestamp falls within the last N days
+func isWithinDays(timestamp string, days int, now time.Time) bool {
+	if timestamp == "" {
+		return false
+	}
+	t, err := time.Parse(taskdata.TimestampFormat, timestamp)
+	if err != nil {
+		return false
+	}
+	return !t.Before(now.AddDate(0, 0, -days))
+}
+
+// reportSortKey is one field of a report sort specification
+type reportSortKey struct {
+	field      string
+	descending bool
+}
+
+func parseReportSort(spec string) ([]reportSortKey, error) {
+	var keys []reportSortKey
+
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		key := reportSortKey{field: strings.TrimRight(part, "+-")}
+		key.descending = strings.HasSuffix(part, "-")
+
+		switch key.field {
+		case "id", "priority", "urgency", "due", "wait", "description", "created", "completed":
+		default:
+			return nil, fmt.Errorf("unknown sort field '%s'", key.field)
+		}
+		keys = append(keys, key)
+	}
+
+	return keys, nil
+}
+
+func sortReportTasks(store *taskdata.TaskStore, tasks []taskdata.Task, keys []reportSortKey, now time.Time) {
+	sort.SliceStable(tasks, func(i, j int) bool {
+		for _, key := range keys {
+			var a, b string
+			switch key.field {
+			case "id":
+				if tasks[i].ID == tasks[j].ID {
+					continue
+				}
+				return (tasks[i].ID < tasks[j].ID) != key.descending
+			case "priority":
+				pi, pj := taskdata.PriorityRank(tasks[i].Priority), taskdata.PriorityRank(tasks[j].Priority)
+				if pi == pj {
+					continue
+				}
+				return (pi < pj) != key.descending
+			case "urgency":
+				ui, uj := store.Urgency(tasks[i], now), store.Urgency(tasks[j], now)
+				if ui == uj {
+					continue
+				}
+				return (ui < uj) != key.descending
+			case "due":
+				a, b = tasks[i].DueDate, tasks[j].DueDate
+			case "wait":
+				a, b = tasks[i].WaitUntil, tasks[j].WaitUntil
+			case "description":
+				a, b = strings.ToLower(tasks[i].Description), strings.ToLower(tasks[j].Description)
+			case "created":
+				a, b = tasks[i].CreatedAt, tasks[j].CreatedAt
+			case "completed":
+				a, b = tasks[i].CompletedAt, tasks[j].CompletedAt
+			}
+
+			if a == b {
+				continue
+			}
+			// Empty values always sort last
+			if a == "" || b == "" {
+				return b == ""
+			}
+			return (a < b) != key.descending
+		}
+		return false
+	})
+}
+
+func isValidReportColumn(column string) bool {
+	switch column {
+	case "id", "status", "priority", "urgency", "due", "wait", "description", "notes", "tags", "project",
+		"contexts", "source", "depends", "assignee", "created", "completed", "age":
+		return true
+	}
+	return false
+}
+
+func displayReportTable(store *taskdata.TaskStore, tasks []taskdata.Task, columns []string, now time.Time) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+
+	headers := make([]string, len(columns))
+	for i, column := range columns {
+		headers[i] = strings.ToUpper(column)
+	}
+	fmt.Fprintln(w, strings.Join(headers, "\t"))
+
+	for _, task := range tasks {
+		cells := make([]string, len(columns))
+		for i, column := range columns {
+			cells[i] = reportCell(store, task, column, now)
+		}
+		fmt.Fprintln(w, strings.Join(cells, "\t"))
+	}
+
+	w.Flush()
+}
+
+func reportCell(store *taskdata.TaskStore, task taskdata.Task, column string, now time.Time) string {
+	switch column {
+	case "id":
+		return fmt.Sprintf("#%d", task.ID)
+	case "status":
+		switch {
+		case task.Completed:
+			return "done"
+		case task.IsWaiting(now):
+			return "waiting"
+		case store.IsBlocked(task):
+			return "blocked"
+		case isOverdue(task, now):
+			return "overdue"
+		}
+		return "pending"
+	case "priority":
+		return task.Priority
+	case "urgency":
+		return fmt.Sprintf("%.1f", store.Urgency(task, now))
+	case "tags":
+		if len(task.Tags) == 0 {
+			return ""
+		}
+		return formatTags(task.Tags)
+	case "project":
+		return task.Project
+	case "contexts":
+		return formatContexts(task.Contexts)
+	case "source":
+		return task.Source
+	case "assignee":
+		return task.Assignee
+	case "depends":
+		if len(task.DependsOn) == 0 {
+			return ""
+		}
+		return formatDependencies(task.DependsOn)
+	case "due":
+		return strings.TrimSpace(formatDate(task.DueDate) + " " + task.DueTime)
+	case "wait":
+		return formatDate(task.WaitUntil)
+	case "description":
+		return task.Description
+	case "notes":
+		return task.Notes
+	case "created":
+		return formatTimestampDate(task.CreatedAt)
+	case "completed":
+		return formatTimestampDate(task.CompletedAt)
+	case "age":
+		created, err := time.Parse(taskdata.TimestampFormat, task.CreatedAt)
+		if err != nil {
+			return ""
+		}
+		return fmt.Sprintf("%dd", int(now.Sub(created).Hours()/24))
+	}
+	return ""
+}
+
+// formatTimestampDate shortens an RFC 3339 timestamp to its configured date format
+func formatTimestampDate(timestamp string) string {
+	t, err := time.Parse(taskdata.TimestampFormat, timestamp)
+	if err != nil {
+		return ""
+	}
+	return t.Format(appConfig.DateFormat)
+}
+
+func init() {
+	rootCmd.AddCommand(reportCmd)
+	reportCmd.ValidArgsFunction = completeFirstArg(completeReportNames)
+}
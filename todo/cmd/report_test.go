@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"todo/taskdata"
+)
+
+func TestReportFilter(t *testing.T) {
+	now := time.Date(2025, time.July, 16, 12, 0, 0, 0, time.Local)
+	tasks := []taskdata.Task{
+		{ID: 1, Description: "Write report", Priority: "high", DueDate: "2025-07-17", Tags: []string{"work"}, Project: "work.reports"},
+		{ID: 2, Description: "Buy milk", Priority: "normal", Contexts: []string{"Shop"}},
+		{ID: 3, Description: "Pay taxes", Priority: "high", DueDate: "2025-07-10", Project: "home"},
+		{ID: 4, Description: "Call mom", Priority: "low", Completed: true, CompletedAt: "2025-07-15T10:00:00Z"},
+		{ID: 5, Description: "Renew passport", Priority: "normal", WaitUntil: "2025-08-01", Assignee: "bob"},
+		{ID: 6, Description: "Old done", Priority: "normal", Completed: true, CompletedAt: "2025-01-01T10:00:00Z"},
+	}
+
+	tests := []struct {
+		filter string
+		want   []int
+	}{
+		{"", []int{1, 2, 3, 4, 5, 6}},
+		{"status:pending", []int{1, 2, 3}},
+		{"Status:Completed", []int{4, 6}},
+		{"status:waiting", []int{5}},
+		{"status:pending priority:high", []int{1, 3}},
+		{"priority:H", []int{1, 3}},
+		{"due:overdue", []int{3}},
+		{"due:soon", []int{1}},
+		{"due:none", []int{2, 4, 5, 6}},
+		{"completed:7", []int{4}},
+		{"tag:work", []int{1}},
+		{"project:work", []int{1}},
+		{"project:wor", nil},
+		{"context:@shop", []int{2}},
+		{"assignee:bob", []int{5}},
+		{"assignee:none status:pending", []int{1, 2, 3}},
+		{"taxes", []int{3}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.filter, func(t *testing.T) {
+			filter, err := parseReportFilter(tt.filter)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []int
+			for _, task := range tasks {
+				if filter.matches(task, now) {
+					got = append(got, task.ID)
+				}
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("matched %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReportFilterErrors(t *testing.T) {
+	for _, filter := range []string{"status:open", "priority:urgent", "due:later", "completed:-1", "created:week", "colour:red"} {
+		if _, err := parseReportFilter(filter); err == nil {
+			t.Errorf("%q was accepted", filter)
+		}
+	}
+}
+
+func TestReportSort(t *testing.T) {
+	tasks := []taskdata.Task{
+		{ID: 1, Description: "b", Priority: "low", DueDate: "2025-07-20"},
+		{ID: 2, Description: "A", Priority: "high"},
+		{ID: 3, Description: "c", Priority: "high", DueDate: "2025-07-18"},
+		{ID: 4, Description: "d", Priority: "normal", DueDate: "2025-07-18"},
+	}
+	store := &taskdata.TaskStore{Tasks: tasks}
+
+	tests := []struct {
+		spec string
+		want []int
+	}{
+		{"id-", []int{4, 3, 2, 1}},
+		{"due+", []int{3, 4, 1, 2}}, // Tasks without a due date last
+		{"due-", []int{1, 3, 4, 2}},
+		{"priority-,id+", []int{2, 3, 4, 1}},
+		{"due+,priority-", []int{3, 4, 1, 2}},
+		{"description", []int{2, 1, 3, 4}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			keys, err := parseReportSort(tt.spec)
+			if err != nil {
+				t.Fatal(err)
+			}
+			sorted := append([]taskdata.Task(nil), tasks...)
+			sortReportTasks(store, sorted, keys, time.Now())
+			var got []int
+			for _, task := range sorted {
+				got = append(got, task.ID)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("sorted %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := parseReportSort("size-"); err == nil {
+		t.Error("an unknown sort field was accepted")
+	}
+}
@@ -0,0 +1,74 @@
+/*
+Copyright © 2025 Smart Todo CLI
+*/
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"todo/search"
+	"todo/taskdata"
+)
+
+// resolveTask finds a task by ID, UID or name through the search package.
+// Unless the identifier names the task exactly, the user confirms the match
+// or chooses among several. It returns nil (after printing why) if no task
+// was selected.
+func resolveTask(store *taskdata.TaskStore, identifier string) *taskdata.Task {
+	matches, err := search.Resolve(store.Tasks, identifier)
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		if _, convErr := strconv.Atoi(identifier); convErr != nil {
+			fmt.Printf("💡 Try: \n")
+			fmt.Printf("   - Using partial words\n")
+			fmt.Printf("   - Checking task IDs with 'todo list -a'\n")
+			fmt.Printf("   - Searching with 'todo search \"%s\"'\n", identifier)
+		}
+		return nil
+	}
+
+	if len(matches) == 1 && matches[0].Exact() {
+		task := matches[0].Task
+		return &task
+	}
+
+	return chooseTask(matches, identifier)
+}
+
+// chooseTask shows ranked candidates and reads the user's choice from
+// stdin; a single candidate only needs to be confirmed
+func chooseTask(matches []search.Result, identifier string) *taskdata.Task {
+	reader := bufio.NewReader(os.Stdin)
+	if len(matches) == 1 {
+		match := matches[0]
+		fmt.Printf("❓ No task is called '%s'. Did you mean #%d: %s (%.0f%% match)? (y/N): ",
+			identifier, match.Task.ID, match.Task.Description, match.Score*100)
+		input, _ := reader.ReadString('\n')
+		if answer := strings.ToLower(strings.TrimSpace(input)); answer != "y" && answer != "yes" {
+			fmt.Println("Operation cancelled.")
+			return nil
+		}
+		task := match.Task
+		return &task
+	}
+
+	fmt.Printf("🔍 Found %d tasks matching '%s' (ranked by relevance):\n", len(matches), identifier)
+	for i, match := range matches {
+		fmt.Printf("  %d. #%d: %s (%.0f%% match)\n", i+1, match.Task.ID, match.Task.Description, match.Score*100)
+	}
+
+	fmt.Print("\nEnter the number of the task (0 to cancel): ")
+	input, _ := reader.ReadString('\n')
+	choice, err := strconv.Atoi(strings.TrimSpace(input))
+
+	if err != nil || choice < 1 || choice > len(matches) {
+		fmt.Println("Operation cancelled.")
+		return nil
+	}
+
+	task := matches[choice-1].Task
+	return &task
+}
@@ -6,10 +6,25 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"os/user"
+	"todo/config"
+	"todo/taskdata"
 
 	"github.com/spf13/cobra"
 )
 
+// cfgFile is the config file path given with --config
+var cfgFile string
+
+// listName is the task list given with --list
+var listName string
+
+// appConfig holds the loaded configuration; defaults until initConfig runs
+var appConfig = config.Default()
+
+// exit ends the program; 'todo shell' replaces it to end only the command
+var exit = os.Exit
+
 // rootCmd represents the base command when called without any subcommands
 var rootCmd = &cobra.Command{
 	Use:   "todo",
@@ -25,6 +40,7 @@ need to do.`,
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute() {
+	addPluginCommands(os.Args[1:])
 	err := rootCmd.Execute()
 	if err != nil {
 		fmt.Println(err)
@@ -33,16 +49,71 @@ func Execute() {
 }
 
 func init() {
-	// Here you will define your flags and configuration settings.
-	
+	cobra.OnInitialize(initConfig)
+
 	// Cobra supports persistent flags, which, if defined here,
 	// will be global for your application.
+	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.config/todo/config.toml)")
+	rootCmd.PersistentFlags().StringVar(&listName, "list", "", "task list to use instead of the current one (see 'todo lists')")
+	rootCmd.RegisterFlagCompletionFunc("list", completeListNames)
+}
+
+// initConfig reads the config file and applies its settings.
+// An invalid config file is reported but does not stop the command,
+// so that 'todo config set' can still be used to fix it.
+func initConfig() {
+	if cfgFile != "" {
+		config.SetPath(cfgFile)
+	}
 
-	// rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.todo.yaml)")
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Printf("⚠️  %v (using defaults)\n", err)
+		cfg = config.Default()
+	}
+	appConfig = cfg
 
-	// Cobra also supports local flags, which will only run
-	// when this action is called directly.
-	rootCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+	taskdata.SetDataFilePath(appConfig.DataFile)
+	selectList()
+	taskdata.SetIdentity(currentUser())
+	taskdata.SetUrgencyWeights(appConfig.Urgency)
+	if scale, err := appConfig.PriorityScale(); err == nil {
+		taskdata.SetPriorityScale(scale)
+	}
 }
 
+// selectList makes the --list list, or else the configured current list,
+// the one commands load and save. A missing --list list stops the command;
+// a missing current list falls back to the default one.
+func selectList() {
+	if listName != "" {
+		if err := taskdata.ValidateListName(listName); err != nil {
+			fmt.Printf("❌ %v\n", err)
+			exit(1)
+		}
+		if !taskdata.ListExists(listName) {
+			fmt.Printf("❌ List '%s' does not exist. Create it with 'todo list-create %s'\n", listName, listName)
+			exit(1)
+		}
+		taskdata.SetList(listName)
+		return
+	}
 
+	if !taskdata.ListExists(appConfig.CurrentList) {
+		fmt.Printf("⚠️  Current list '%s' does not exist (using %s)\n", appConfig.CurrentList, taskdata.DefaultList)
+		taskdata.SetList(taskdata.DefaultList)
+		return
+	}
+	taskdata.SetList(appConfig.CurrentList)
+}
+
+// currentUser is the configured user, or else the login name
+func currentUser() string {
+	if appConfig.User != "" {
+		return appConfig.User
+	}
+	if login, err := user.Current(); err == nil {
+		return login.Username
+	}
+	return os.Getenv("USER")
+}
@@ -0,0 +1,240 @@
+/*
+Copyright © 2025 Smart Todo CLI
+*/
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"todo/codescan"
+	"todo/taskdata"
+
+	"github.com/spf13/cobra"
+)
+
+// scanCmd represents the scan command
+var scanCmd = &cobra.Command{
+	Use:   "scan [path/...]",
+	Short: "Turn TODO/FIXME/HACK comments in source code into tasks",
+	Long: `Walk a directory and track its TODO, FIXME and HACK comments as tasks.
+
+Comments after // # /* * -- ; <!-- or % markers are found in common source
+files; hidden directories, node_modules and vendor are skipped. Each task
+records the comment's file:line and a fingerprint of the repository (its
+first commit or origin URL), the file's path in it and the comment text, so
+re-scans update tasks (for example when the line moves) instead of adding
+duplicates, and the same comment in two repositories makes two tasks. Files that cannot be read
+are reported and skipped.
+
+FIXME comments get the highest priority, HACK the lowest and TODO the
+default. Tasks whose comment has disappeared are completed as resolved, and
+reopened if the comment comes back.
+
+Examples:
+  todo scan ./...          # Scan the current directory
+  todo scan src/...        # Scan a subdirectory
+  todo scan --dry-run      # Show what would change`,
+	Args: cobra.MaximumNArgs(1),
+	Run:  scanRun,
+}
+
+// scanInfo is kept in Task.Extra["scan"] for tasks created by 'todo scan'
+type scanInfo struct {
+	Repo     string `json:"repo"`              // Absolute path of the repository root when first scanned
+	RepoID   string `json:"repo_id,omitempty"` // Identity of the repository, see codescan.RepoID
+	File     string `json:"file"`              // Path relative to Repo
+	Kind     string `json:"kind"`
+	Resolved bool   `json:"resolved,omitempty"` // Completed because the comment disappeared
+}
+
+func scanRun(cmd *cobra.Command, args []string) {
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+	pattern := "./..."
+	if len(args) > 0 {
+		pattern = args[0]
+	}
+	root := codescan.Root(pattern)
+
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		return
+	}
+
+	result, err := codescan.Scan(root)
+	if err != nil {
+		fmt.Printf("❌ Scan failed: %v\n", err)
+		return
+	}
+	comments := result.Comments
+
+	// Only tasks in the scanned part of the repository can be resolved
+	scanned, err := filepath.Rel(result.Repo, absRoot)
+	if err != nil {
+		scanned = "."
+	}
+	scanned = filepath.ToSlash(scanned)
+
+	store, err := taskdata.LoadTasks()
+	if err != nil {
+		fmt.Printf("Error loading tasks: %v\n", err)
+		return
+	}
+
+	fmt.Printf("🔎 Scanning %s\n", absRoot)
+	fmt.Println(strings.Repeat("=", 50))
+
+	for _, e := range result.Errors {
+		fmt.Printf("  ⚠️  Skipped %v\n", e)
+	}
+
+	var added, moved, reopened, resolved int
+	found := map[string]bool{}
+
+	for _, comment := range comments {
+		found[comment.Fingerprint] = true
+		description := comment.Text
+		if description == "" {
+			description = fmt.Sprintf("%s in %s", comment.Kind, comment.File)
+		}
+
+		existing := store.FindByUID(comment.Fingerprint)
+		if legacy := store.FindByUID(comment.PathFingerprint); existing == nil && legacy != nil && sameRepo(*legacy, result) {
+			// Created before fingerprints included the repository
+			existing = legacy
+			found[legacy.UID] = true
+		}
+		if existing == nil {
+			task := taskdata.Task{
+				UID:         comment.Fingerprint,
+				Description: description,
+				Priority:    scanPriority(comment.Kind),
+				Tags:        []string{strings.ToLower(comment.Kind)},
+				Source:      comment.Location(),
+			}
+			setScanInfo(&task, scanInfo{Repo: result.Repo, RepoID: result.RepoID, File: comment.File, Kind: comment.Kind})
+
+			imported, err := store.ImportTask(task)
+			if err != nil {
+				fmt.Printf("  ❌ %s: %v\n", comment.Location(), err)
+				continue
+			}
+			fmt.Printf("  ➕ #%d %s: %s\n", imported.ID, comment.Location(), description)
+			added++
+			continue
+		}
+
+		info := getScanInfo(*existing)
+		if existing.Source != comment.Location() {
+			fmt.Printf("  ↕️  #%d moved: %s → %s\n", existing.ID, existing.Source, comment.Location())
+			existing.Source = comment.Location()
+			moved++
+		}
+		existing.Description = description
+		if info.RepoID == "" {
+			info.RepoID = result.RepoID
+			setScanInfo(existing, info)
+		}
+		if existing.Completed && info.Resolved {
+			existing.Completed = false
+			existing.CompletedAt = ""
+			info.Resolved = false
+			setScanInfo(existing, info)
+			fmt.Printf("  🔁 #%d reopened, the comment is back: %s\n", existing.ID, comment.Location())
+			reopened++
+		}
+	}
+
+	// Pending tasks from earlier scans of this directory whose comment is
+	// gone, leaving alone those in files that could not be read this time
+	for i := range store.Tasks {
+		task := &store.Tasks[i]
+		info := getScanInfo(*task)
+		if !sameRepo(*task, result) || task.Completed || found[task.UID] || unreadableFile(result, info.File) {
+			continue
+		}
+		if scanned != "." && !strings.HasPrefix(info.File, scanned+"/") {
+			continue
+		}
+		store.CompleteTask(task.ID)
+		info.Resolved = true
+		setScanInfo(task, info)
+		fmt.Printf("  ✅ #%d resolved, comment removed from %s: %s\n", task.ID, task.Source, task.Description)
+		resolved++
+	}
+
+	fmt.Printf("\n📊 %d comment(s): %d new, %d moved, %d reopened, %d resolved\n",
+		len(comments), added, moved, reopened, resolved)
+	if len(result.Errors) > 0 {
+		fmt.Printf("⚠️  %d file(s) could not be read\n", len(result.Errors))
+	}
+
+	if dryRun {
+		fmt.Println("🔍 Dry run: nothing was saved.")
+		return
+	}
+	if err := store.SaveTasks(); err != nil {
+		fmt.Printf("Error saving tasks: %v\n", err)
+	}
+}
+
+// sameRepo reports whether a task was created by scanning the repository
+// of result, in any clone; tasks from before repository identities were
+// recorded only match the checkout they were scanned in
+func sameRepo(task taskdata.Task, result *codescan.Result) bool {
+	info := getScanInfo(task)
+	if info.RepoID != "" {
+		return info.RepoID == result.RepoID
+	}
+	return info.Repo != "" && info.Repo == result.Repo
+}
+
+// unreadableFile reports whether the scan skipped file, or a directory
+// holding it, because it could not be read
+func unreadableFile(result *codescan.Result, file string) bool {
+	for _, e := range result.Errors {
+		if file == e.File || strings.HasPrefix(file, e.File+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// scanPriority maps FIXME to the highest priority and HACK to the lowest
+func scanPriority(kind string) string {
+	switch kind {
+	case "FIXME":
+		return taskdata.HighestPriority()
+	case "HACK":
+		return taskdata.LowestPriority()
+	}
+	return appConfig.DefaultPriority
+}
+
+func getScanInfo(task taskdata.Task) scanInfo {
+	var info scanInfo
+	if raw, exists := task.Extra["scan"]; exists {
+		json.Unmarshal(raw, &info)
+	}
+	return info
+}
+
+func setScanInfo(task *taskdata.Task, info scanInfo) {
+	data, err := json.Marshal(info)
+	if err != nil {
+		return
+	}
+	if task.Extra == nil {
+		task.Extra = map[string]json.RawMessage{}
+	}
+	task.Extra["scan"] = data
+}
+
+func init() {
+	rootCmd.AddCommand(scanCmd)
+
+	scanCmd.Flags().Bool("dry-run", false, "Show what would change without saving")
+}
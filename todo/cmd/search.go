@@ -0,0 +1,86 @@
+/*
+Copyright © 2025 Smart Todo CLI
+*/
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"todo/search"
+	"todo/taskdata"
+
+	"github.com/spf13/cobra"
+)
+
+// searchCmd represents the search command
+var searchCmd = &cobra.Command{
+	Use:   "search <query>",
+	Short: "Search tasks with ranked fuzzy matching",
+	Long: `Search task descriptions and notes with ranked fuzzy matching.
+
+Matching tolerates typos, matches word prefixes and ranks the best hits first.
+Use --regex to match a regular expression instead.
+
+Examples:
+  todo search groceries          # Find tasks mentioning groceries
+  todo search "grocry shop"      # Typos are tolerated
+  todo search rep --all          # Include completed tasks
+  todo search "^fix.*bug$" -r    # Regular expression search
+  todo search meeting -n 3       # Show only the top 3 matches`,
+	Args: cobra.MinimumNArgs(1),
+	Run:  searchRun,
+}
+
+func searchRun(cmd *cobra.Command, args []string) {
+	// Get flags
+	regex, _ := cmd.Flags().GetBool("regex")
+	includeCompleted, _ := cmd.Flags().GetBool("all")
+	limit, _ := cmd.Flags().GetInt("limit")
+
+	// Load tasks
+	store, err := taskdata.LoadTasks()
+	if err != nil {
+		fmt.Printf("Error loading tasks: %v\n", err)
+		return
+	}
+
+	query := strings.Join(args, " ")
+	results, err := search.Search(store.Tasks, query, search.Options{
+		Regex:            regex,
+		IncludeCompleted: includeCompleted,
+		Limit:            limit,
+	})
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		return
+	}
+
+	if len(results) == 0 {
+		fmt.Printf("No tasks found matching '%s'.\n", query)
+		if !includeCompleted {
+			fmt.Println("💡 Use --all to include completed tasks.")
+		}
+		return
+	}
+
+	fmt.Printf("🔍 Search Results for '%s'\n", query)
+	fmt.Println(strings.Repeat("=", 50))
+
+	for _, result := range results {
+		displayTask(result.Task)
+		fmt.Printf("      %.0f%% match in %s\n", result.Score*100, result.Field)
+		if result.Field == "notes" {
+			fmt.Printf("      📝 %s\n", result.Task.Notes)
+		}
+	}
+
+	fmt.Printf("\nTotal: %d matching tasks\n", len(results))
+}
+
+func init() {
+	rootCmd.AddCommand(searchCmd)
+
+	searchCmd.Flags().BoolP("regex", "r", false, "Treat the query as a regular expression")
+	searchCmd.Flags().BoolP("all", "a", false, "Include completed tasks")
+	searchCmd.Flags().IntP("limit", "n", 0, "Maximum number of results (0 for no limit)")
+}
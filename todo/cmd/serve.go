@@ -0,0 +1,556 @@
+/*
+Copyright © 2025 Smart Todo CLI
+*/
+package cmd
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"todo/taskdata"
+
+	"github.com/spf13/cobra"
+)
+
+// serveCmd represents the serve command
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serve the tasks over a local JSON REST API",
+	Long: `Start an HTTP server that exposes the tasks as a JSON REST API.
+
+Every request needs an "Authorization: Bearer <token>" header. The token
+comes from --token or $TODO_API_TOKEN; without either a random token is
+generated and printed at startup.
+
+Endpoints:
+  GET    /api/tasks                 List tasks (filters: filter, status,
+                                    priority, tag, project, context, due, q)
+  POST   /api/tasks                 Create a task
+  GET    /api/tasks/{id}            Get a task
+  PATCH  /api/tasks/{id}            Update some fields of a task
+  POST   /api/tasks/{id}/complete   Complete a task
+  DELETE /api/tasks/{id}            Delete a task
+  GET    /api/views/smart           The 'todo list --smart' view
+  GET    /api/views/stats           The 'todo list --stats' numbers
+  GET    /api/events                Server-sent "change" events
+
+Each task is returned with an ETag. Send it back in an If-Match header when
+changing the task and the change is refused with 412 Precondition Failed if
+the task was modified in the meantime; changes sent without If-Match (or
+with If-Match: *) are applied without that check. Every change reloads tasks.json and
+saves it under a lock, so the server and CLI commands can be used together.
+
+The web UI at http://<addr>/ shows the list, smart and statistics views
+and updates live when tasks.json changes. Open the URL printed at startup,
+which carries the token.
+
+Examples:
+  todo serve                                   # http://127.0.0.1:8080
+  todo serve --addr 127.0.0.1:9000 --token s3cret
+  curl -H "Authorization: Bearer s3cret" "localhost:9000/api/tasks?status=pending&tag=work"`,
+	Args: cobra.NoArgs,
+	Run:  serveRun,
+}
+
+// apiTask is a task as returned by the API
+type apiTask struct {
+	taskdata.Task
+	ETag string `json:"etag"`
+}
+
+// apiTaskInput holds the fields of a create or update request. Fields that
+// are left out (nil) are not changed.
+type apiTaskInput struct {
+	Description *string   `json:"description"`
+	DueDate     *string   `json:"due_date"`
+	DueTime     *string   `json:"due_time"`
+	Priority    *string   `json:"priority"`
+	Completed   *bool     `json:"completed"`
+	Notes       *string   `json:"notes"`
+	WaitUntil   *string   `json:"wait_until"`
+	Tags        *[]string `json:"tags"`
+	Project     *string   `json:"project"`
+	Contexts    *[]string `json:"contexts"`
+	DependsOn   *[]int    `json:"depends_on"`
+}
+
+// apiError is an error with the HTTP status it is reported with
+type apiError struct {
+	status  int
+	message string
+}
+
+func (e apiError) Error() string { return e.message }
+
+func apiErrorf(status int, format string, args ...any) apiError {
+	return apiError{status: status, message: fmt.Sprintf(format, args...)}
+}
+
+func serveRun(cmd *cobra.Command, args []string) {
+	addr, _ := cmd.Flags().GetString("addr")
+	noUI, _ := cmd.Flags().GetBool("no-ui")
+	token, generated := serverToken(cmd)
+
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           newAPIHandler(token, !noUI),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		return
+	}
+
+	fmt.Printf("🌐 Serving %s on http://%s\n", taskdata.GetDataFilePath(), listener.Addr())
+	if generated {
+		fmt.Printf("🔑 Token: %s\n", token)
+	}
+	if !noUI {
+		fmt.Printf("🖥️  Web UI: http://%s/#token=%s\n", listener.Addr(), token)
+	}
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		if ip := net.ParseIP(host); ip == nil || !ip.IsLoopback() {
+			fmt.Println("⚠️  The API is reachable from other machines and does not use TLS")
+		}
+	}
+
+	if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		fmt.Printf("❌ %v\n", err)
+	}
+}
+
+// newAPIHandler routes the REST API behind bearer-token authentication,
+// and the web UI unless it is disabled
+func newAPIHandler(token string, webUI bool) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/meta", apiMeta)
+	mux.HandleFunc("GET /api/events", apiEvents)
+	mux.HandleFunc("GET /api/views/smart", apiSmartView)
+	mux.HandleFunc("GET /api/views/stats", apiStatistics)
+	mux.HandleFunc("GET /api/tasks", apiListTasks)
+	mux.HandleFunc("POST /api/tasks", apiCreateTask)
+	mux.HandleFunc("GET /api/tasks/{id}", apiGetTask)
+	mux.HandleFunc("PATCH /api/tasks/{id}", apiUpdateTask)
+	mux.HandleFunc("POST /api/tasks/{id}/complete", apiCompleteTask)
+	mux.HandleFunc("DELETE /api/tasks/{id}", apiDeleteTask)
+
+	root := http.NewServeMux()
+	root.Handle("/api/", requireToken(token, mux))
+	if webUI {
+		// The page holds no data and asks for the token itself
+		root.Handle("/", webUIHandler())
+	}
+	return logRequests(root)
+}
+
+// requireToken rejects requests without the bearer token
+func requireToken(token string, next http.Handler) http.Handler {
+	expected := []byte("Bearer " + token)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		given := []byte(r.Header.Get("Authorization"))
+		if subtle.ConstantTimeCompare(given, expected) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="todo"`)
+			writeAPIError(w, apiErrorf(http.StatusUnauthorized, "missing or invalid bearer token"))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// statusRecorder remembers the status code written by a handler
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// Flush lets the events stream through
+func (r *statusRecorder) Flush() {
+	if flusher, ok := r.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func logRequests(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(recorder, r)
+		fmt.Printf("%s %s %s %d\n", time.Now().Format("15:04:05"), r.Method, r.URL.RequestURI(), recorder.status)
+	})
+}
+
+func apiListTasks(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseReportFilter(apiFilterExpression(r))
+	if err != nil {
+		writeAPIError(w, apiErrorf(http.StatusBadRequest, "%v", err))
+		return
+	}
+
+	store, err := taskdata.LoadTasks()
+	if err != nil {
+		writeAPIError(w, err)
+		return
+	}
+
+	etag := `"` + store.Revision() + `"`
+	w.Header().Set("ETag", etag)
+	if r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	now := time.Now()
+	tasks := []apiTask{}
+	for _, task := range store.Tasks {
+		if filter.matches(task, now) {
+			tasks = append(tasks, newAPITask(task))
+		}
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{"tasks": tasks, "revision": store.Revision()})
+}
+
+// apiFilterExpression turns the query string into a report filter expression.
+// "filter" takes a whole expression; the other parameters add single terms.
+func apiFilterExpression(r *http.Request) string {
+	query := r.URL.Query()
+	terms := []string{query.Get("filter")}
+	for _, key := range []string{"status", "priority", "tag", "project", "context", "due"} {
+		for _, value := range query[key] {
+			terms = append(terms, key+":"+strings.ReplaceAll(value, " ", ""))
+		}
+	}
+	terms = append(terms, strings.Fields(query.Get("q"))...)
+	return strings.Join(terms, " ")
+}
+
+func apiGetTask(w http.ResponseWriter, r *http.Request) {
+	id, err := apiTaskID(r)
+	if err != nil {
+		writeAPIError(w, err)
+		return
+	}
+
+	store, err := taskdata.LoadTasks()
+	if err != nil {
+		writeAPIError(w, err)
+		return
+	}
+
+	task := findTaskByID(store, id)
+	if task == nil {
+		writeAPIError(w, apiErrorf(http.StatusNotFound, "task #%d not found", id))
+		return
+	}
+	writeTask(w, http.StatusOK, *task)
+}
+
+func apiCreateTask(w http.ResponseWriter, r *http.Request) {
+	var input apiTaskInput
+	if err := readJSON(r, &input); err != nil {
+		writeAPIError(w, err)
+		return
+	}
+	if input.Description == nil || strings.TrimSpace(*input.Description) == "" {
+		writeAPIError(w, apiErrorf(http.StatusBadRequest, "description is required"))
+		return
+	}
+
+	var id int
+	store, err := taskdata.UpdateTasks(func(store *taskdata.TaskStore) error {
+		dueDate, priority := "", appConfig.DefaultPriority
+		if input.DueDate != nil {
+			dueDate = *input.DueDate
+		}
+		if input.Priority != nil {
+			priority = *input.Priority
+		}
+
+		task, err := store.AddTask(strings.TrimSpace(*input.Description), dueDate, priority)
+		if err != nil {
+			return apiErrorf(http.StatusBadRequest, "%v", err)
+		}
+
+		stored := findTaskByID(store, task.ID)
+		if err := applyTaskInput(store, stored, input); err != nil {
+			return err
+		}
+		id = task.ID
+		return nil
+	})
+	if err != nil {
+		writeAPIError(w, err)
+		return
+	}
+
+	// Respond with the task as saved, so the ETag covers its ModifiedAt
+	w.Header().Set("Location", fmt.Sprintf("/api/tasks/%d", id))
+	writeTask(w, http.StatusCreated, *findTaskByID(store, id))
+}
+
+func apiUpdateTask(w http.ResponseWriter, r *http.Request) {
+	var input apiTaskInput
+	if err := readJSON(r, &input); err != nil {
+		writeAPIError(w, err)
+		return
+	}
+
+	apiChangeTask(w, r, func(store *taskdata.TaskStore, task *taskdata.Task) error {
+		return applyTaskInput(store, task, input)
+	})
+}
+
+func apiCompleteTask(w http.ResponseWriter, r *http.Request) {
+	apiChangeTask(w, r, func(store *taskdata.TaskStore, task *taskdata.Task) error {
+		if task.Completed {
+			return nil
+		}
+		return updateTaskCompletion(store, task.ID, true)
+	})
+}
+
+func apiDeleteTask(w http.ResponseWriter, r *http.Request) {
+	id, err := apiTaskID(r)
+	if err != nil {
+		writeAPIError(w, err)
+		return
+	}
+
+	_, err = taskdata.UpdateTasks(func(store *taskdata.TaskStore) error {
+		task := findTaskByID(store, id)
+		if task == nil {
+			return apiErrorf(http.StatusNotFound, "task #%d not found", id)
+		}
+		if err := checkIfMatch(r, *task); err != nil {
+			return err
+		}
+		deleteTaskByID(store, id)
+		return nil
+	})
+	if err != nil {
+		writeAPIError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// apiChangeTask applies change to the task named in the URL under the store
+// lock, honouring If-Match, and responds with the updated task
+func apiChangeTask(w http.ResponseWriter, r *http.Request, change func(*taskdata.TaskStore, *taskdata.Task) error) {
+	id, err := apiTaskID(r)
+	if err != nil {
+		writeAPIError(w, err)
+		return
+	}
+
+	store, err := taskdata.UpdateTasks(func(store *taskdata.TaskStore) error {
+		task := findTaskByID(store, id)
+		if task == nil {
+			return apiErrorf(http.StatusNotFound, "task #%d not found", id)
+		}
+		if err := checkIfMatch(r, *task); err != nil {
+			return err
+		}
+		return change(store, task)
+	})
+	if err != nil {
+		writeAPIError(w, err)
+		return
+	}
+	writeTask(w, http.StatusOK, *findTaskByID(store, id))
+}
+
+// applyTaskInput validates the given fields like the CLI does and copies
+// them onto the task
+func applyTaskInput(store *taskdata.TaskStore, task *taskdata.Task, input apiTaskInput) error {
+	changed := *task
+
+	if input.Description != nil {
+		changed.Description = strings.TrimSpace(*input.Description)
+		if changed.Description == "" {
+			return apiErrorf(http.StatusBadRequest, "description cannot be empty")
+		}
+	}
+	if input.DueDate != nil {
+		if err := taskdata.ValidateDate(*input.DueDate); err != nil {
+			return apiErrorf(http.StatusBadRequest, "due_date: %v", err)
+		}
+		changed.DueDate = *input.DueDate
+	}
+	if input.DueTime != nil {
+		if err := taskdata.ValidateTime(*input.DueTime); err != nil {
+			return apiErrorf(http.StatusBadRequest, "due_time: %v", err)
+		}
+		changed.DueTime = *input.DueTime
+	}
+	if changed.DueDate == "" {
+		if input.DueTime != nil && *input.DueTime != "" {
+			return apiErrorf(http.StatusBadRequest, "due_time needs a due_date")
+		}
+		changed.DueTime = ""
+	}
+	if input.Priority != nil {
+		priority, err := taskdata.NormalizePriority(*input.Priority)
+		if err != nil {
+			return apiErrorf(http.StatusBadRequest, "priority: %v", err)
+		}
+		changed.Priority = priority
+	}
+	if input.Notes != nil {
+		changed.Notes = *input.Notes
+	}
+	if input.WaitUntil != nil {
+		if err := taskdata.ValidateDate(*input.WaitUntil); err != nil {
+			return apiErrorf(http.StatusBadRequest, "wait_until: %v", err)
+		}
+		changed.WaitUntil = *input.WaitUntil
+	}
+	if input.Tags != nil {
+		changed.Tags = addTags(nil, strings.Join(*input.Tags, ","))
+	}
+	if input.Project != nil {
+		changed.Project = strings.TrimSpace(*input.Project)
+	}
+	if input.Contexts != nil {
+		changed.Contexts = nil
+		for _, context := range *input.Contexts {
+			context = strings.TrimPrefix(strings.TrimSpace(context), "@")
+			if context != "" && !slices.Contains(changed.Contexts, context) {
+				changed.Contexts = append(changed.Contexts, context)
+			}
+		}
+	}
+	if input.DependsOn != nil {
+		changed.DependsOn = nil
+		for _, id := range *input.DependsOn {
+			if err := validateDependency(store, task.ID, id); err != nil {
+				return apiErrorf(http.StatusBadRequest, "depends_on: %v", err)
+			}
+			changed.DependsOn = addDependency(changed.DependsOn, id)
+		}
+	}
+	if input.Completed != nil && *input.Completed != changed.Completed {
+		changed.Completed = *input.Completed
+		changed.CompletedAt = ""
+		if changed.Completed {
+			changed.CompletedAt = time.Now().Format(taskdata.TimestampFormat)
+		}
+	}
+
+	*task = changed
+	return nil
+}
+
+// taskETag identifies the current contents of a task
+func taskETag(task taskdata.Task) string {
+	data, _ := json.Marshal(task)
+	sum := sha256.Sum256(data)
+	return `"` + hex.EncodeToString(sum[:8]) + `"`
+}
+
+func newAPITask(task taskdata.Task) apiTask {
+	return apiTask{Task: task, ETag: taskETag(task)}
+}
+
+// checkIfMatch refuses a change when the client's ETag is out of date.
+// Requests without If-Match skip the check.
+func checkIfMatch(r *http.Request, task taskdata.Task) error {
+	header := r.Header.Get("If-Match")
+	if header == "" || header == "*" {
+		return nil
+	}
+	current := taskETag(task)
+	for _, etag := range strings.Split(header, ",") {
+		if strings.TrimPrefix(strings.TrimSpace(etag), "W/") == current {
+			return nil
+		}
+	}
+	return apiErrorf(http.StatusPreconditionFailed, "task #%d was modified since it was read (current ETag %s)", task.ID, current)
+}
+
+func apiTaskID(r *http.Request) (int, error) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil || id < 1 {
+		return 0, apiErrorf(http.StatusBadRequest, "invalid task ID '%s'", r.PathValue("id"))
+	}
+	return id, nil
+}
+
+// readJSON decodes a request body, rejecting unknown fields
+func readJSON(r *http.Request, v any) error {
+	decoder := json.NewDecoder(http.MaxBytesReader(nil, r.Body, 1<<20))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		return apiErrorf(http.StatusBadRequest, "invalid JSON body: %v", err)
+	}
+	return nil
+}
+
+func writeTask(w http.ResponseWriter, status int, task taskdata.Task) {
+	result := newAPITask(task)
+	w.Header().Set("ETag", result.ETag)
+	writeJSON(w, status, result)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	encoder.Encode(v)
+}
+
+// writeAPIError reports an error as {"error": "..."}; errors other than
+// apiError are server errors, except for a store changed under the lock
+func writeAPIError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	var apiErr apiError
+	switch {
+	case errors.As(err, &apiErr):
+		status = apiErr.status
+	case errors.Is(err, taskdata.ErrStoreChanged):
+		status = http.StatusConflict
+	}
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
+
+// serverToken returns the token from --token or $TODO_API_TOKEN, or a
+// random one (generated is then true)
+func serverToken(cmd *cobra.Command) (token string, generated bool) {
+	token, _ = cmd.Flags().GetString("token")
+	if token == "" {
+		token = os.Getenv("TODO_API_TOKEN")
+	}
+	if token != "" {
+		return token, false
+	}
+
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b), true
+}
+
+func init() {
+	rootCmd.AddCommand(serveCmd)
+
+	serveCmd.Flags().String("addr", "127.0.0.1:8080", "Address to listen on")
+	serveCmd.Flags().String("token", "", "Bearer token clients must send (default $TODO_API_TOKEN or a random token)")
+	serveCmd.Flags().Bool("no-ui", false, "Serve only the API, without the web UI")
+}
@@ -0,0 +1,270 @@
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"todo/taskdata"
+)
+
+const apiTestToken = "s3cret"
+
+// apiDo sends a request to the API handler backed by a tasks file in a
+// temporary directory, set up by useAPIStore
+func apiDo(t *testing.T, method, path, body string, headers map[string]string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer "+apiTestToken)
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	recorder := httptest.NewRecorder()
+	newAPIHandler(apiTestToken, false).ServeHTTP(recorder, req)
+	return recorder
+}
+
+func useAPIStore(t *testing.T) {
+	t.Helper()
+	taskdata.SetDataFilePath(filepath.Join(t.TempDir(), "tasks.json"))
+	t.Cleanup(func() { taskdata.SetDataFilePath("") })
+}
+
+// decodeAPITask reads a task response
+func decodeAPITask(t *testing.T, recorder *httptest.ResponseRecorder) apiTask {
+	t.Helper()
+	var task apiTask
+	if err := json.Unmarshal(recorder.Body.Bytes(), &task); err != nil {
+		t.Fatalf("response is not a task: %v\n%s", err, recorder.Body)
+	}
+	return task
+}
+
+func TestAPIRequiresToken(t *testing.T) {
+	useAPIStore(t)
+	tests := []struct {
+		name   string
+		header string
+		want   int
+	}{
+		{"no header", "", http.StatusUnauthorized},
+		{"wrong token", "Bearer nope", http.StatusUnauthorized},
+		{"basic auth", "Basic czNjcmV0", http.StatusUnauthorized},
+		{"bearer token", "Bearer " + apiTestToken, http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := apiDo(t, "GET", "/api/tasks", "", map[string]string{"Authorization": tt.header})
+			if recorder.Code != tt.want {
+				t.Errorf("status %d, want %d", recorder.Code, tt.want)
+			}
+		})
+	}
+}
+
+func TestAPITaskLifecycle(t *testing.T) {
+	useAPIStore(t)
+
+	created := apiDo(t, "POST", "/api/tasks", `{"description":" Write report ","due_date":"2025-07-20","priority":"h","tags":["Work"],"contexts":["@office","office"]}`, nil)
+	if created.Code != http.StatusCreated {
+		t.Fatalf("create: %d %s", created.Code, created.Body)
+	}
+	task := decodeAPITask(t, created)
+	if task.Description != "Write report" || task.Priority != "high" || task.DueDate != "2025-07-20" ||
+		!slices.Equal(task.Tags, []string{"work"}) || !slices.Equal(task.Contexts, []string{"office"}) {
+		t.Errorf("created %+v", task.Task)
+	}
+	if created.Header().Get("Location") != "/api/tasks/1" || created.Header().Get("ETag") != task.ETag {
+		t.Errorf("headers %v", created.Header())
+	}
+
+	got := apiDo(t, "GET", "/api/tasks/1", "", nil)
+	if got.Code != http.StatusOK || decodeAPITask(t, got).ETag != task.ETag {
+		t.Errorf("get: %d %s", got.Code, got.Body)
+	}
+
+	updated := apiDo(t, "PATCH", "/api/tasks/1", `{"due_time":"14:30","notes":"Q2 numbers"}`, map[string]string{"If-Match": task.ETag})
+	if updated.Code != http.StatusOK {
+		t.Fatalf("update: %d %s", updated.Code, updated.Body)
+	}
+	if task = decodeAPITask(t, updated); task.DueTime != "14:30" || task.Notes != "Q2 numbers" || task.Priority != "high" {
+		t.Errorf("updated %+v", task.Task)
+	}
+
+	completed := apiDo(t, "POST", "/api/tasks/1/complete", "", nil)
+	if task = decodeAPITask(t, completed); completed.Code != http.StatusOK || !task.Completed || task.CompletedAt == "" {
+		t.Errorf("complete: %d %+v", completed.Code, task.Task)
+	}
+
+	deleted := apiDo(t, "DELETE", "/api/tasks/1", "", nil)
+	if deleted.Code != http.StatusNoContent {
+		t.Errorf("delete: %d %s", deleted.Code, deleted.Body)
+	}
+	if gone := apiDo(t, "GET", "/api/tasks/1", "", nil); gone.Code != http.StatusNotFound {
+		t.Errorf("get after delete: %d", gone.Code)
+	}
+}
+
+func TestAPIRejectsInvalidInput(t *testing.T) {
+	useAPIStore(t)
+	addDAVTestTask(t, "Collect numbers")
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		want   int
+	}{
+		{"not JSON", "POST", "/api/tasks", `description=x`, http.StatusBadRequest},
+		{"unknown field", "POST", "/api/tasks", `{"description":"x","colour":"red"}`, http.StatusBadRequest},
+		{"missing description", "POST", "/api/tasks", `{"priority":"high"}`, http.StatusBadRequest},
+		{"blank description", "POST", "/api/tasks", `{"description":"  "}`, http.StatusBadRequest},
+		{"invalid due date", "POST", "/api/tasks", `{"description":"x","due_date":"tomorrow"}`, http.StatusBadRequest},
+		{"invalid priority", "POST", "/api/tasks", `{"description":"x","priority":"extreme"}`, http.StatusBadRequest},
+		{"due time without due date", "PATCH", "/api/tasks/1", `{"due_time":"10:00"}`, http.StatusBadRequest},
+		{"invalid due time", "PATCH", "/api/tasks/1", `{"due_date":"2025-07-20","due_time":"25:00"}`, http.StatusBadRequest},
+		{"depends on itself", "PATCH", "/api/tasks/1", `{"depends_on":[1]}`, http.StatusBadRequest},
+		{"depends on a missing task", "PATCH", "/api/tasks/1", `{"depends_on":[9]}`, http.StatusBadRequest},
+		{"empty description", "PATCH", "/api/tasks/1", `{"description":""}`, http.StatusBadRequest},
+		{"invalid ID", "GET", "/api/tasks/abc", "", http.StatusBadRequest},
+		{"zero ID", "DELETE", "/api/tasks/0", "", http.StatusBadRequest},
+		{"missing task", "PATCH", "/api/tasks/9", `{"notes":"x"}`, http.StatusNotFound},
+		{"invalid filter", "GET", "/api/tasks?status=someday", "", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := apiDo(t, tt.method, tt.path, tt.body, nil)
+			if recorder.Code != tt.want {
+				t.Fatalf("status %d, want %d: %s", recorder.Code, tt.want, recorder.Body)
+			}
+			var body map[string]string
+			if err := json.Unmarshal(recorder.Body.Bytes(), &body); err != nil || body["error"] == "" {
+				t.Errorf("error body %s", recorder.Body)
+			}
+		})
+	}
+
+	store, err := taskdata.LoadTasks()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(store.Tasks) != 1 || store.Tasks[0].DueTime != "" || store.Tasks[0].DependsOn != nil {
+		t.Errorf("a rejected request changed the tasks: %+v", store.Tasks)
+	}
+}
+
+func TestAPIIfMatch(t *testing.T) {
+	useAPIStore(t)
+	original := newAPITask(addDAVTestTask(t, "Collect numbers")).ETag
+
+	if recorder := apiDo(t, "PATCH", "/api/tasks/1", `{"notes":"first"}`, map[string]string{"If-Match": original}); recorder.Code != http.StatusOK {
+		t.Fatalf("update with the current ETag: %d %s", recorder.Code, recorder.Body)
+	}
+
+	tests := []struct {
+		name    string
+		method  string
+		path    string
+		ifMatch string
+		want    int
+	}{
+		{"stale update", "PATCH", "/api/tasks/1", original, http.StatusPreconditionFailed},
+		{"stale complete", "POST", "/api/tasks/1/complete", original, http.StatusPreconditionFailed},
+		{"stale delete", "DELETE", "/api/tasks/1", original, http.StatusPreconditionFailed},
+		{"one of several, weak", "PATCH", "/api/tasks/1", `"nope", W/` + apiCurrentETag(t), http.StatusOK},
+		{"any version", "PATCH", "/api/tasks/1", "*", http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := apiDo(t, tt.method, tt.path, `{"notes":"second"}`, map[string]string{"If-Match": tt.ifMatch})
+			if recorder.Code != tt.want {
+				t.Errorf("status %d, want %d: %s", recorder.Code, tt.want, recorder.Body)
+			}
+		})
+	}
+}
+
+// apiCurrentETag returns the ETag of task #1 as stored
+func apiCurrentETag(t *testing.T) string {
+	t.Helper()
+	store, err := taskdata.LoadTasks()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return taskETag(*findTaskByID(store, 1))
+}
+
+func TestAPIListTasks(t *testing.T) {
+	useAPIStore(t)
+	for _, body := range []string{
+		`{"description":"Write report","priority":"high","tags":["work"]}`,
+		`{"description":"Buy milk","contexts":["shop"]}`,
+		`{"description":"Call mom","completed":true}`,
+		`{"description":"Plan the work offsite","project":"work"}`,
+	} {
+		if recorder := apiDo(t, "POST", "/api/tasks", body, nil); recorder.Code != http.StatusCreated {
+			t.Fatalf("create: %d %s", recorder.Code, recorder.Body)
+		}
+	}
+
+	tests := []struct {
+		query string
+		want  []int
+	}{
+		{"", []int{1, 2, 3, 4}},
+		{"status=pending", []int{1, 2, 4}},
+		{"status=completed", []int{3}},
+		{"priority=high", []int{1}},
+		{"tag=work", []int{1}},
+		{"context=shop", []int{2}},
+		{"project=work", []int{4}},
+		{"q=work", []int{4}},
+		{"filter=" + url.QueryEscape("status:pending priority:high"), []int{1}},
+		{"status=pending&q=milk", []int{2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			recorder := apiDo(t, "GET", "/api/tasks?"+tt.query, "", nil)
+			if recorder.Code != http.StatusOK {
+				t.Fatalf("status %d: %s", recorder.Code, recorder.Body)
+			}
+			var body struct {
+				Tasks []apiTask `json:"tasks"`
+			}
+			if err := json.Unmarshal(recorder.Body.Bytes(), &body); err != nil {
+				t.Fatal(err)
+			}
+			var ids []int
+			for _, task := range body.Tasks {
+				ids = append(ids, task.ID)
+			}
+			if !slices.Equal(ids, tt.want) {
+				t.Errorf("tasks %v, want %v", ids, tt.want)
+			}
+		})
+	}
+}
+
+func TestAPIListNotModified(t *testing.T) {
+	useAPIStore(t)
+	addDAVTestTask(t, "Collect numbers")
+
+	first := apiDo(t, "GET", "/api/tasks", "", nil)
+	etag := first.Header().Get("ETag")
+	if etag == "" {
+		t.Fatal("the list has no ETag")
+	}
+	if again := apiDo(t, "GET", "/api/tasks", "", map[string]string{"If-None-Match": etag}); again.Code != http.StatusNotModified {
+		t.Errorf("unchanged list: %d", again.Code)
+	}
+
+	addDAVTestTask(t, "Write report")
+	if changed := apiDo(t, "GET", "/api/tasks", "", map[string]string{"If-None-Match": etag}); changed.Code != http.StatusOK {
+		t.Errorf("changed list: %d", changed.Code)
+	}
+}
@@ -0,0 +1,365 @@
+/*
+Copyright © 2025 Smart Todo CLI
+*/
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+	"todo/config"
+	"todo/shell"
+	"todo/taskdata"
+	"todo/tasksync"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// shellCmd represents the shell command
+var shellCmd = &cobra.Command{
+	Use:   "shell",
+	Short: "Run todo commands in an interactive shell",
+	Long: `Start an interactive shell that runs todo commands without the 'todo'
+prefix. Every command works as it does on the command line, with line
+editing, history and tab completion of commands, flags, task IDs and tags.
+
+The tasks are kept in memory while the shell runs and written to disk every
+--autosave interval, on 'save' and on exit; the prompt shows * while there
+are unsaved changes. When another process changes the tasks, the shell
+picks the changes up before the next command, merging them with unsaved
+ones (edits made in the shell win conflicts).
+
+Shell commands:
+  save          Write unsaved changes now
+  exit, quit    Leave the shell (also Ctrl+D)
+
+Examples:
+  todo shell
+  todo shell --autosave 5m
+  todo --list work shell`,
+	Args: cobra.NoArgs,
+	Run:  shellRun,
+}
+
+// shellSession is the running shell's session, nil outside the shell
+var shellSession *taskdata.Session
+
+// shellNotices are messages from the autosave, shown before the next prompt
+var shellNotices struct {
+	sync.Mutex
+	messages []string
+}
+
+// shellExit is raised in place of exiting the program while the shell runs
+type shellExit int
+
+func shellRun(cmd *cobra.Command, args []string) {
+	if shellSession != nil {
+		fmt.Println("⚠️  Already in the todo shell")
+		return
+	}
+	autosave, _ := cmd.Flags().GetDuration("autosave")
+	if autosave <= 0 {
+		fmt.Println("❌ --autosave must be positive")
+		return
+	}
+
+	shellSession = taskdata.StartSession()
+	shellSession.Merge = mergeShellChanges
+	exit = func(code int) { panic(shellExit(code)) }
+	startList, startConfig := listName, cfgFile
+
+	stop := make(chan struct{})
+	go autosaveShell(autosave, stop)
+
+	historyFile := filepath.Join(filepath.Dir(taskdata.ListFilePath(taskdata.DefaultList)), "shell_history")
+	reader := shell.NewReader(shellPrompt(startList), historyFile, completeShell)
+	fmt.Printf("🐚 todo shell: run commands without 'todo', 'exit' to leave. Changes are saved every %s.\n", autosave)
+
+	for {
+		showShellNotices()
+		reader.SetPrompt(shellPrompt(startList))
+		line, err := reader.ReadLine()
+		if errors.Is(err, io.EOF) {
+			fmt.Println()
+			break
+		}
+		if err != nil {
+			fmt.Printf("❌ %v\n", err)
+			break
+		}
+
+		// The tasks may have changed while the line was typed
+		showShellNotices()
+
+		words, err := shell.Split(line)
+		if err != nil {
+			fmt.Printf("❌ %v\n", err)
+			continue
+		}
+		if len(words) > 0 && words[0] == "todo" {
+			words = words[1:]
+		}
+		if len(words) == 0 {
+			continue
+		}
+
+		switch words[0] {
+		case "exit", "quit":
+			close(stop)
+			endShell()
+			return
+		case "save":
+			if err := shellSession.Flush(); err != nil {
+				fmt.Printf("❌ Failed to save: %v\n", err)
+			} else {
+				fmt.Println("💾 Saved")
+			}
+			continue
+		}
+		runShellCommand(words, startList, startConfig)
+	}
+	close(stop)
+	endShell()
+}
+
+// runShellCommand runs one command line as 'todo' would, with every flag
+// back at its default except the --list and --config 'todo shell' was
+// started with
+func runShellCommand(args []string, list, config string) {
+	defer func() {
+		if r := recover(); r != nil {
+			if _, ok := r.(shellExit); !ok {
+				panic(r)
+			}
+		}
+	}()
+
+	resetFlags(rootCmd)
+	listName, cfgFile = list, config
+	addPluginCommands(args)
+	rootCmd.SetArgs(args)
+	// Cobra reports usage errors itself
+	rootCmd.Execute()
+}
+
+// resetFlags sets the flags of cmd and its subcommands that the previous
+// command line changed back to their defaults
+func resetFlags(cmd *cobra.Command) {
+	reset := func(flag *pflag.Flag) {
+		if !flag.Changed {
+			return
+		}
+		if slice, ok := flag.Value.(pflag.SliceValue); ok {
+			defaults := strings.Trim(flag.DefValue, "[]")
+			if defaults == "" {
+				slice.Replace(nil)
+			} else {
+				slice.Replace(strings.Split(defaults, ","))
+			}
+		} else {
+			flag.Value.Set(flag.DefValue)
+		}
+		flag.Changed = false
+	}
+	cmd.Flags().VisitAll(reset)
+	cmd.PersistentFlags().VisitAll(reset)
+	for _, sub := range cmd.Commands() {
+		resetFlags(sub)
+	}
+}
+
+// endShell writes unsaved changes and ends the session
+func endShell() {
+	if err := shellSession.End(); err != nil {
+		fmt.Printf("❌ Failed to save: %v\n", err)
+	}
+	shellSession = nil
+}
+
+// autosaveShell writes unsaved changes every interval until stop closes
+func autosaveShell(interval time.Duration, stop chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			if err := shellSession.Flush(); err != nil {
+				addShellNotice(fmt.Sprintf("❌ Autosave failed: %v", err))
+			}
+		}
+	}
+}
+
+// flushShell writes unsaved changes before other processes read the
+// tasks, such as plugins
+func flushShell() {
+	if shellSession == nil {
+		return
+	}
+	if err := shellSession.Flush(); err != nil {
+		fmt.Printf("⚠️  Failed to save: %v\n", err)
+	}
+}
+
+// mergeShellChanges merges changes saved by other processes with the
+// shell's unsaved ones; the shell's side wins conflicts
+func mergeShellChanges(base, local, disk *taskdata.TaskStore) {
+	result := tasksync.Merge(base, local, disk)
+	for _, conflict := range result.Conflicts {
+		conflict.Choice = tasksync.Local
+	}
+	result.Apply(local)
+	if len(result.Conflicts) > 0 {
+		addShellNotice(fmt.Sprintf("⚔️  Kept this shell's side of %d conflicting change(s)", len(result.Conflicts)))
+	}
+}
+
+func addShellNotice(message string) {
+	shellNotices.Lock()
+	defer shellNotices.Unlock()
+	shellNotices.messages = append(shellNotices.messages, message)
+}
+
+// showShellNotices picks up changes made on disk and prints what happened
+// since the last prompt
+func showShellNotices() {
+	changed, err := shellSession.Refresh()
+	if err != nil {
+		fmt.Printf("⚠️  Failed to reload tasks: %v\n", err)
+	}
+	for _, path := range changed {
+		fmt.Printf("🔄 %s changed on disk; reloaded\n", filepath.Base(path))
+	}
+
+	shellNotices.Lock()
+	messages := shellNotices.messages
+	shellNotices.messages = nil
+	shellNotices.Unlock()
+	for _, message := range messages {
+		fmt.Println(message)
+	}
+}
+
+// shellPrompt shows the list the next command uses, which 'todo use' may
+// have just changed
+func shellPrompt(list string) string {
+	if list == "" {
+		list = taskdata.DefaultList
+		if cfg, err := config.Load(); err == nil && taskdata.ListExists(cfg.CurrentList) {
+			list = cfg.CurrentList
+		}
+	}
+
+	prompt := "todo"
+	if list != taskdata.DefaultList {
+		prompt += ":" + list
+	}
+	if shellSession.Unsaved() {
+		prompt += "*"
+	}
+	return prompt + "> "
+}
+
+// completeShell completes a word of a shell command line with the same
+// completions as 'todo completion' gives the system shell
+func completeShell(words []string, word string) []string {
+	if len(words) > 0 && words[0] == "todo" {
+		words = words[1:]
+	}
+	if len(words) == 0 {
+		return commandNames()
+	}
+
+	cmd, rest, err := rootCmd.Find(words)
+	if err != nil {
+		return nil
+	}
+	if strings.HasPrefix(word, "-") {
+		return flagNames(cmd)
+	}
+
+	var complete cobra.CompletionFunc
+	flag := pendingFlag(cmd, words[len(words)-1])
+	if flag != nil {
+		complete, _ = cmd.GetFlagCompletionFunc(flag.Name)
+		rest = rest[:len(rest)-1]
+	} else if cmd.ValidArgsFunction != nil {
+		complete = cmd.ValidArgsFunction
+	} else {
+		return cmd.ValidArgs
+	}
+	if complete == nil {
+		return nil
+	}
+
+	// Parse the line's flags for completions that depend on them, such as
+	// mark --undone; the next command resets them
+	resetFlags(rootCmd)
+	cmd.ParseFlags(rest)
+	args := cmd.Flags().Args()
+
+	completions, _ := complete(cmd, args, word)
+	var candidates []string
+	for _, completion := range completions {
+		value, _, _ := strings.Cut(completion, "\t")
+		candidates = append(candidates, value)
+	}
+	return candidates
+}
+
+// commandNames are the commands and shell commands to start a line with
+func commandNames() []string {
+	addAllPluginCommands()
+	names := []string{"exit", "quit", "save", "help"}
+	for _, c := range rootCmd.Commands() {
+		if !c.Hidden && c.Name() != "shell" {
+			names = append(names, c.Name())
+		}
+	}
+	return names
+}
+
+// flagNames are the flags of cmd, including the global ones
+func flagNames(cmd *cobra.Command) []string {
+	var names []string
+	add := func(flag *pflag.Flag) {
+		if flag.Hidden {
+			return
+		}
+		names = append(names, "--"+flag.Name)
+		if flag.Shorthand != "" {
+			names = append(names, "-"+flag.Shorthand)
+		}
+	}
+	cmd.NonInheritedFlags().VisitAll(add)
+	cmd.InheritedFlags().VisitAll(add)
+	return names
+}
+
+// pendingFlag returns the flag word names when it still needs its value
+func pendingFlag(cmd *cobra.Command, word string) *pflag.Flag {
+	var flag *pflag.Flag
+	if name, ok := strings.CutPrefix(word, "--"); ok && !strings.Contains(name, "=") {
+		flag = cmd.Flag(name)
+	} else if short, ok := strings.CutPrefix(word, "-"); ok && len(short) == 1 {
+		flag = cmd.Flags().ShorthandLookup(short)
+	}
+	if flag == nil || flag.NoOptDefVal != "" {
+		return nil
+	}
+	return flag
+}
+
+func init() {
+	rootCmd.AddCommand(shellCmd)
+
+	shellCmd.Flags().Duration("autosave", 30*time.Second, "How often to write unsaved changes to disk")
+}
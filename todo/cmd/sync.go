@@ -0,0 +1,242 @@
+/*
+Copyright © 2025 Smart Todo CLI
+*/
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"todo/config"
+	"todo/taskdata"
+	"todo/tasksync"
+
+	"github.com/spf13/cobra"
+)
+
+// syncCmd represents the sync command
+var syncCmd = &cobra.Command{
+	Use:   "sync [folder|repository]",
+	Short: "Merge tasks with a shared folder or git repository",
+	Long: `Merge your tasks with a copy shared with other machines.
+
+The shared copy is a tasks.json in a folder (for example one synced by
+Dropbox or on a network drive) or in a git repository, which can be a bare
+repository on disk or any URL git can push to. Git repositories are
+reached through a clone kept next to your data file.
+
+Tasks are matched by their UID and merged field by field against the state
+of the last sync, so edits to different fields of a task on two machines
+are combined. When both sides changed the same field, or one deleted a task
+the other changed, the conflict is resolved by --strategy:
+
+  ask     Prompt for each conflict (the default)
+  local   Keep this machine's edits
+  remote  Take the shared copy's edits
+  newest  Keep the side whose task was modified last
+
+The location is remembered as sync.remote after the first successful sync,
+and the strategy can be set with 'todo config set sync.strategy'.
+
+Examples:
+  todo sync ~/Dropbox/todo             # Sync with a shared folder
+  todo sync ~/git/tasks.git            # Sync with a bare repository
+  todo sync                            # Sync with the remembered location
+  todo sync --strategy newest          # Resolve conflicts by modification time
+  todo sync --dry-run                  # Show what would change`,
+	Args: cobra.MaximumNArgs(1),
+	Run:  syncRun,
+}
+
+// errSyncCancelled stops a sync without saving anything
+var errSyncCancelled = errors.New("sync cancelled, nothing was changed")
+
+// errSyncChanged stops a sync whose merge is out of date
+var errSyncChanged = errors.New("the tasks changed while the sync was merging them; nothing was changed, run 'todo sync' again")
+
+func syncRun(cmd *cobra.Command, args []string) {
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	strategy, _ := cmd.Flags().GetString("strategy")
+	if !cmd.Flags().Changed("strategy") {
+		strategy = appConfig.Sync.Strategy
+	}
+	switch strategy {
+	case "ask", "local", "remote", "newest":
+	default:
+		fmt.Printf("❌ Unknown strategy '%s'. Use ask, local, remote or newest\n", strategy)
+		return
+	}
+
+	location := appConfig.Sync.Remote
+	if len(args) > 0 {
+		location = args[0]
+	}
+	if location == "" {
+		fmt.Println("❌ No sync location. Pass a folder or repository, e.g. 'todo sync ~/Dropbox/todo'")
+		return
+	}
+	location = expandHome(location)
+
+	shared, err := tasksync.Open(location, syncPath("repo"))
+	if err != nil {
+		fmt.Printf("❌ Sync failed: %v\n", err)
+		return
+	}
+	defer shared.Close()
+
+	fmt.Printf("🔄 Syncing with %s\n", location)
+	fmt.Println(strings.Repeat("=", 50))
+
+	// Merge and settle conflicts before taking the lock, so that asking
+	// about them does not keep other commands or machines waiting
+	local, err := taskdata.LoadTasks()
+	if err != nil {
+		fmt.Printf("❌ Sync failed: %v\n", err)
+		return
+	}
+	remote, err := shared.Pull()
+	if err != nil {
+		fmt.Printf("❌ Sync failed: %v\n", err)
+		return
+	}
+	base, err := taskdata.LoadTasksFrom(syncPath("base.json"))
+	if err != nil {
+		fmt.Printf("❌ Sync failed: %v\n", err)
+		return
+	}
+
+	result := tasksync.Merge(base, local, remote)
+	if err := resolveConflicts(result.Conflicts, strategy); err != nil {
+		fmt.Printf("🚫 %v\n", err)
+		return
+	}
+	fmt.Printf("📥 %d task(s) changed remotely, 📤 %d changed locally, ⚔️  %d conflict(s)\n",
+		result.Incoming, result.Outgoing, len(result.Conflicts))
+	if dryRun {
+		fmt.Println("🔍 Dry run: nothing was saved.")
+		return
+	}
+
+	merged, err := taskdata.UpdateTasks(func(store *taskdata.TaskStore) error {
+		// The merge is only valid for the tasks it was made from
+		if store.Revision() != local.Revision() {
+			return errSyncChanged
+		}
+		result.Apply(store)
+		return shared.Push(store)
+	})
+	if err != nil {
+		fmt.Printf("❌ Sync failed: %v\n", err)
+		return
+	}
+
+	// The merged store is what both sides now share
+	basePath := syncPath("base.json")
+	if err := os.MkdirAll(filepath.Dir(basePath), 0755); err != nil {
+		fmt.Printf("⚠️  Synced, but failed to record the sync state: %v\n", err)
+		return
+	}
+	if err := merged.SaveTasksTo(basePath); err != nil {
+		fmt.Printf("⚠️  Synced, but failed to record the sync state: %v\n", err)
+		return
+	}
+	fmt.Printf("✅ Synced %d task(s)\n", len(merged.Tasks))
+
+	if len(args) > 0 && location != expandHome(appConfig.Sync.Remote) {
+		if err := config.Set("sync.remote", args[0]); err != nil {
+			fmt.Printf("⚠️  Could not remember the sync location: %v\n", err)
+		} else {
+			fmt.Printf("💾 Saved sync.remote = %s\n", args[0])
+		}
+	}
+}
+
+// resolveConflicts sets the choice of every conflict according to strategy
+func resolveConflicts(conflicts []*tasksync.Conflict, strategy string) error {
+	var reader *bufio.Reader
+	for _, conflict := range conflicts {
+		switch strategy {
+		case "local":
+			conflict.Choice = tasksync.Local
+		case "remote":
+			conflict.Choice = tasksync.Remote
+		case "newest":
+			conflict.Choice = conflict.Newest()
+		case "ask":
+			if reader == nil {
+				reader = bufio.NewReader(os.Stdin)
+			}
+			choice, err := askConflict(reader, conflict)
+			if err != nil {
+				return err
+			}
+			conflict.Choice = choice
+			continue
+		}
+		fmt.Printf("  ⚔️  %s → keeping %s\n", conflict, conflict.Choice)
+	}
+	return nil
+}
+
+// askConflict prompts for the side to keep
+func askConflict(reader *bufio.Reader, conflict *tasksync.Conflict) (tasksync.Side, error) {
+	fmt.Printf("\n⚔️  Conflict on %q\n", conflict.Description)
+	if conflict.Field != "" {
+		fmt.Printf("   Field:  %s\n", conflict.Field)
+		if conflict.Base != "" {
+			fmt.Printf("   Before: %s\n", conflict.Base)
+		}
+	}
+	fmt.Printf("   Local:  %s%s\n", conflict.Local, modifiedSuffix(conflict.LocalModified))
+	fmt.Printf("   Remote: %s%s\n", conflict.Remote, modifiedSuffix(conflict.RemoteModified))
+
+	for {
+		fmt.Print("Keep [l]ocal or [r]emote (q to cancel)? ")
+		response, err := reader.ReadString('\n')
+		switch strings.ToLower(strings.TrimSpace(response)) {
+		case "l", "local":
+			return tasksync.Local, nil
+		case "r", "remote":
+			return tasksync.Remote, nil
+		case "q", "quit":
+			return 0, errSyncCancelled
+		}
+		if err != nil {
+			fmt.Println()
+			return 0, errSyncCancelled
+		}
+	}
+}
+
+func modifiedSuffix(modifiedAt string) string {
+	if modifiedAt == "" {
+		return ""
+	}
+	return fmt.Sprintf(" (modified %s)", modifiedAt)
+}
+
+// syncPath returns where sync keeps its state for the current data file,
+// e.g. ~/.todo/tasks.sync/base.json
+func syncPath(name string) string {
+	dataFile := taskdata.GetDataFilePath()
+	return filepath.Join(strings.TrimSuffix(dataFile, ".json")+".sync", name)
+}
+
+// expandHome expands a leading ~ in a path from the config file
+func expandHome(path string) string {
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		if homeDir, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(homeDir, path[1:])
+		}
+	}
+	return path
+}
+
+func init() {
+	rootCmd.AddCommand(syncCmd)
+	syncCmd.Flags().String("strategy", "ask", "How to resolve conflicts: ask, local, remote or newest")
+	syncCmd.Flags().Bool("dry-run", false, "Show what would change without saving")
+}
@@ -0,0 +1,130 @@
+/*
+Copyright © 2025 Smart Todo CLI
+*/
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"todo/taskdata"
+	"todo/tui"
+
+	"github.com/spf13/cobra"
+)
+
+// tuiCmd represents the tui command
+var tuiCmd = &cobra.Command{
+	Use:   "tui",
+	Short: "Full-screen task interface",
+	Long: `Open a keyboard-driven, full-screen interface to the tasks, with a
+filterable list, a detail pane, inline editing and bulk actions. Changes
+other commands or processes make to the tasks show up while it is open.
+
+Keys:
+  j/k, ↑/↓, PgUp/PgDn  Move                  1-5, Tab   Today, week, overdue,
+  g/G                  First/last task                  smart and all tasks
+  /                    Filter (Esc clears)   space      Select the task
+  e or Enter           Edit the description  a          Select all shown tasks
+  d                    Edit the due date     x          Complete or reopen
+  p                    Edit the priority     D          Delete (asks first)
+  r                    Reload                q          Quit
+
+x and D act on the selected tasks, or the task under the cursor when none
+are selected. Hooks run as they do for 'todo mark' and 'todo delete', and
+what they print is shown in the status bar.
+
+Examples:
+  todo tui
+  todo tui --view overdue
+  todo --list work tui`,
+	Args: cobra.NoArgs,
+	Run:  tuiRun,
+}
+
+func tuiRun(cmd *cobra.Command, args []string) {
+	views := tuiViews()
+	app := tui.New(tuiOptions(views))
+
+	name, _ := cmd.Flags().GetString("view")
+	if name != "" {
+		if err := app.SelectView(name); err != nil {
+			fmt.Printf("❌ %v\n", err)
+			return
+		}
+	}
+
+	terminal, err := tui.OpenTTY()
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		return
+	}
+	err = app.Run(terminal)
+	terminal.Close()
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+	}
+}
+
+// tuiOptions connect the interface to the current task list
+func tuiOptions(views []tui.View) tui.Options {
+	return tui.Options{
+		Views:    views,
+		Load:     taskdata.LoadTasks,
+		Save:     saveFromTUI,
+		Revision: taskdata.CurrentRevision,
+	}
+}
+
+// saveFromTUI saves like the other commands, returning what the hooks
+// reported and printed for the status bar instead of printing it over the
+// screen
+func saveFromTUI(store *taskdata.TaskStore) (string, error) {
+	var output bytes.Buffer
+	err := saveTasksReporting(store, &output)
+	if errors.Is(err, taskdata.ErrStoreChanged) {
+		return "Tasks changed on disk; reloaded, try again", err
+	}
+
+	var lines []string
+	for _, line := range strings.Split(output.String(), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, " | "), err
+}
+
+// tuiViews are the views of 'todo list' the interface switches between
+func tuiViews() []tui.View {
+	timeView := func(name, timeFilter string) tui.View {
+		return tui.View{Name: name, Groups: func(store *taskdata.TaskStore, now time.Time) []tui.Group {
+			return []tui.Group{{Tasks: filterTasks(store.Tasks, filterOptions{timeFilter: timeFilter})}}
+		}}
+	}
+
+	return []tui.View{
+		timeView("Today", "today"),
+		timeView("Week", "week"),
+		{Name: "Overdue", Groups: func(store *taskdata.TaskStore, now time.Time) []tui.Group {
+			return []tui.Group{{Tasks: filterTasks(store.Tasks, filterOptions{showOverdue: true})}}
+		}},
+		{Name: "Smart", Groups: func(store *taskdata.TaskStore, now time.Time) []tui.Group {
+			var groups []tui.Group
+			for _, section := range buildSmartView(tasksForCurrentUser(store), now).Sections {
+				groups = append(groups, tui.Group{Title: section.Title, Tasks: section.Tasks})
+			}
+			return groups
+		}},
+		timeView("All", "all"),
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(tuiCmd)
+
+	tuiCmd.Flags().String("view", "", "View to start in (today, week, overdue, smart or all)")
+	tuiCmd.RegisterFlagCompletionFunc("view", cobra.FixedCompletions([]cobra.Completion{"today", "week", "overdue", "smart", "all"}, cobra.ShellCompDirectiveNoFileComp))
+}
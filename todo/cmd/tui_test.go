@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"todo/config"
+	"todo/taskdata"
+	"todo/tui"
+)
+
+func TestTUIShowsHookOutputInStatusBar(t *testing.T) {
+	taskdata.SetDataFilePath(filepath.Join(t.TempDir(), "tasks.json"))
+	t.Cleanup(func() { taskdata.SetDataFilePath("") })
+	t.Cleanup(func() { appConfig = config.Default() })
+	appConfig.Hooks.PreComplete = "echo checking the tracker"
+	appConfig.Hooks.OnComplete = "echo closed the ticket >&2"
+
+	addDAVTestTask(t, "Fix the login bug")
+
+	app := tui.New(tuiOptions(tuiViews()))
+	if err := app.SelectView("all"); err != nil {
+		t.Fatal(err)
+	}
+	term := tui.NewVirtualTerminal(100, 20)
+	term.Type("x")
+	if err := app.Run(term); err != nil {
+		t.Fatal(err)
+	}
+
+	var status string
+	for _, line := range term.Lines() {
+		if line.Style == tui.StyleStatus {
+			status = line.Text
+		}
+	}
+	for _, want := range []string{"checking the tracker", "closed the ticket"} {
+		if !strings.Contains(status, want) {
+			t.Errorf("status bar %q does not show %q", strings.TrimSpace(status), want)
+		}
+	}
+
+	store, err := taskdata.LoadTasks()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !store.Tasks[0].Completed {
+		t.Error("the task was not completed")
+	}
+}
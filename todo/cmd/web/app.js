@@ -0,0 +1,370 @@
+// Smart Todo web UI. Talks to the REST API of 'todo serve' and redraws the
+// current view whenever the server reports that tasks.json changed.
+"use strict";
+
+const state = { token: "", view: "list", priorities: [], defaultPriority: "" };
+
+const $ = (selector, root = document) => root.querySelector(selector);
+
+// The URL printed by 'todo serve' carries the token in its fragment
+function loadToken() {
+  const match = location.hash.match(/token=([^&]+)/);
+  if (match) {
+    localStorage.setItem("todo-token", decodeURIComponent(match[1]));
+    history.replaceState(null, "", location.pathname);
+  }
+  state.token = localStorage.getItem("todo-token") || "";
+  if (!state.token) {
+    state.token = prompt("API token (printed by 'todo serve'):") || "";
+    localStorage.setItem("todo-token", state.token);
+  }
+}
+
+async function api(method, path, body, etag) {
+  const headers = { Authorization: "Bearer " + state.token };
+  if (body !== undefined) headers["Content-Type"] = "application/json";
+  if (etag) headers["If-Match"] = etag;
+
+  const response = await fetch(path, {
+    method,
+    headers,
+    body: body === undefined ? undefined : JSON.stringify(body),
+  });
+  if (response.status === 401) {
+    localStorage.removeItem("todo-token");
+    throw new Error("The token was rejected. Reload the page and enter the token printed by 'todo serve'.");
+  }
+  if (response.status === 204) return null;
+
+  const data = await response.json();
+  if (!response.ok) {
+    const error = new Error(data.error || response.statusText);
+    error.status = response.status;
+    throw error;
+  }
+  return data;
+}
+
+function showError(error) {
+  const box = $("#error");
+  if (!error) {
+    box.hidden = true;
+    return;
+  }
+  box.textContent = "❌ " + error.message;
+  box.hidden = false;
+}
+
+// run performs a change and redraws; a 412 means someone else changed the
+// task first, so the fresh copy is shown instead
+async function run(change) {
+  try {
+    await change();
+    showError(null);
+  } catch (error) {
+    if (error.status === 412) error.message += " — showing the latest version.";
+    showError(error);
+  }
+  render();
+}
+
+// Same markers as the CLI: most important red, least important green
+function priorityIcon(priority) {
+  const rank = state.priorities.indexOf(priority);
+  if (rank < 0) return "";
+  if (rank === 0) return "🔴";
+  if (rank === state.priorities.length - 1) return "🟢";
+  return "🟡";
+}
+
+function today() {
+  const now = new Date();
+  const pad = (n) => String(n).padStart(2, "0");
+  return `${now.getFullYear()}-${pad(now.getMonth() + 1)}-${pad(now.getDate())}`;
+}
+
+function taskElement(task) {
+  const element = $("#task-template").content.firstElementChild.cloneNode(true);
+  element.classList.toggle("completed", task.completed);
+  $(".done", element).checked = task.completed;
+  $(".icon", element).textContent = priorityIcon(task.priority);
+  $(".id", element).textContent = "#" + task.id;
+  $(".description", element).textContent = task.description;
+
+  const meta = [];
+  if (task.due_date) {
+    const overdue = !task.completed && task.due_date < today();
+    meta.push(`<span class="${overdue ? "overdue" : ""}">📅 ${task.due_date}${task.due_time ? " ⏰ " + task.due_time : ""}</span>`);
+  }
+  if (task.wait_until) meta.push(`⏳ ${task.wait_until}`);
+  if (task.project) meta.push(`📁 ${escapeHTML(task.project)}`);
+  for (const tag of task.tags || []) meta.push(`+${escapeHTML(tag)}`);
+  for (const context of task.contexts || []) meta.push(`@${escapeHTML(context)}`);
+  if (task.depends_on && task.depends_on.length) meta.push(`🔗 ${task.depends_on.map((id) => "#" + id).join(", ")}`);
+  $(".meta", element).innerHTML = meta.join(" ");
+  if (task.notes) element.title = task.notes;
+
+  $(".done", element).addEventListener("change", (event) =>
+    run(() => api("PATCH", `/api/tasks/${task.id}`, { completed: event.target.checked }, task.etag)));
+  $(".delete", element).addEventListener("click", () => {
+    if (confirm(`Delete task #${task.id}: ${task.description}?`)) {
+      run(() => api("DELETE", `/api/tasks/${task.id}`, undefined, task.etag));
+    }
+  });
+  $(".edit", element).addEventListener("click", () => element.replaceWith(editorElement(task)));
+  return element;
+}
+
+function escapeHTML(text) {
+  const div = document.createElement("div");
+  div.textContent = text;
+  return div.innerHTML;
+}
+
+function priorityOptions(select, selected, blank) {
+  select.innerHTML = "";
+  if (blank) select.add(new Option(blank, ""));
+  for (const level of state.priorities) {
+    select.add(new Option(priorityIcon(level) + " " + level, level, false, level === selected));
+  }
+}
+
+function editorElement(task) {
+  const form = document.createElement("form");
+  form.className = "editor";
+  form.innerHTML = `
+    <input name="description" required>
+    <input name="due_date" type="date">
+    <input name="due_time" type="time">
+    <select name="priority"></select>
+    <input name="tags" placeholder="tags">
+    <input name="project" placeholder="project">
+    <input name="wait_until" type="date" title="Wait until">
+    <textarea name="notes" rows="2" placeholder="notes"></textarea>
+    <button type="submit">💾 Save</button>
+    <button type="button" class="cancel">Cancel</button>`;
+  form.description.value = task.description;
+  form.due_date.value = task.due_date || "";
+  form.due_time.value = task.due_time || "";
+  priorityOptions(form.priority, task.priority);
+  form.tags.value = (task.tags || []).join(", ");
+  form.project.value = task.project || "";
+  form.wait_until.value = task.wait_until || "";
+  form.notes.value = task.notes || "";
+
+  // A failed save keeps the editor open, unless the task changed meanwhile
+  form.addEventListener("submit", async (event) => {
+    event.preventDefault();
+    try {
+      await api("PATCH", `/api/tasks/${task.id}`, {
+        description: form.description.value,
+        due_date: form.due_date.value,
+        due_time: form.due_date.value ? form.due_time.value : "",
+        priority: form.priority.value,
+        tags: splitList(form.tags.value),
+        project: form.project.value,
+        wait_until: form.wait_until.value,
+        notes: form.notes.value,
+      }, task.etag);
+      form.remove();
+      showError(null);
+    } catch (error) {
+      showError(error);
+      if (error.status !== 412) return;
+      error.message += " — showing the latest version.";
+      showError(error);
+      form.remove();
+    }
+    render();
+  });
+  $(".cancel", form).addEventListener("click", () => {
+    form.remove();
+    render();
+  });
+  return form;
+}
+
+function splitList(value) {
+  return value.split(",").map((s) => s.trim()).filter(Boolean);
+}
+
+function taskGroup(title, tasks) {
+  const fragment = document.createDocumentFragment();
+  const heading = document.createElement("h2");
+  heading.textContent = `${title} (${tasks.length})`;
+  fragment.append(heading);
+  for (const task of tasks) fragment.append(taskElement(task));
+  return fragment;
+}
+
+// renderList mirrors 'todo list': pending tasks first, then completed ones
+async function renderList() {
+  const params = new URLSearchParams();
+  for (const [key, value] of new FormData($("#filters"))) {
+    if (value) params.append(key, value);
+  }
+  const { tasks } = await api("GET", "/api/tasks?" + params);
+  const list = $("#list");
+  list.innerHTML = "";
+
+  if (tasks.length === 0) {
+    list.innerHTML = `<p class="empty">No tasks found.</p>`;
+    return;
+  }
+
+  const byDue = (a, b) => (a.due_date || "9999").localeCompare(b.due_date || "9999") || a.id - b.id;
+  const pending = tasks.filter((t) => !t.completed).sort(byDue);
+  const completed = tasks.filter((t) => t.completed).sort((a, b) => a.id - b.id);
+  if (pending.length) list.append(taskGroup("🔲 Pending Tasks", pending));
+  if (completed.length) list.append(taskGroup("✅ Completed Tasks", completed));
+}
+
+// renderSmart shows the sections of 'todo list --smart'
+async function renderSmart() {
+  const view = await api("GET", "/api/views/smart");
+  const section = $("#smart-view");
+  section.innerHTML = "";
+
+  for (const group of view.sections) {
+    section.append(taskGroup(`${group.icon} ${group.title}`, group.tasks));
+  }
+  if (view.sections.length === 0) {
+    section.innerHTML = `<p class="empty">Nothing needs attention right now. 🎉</p>`;
+  }
+
+  const heading = document.createElement("h2");
+  heading.textContent = "💡 Smart Recommendations";
+  const list = document.createElement("ul");
+  list.className = "recommendations";
+  for (const text of view.recommendations) {
+    const item = document.createElement("li");
+    item.textContent = text;
+    list.append(item);
+  }
+  section.append(heading, list);
+}
+
+// renderStats shows the numbers of 'todo list --stats'
+async function renderStats() {
+  const stats = await api("GET", "/api/views/stats");
+  const percent = (n) => (stats.total ? ((n / stats.total) * 100).toFixed(1) : "0.0") + "%";
+  const cards = (items) => `<div class="stats">${items
+    .map(([label, value]) => `<div class="stat">${label}<b>${value}</b></div>`).join("")}</div>`;
+
+  $("#stats-view").innerHTML = `
+    <h2>📈 Task Overview</h2>
+    ${cards([
+      ["Total Tasks", stats.total],
+      [`Completed (${percent(stats.completed)})`, stats.completed],
+      [`Pending (${percent(stats.pending)})`, stats.pending],
+      ["⚠️ Overdue", stats.overdue],
+      ["⏰ Due Soon", stats.due_soon],
+      ["📝 No Due Date", stats.no_due_date],
+    ])}
+    <h2>🎯 Priority Breakdown</h2>
+    ${cards(stats.priorities.map((p) => [`${priorityIcon(p.priority)} ${escapeHTML(p.priority)}`, p.count]))}
+    <h2>📅 Time-based Analysis</h2>
+    ${cards([
+      ["Due Today", stats.due_today],
+      ["Due This Week", stats.due_this_week],
+      ["Due This Month", stats.due_this_month],
+    ])}`;
+}
+
+async function render() {
+  // Do not throw away an edit in progress
+  if (document.querySelector(".editor")) return;
+  try {
+    if (state.view === "smart") await renderSmart();
+    else if (state.view === "stats") await renderStats();
+    else await renderList();
+  } catch (error) {
+    showError(error);
+  }
+}
+
+function showView(name) {
+  state.view = name;
+  for (const button of document.querySelectorAll("nav button")) {
+    button.classList.toggle("active", button.dataset.view === name);
+  }
+  for (const view of ["list", "smart", "stats"]) {
+    $(`#${view}-view`).hidden = view !== name;
+  }
+  render();
+}
+
+// watchChanges reads the server-sent events stream. fetch is used instead of
+// EventSource because EventSource cannot send the Authorization header.
+async function watchChanges() {
+  const live = $("#live");
+  for (;;) {
+    try {
+      const response = await fetch("/api/events", { headers: { Authorization: "Bearer " + state.token } });
+      if (!response.ok) throw new Error(response.statusText);
+      live.classList.add("on");
+
+      const reader = response.body.pipeThrough(new TextDecoderStream()).getReader();
+      let buffer = "";
+      let first = true;
+      for (;;) {
+        const { value, done } = await reader.read();
+        if (done) break;
+        buffer += value;
+        let end;
+        while ((end = buffer.indexOf("\n\n")) >= 0) {
+          const event = buffer.slice(0, end);
+          buffer = buffer.slice(end + 2);
+          // The first event reports the current revision
+          if (event.includes("event: change") && !first) render();
+          first = false;
+        }
+      }
+    } catch (error) {
+      // Reconnect below
+    }
+    live.classList.remove("on");
+    await new Promise((resolve) => setTimeout(resolve, 3000));
+  }
+}
+
+async function start() {
+  loadToken();
+  try {
+    const meta = await api("GET", "/api/meta");
+    state.priorities = meta.priorities;
+    state.defaultPriority = meta.default_priority;
+    document.title = "Smart Todo — " + meta.data_file;
+  } catch (error) {
+    showError(error);
+    return;
+  }
+
+  priorityOptions($("#add-form").priority, state.defaultPriority);
+  priorityOptions($("#filters").priority, "", "Any priority");
+
+  $("#add-form").addEventListener("submit", (event) => {
+    event.preventDefault();
+    const form = event.target;
+    run(async () => {
+      await api("POST", "/api/tasks", {
+        description: form.description.value,
+        due_date: form.due_date.value,
+        due_time: form.due_date.value ? form.due_time.value : "",
+        priority: form.priority.value,
+        tags: splitList(form.tags.value),
+      });
+      form.reset();
+      form.priority.value = state.defaultPriority;
+    });
+  });
+  $("#filters").addEventListener("input", render);
+  for (const button of document.querySelectorAll("nav button")) {
+    button.addEventListener("click", () => showView(button.dataset.view));
+  }
+
+  showView("list");
+  watchChanges();
+}
+
+start();
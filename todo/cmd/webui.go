@@ -0,0 +1,112 @@
+/*
+Copyright © 2025 Smart Todo CLI
+*/
+package cmd
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"time"
+	"todo/taskdata"
+)
+
+//go:embed web
+var webFiles embed.FS
+
+// webUIHandler serves the single-page web UI embedded in the binary
+func webUIHandler() http.Handler {
+	files, err := fs.Sub(webFiles, "web")
+	if err != nil {
+		panic(err)
+	}
+	return http.FileServerFS(files)
+}
+
+// apiMeta describes the settings the web UI needs
+func apiMeta(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]any{
+		"priorities":       taskdata.Priorities(),
+		"default_priority": appConfig.DefaultPriority,
+		"due_soon_days":    appConfig.DueSoonDays,
+		"data_file":        taskdata.GetDataFilePath(),
+	})
+}
+
+// apiSmartView returns the sections of 'todo list --smart'
+func apiSmartView(w http.ResponseWriter, r *http.Request) {
+	store, err := taskdata.LoadTasks()
+	if err != nil {
+		writeAPIError(w, err)
+		return
+	}
+
+	type section struct {
+		Icon  string    `json:"icon"`
+		Title string    `json:"title"`
+		Tasks []apiTask `json:"tasks"`
+	}
+
+	view := buildSmartView(store, time.Now())
+	sections := []section{}
+	for _, s := range view.Sections {
+		tasks := make([]apiTask, len(s.Tasks))
+		for i, task := range s.Tasks {
+			tasks[i] = newAPITask(task)
+		}
+		sections = append(sections, section{Icon: s.Icon, Title: s.Title, Tasks: tasks})
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"sections":        sections,
+		"recommendations": append([]string{}, view.Recommendations...),
+		"revision":        store.Revision(),
+	})
+}
+
+// apiStatistics returns the numbers behind 'todo list --stats'
+func apiStatistics(w http.ResponseWriter, r *http.Request) {
+	store, err := taskdata.LoadTasks()
+	if err != nil {
+		writeAPIError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, buildStatistics(store, time.Now()))
+}
+
+// storePollInterval is how often the events stream checks tasks.json
+const storePollInterval = time.Second
+
+// apiEvents streams a "change" event whenever tasks.json changes on disk,
+// whether through the API or a CLI command
+func apiEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeAPIError(w, apiErrorf(http.StatusInternalServerError, "streaming is not supported"))
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+
+	ticker := time.NewTicker(storePollInterval)
+	defer ticker.Stop()
+
+	last := ""
+	for {
+		revision, err := taskdata.CurrentRevision()
+		if err == nil && revision != last {
+			last = revision
+			fmt.Fprintf(w, "event: change\ndata: %q\n\n", revision)
+			flusher.Flush()
+		}
+
+		select {
+		case <-r.Context().Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWebUIServedWithoutToken(t *testing.T) {
+	useAPIStore(t)
+	tests := []struct {
+		name        string
+		path        string
+		webUI       bool
+		want        int
+		contentType string
+	}{
+		{"index", "/", true, http.StatusOK, "text/html"},
+		{"script", "/app.js", true, http.StatusOK, "javascript"},
+		{"style", "/style.css", true, http.StatusOK, "text/css"},
+		{"missing file", "/secret.txt", true, http.StatusNotFound, ""},
+		{"disabled", "/", false, http.StatusNotFound, ""},
+		{"API still needs the token", "/api/tasks", true, http.StatusUnauthorized, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			newAPIHandler(apiTestToken, tt.webUI).ServeHTTP(recorder, httptest.NewRequest("GET", tt.path, nil))
+			if recorder.Code != tt.want {
+				t.Fatalf("status %d, want %d", recorder.Code, tt.want)
+			}
+			if got := recorder.Header().Get("Content-Type"); !strings.Contains(got, tt.contentType) {
+				t.Errorf("content type %q, want %s", got, tt.contentType)
+			}
+		})
+	}
+}
+
+func TestWebUIViews(t *testing.T) {
+	useAPIStore(t)
+	addDAVTestTask(t, "Collect numbers")
+	today := time.Now().Format("2006-01-02")
+	if recorder := apiDo(t, "POST", "/api/tasks", `{"description":"Pay rent","priority":"high","due_date":"`+today+`"}`, nil); recorder.Code != http.StatusCreated {
+		t.Fatalf("create: %d %s", recorder.Code, recorder.Body)
+	}
+	apiDo(t, "POST", "/api/tasks/1/complete", "", nil)
+
+	var meta struct {
+		Priorities      []string `json:"priorities"`
+		DefaultPriority string   `json:"default_priority"`
+	}
+	decodeAPIResponse(t, apiDo(t, "GET", "/api/meta", "", nil), &meta)
+	if len(meta.Priorities) != 3 || meta.DefaultPriority != appConfig.DefaultPriority {
+		t.Errorf("meta = %+v", meta)
+	}
+
+	var stats taskStatistics
+	decodeAPIResponse(t, apiDo(t, "GET", "/api/views/stats", "", nil), &stats)
+	if stats.Total != 2 || stats.Completed != 1 || stats.Pending != 1 || stats.DueToday != 1 {
+		t.Errorf("stats = %+v", stats)
+	}
+
+	var smart struct {
+		Sections []struct {
+			Title string    `json:"title"`
+			Tasks []apiTask `json:"tasks"`
+		} `json:"sections"`
+		Recommendations []string `json:"recommendations"`
+		Revision        string   `json:"revision"`
+	}
+	decodeAPIResponse(t, apiDo(t, "GET", "/api/views/smart", "", nil), &smart)
+	found := false
+	for _, section := range smart.Sections {
+		for _, task := range section.Tasks {
+			if task.Completed {
+				t.Errorf("completed task in section %q", section.Title)
+			}
+			found = found || task.Description == "Pay rent" && task.ETag != ""
+		}
+	}
+	if !found || smart.Recommendations == nil || smart.Revision == "" {
+		t.Errorf("smart view = %+v", smart)
+	}
+}
+
+// decodeAPIResponse checks for 200 OK and decodes the JSON body into v
+func decodeAPIResponse(t *testing.T, recorder *httptest.ResponseRecorder, v any) {
+	t.Helper()
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("status %d: %s", recorder.Code, recorder.Body)
+	}
+	if err := json.Unmarshal(recorder.Body.Bytes(), v); err != nil {
+		t.Fatalf("%v\n%s", err, recorder.Body)
+	}
+}
+
+func TestEventsReportChanges(t *testing.T) {
+	useAPIStore(t)
+	addDAVTestTask(t, "Collect numbers")
+	server := httptest.NewServer(newAPIHandler(apiTestToken, false))
+	t.Cleanup(server.Close)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, "GET", server.URL+"/api/events", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Authorization", "Bearer "+apiTestToken)
+	resp, err := server.Client().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.Header.Get("Content-Type") != "text/event-stream" {
+		t.Fatalf("content type %q", resp.Header.Get("Content-Type"))
+	}
+
+	// Each event is an "event: change" line and a data line with the revision
+	lines := bufio.NewScanner(resp.Body)
+	nextRevision := func() string {
+		t.Helper()
+		for lines.Scan() {
+			if data, found := strings.CutPrefix(lines.Text(), "data: "); found {
+				return data
+			}
+		}
+		t.Fatalf("the stream ended: %v", lines.Err())
+		return ""
+	}
+
+	first := nextRevision()
+	addDAVTestTask(t, "Write report")
+	if second := nextRevision(); second == first {
+		t.Errorf("the revision did not change: %s", second)
+	}
+}
@@ -0,0 +1,246 @@
+// Package codescan finds TODO, FIXME and HACK comments in source files.
+package codescan
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"todo/taskdata"
+)
+
+// Comment is one TODO-style comment found in a file
+type Comment struct {
+	File string // Path relative to the repository root, with forward slashes
+	Line int
+	Kind string // TODO, FIXME or HACK
+	Text string // The comment text after the keyword
+
+	// Fingerprint identifies the comment across scans. It depends on the
+	// repository (see RepoID), the file's path in it, the keyword and the
+	// normalized text (as used to spot duplicate tasks), not the line number
+	// or where the repository is checked out, so comments keep their
+	// fingerprint when code above them moves, when a subdirectory is scanned
+	// and in other clones, while the same comment in another repository
+	// gets its own.
+	Fingerprint string
+
+	// PathFingerprint is the fingerprint versions before RepoID gave, for
+	// finding tasks they created
+	PathFingerprint string
+}
+
+// Result is the outcome of a scan
+type Result struct {
+	Repo     string // Absolute path of the repository root, see RepoRoot
+	RepoID   string // Identity of the repository, see RepoID
+	Comments []Comment
+	Errors   []FileError // Files and directories that could not be read
+}
+
+// FileError reports a file or directory that was skipped because it could
+// not be read
+type FileError struct {
+	File string // Path relative to the repository root, with forward slashes
+	Err  error
+}
+
+func (e FileError) Error() string {
+	return fmt.Sprintf("%s: %v", e.File, e.Err)
+}
+
+// Location returns the "file:line" reference for the comment
+func (c Comment) Location() string {
+	return c.File + ":" + strconv.Itoa(c.Line)
+}
+
+// maxFileSize skips generated or minified files
+const maxFileSize = 1 << 20
+
+// commentPattern matches a keyword right after a comment marker in common
+// languages: // # /* * -- ; <!-- %
+var commentPattern = regexp.MustCompile(
+	`(?:^|\s)(?://+|#+|/\*+|\*|--|;+|<!--|%+)\s*(TODO|FIXME|HACK)\b(?:\([^)]*\))?:?\s*(.*?)\s*(?:\*+/|-->)?\s*$`)
+
+// sourceExtensions are the file types that are scanned
+var sourceExtensions = map[string]bool{
+	".go": true, ".c": true, ".h": true, ".cc": true, ".cpp": true, ".hpp": true, ".cs": true,
+	".java": true, ".kt": true, ".scala": true, ".swift": true, ".m": true, ".rs": true,
+	".js": true, ".jsx": true, ".ts": true, ".tsx": true, ".mjs": true, ".vue": true, ".svelte": true,
+	".py": true, ".rb": true, ".pl": true, ".php": true, ".sh": true, ".bash": true, ".zsh": true,
+	".lua": true, ".sql": true, ".hs": true, ".el": true, ".clj": true, ".ex": true, ".exs": true, ".erl": true,
+	".r": true, ".jl": true, ".dart": true, ".css": true, ".scss": true, ".html": true, ".xml": true,
+	".yaml": true, ".yml": true, ".toml": true, ".tf": true, ".tex": true, ".proto": true,
+	".dockerfile": true, ".mk": true,
+}
+
+// sourceNames are scanned regardless of extension
+var sourceNames = map[string]bool{
+	"Makefile": true, "Dockerfile": true, "Rakefile": true, "Gemfile": true, "Jenkinsfile": true,
+}
+
+// skippedDirs are never descended into
+var skippedDirs = map[string]bool{
+	"node_modules": true, "vendor": true, "dist": true, "build": true, "target": true, "__pycache__": true,
+}
+
+// Root turns a Go-style pattern such as "./..." or "src/..." into a directory
+func Root(pattern string) string {
+	root := strings.TrimSuffix(strings.TrimSuffix(pattern, "..."), "/")
+	if root == "" || root == "." {
+		return "."
+	}
+	return root
+}
+
+// RepoRoot returns the top directory of the git repository holding dir,
+// or dir itself when it is not inside one
+func RepoRoot(dir string) string {
+	for current := dir; ; {
+		if _, err := os.Stat(filepath.Join(current, ".git")); err == nil {
+			return current
+		}
+		parent := filepath.Dir(current)
+		if parent == current {
+			return dir
+		}
+		current = parent
+	}
+}
+
+// RepoID identifies the repository at root the same way in every clone:
+// by its first commit, else its origin URL, else (outside git, or in a
+// repository without either) by the root's path
+func RepoID(root string) string {
+	if out, err := git(root, "rev-list", "--max-parents=0", "HEAD"); err == nil && out != "" {
+		commits := strings.Fields(out)
+		sort.Strings(commits)
+		return "commit:" + commits[0]
+	}
+	if out, err := git(root, "config", "--get", "remote.origin.url"); err == nil && out != "" {
+		return "origin:" + out
+	}
+	return "path:" + filepath.ToSlash(root)
+}
+
+// git runs a git command in dir, failing outside a repository or when git
+// is not installed
+func git(dir string, args ...string) (string, error) {
+	if _, err := os.Stat(filepath.Join(dir, ".git")); err != nil {
+		return "", err
+	}
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	out, err := cmd.Output()
+	return strings.TrimSpace(string(out)), err
+}
+
+// Scan walks root and returns every TODO-style comment, in file order.
+// Files that cannot be read are listed in the result's Errors and skipped.
+func Scan(root string) (*Result, error) {
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
+	result := &Result{Repo: RepoRoot(absRoot)}
+	result.RepoID = RepoID(result.Repo)
+
+	err = filepath.WalkDir(absRoot, func(path string, entry fs.DirEntry, err error) error {
+		rel, relErr := filepath.Rel(result.Repo, path)
+		if relErr != nil {
+			rel = path
+		}
+		rel = filepath.ToSlash(rel)
+
+		if err != nil {
+			if path == absRoot {
+				return err
+			}
+			result.Errors = append(result.Errors, FileError{File: rel, Err: err})
+			if entry != nil && entry.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		name := entry.Name()
+		if entry.IsDir() {
+			if path != absRoot && (strings.HasPrefix(name, ".") || skippedDirs[name]) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !entry.Type().IsRegular() || !isSourceFile(name) {
+			return nil
+		}
+
+		found, err := scanFile(path, rel, result.RepoID)
+		if err != nil {
+			result.Errors = append(result.Errors, FileError{File: rel, Err: err})
+			return nil
+		}
+		result.Comments = append(result.Comments, found...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func isSourceFile(name string) bool {
+	return sourceNames[name] || sourceExtensions[strings.ToLower(filepath.Ext(name))]
+}
+
+// scanFile finds the comments in one file
+func scanFile(path, rel, repoID string) ([]Comment, error) {
+	info, err := os.Stat(path)
+	if err != nil || info.Size() > maxFileSize {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	// Binary files
+	if bytes.IndexByte(data[:min(len(data), 8000)], 0) >= 0 {
+		return nil, nil
+	}
+
+	var comments []Comment
+	seen := map[string]int{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 64*1024), maxFileSize)
+
+	line := 0
+	for scanner.Scan() {
+		line++
+		m := commentPattern.FindStringSubmatch(scanner.Text())
+		if m == nil {
+			continue
+		}
+
+		comment := Comment{File: rel, Line: line, Kind: m[1], Text: strings.TrimSpace(m[2])}
+
+		// The same comment twice in a file gets distinct fingerprints
+		key := comment.Kind + ":" + taskdata.NormalizeDescription(comment.Text)
+		occurrence := seen[key]
+		seen[key]++
+		if occurrence > 0 {
+			key += ":" + strconv.Itoa(occurrence)
+		}
+		comment.Fingerprint = taskdata.NameUID("scan:" + repoID + ":" + rel + ":" + key)
+		comment.PathFingerprint = taskdata.NameUID("scan:" + rel + ":" + key)
+
+		comments = append(comments, comment)
+	}
+
+	return comments, scanner.Err()
+}
@@ -0,0 +1,212 @@
+package codescan
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+// writeFiles creates files under dir from a map of relative path to content
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// newRepo creates a git repository with one commit of the given files
+func newRepo(t *testing.T, files map[string]string) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+
+	dir := t.TempDir()
+	writeFiles(t, dir, files)
+	for _, args := range [][]string{
+		{"init", "--quiet"},
+		{"add", "."},
+		{"-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "--quiet", "-m", "Initial commit"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	return dir
+}
+
+func scan(t *testing.T, root string) *Result {
+	t.Helper()
+	result, err := Scan(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return result
+}
+
+func TestCommentPattern(t *testing.T) {
+	tests := []struct {
+		line string
+		kind string
+		text string
+	}{
+		{"// TODO: add tests", "TODO", "add tests"},
+		{"\tx := 1 // FIXME handle overflow", "FIXME", "handle overflow"},
+		{"# HACK(bob): works around the proxy", "HACK", "works around the proxy"},
+		{"/* TODO: close the file */", "TODO", "close the file"},
+		{"-- TODO drop the column", "TODO", "drop the column"},
+		{"<!-- TODO: translate -->", "TODO", "translate"},
+		{"; TODO", "TODO", ""},
+		{`fmt.Println("TODO: not a comment")`, "", ""},
+		{"// TODOS are fine", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			m := commentPattern.FindStringSubmatch(tt.line)
+			if tt.kind == "" {
+				if m != nil {
+					t.Fatalf("matched %q", m)
+				}
+				return
+			}
+			if m == nil || m[1] != tt.kind || m[2] != tt.text {
+				t.Fatalf("got %q, want %s %q", m, tt.kind, tt.text)
+			}
+		})
+	}
+}
+
+func TestScanFindsComments(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"main.go":              "package main\n\n// TODO: add tests\nfunc main() {} // FIXME: exit code\n",
+		"lib/util.py":          "# HACK: retry twice\n",
+		"notes.txt":            "TODO: not source\n",
+		"node_modules/x/a.js":  "// TODO: vendored\n",
+		".hidden/b.go":         "// TODO: hidden\n",
+		"lib/repeated.go":      "// TODO: same\n// TODO: same\n",
+		"lib/nocomment.go":     "package lib\n",
+		"web/page.html":        "<!-- TODO: title -->\n",
+		"scripts/deploy.sh":    "#!/bin/sh\n# TODO: dry run\n",
+		"scripts/Makefile":     "# FIXME: phony targets\n",
+		"lib/binary.go":        "// TODO: binary\x00\n",
+		"lib/deeper/nested.rs": "// TODO: nested\n",
+	})
+
+	result := scan(t, dir)
+	got := map[string]Comment{}
+	for _, c := range result.Comments {
+		got[c.Location()+" "+c.Kind+" "+c.Text] = c
+	}
+	for _, want := range []string{
+		"main.go:3 TODO add tests",
+		"main.go:4 FIXME exit code",
+		"lib/util.py:1 HACK retry twice",
+		"lib/repeated.go:1 TODO same",
+		"lib/repeated.go:2 TODO same",
+		"web/page.html:1 TODO title",
+		"scripts/deploy.sh:2 TODO dry run",
+		"scripts/Makefile:1 FIXME phony targets",
+		"lib/deeper/nested.rs:1 TODO nested",
+	} {
+		if _, ok := got[want]; !ok {
+			t.Errorf("missing %s", want)
+		}
+	}
+	if len(result.Comments) != 9 {
+		t.Errorf("%d comments, want 9: %v", len(result.Comments), got)
+	}
+	if got["lib/repeated.go:1 TODO same"].Fingerprint == got["lib/repeated.go:2 TODO same"].Fingerprint {
+		t.Error("a repeated comment has the same fingerprint twice")
+	}
+}
+
+func TestFingerprintIgnoresLineAndScannedDirectory(t *testing.T) {
+	repo := newRepo(t, map[string]string{"src/main.go": "// TODO: add tests\n"})
+	before := scan(t, repo).Comments[0]
+
+	writeFiles(t, repo, map[string]string{"src/main.go": "package main\n\n// TODO: add  Tests\n"})
+	after := scan(t, filepath.Join(repo, "src")).Comments[0]
+
+	if after.File != "src/main.go" || after.Line != 3 {
+		t.Errorf("comment at %s, want src/main.go:3", after.Location())
+	}
+	if after.Fingerprint != before.Fingerprint {
+		t.Error("the fingerprint changed when the comment moved and a subdirectory was scanned")
+	}
+}
+
+func TestSameCommentInTwoRepositories(t *testing.T) {
+	repoA := newRepo(t, map[string]string{"main.go": "// TODO: add tests\n", "README": "A\n"})
+	repoB := newRepo(t, map[string]string{"main.go": "// TODO: add tests\n", "README": "B\n"})
+
+	a, b := scan(t, repoA), scan(t, repoB)
+	if a.RepoID == b.RepoID {
+		t.Fatalf("both repositories have the identity %s", a.RepoID)
+	}
+	if a.Comments[0].Fingerprint == b.Comments[0].Fingerprint {
+		t.Error("the same comment in two repositories has the same fingerprint")
+	}
+	if a.Comments[0].PathFingerprint != b.Comments[0].PathFingerprint {
+		t.Error("the path fingerprint should only depend on the path and text")
+	}
+}
+
+func TestCloneKeepsFingerprints(t *testing.T) {
+	repo := newRepo(t, map[string]string{"main.go": "// TODO: add tests\n"})
+	clone := filepath.Join(t.TempDir(), "clone")
+	if out, err := exec.Command("git", "clone", "--quiet", repo, clone).CombinedOutput(); err != nil {
+		t.Fatalf("git clone: %v\n%s", err, out)
+	}
+
+	original, cloned := scan(t, repo), scan(t, clone)
+	if original.RepoID != cloned.RepoID {
+		t.Errorf("clone identity %s, want %s", cloned.RepoID, original.RepoID)
+	}
+	if original.Comments[0].Fingerprint != cloned.Comments[0].Fingerprint {
+		t.Error("the clone's comment has another fingerprint")
+	}
+}
+
+func TestRepoIDOutsideGit(t *testing.T) {
+	dir := t.TempDir()
+	if id := RepoID(dir); id != "path:"+filepath.ToSlash(dir) {
+		t.Errorf("RepoID = %s, want the path", id)
+	}
+}
+
+func TestScanReportsUnreadableFiles(t *testing.T) {
+	if os.Getuid() == 0 {
+		t.Skip("root can read every file")
+	}
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{"ok.go": "// TODO: fine\n", "secret.go": "// TODO: hidden\n"})
+	if err := os.Chmod(filepath.Join(dir, "secret.go"), 0); err != nil {
+		t.Fatal(err)
+	}
+
+	result := scan(t, dir)
+	if len(result.Comments) != 1 || len(result.Errors) != 1 || result.Errors[0].File != "secret.go" {
+		t.Errorf("comments %v, errors %v", result.Comments, result.Errors)
+	}
+}
+
+func TestRoot(t *testing.T) {
+	tests := map[string]string{"./...": ".", "...": ".", "src/...": "src", "src": "src", ".": "."}
+	for pattern, want := range tests {
+		if got := Root(pattern); got != want {
+			t.Errorf("Root(%q) = %q, want %q", pattern, got, want)
+		}
+	}
+}
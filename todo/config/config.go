@@ -0,0 +1,400 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"todo/taskdata"
+)
+
+const (
+	configDirName  = "todo"
+	configFileName = "config.toml"
+	reportPrefix   = "report."
+)
+
+// Report is a named, saved combination of filter, sort, columns and format
+type Report struct {
+	Name        string
+	Description string
+	Filter      string   // e.g. "status:pending priority:high due:soon"
+	Sort        string   // e.g. "priority-,due+"
+	Columns     []string // e.g. ["id", "priority", "due", "description"]
+	Format      string   // "list", "table" or "json"
+	Limit       int      // 0 means unlimited
+	BuiltIn     bool
+}
+
+// SmartThresholds tune the smart view and cleanup heuristics
+type SmartThresholds struct {
+	AncientDays        int      // Low priority tasks overdue by this many days are "ancient"
+	LowImpactLimit     int      // Suggest low-impact cleanup once there are more than this many
+	VagueMinLength     int      // Descriptions shorter than this are considered vague
+	VagueKeywords      []string // Words that make a description vague
+	HighPriorityLimit  int      // Warn when more high-priority tasks than this are pending
+	NoDateLimit        int      // Warn when more undated tasks than this are pending
+	CompletedCleanup   int      // Suggest cleanup once more tasks than this are completed
+	UpcomingDays       int      // Window for "upcoming deadlines" in smart analysis
+	SuggestionsPerList int      // How many tasks to show per suggestion group
+}
+
+// MatrixThresholds decide the Eisenhower matrix quadrants
+type MatrixThresholds struct {
+	ImportantPriority string // Tasks at or above this level are important (empty means the highest level)
+	UrgentDays        int    // Tasks overdue or due within this many days are urgent
+}
+
+// ReminderSettings configure 'todo daemon'
+type ReminderSettings struct {
+	Offsets    []string // How long before the due time to remind, e.g. "1d", "1h", "0m"
+	AllDayTime string   // HH:MM at which tasks without a due time are due
+	Notifiers  []string // desktop, bell, command and/or webhook
+	Command    string   // Shell command for the command notifier
+	Webhook    string   // URL for the webhook notifier
+}
+
+// SyncSettings configure 'todo sync'
+type SyncSettings struct {
+	Remote   string // Shared folder or git repository to sync with
+	Strategy string // How conflicting edits are resolved: ask, local, remote or newest
+}
+
+// HookSettings map task events to shell commands or http(s) URLs run
+// before (pre) and after (on) the change is saved, see package hooks
+type HookSettings struct {
+	PreAdd      string
+	PreModify   string
+	PreComplete string
+	PreDelete   string
+	OnAdd       string
+	OnModify    string
+	OnComplete  string
+	OnDelete    string
+}
+
+// PluginSettings configure todo-<name> plugins on PATH
+type PluginSettings struct {
+	Suggest []string // Plugins asked for 'todo delete' suggestions
+}
+
+// Config holds the user's configuration: settings and reports
+type Config struct {
+	DataFile          string   // Empty means ~/.todo/tasks.json
+	CurrentList       string   // List used without --list, set by 'todo use'
+	User              string   // Name used for assignments; empty uses the login name
+	DateFormat        string   // Go time layout used when displaying dates
+	Color             bool     // Colored priority markers
+	DefaultPriority   string   // Priority used by 'todo add' without --priority
+	DefaultListFilter string   // Time filter used by 'todo list' without flags
+	DueSoonDays       int      // Tasks due within this many days are "due soon"
+	WeekStart         string   // "today" or a weekday name
+	WeekDays          int      // Length of the week range in days
+	PriorityLevels    []string // Ordered priority scale, most important first
+	PriorityAliases   []string // "alias=level" pairs, e.g. "urgent=p0"
+	Urgency           taskdata.UrgencyWeights
+	Matrix            MatrixThresholds
+	Smart             SmartThresholds
+	Reminders         ReminderSettings
+	Sync              SyncSettings
+	Hooks             HookSettings
+	Plugins           PluginSettings
+	Reports           map[string]Report
+
+	doc  document // Parsed file contents, used by Set to validate changes
+	text string   // File contents, edited in place by Set and Unset
+}
+
+// builtinReports are always available and can be overridden by the config file
+var builtinReports = []Report{
+	{
+		Name:        "next",
+		Description: "Most important pending tasks to work on next",
+		Filter:      "status:pending",
+		Sort:        "urgency-,due+",
+		Columns:     []string{"id", "priority", "urgency", "due", "description"},
+		Format:      "table",
+		Limit:       10,
+	},
+	{
+		Name:        "waiting",
+		Description: "Tasks deferred with --wait until a later date",
+		Filter:      "status:waiting",
+		Sort:        "wait+,priority-",
+		Columns:     []string{"id", "priority", "wait", "due", "description"},
+		Format:      "table",
+	},
+	{
+		Name:        "overdue",
+		Description: "Pending tasks past their due date",
+		Filter:      "status:pending due:overdue",
+		Sort:        "due+,priority-",
+		Columns:     []string{"id", "priority", "due", "description"},
+		Format:      "table",
+	},
+	{
+		Name:        "recent",
+		Description: "Tasks completed in the last 7 days",
+		Filter:      "status:completed completed:7",
+		Sort:        "completed-",
+		Columns:     []string{"id", "completed", "description"},
+		Format:      "table",
+	},
+}
+
+// pathOverride is set by the --config flag
+var pathOverride string
+
+// SetPath overrides the config file location
+func SetPath(path string) {
+	pathOverride = path
+}
+
+// Path returns the path to the config file (~/.config/todo/config.toml)
+func Path() string {
+	if pathOverride != "" {
+		return pathOverride
+	}
+	if xdg := os.Getenv("XDG_CONFIG_HOME"); xdg != "" {
+		return filepath.Join(xdg, configDirName, configFileName)
+	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		// Fallback to current directory if home directory is not accessible
+		return configFileName
+	}
+	return filepath.Join(homeDir, ".config", configDirName, configFileName)
+}
+
+// Default returns the configuration used when no config file exists
+func Default() *Config {
+	_, noColor := os.LookupEnv("NO_COLOR")
+
+	cfg := &Config{
+		CurrentList:       taskdata.DefaultList,
+		DateFormat:        "2006-01-02",
+		Color:             !noColor,
+		DefaultPriority:   "normal",
+		DefaultListFilter: "today",
+		DueSoonDays:       3,
+		WeekStart:         "today",
+		WeekDays:          7,
+		PriorityLevels:    append([]string(nil), taskdata.DefaultPriorityLevels...),
+		Urgency:           taskdata.DefaultUrgencyWeights(),
+		Matrix:            MatrixThresholds{UrgentDays: 3},
+		Smart: SmartThresholds{
+			AncientDays:        30,
+			LowImpactLimit:     3,
+			VagueMinLength:     10,
+			VagueKeywords:      []string{"stuff", "things", "misc", "todo", "remember", "check", "fix", "update"},
+			HighPriorityLimit:  3,
+			NoDateLimit:        5,
+			CompletedCleanup:   5,
+			UpcomingDays:       7,
+			SuggestionsPerList: 3,
+		},
+		Reminders: ReminderSettings{
+			Offsets:    []string{"1d", "1h", "0m"},
+			AllDayTime: "09:00",
+			Notifiers:  []string{"desktop", "bell"},
+		},
+		Sync:    SyncSettings{Strategy: "ask"},
+		Reports: map[string]Report{},
+		doc:     document{"": {}},
+	}
+
+	for _, report := range builtinReports {
+		report.BuiltIn = true
+		cfg.Reports[report.Name] = report
+	}
+
+	return cfg
+}
+
+// Load reads the config file, returning the defaults if it does not exist
+func Load() (*Config, error) {
+	cfg := Default()
+
+	data, err := os.ReadFile(Path())
+	if os.IsNotExist(err) {
+		return cfg, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %v", err)
+	}
+
+	doc, err := parseTOML(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse config file %s: %v", Path(), err)
+	}
+
+	if err := cfg.apply(doc); err != nil {
+		return nil, fmt.Errorf("invalid config file %s: %v", Path(), err)
+	}
+	cfg.doc = doc
+	cfg.text = string(data)
+
+	return cfg, nil
+}
+
+// apply overlays the values of a parsed document onto the config
+func (cfg *Config) apply(doc document) error {
+	for table, values := range doc {
+		if strings.HasPrefix(table, reportPrefix) {
+			report, err := parseReport(strings.TrimPrefix(table, reportPrefix), values)
+			if err != nil {
+				return err
+			}
+			cfg.Reports[report.Name] = report
+			continue
+		}
+
+		for key, value := range values {
+			name := key
+			if table != "" {
+				name = table + "." + key
+			}
+			s, err := lookupSetting(name)
+			if err != nil {
+				return err
+			}
+			if err := s.assign(cfg, value); err != nil {
+				return fmt.Errorf("%s: %v", name, err)
+			}
+		}
+	}
+
+	// Settings that depend on each other are checked once everything is applied
+	scale, err := cfg.PriorityScale()
+	if err != nil {
+		return fmt.Errorf("priorities: %v", err)
+	}
+	if _, err := scale.Normalize(cfg.DefaultPriority); err != nil {
+		if _, explicit := doc["defaults"]["priority"]; explicit {
+			return fmt.Errorf("defaults.priority: %v", err)
+		}
+		// The built-in default is not on a custom scale: use its middle level
+		levels := scale.Levels()
+		cfg.DefaultPriority = levels[len(levels)/2]
+	}
+	if cfg.Matrix.ImportantPriority != "" {
+		if _, err := scale.Normalize(cfg.Matrix.ImportantPriority); err != nil {
+			return fmt.Errorf("matrix.important_priority: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// PriorityScale builds the configured priority scale
+func (cfg *Config) PriorityScale() (*taskdata.PriorityScale, error) {
+	aliases := map[string]string{}
+	for _, pair := range cfg.PriorityAliases {
+		alias, level, ok := strings.Cut(pair, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid alias '%s' (use alias=level)", pair)
+		}
+		aliases[strings.TrimSpace(alias)] = strings.TrimSpace(level)
+	}
+	return taskdata.NewPriorityScale(cfg.PriorityLevels, aliases)
+}
+
+// Report returns the named report
+func (cfg *Config) Report(name string) (Report, error) {
+	report, exists := cfg.Reports[strings.TrimPrefix(name, "@")]
+	if !exists {
+		return Report{}, fmt.Errorf("unknown report '%s'. Available reports: %s",
+			name, strings.Join(cfg.ReportNames(), ", "))
+	}
+	return report, nil
+}
+
+// ReportNames returns all report names in alphabetical order
+func (cfg *Config) ReportNames() []string {
+	names := make([]string, 0, len(cfg.Reports))
+	for name := range cfg.Reports {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func parseReport(name string, values map[string]any) (Report, error) {
+	if name == "" {
+		return Report{}, fmt.Errorf("report table needs a name, e.g. [report.standup]")
+	}
+
+	report := Report{Name: name, Format: "list"}
+	for key, value := range values {
+		var err error
+		switch key {
+		case "description":
+			report.Description, err = asString(value)
+		case "filter":
+			report.Filter, err = asString(value)
+		case "sort":
+			report.Sort, err = asString(value)
+		case "columns":
+			report.Columns, err = asStrings(value)
+		case "format":
+			report.Format, err = asString(value)
+		case "limit":
+			report.Limit, err = asInt(value)
+		default:
+			err = fmt.Errorf("unknown key")
+		}
+		if err != nil {
+			return Report{}, fmt.Errorf("report '%s' key '%s': %v", name, key, err)
+		}
+	}
+
+	switch report.Format {
+	case "list", "table", "json":
+	default:
+		return Report{}, fmt.Errorf("report '%s': invalid format '%s' (use list, table or json)", name, report.Format)
+	}
+
+	return report, nil
+}
+
+func asString(value any) (string, error) {
+	s, ok := value.(string)
+	if !ok {
+		return "", fmt.Errorf("expected a string")
+	}
+	return s, nil
+}
+
+func asStrings(value any) ([]string, error) {
+	switch v := value.(type) {
+	case string:
+		// Allow a comma-separated string as a shorthand for an array
+		var items []string
+		for _, item := range strings.Split(v, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		return items, nil
+	case []any:
+		items := make([]string, 0, len(v))
+		for _, item := range v {
+			s, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("expected an array of strings")
+			}
+			items = append(items, s)
+		}
+		return items, nil
+	}
+	return nil, fmt.Errorf("expected an array of strings")
+}
+
+func asInt(value any) (int, error) {
+	i, ok := value.(int64)
+	if !ok {
+		return 0, fmt.Errorf("expected an integer")
+	}
+	return int(i), nil
+}
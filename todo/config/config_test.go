@@ -0,0 +1,154 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// useConfigFile points Load at a config file in a temporary directory
+// holding text, or at a missing file when text is empty
+func useConfigFile(t *testing.T, text string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.toml")
+	if text != "" {
+		if err := os.WriteFile(path, []byte(text), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	SetPath(path)
+	t.Cleanup(func() { SetPath("") })
+	return path
+}
+
+func TestLoadReports(t *testing.T) {
+	useConfigFile(t, `
+[report.standup]
+description = "High priority work due soon"
+filter = "status:pending priority:high due:soon"
+sort = "due+,priority-"
+columns = ["id", "priority", "due", "description"]
+format = "table"
+limit = 10
+
+[report.next]   # Overrides the built-in report
+filter = "status:pending tag:work"
+columns = "id, description"
+`)
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	standup, err := cfg.Report("@standup")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Report{
+		Name:        "standup",
+		Description: "High priority work due soon",
+		Filter:      "status:pending priority:high due:soon",
+		Sort:        "due+,priority-",
+		Columns:     []string{"id", "priority", "due", "description"},
+		Format:      "table",
+		Limit:       10,
+	}
+	if !reflect.DeepEqual(standup, want) {
+		t.Errorf("standup = %+v\nwant %+v", standup, want)
+	}
+
+	next, _ := cfg.Report("next")
+	if next.BuiltIn || next.Filter != "status:pending tag:work" || next.Format != "list" || len(next.Columns) != 2 {
+		t.Errorf("next = %+v, want the configured report", next)
+	}
+	if names := cfg.ReportNames(); !reflect.DeepEqual(names, []string{"next", "overdue", "recent", "standup", "waiting"}) {
+		t.Errorf("report names = %v", names)
+	}
+	if _, err := cfg.Report("missing"); err == nil || !strings.Contains(err.Error(), "standup") {
+		t.Errorf("unknown report error = %v, want the available reports", err)
+	}
+}
+
+func TestLoadRejectsInvalidReports(t *testing.T) {
+	tests := map[string]string{
+		"unknown key":    "[report.x]\ncolour = \"red\"\n",
+		"invalid format": "[report.x]\nformat = \"csv\"\n",
+		"wrong type":     "[report.x]\nlimit = \"ten\"\n",
+		"no name":        "[report.]\nfilter = \"status:pending\"\n",
+		"number columns": "[report.x]\ncolumns = [1, 2]\n",
+	}
+	for name, text := range tests {
+		t.Run(name, func(t *testing.T) {
+			useConfigFile(t, text)
+			if _, err := Load(); err == nil {
+				t.Errorf("loaded:\n%s", text)
+			}
+		})
+	}
+}
+
+func TestLoadWithoutFile(t *testing.T) {
+	useConfigFile(t, "")
+	cfg, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"next", "waiting", "overdue", "recent"} {
+		if report, err := cfg.Report(name); err != nil || !report.BuiltIn {
+			t.Errorf("built-in report %s: %+v, %v", name, report, err)
+		}
+	}
+}
+
+func TestParseTOML(t *testing.T) {
+	doc, err := parseTOML(`
+# Comment
+title = "Tasks # not a comment"   # comment
+raw = 'C:\path'
+count = 1_000
+ratio = 0.5
+enabled = true
+list = ["a", 'b,c', 3, false]
+empty = []
+
+[report.standup]
+"quoted key" = "value"
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := document{
+		"": {
+			"title": "Tasks # not a comment", "raw": `C:\path`, "count": int64(1000), "ratio": 0.5,
+			"enabled": true, "list": []any{"a", "b,c", int64(3), false}, "empty": []any(nil),
+		},
+		"report.standup": {"quoted key": "value"},
+	}
+	if !reflect.DeepEqual(doc, want) {
+		t.Errorf("parsed %#v\nwant %#v", doc, want)
+	}
+}
+
+func TestParseTOMLErrors(t *testing.T) {
+	for _, text := range []string{
+		"[table",
+		"[[array.table]]",
+		"[]",
+		"key",
+		"= 1",
+		"key =",
+		`key = "open`,
+		"key = [1, 2",
+		"key = yes",
+	} {
+		if _, err := parseTOML(text); err == nil {
+			t.Errorf("parsed %q", text)
+		} else if !strings.HasPrefix(err.Error(), "line 1:") {
+			t.Errorf("error %q does not name the line", err)
+		}
+	}
+}
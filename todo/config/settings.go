@@ -0,0 +1,437 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"todo/reminder"
+	"todo/taskdata"
+)
+
+// Setting describes a single configurable value
+type Setting struct {
+	Key         string // Dotted key, e.g. "dates.due_soon_days"
+	Description string
+
+	field    func(cfg *Config) any // Pointer to the Config field
+	validate func(value any) error // Optional extra validation
+}
+
+// settings lists every supported key in the order shown by 'todo config list'
+var settings = []Setting{
+	{Key: "data_file", Description: "Path to the tasks data file (empty uses ~/.todo/tasks.json)",
+		field: func(cfg *Config) any { return &cfg.DataFile }},
+	{Key: "current_list", Description: "Task list used when --list is not given (see 'todo lists')",
+		field: func(cfg *Config) any { return &cfg.CurrentList }, validate: validListName},
+	{Key: "user", Description: "Your name for assigning tasks (empty uses the login name)",
+		field: func(cfg *Config) any { return &cfg.User }},
+	{Key: "date_format", Description: "Go time layout used to display dates",
+		field: func(cfg *Config) any { return &cfg.DateFormat }, validate: notEmpty},
+	{Key: "color", Description: "Show colored priority markers",
+		field: func(cfg *Config) any { return &cfg.Color }},
+	{Key: "defaults.priority", Description: "Priority for 'todo add' when --priority is not given",
+		field: func(cfg *Config) any { return &cfg.DefaultPriority }},
+	{Key: "defaults.list_filter", Description: "Time filter for 'todo list' without flags (today, week, month, all)",
+		field: func(cfg *Config) any { return &cfg.DefaultListFilter }, validate: oneOf("today", "week", "month", "all")},
+	{Key: "dates.due_soon_days", Description: "Tasks due within this many days are due soon",
+		field: func(cfg *Config) any { return &cfg.DueSoonDays }, validate: atLeast(0)},
+	{Key: "dates.week_start", Description: "First day of the week range (today, or a weekday such as monday)",
+		field: func(cfg *Config) any { return &cfg.WeekStart }, validate: validWeekStart},
+	{Key: "dates.week_days", Description: "Number of days in the week range",
+		field: func(cfg *Config) any { return &cfg.WeekDays }, validate: atLeast(1)},
+	{Key: "priorities.levels", Description: "Priority scale, most important first (e.g. p0, p1, p2, p3, p4)",
+		field: func(cfg *Config) any { return &cfg.PriorityLevels }},
+	{Key: "priorities.aliases", Description: "Alternative priority names as alias=level pairs (e.g. urgent=p0)",
+		field: func(cfg *Config) any { return &cfg.PriorityAliases }},
+	{Key: "urgency.priority", Description: "Urgency weight of the priority level",
+		field: func(cfg *Config) any { return &cfg.Urgency.Priority }},
+	{Key: "urgency.due", Description: "Urgency weight of due date proximity",
+		field: func(cfg *Config) any { return &cfg.Urgency.Due }},
+	{Key: "urgency.age", Description: "Urgency weight of task age",
+		field: func(cfg *Config) any { return &cfg.Urgency.Age }},
+	{Key: "urgency.tags", Description: "Urgency weight of having tags",
+		field: func(cfg *Config) any { return &cfg.Urgency.Tags }},
+	{Key: "urgency.blocking", Description: "Urgency added when other tasks depend on a task",
+		field: func(cfg *Config) any { return &cfg.Urgency.Blocking }},
+	{Key: "urgency.blocked", Description: "Urgency added when a task depends on pending tasks",
+		field: func(cfg *Config) any { return &cfg.Urgency.Blocked }},
+	{Key: "matrix.important_priority", Description: "Tasks at or above this priority are important (empty uses the highest level)",
+		field: func(cfg *Config) any { return &cfg.Matrix.ImportantPriority }},
+	{Key: "matrix.urgent_days", Description: "Tasks overdue or due within this many days are urgent",
+		field: func(cfg *Config) any { return &cfg.Matrix.UrgentDays }, validate: atLeast(0)},
+	{Key: "smart.ancient_days", Description: "Low priority tasks overdue by this many days are ancient",
+		field: func(cfg *Config) any { return &cfg.Smart.AncientDays }, validate: atLeast(1)},
+	{Key: "smart.low_impact_limit", Description: "Suggest low-impact cleanup when more than this many exist",
+		field: func(cfg *Config) any { return &cfg.Smart.LowImpactLimit }, validate: atLeast(0)},
+	{Key: "smart.vague_min_length", Description: "Descriptions shorter than this are considered vague",
+		field: func(cfg *Config) any { return &cfg.Smart.VagueMinLength }, validate: atLeast(0)},
+	{Key: "smart.vague_keywords", Description: "Words that make a description vague",
+		field: func(cfg *Config) any { return &cfg.Smart.VagueKeywords }},
+	{Key: "smart.high_priority_limit", Description: "Warn when more high-priority tasks than this are pending",
+		field: func(cfg *Config) any { return &cfg.Smart.HighPriorityLimit }, validate: atLeast(0)},
+	{Key: "smart.no_date_limit", Description: "Warn when more tasks without due dates than this are pending",
+		field: func(cfg *Config) any { return &cfg.Smart.NoDateLimit }, validate: atLeast(0)},
+	{Key: "smart.completed_cleanup", Description: "Suggest cleanup when more tasks than this are completed",
+		field: func(cfg *Config) any { return &cfg.Smart.CompletedCleanup }, validate: atLeast(0)},
+	{Key: "smart.upcoming_days", Description: "Window in days for upcoming deadlines",
+		field: func(cfg *Config) any { return &cfg.Smart.UpcomingDays }, validate: atLeast(1)},
+	{Key: "smart.suggestions_per_list", Description: "Tasks shown per suggestion group",
+		field: func(cfg *Config) any { return &cfg.Smart.SuggestionsPerList }, validate: atLeast(1)},
+	{Key: "reminders.offsets", Description: "When 'todo daemon' reminds before a task is due (e.g. 1d, 1h, 0m)",
+		field: func(cfg *Config) any { return &cfg.Reminders.Offsets }, validate: validOffsets},
+	{Key: "reminders.all_day_time", Description: "Time of day (HH:MM) tasks without a due time are due, for reminders",
+		field: func(cfg *Config) any { return &cfg.Reminders.AllDayTime }, validate: validTime},
+	{Key: "reminders.notifiers", Description: "How reminders are delivered (desktop, bell, command, webhook)",
+		field: func(cfg *Config) any { return &cfg.Reminders.Notifiers }, validate: eachOneOf(reminder.Notifiers...)},
+	{Key: "reminders.command", Description: "Shell command run for each reminder by the command notifier",
+		field: func(cfg *Config) any { return &cfg.Reminders.Command }},
+	{Key: "reminders.webhook", Description: "URL the webhook notifier posts reminders to as JSON",
+		field: func(cfg *Config) any { return &cfg.Reminders.Webhook }},
+	{Key: "sync.remote", Description: "Shared folder or git repository used by 'todo sync'",
+		field: func(cfg *Config) any { return &cfg.Sync.Remote }},
+	{Key: "sync.strategy", Description: "How 'todo sync' resolves conflicting edits (ask, local, remote, newest)",
+		field: func(cfg *Config) any { return &cfg.Sync.Strategy }, validate: oneOf("ask", "local", "remote", "newest")},
+	{Key: "hooks.pre-add", Description: "Command or URL that can veto or rewrite a task before it is added",
+		field: func(cfg *Config) any { return &cfg.Hooks.PreAdd }, validate: validHook},
+	{Key: "hooks.pre-modify", Description: "Command or URL that can veto or rewrite a change to a task",
+		field: func(cfg *Config) any { return &cfg.Hooks.PreModify }, validate: validHook},
+	{Key: "hooks.pre-complete", Description: "Command or URL that can veto or rewrite completing a task",
+		field: func(cfg *Config) any { return &cfg.Hooks.PreComplete }, validate: validHook},
+	{Key: "hooks.pre-delete", Description: "Command or URL that can veto deleting a task",
+		field: func(cfg *Config) any { return &cfg.Hooks.PreDelete }, validate: validHook},
+	{Key: "hooks.on-add", Description: "Command or URL run after a task is added",
+		field: func(cfg *Config) any { return &cfg.Hooks.OnAdd }, validate: validHook},
+	{Key: "hooks.on-modify", Description: "Command or URL run after a task is changed",
+		field: func(cfg *Config) any { return &cfg.Hooks.OnModify }, validate: validHook},
+	{Key: "hooks.on-complete", Description: "Command or URL run after a task is completed",
+		field: func(cfg *Config) any { return &cfg.Hooks.OnComplete }, validate: validHook},
+	{Key: "hooks.on-delete", Description: "Command or URL run after a task is deleted",
+		field: func(cfg *Config) any { return &cfg.Hooks.OnDelete }, validate: validHook},
+	{Key: "plugins.suggest", Description: "Plugins (todo-<name> on PATH) asked for 'todo delete' suggestions",
+		field: func(cfg *Config) any { return &cfg.Plugins.Suggest }},
+}
+
+// Settings returns all supported settings
+func Settings() []Setting {
+	return settings
+}
+
+func lookupSetting(key string) (Setting, error) {
+	for _, s := range settings {
+		if s.Key == key {
+			return s, nil
+		}
+	}
+	return Setting{}, fmt.Errorf("unknown config key '%s'. Run 'todo config list' to see all keys", key)
+}
+
+// assign stores a parsed TOML value into the config field for this setting
+func (s Setting) assign(cfg *Config, value any) error {
+	switch field := s.field(cfg).(type) {
+	case *string:
+		v, err := asString(value)
+		if err != nil {
+			return err
+		}
+		if s.validate != nil {
+			if err := s.validate(v); err != nil {
+				return err
+			}
+		}
+		*field = v
+	case *int:
+		v, err := asInt(value)
+		if err != nil {
+			return err
+		}
+		if s.validate != nil {
+			if err := s.validate(v); err != nil {
+				return err
+			}
+		}
+		*field = v
+	case *float64:
+		switch v := value.(type) {
+		case float64:
+			*field = v
+		case int64:
+			*field = float64(v)
+		default:
+			return fmt.Errorf("expected a number")
+		}
+	case *bool:
+		v, ok := value.(bool)
+		if !ok {
+			return fmt.Errorf("expected true or false")
+		}
+		*field = v
+	case *[]string:
+		v, err := asStrings(value)
+		if err != nil {
+			return err
+		}
+		if s.validate != nil {
+			if err := s.validate(v); err != nil {
+				return err
+			}
+		}
+		*field = v
+	}
+	return nil
+}
+
+// parse converts a command-line string into the TOML value type for this setting
+func (s Setting) parse(cfg *Config, raw string) (any, error) {
+	switch s.field(cfg).(type) {
+	case *int:
+		i, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("'%s' is not a number", raw)
+		}
+		return i, nil
+	case *float64:
+		f, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return nil, fmt.Errorf("'%s' is not a number", raw)
+		}
+		return f, nil
+	case *bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, fmt.Errorf("'%s' is not true or false", raw)
+		}
+		return b, nil
+	case *[]string:
+		return splitList(raw), nil
+	}
+	return raw, nil
+}
+
+// Get returns the current value of a key as a string
+func (cfg *Config) Get(key string) (string, error) {
+	if strings.HasPrefix(key, reportPrefix) {
+		table, field := splitKey(key)
+		values, exists := cfg.doc[table]
+		if !exists {
+			return "", fmt.Errorf("report '%s' is not defined in the config file", strings.TrimPrefix(table, reportPrefix))
+		}
+		value, exists := values[field]
+		if !exists {
+			return "", fmt.Errorf("config key '%s' is not set", key)
+		}
+		return formatValue(value), nil
+	}
+
+	s, err := lookupSetting(key)
+	if err != nil {
+		return "", err
+	}
+
+	switch field := s.field(cfg).(type) {
+	case *string:
+		return *field, nil
+	case *int:
+		return strconv.Itoa(*field), nil
+	case *float64:
+		return strconv.FormatFloat(*field, 'f', -1, 64), nil
+	case *bool:
+		return strconv.FormatBool(*field), nil
+	case *[]string:
+		return strings.Join(*field, ", "), nil
+	}
+	return "", nil
+}
+
+// IsSet reports whether a key is set explicitly in the config file
+func (cfg *Config) IsSet(key string) bool {
+	table, field := splitKey(key)
+	_, exists := cfg.doc[table][field]
+	return exists
+}
+
+// Set validates a value and writes it to the config file.
+// Report fields can be set with keys like "report.standup.filter".
+func Set(key, raw string) error {
+	cfg, err := Load()
+	if err != nil {
+		return err
+	}
+
+	table, field := splitKey(key)
+	var value any
+
+	if strings.HasPrefix(key, reportPrefix) {
+		switch field {
+		case "limit":
+			i, err := strconv.ParseInt(raw, 10, 64)
+			if err != nil {
+				return fmt.Errorf("'%s' is not a number", raw)
+			}
+			value = i
+		case "columns":
+			value = splitList(raw)
+		default:
+			value = raw
+		}
+	} else {
+		s, err := lookupSetting(key)
+		if err != nil {
+			return err
+		}
+		if value, err = s.parse(cfg, raw); err != nil {
+			return fmt.Errorf("%s: %v", key, err)
+		}
+	}
+
+	if cfg.doc[table] == nil {
+		cfg.doc[table] = map[string]any{}
+	}
+	cfg.doc[table][field] = value
+	cfg.text = setKey(cfg.text, table, field, value)
+
+	return cfg.save()
+}
+
+// Unset removes a key from the config file so its default applies again
+func Unset(key string) error {
+	cfg, err := Load()
+	if err != nil {
+		return err
+	}
+
+	table, field := splitKey(key)
+	if _, exists := cfg.doc[table][field]; !exists {
+		return fmt.Errorf("config key '%s' is not set", key)
+	}
+	delete(cfg.doc[table], field)
+	if len(cfg.doc[table]) == 0 && table != "" {
+		delete(cfg.doc, table)
+	}
+	cfg.text = unsetKey(cfg.text, table, field)
+
+	return cfg.save()
+}
+
+// save validates the raw document and writes it to the config file
+func (cfg *Config) save() error {
+	// Validate the whole document before touching the file
+	if err := Default().apply(cfg.doc); err != nil {
+		return err
+	}
+
+	filePath := Path()
+	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %v", err)
+	}
+
+	if err := os.WriteFile(filePath, []byte(cfg.text), 0644); err != nil {
+		return fmt.Errorf("failed to write config file: %v", err)
+	}
+
+	return nil
+}
+
+// splitKey splits "a.b.c" into table "a.b" and key "c"
+func splitKey(key string) (string, string) {
+	i := strings.LastIndex(key, ".")
+	if i < 0 {
+		return "", key
+	}
+	return key[:i], key[i+1:]
+}
+
+func splitList(raw string) []any {
+	items := []any{}
+	for _, item := range strings.Split(raw, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func formatValue(value any) string {
+	if items, ok := value.([]any); ok {
+		parts := make([]string, len(items))
+		for i, item := range items {
+			parts[i] = fmt.Sprint(item)
+		}
+		return strings.Join(parts, ", ")
+	}
+	return fmt.Sprint(value)
+}
+
+// Validators
+
+func notEmpty(value any) error {
+	if value == "" {
+		return fmt.Errorf("value cannot be empty")
+	}
+	return nil
+}
+
+func oneOf(allowed ...string) func(any) error {
+	return func(value any) error {
+		for _, a := range allowed {
+			if value == a {
+				return nil
+			}
+		}
+		return fmt.Errorf("invalid value '%v' (use %s)", value, strings.Join(allowed, ", "))
+	}
+}
+
+func atLeast(minimum int) func(any) error {
+	return func(value any) error {
+		if value.(int) < minimum {
+			return fmt.Errorf("value must be at least %d", minimum)
+		}
+		return nil
+	}
+}
+
+func eachOneOf(allowed ...string) func(any) error {
+	return func(value any) error {
+		for _, v := range value.([]string) {
+			if err := oneOf(allowed...)(v); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+func validOffsets(value any) error {
+	for _, offset := range value.([]string) {
+		if _, err := reminder.ParseOffset(offset); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validTime(value any) error {
+	clock, _ := value.(string)
+	return taskdata.ValidateTime(clock)
+}
+
+func validHook(value any) error {
+	hook, _ := value.(string)
+	if scheme, _, found := strings.Cut(hook, "://"); found && !strings.ContainsAny(scheme, " \t") {
+		if scheme != "http" && scheme != "https" {
+			return fmt.Errorf("hook URLs must use http or https, not '%s'", scheme)
+		}
+	}
+	return nil
+}
+
+func validListName(value any) error {
+	name, _ := value.(string)
+	return taskdata.ValidateListName(name)
+}
+
+func validWeekStart(value any) error {
+	return oneOf("today", "sunday", "monday", "tuesday", "wednesday", "thursday", "friday", "saturday")(value)
+}
@@ -0,0 +1,232 @@
+package config
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestSetAndGet(t *testing.T) {
+	tests := []struct {
+		key  string
+		raw  string
+		want string
+	}{
+		{"dates.due_soon_days", "5", "5"},
+		{"dates.week_start", "monday", "monday"},
+		{"color", "false", "false"},
+		{"urgency.due", "2.5", "2.5"},
+		{"smart.vague_keywords", "stuff, things ,", "stuff, things"},
+		{"date_format", "02.01.2006", "02.01.2006"},
+		{"report.standup.limit", "5", "5"},
+		{"report.standup.columns", "id, description", "id, description"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			useConfigFile(t, "")
+			if err := Set(tt.key, tt.raw); err != nil {
+				t.Fatal(err)
+			}
+			cfg, err := Load()
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := cfg.Get(tt.key)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("Get = %q, want %q", got, tt.want)
+			}
+			if !cfg.IsSet(tt.key) {
+				t.Error("the key is not set in the file")
+			}
+		})
+	}
+}
+
+func TestSetRejectsInvalidValues(t *testing.T) {
+	tests := []struct {
+		key string
+		raw string
+	}{
+		{"no_such_key", "1"},
+		{"dates.due_soon_days", "soon"},
+		{"dates.due_soon_days", "-1"},
+		{"dates.week_days", "0"},
+		{"dates.week_start", "someday"},
+		{"date_format", ""},
+		{"color", "maybe"},
+		{"defaults.list_filter", "year"},
+		{"defaults.priority", "extreme"},
+		{"reminders.offsets", "1d, soon"},
+		{"reminders.all_day_time", "25:00"},
+		{"reminders.notifiers", "desktop, pigeon"},
+		{"hooks.pre-add", "ftp://example.com/hook"},
+		{"sync.strategy", "coin"},
+		{"report.standup.limit", "ten"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key+"="+tt.raw, func(t *testing.T) {
+			path := useConfigFile(t, "")
+			if err := Set(tt.key, tt.raw); err == nil {
+				t.Fatal("the value was accepted")
+			}
+			if _, err := os.Stat(path); !os.IsNotExist(err) {
+				t.Error("the config file was written")
+			}
+		})
+	}
+}
+
+func TestDefaultPriorityFollowsTheScale(t *testing.T) {
+	useConfigFile(t, "[priorities]\nlevels = [\"p0\", \"p1\", \"p2\"]\n")
+	if err := Set("defaults.priority", "p1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := Set("defaults.priority", "p3"); err == nil {
+		t.Error("a priority outside the configured scale was accepted")
+	}
+}
+
+func TestUnset(t *testing.T) {
+	useConfigFile(t, "[dates]\ndue_soon_days = 7\n")
+	if err := Unset("dates.due_soon_days"); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.DueSoonDays != Default().DueSoonDays || cfg.IsSet("dates.due_soon_days") {
+		t.Errorf("due_soon_days = %d after unset", cfg.DueSoonDays)
+	}
+	if err := Unset("dates.due_soon_days"); err == nil {
+		t.Error("unsetting a key that is not set succeeded")
+	}
+}
+
+func TestSetKey(t *testing.T) {
+	tests := []struct {
+		name  string
+		text  string
+		table string
+		key   string
+		value any
+		want  string
+	}{
+		{
+			name:  "empty file",
+			table: "dates", key: "week_days", value: int64(5),
+			want: "[dates]\nweek_days = 5\n",
+		},
+		{
+			name: "root key goes first",
+			text: "[dates]\nweek_days = 5\n",
+			key:  "color", value: false,
+			want: "color = false\n[dates]\nweek_days = 5\n",
+		},
+		{
+			name:  "replacing keeps the comment and indent",
+			text:  "# My settings\n[dates]\n  week_days = 7   # a full week\n",
+			table: "dates", key: "week_days", value: int64(5),
+			want: "# My settings\n[dates]\n  week_days = 5   # a full week\n",
+		},
+		{
+			name:  "new key goes after the table's last key",
+			text:  "[dates]\nweek_days = 7\n\n# Smart suggestions\n[smart]\nupcoming_days = 3\n",
+			table: "dates", key: "week_start", value: "monday",
+			want: "[dates]\nweek_days = 7\nweek_start = \"monday\"\n\n# Smart suggestions\n[smart]\nupcoming_days = 3\n",
+		},
+		{
+			name:  "new table goes at the end",
+			text:  "color = true\n",
+			table: "smart", key: "vague_keywords", value: []any{"stuff", "things"},
+			want: "color = true\n\n[smart]\nvague_keywords = [\"stuff\", \"things\"]\n",
+		},
+		{
+			name:  "quoted keys are found",
+			text:  "[hooks]\n\"pre-add\" = \"old\"\n",
+			table: "hooks", key: "pre-add", value: "new",
+			want: "[hooks]\npre-add = \"new\"\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := setKey(tt.text, tt.table, tt.key, tt.value)
+			if got != tt.want {
+				t.Errorf("got\n%s\nwant\n%s", got, tt.want)
+			}
+			if _, err := parseTOML(got); err != nil {
+				t.Errorf("the result does not parse: %v", err)
+			}
+		})
+	}
+}
+
+func TestUnsetKey(t *testing.T) {
+	tests := []struct {
+		name  string
+		text  string
+		table string
+		key   string
+		want  string
+	}{
+		{
+			name:  "other keys and comments stay",
+			text:  "[dates]\n# Work week\nweek_days = 5\nweek_start = \"monday\"\n",
+			table: "dates", key: "week_days",
+			want: "[dates]\n# Work week\nweek_start = \"monday\"\n",
+		},
+		{
+			name:  "an empty table is removed",
+			text:  "color = true\n\n[dates]\nweek_days = 5\n\n[smart]\nupcoming_days = 3\n",
+			table: "dates", key: "week_days",
+			want: "color = true\n\n[smart]\nupcoming_days = 3\n",
+		},
+		{
+			name:  "a missing key leaves the text alone",
+			text:  "[dates]\nweek_days = 5\n",
+			table: "smart", key: "upcoming_days",
+			want: "[dates]\nweek_days = 5\n",
+		},
+		{
+			name:  "the last table takes its blank line along",
+			text:  "color = true\n\n[dates]\nweek_days = 5\n",
+			table: "dates", key: "week_days",
+			want: "color = true\n",
+		},
+		{
+			name: "root key",
+			text: "color = true\ndate_format = \"2006-01-02\"\n",
+			key:  "color",
+			want: "date_format = \"2006-01-02\"\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := unsetKey(tt.text, tt.table, tt.key); got != tt.want {
+				t.Errorf("got\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetKeepsTheRestOfTheFile(t *testing.T) {
+	text := "# Personal settings\ncolor = true # keep it\n\n[dates]\nweek_days = 7\n"
+	path := useConfigFile(t, text)
+	if err := Set("dates.week_days", "5"); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := strings.Replace(text, "week_days = 7", "week_days = 5", 1); string(data) != want {
+		t.Errorf("file is\n%s\nwant\n%s", data, want)
+	}
+}
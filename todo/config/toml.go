@@ -0,0 +1,333 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// document holds a parsed TOML file as table name -> key -> value.
+// The root table is stored under the empty name. Values are string,
+// int64, float64, bool or []any.
+type document map[string]map[string]any
+
+// parseTOML parses the subset of TOML used by the config file:
+// [tables], [dotted.tables], key = value pairs with strings, integers,
+// floats, booleans and single-line arrays, and # comments.
+func parseTOML(data string) (document, error) {
+	doc := document{"": {}}
+	table := ""
+
+	for n, line := range strings.Split(data, "\n") {
+		lineNum := n + 1
+		line = strings.TrimSpace(stripComment(line))
+		if line == "" {
+			continue
+		}
+
+		// Table header
+		if strings.HasPrefix(line, "[") {
+			if !strings.HasSuffix(line, "]") || strings.HasPrefix(line, "[[") {
+				return nil, fmt.Errorf("line %d: invalid table header '%s'", lineNum, line)
+			}
+			table = strings.TrimSpace(line[1 : len(line)-1])
+			if table == "" {
+				return nil, fmt.Errorf("line %d: empty table name", lineNum)
+			}
+			if _, exists := doc[table]; !exists {
+				doc[table] = map[string]any{}
+			}
+			continue
+		}
+
+		// Key/value pair
+		eq := strings.Index(line, "=")
+		if eq < 0 {
+			return nil, fmt.Errorf("line %d: expected key = value", lineNum)
+		}
+		key := unquoteKey(strings.TrimSpace(line[:eq]))
+		if key == "" {
+			return nil, fmt.Errorf("line %d: missing key", lineNum)
+		}
+
+		value, err := parseValue(strings.TrimSpace(line[eq+1:]))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", lineNum, err)
+		}
+		doc[table][key] = value
+	}
+
+	return doc, nil
+}
+
+// stripComment removes a trailing # comment that is not inside a string
+func stripComment(line string) string {
+	inString := byte(0)
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case inString != 0 && c == '\\' && inString == '"':
+			i++
+		case inString != 0 && c == inString:
+			inString = 0
+		case inString == 0 && (c == '"' || c == '\''):
+			inString = c
+		case inString == 0 && c == '#':
+			return line[:i]
+		}
+	}
+	return line
+}
+
+func unquoteKey(key string) string {
+	if len(key) >= 2 && (key[0] == '"' || key[0] == '\'') && key[len(key)-1] == key[0] {
+		return key[1 : len(key)-1]
+	}
+	return key
+}
+
+func parseValue(raw string) (any, error) {
+	switch {
+	case raw == "":
+		return nil, fmt.Errorf("missing value")
+	case raw == "true":
+		return true, nil
+	case raw == "false":
+		return false, nil
+	case strings.HasPrefix(raw, `"`):
+		if len(raw) < 2 || !strings.HasSuffix(raw, `"`) {
+			return nil, fmt.Errorf("unterminated string %s", raw)
+		}
+		s, err := strconv.Unquote(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid string %s", raw)
+		}
+		return s, nil
+	case strings.HasPrefix(raw, "'"):
+		if len(raw) < 2 || !strings.HasSuffix(raw, "'") {
+			return nil, fmt.Errorf("unterminated string %s", raw)
+		}
+		return raw[1 : len(raw)-1], nil
+	case strings.HasPrefix(raw, "["):
+		if !strings.HasSuffix(raw, "]") {
+			return nil, fmt.Errorf("arrays must be on a single line")
+		}
+		return parseArray(raw[1 : len(raw)-1])
+	}
+
+	if i, err := strconv.ParseInt(strings.ReplaceAll(raw, "_", ""), 10, 64); err == nil {
+		return i, nil
+	}
+	if f, err := strconv.ParseFloat(strings.ReplaceAll(raw, "_", ""), 64); err == nil {
+		return f, nil
+	}
+
+	return nil, fmt.Errorf("unsupported value '%s'", raw)
+}
+
+func parseArray(inner string) ([]any, error) {
+	var items []any
+	var current strings.Builder
+	inString := byte(0)
+
+	flush := func() error {
+		item := strings.TrimSpace(current.String())
+		current.Reset()
+		if item == "" {
+			return nil
+		}
+		value, err := parseValue(item)
+		if err != nil {
+			return err
+		}
+		items = append(items, value)
+		return nil
+	}
+
+	for i := 0; i < len(inner); i++ {
+		c := inner[i]
+		switch {
+		case inString != 0 && c == '\\' && inString == '"' && i+1 < len(inner):
+			current.WriteByte(c)
+			i++
+			c = inner[i]
+		case inString != 0 && c == inString:
+			inString = 0
+		case inString == 0 && (c == '"' || c == '\''):
+			inString = c
+		case inString == 0 && c == ',':
+			if err := flush(); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		current.WriteByte(c)
+	}
+	if err := flush(); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+// setKey sets key in table of a TOML file's text, rewriting only the line
+// that holds the key so comments and the layout of the rest of the file
+// survive. A new key goes at the end of its table, which is added to the
+// end of the file when it does not exist yet.
+func setKey(text, table, key string, value any) string {
+	lines := splitLines(text)
+	line := encodeKey(key) + " = " + encodeValue(value)
+
+	start, end, found := findTable(lines, table)
+	if !found {
+		if table == "" {
+			return joinLines(append([]string{line}, lines...))
+		}
+		if len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) != "" {
+			lines = append(lines, "")
+		}
+		return joinLines(append(lines, "["+table+"]", line))
+	}
+
+	if i := findKey(lines, start, end, key); i >= 0 {
+		old := lines[i]
+		indent := old[:len(old)-len(strings.TrimLeft(old, " \t"))]
+		code := stripComment(old)
+		gap := code[len(strings.TrimRight(code, " \t")):]
+		lines[i] = indent + line + gap + old[len(code):]
+		return joinLines(lines)
+	}
+
+	// Insert after the table's last key so trailing blank lines and the
+	// comments introducing the next table stay where they are
+	at := start
+	for i := start; i < end; i++ {
+		if strings.TrimSpace(stripComment(lines[i])) != "" {
+			at = i + 1
+		}
+	}
+	lines = append(lines[:at], append([]string{line}, lines[at:]...)...)
+	return joinLines(lines)
+}
+
+// unsetKey removes key from table of a TOML file's text, along with the
+// table's header once nothing is left in it
+func unsetKey(text, table, key string) string {
+	lines := splitLines(text)
+	start, end, found := findTable(lines, table)
+	if !found {
+		return text
+	}
+	i := findKey(lines, start, end, key)
+	if i < 0 {
+		return text
+	}
+	lines = append(lines[:i], lines[i+1:]...)
+	end--
+
+	// Drop a table left with nothing but blank lines. Its trailing blank
+	// lines separate the tables around it, so only the last table also
+	// takes the blank line before its header.
+	if table != "" && isBlank(lines[start:end]) {
+		from := start - 1
+		if end == len(lines) && from > 0 && isBlank(lines[from-1:from]) {
+			from--
+		}
+		lines = append(lines[:from], lines[end:]...)
+	}
+	return joinLines(lines)
+}
+
+// findTable returns the range of lines holding the keys of table: from
+// the line after its header up to the next header. The root table starts
+// at the top of the file.
+func findTable(lines []string, table string) (start, end int, found bool) {
+	start, found = 0, table == ""
+	for i, line := range lines {
+		line = strings.TrimSpace(stripComment(line))
+		if !strings.HasPrefix(line, "[") || !strings.HasSuffix(line, "]") {
+			continue
+		}
+		if found {
+			return start, i, true
+		}
+		if strings.TrimSpace(line[1:len(line)-1]) == table {
+			start, found = i+1, true
+		}
+	}
+	return start, len(lines), found
+}
+
+// findKey returns the line between start and end that sets key, or -1
+func findKey(lines []string, start, end int, key string) int {
+	for i := start; i < end; i++ {
+		line := strings.TrimSpace(stripComment(lines[i]))
+		eq := strings.Index(line, "=")
+		if eq > 0 && unquoteKey(strings.TrimSpace(line[:eq])) == key {
+			return i
+		}
+	}
+	return -1
+}
+
+// isBlank reports whether lines hold no keys, headers or comments
+func isBlank(lines []string) bool {
+	for _, line := range lines {
+		if strings.TrimSpace(line) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+func splitLines(text string) []string {
+	text = strings.TrimSuffix(text, "\n")
+	if text == "" {
+		return nil
+	}
+	return strings.Split(text, "\n")
+}
+
+func joinLines(lines []string) string {
+	if len(lines) == 0 {
+		return ""
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+func encodeKey(key string) string {
+	for _, r := range key {
+		if !(r == '_' || r == '-' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9') {
+			return strconv.Quote(key)
+		}
+	}
+	return key
+}
+
+func encodeValue(value any) string {
+	switch v := value.(type) {
+	case string:
+		return strconv.Quote(v)
+	case bool:
+		return strconv.FormatBool(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case int:
+		return strconv.Itoa(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case []any:
+		parts := make([]string, len(v))
+		for i, item := range v {
+			parts[i] = encodeValue(item)
+		}
+		return "[" + strings.Join(parts, ", ") + "]"
+	case []string:
+		parts := make([]string, len(v))
+		for i, item := range v {
+			parts[i] = strconv.Quote(item)
+		}
+		return "[" + strings.Join(parts, ", ") + "]"
+	}
+	return strconv.Quote(fmt.Sprint(value))
+}
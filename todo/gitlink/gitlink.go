@@ -0,0 +1,166 @@
+// Package gitlink links tasks to git commits whose messages reference them.
+package gitlink
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Commit is a git commit that referenced a task
+type Commit struct {
+	Hash    string `json:"hash"`
+	Subject string `json:"subject"`
+	Author  string `json:"author,omitempty"`
+	Date    string `json:"date,omitempty"` // RFC 3339 author date
+	Repo    string `json:"repo,omitempty"` // Top-level directory of the repository
+}
+
+// Short returns the abbreviated commit hash
+func (c Commit) Short() string {
+	return c.Hash[:min(len(c.Hash), 8)]
+}
+
+var (
+	// "closes todo#12", "fixed todo#3", "resolves todo#7"
+	closingPattern = regexp.MustCompile(`(?i)\b(?:close[sd]?|fix(?:e[sd])?|resolve[sd]?)\s+todo#(\d+)\b`)
+	// "todo: #12" or "todo: #12, #13"
+	listPattern = regexp.MustCompile(`(?i)\btodo:\s*((?:#\d+\b[\s,]*)+)`)
+	idPattern   = regexp.MustCompile(`#(\d+)`)
+)
+
+// References returns the task IDs a commit message closes, in order of
+// appearance and without duplicates. Comment lines starting with '#', as in
+// the message file given to the commit-msg hook, are ignored.
+func References(message string) []int {
+	var lines []string
+	for _, line := range strings.Split(message, "\n") {
+		if !strings.HasPrefix(line, "#") {
+			lines = append(lines, line)
+		}
+	}
+	message = strings.Join(lines, "\n")
+
+	type match struct{ pos, id int }
+	var matches []match
+	for _, m := range closingPattern.FindAllStringSubmatchIndex(message, -1) {
+		id, _ := strconv.Atoi(message[m[2]:m[3]])
+		matches = append(matches, match{m[0], id})
+	}
+	for _, m := range listPattern.FindAllStringSubmatchIndex(message, -1) {
+		list := message[m[2]:m[3]]
+		for _, idMatch := range idPattern.FindAllStringSubmatchIndex(list, -1) {
+			id, _ := strconv.Atoi(list[idMatch[2]:idMatch[3]])
+			matches = append(matches, match{m[2] + idMatch[0], id})
+		}
+	}
+	slices.SortFunc(matches, func(a, b match) int { return a.pos - b.pos })
+
+	var ids []int
+	for _, m := range matches {
+		if m.id > 0 && !slices.Contains(ids, m.id) {
+			ids = append(ids, m.id)
+		}
+	}
+	return ids
+}
+
+// HeadCommit reads the commit at HEAD of the repository containing dir
+func HeadCommit(dir string) (Commit, string, error) {
+	out, err := git(dir, "log", "-1", "--format=%H%x00%an%x00%aI%x00%s%x00%B")
+	if err != nil {
+		return Commit{}, "", err
+	}
+	fields := strings.SplitN(out, "\x00", 5)
+	if len(fields) != 5 {
+		return Commit{}, "", fmt.Errorf("unexpected git log output")
+	}
+
+	commit := Commit{Hash: fields[0], Author: fields[1], Date: fields[2], Subject: fields[3]}
+	if top, err := git(dir, "rev-parse", "--show-toplevel"); err == nil {
+		commit.Repo = strings.TrimSpace(top)
+	}
+	return commit, fields[4], nil
+}
+
+// Hooks are the hooks installed by Install
+var Hooks = []string{"commit-msg", "post-commit"}
+
+// hookMarker identifies hook scripts written by Install
+const hookMarker = "# Installed by 'todo git-hook install'"
+
+// HooksDir returns the hooks directory of the repository containing dir,
+// honouring core.hooksPath
+func HooksDir(dir string) (string, error) {
+	out, err := git(dir, "rev-parse", "--git-path", "hooks")
+	if err != nil {
+		return "", err
+	}
+	hooks := strings.TrimSpace(out)
+	if !filepath.IsAbs(hooks) {
+		hooks = filepath.Join(dir, hooks)
+	}
+	return hooks, nil
+}
+
+// Install writes a hook script that runs 'todo git-hook <hook>', where
+// command is the todo executable followed by any global flags. An existing
+// hook that was not written by Install is only replaced when force is set.
+func Install(hooksDir, hook string, command []string, force bool) error {
+	path := filepath.Join(hooksDir, hook)
+	if data, err := os.ReadFile(path); err == nil && !strings.Contains(string(data), hookMarker) && !force {
+		return fmt.Errorf("%s already exists; use --force to replace it", path)
+	}
+
+	if err := os.MkdirAll(hooksDir, 0755); err != nil {
+		return err
+	}
+
+	quoted := make([]string, len(command))
+	for i, arg := range command {
+		quoted[i] = shellQuote(arg)
+	}
+	script := fmt.Sprintf("#!/bin/sh\n%s\nexec %s git-hook %s \"$@\"\n", hookMarker, strings.Join(quoted, " "), hook)
+	return os.WriteFile(path, []byte(script), 0755)
+}
+
+// Uninstall removes a hook script written by Install. It returns false if
+// there was none.
+func Uninstall(hooksDir, hook string) (bool, error) {
+	path := filepath.Join(hooksDir, hook)
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if !strings.Contains(string(data), hookMarker) {
+		return false, fmt.Errorf("%s was not installed by todo; leaving it in place", path)
+	}
+	return true, os.Remove(path)
+}
+
+// git runs a git command in dir and returns its standard output
+func git(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok && len(exitErr.Stderr) > 0 {
+			return "", fmt.Errorf("git %s: %s", args[0], strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return "", fmt.Errorf("git %s: %v", args[0], err)
+	}
+	return strings.TrimSuffix(string(out), "\n"), nil
+}
+
+// shellQuote quotes an argument for /bin/sh
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
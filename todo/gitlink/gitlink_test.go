@@ -0,0 +1,187 @@
+package gitlink
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// newRepo creates a git repository and commits message in it
+func newRepo(t *testing.T, message string) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+
+	dir := t.TempDir()
+	for _, args := range [][]string{
+		{"init", "--quiet"},
+		{"-c", "user.name=Test User", "-c", "user.email=test@example.com", "commit", "--quiet", "--allow-empty", "-m", message},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	return dir
+}
+
+func TestReferences(t *testing.T) {
+	tests := []struct {
+		message string
+		want    []int
+	}{
+		{"Fix login bug", nil},
+		{"Fix login bug\n\nCloses todo#12", []int{12}},
+		{"fixes todo#3 and resolved TODO#7", []int{3, 7}},
+		{"Close todo#4, fixed todo#4", []int{4}},
+		{"todo: #12, #13 #14", []int{12, 13, 14}},
+		{"Resolves todo#9\n\ntodo: #2", []int{9, 2}},
+		{"Mentions todo#5 without closing it", nil},
+		{"Fixes #5 in another tracker", nil},
+		{"closes todo#0", nil},
+		{"Fixes todo#12a", nil},
+		{"Refactor\n# Please enter the commit message\n# closes todo#8", nil},
+		{"Ship it\n# comment\ntodo: #21", []int{21}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.message, func(t *testing.T) {
+			if got := References(tt.message); !slices.Equal(got, tt.want) {
+				t.Errorf("References = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHeadCommit(t *testing.T) {
+	repo := newRepo(t, "Fix login bug\n\nCloses todo#12")
+	sub := filepath.Join(repo, "sub")
+	if err := os.Mkdir(sub, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	commit, message, err := HeadCommit(sub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if commit.Subject != "Fix login bug" || commit.Author != "Test User" || len(commit.Hash) != 40 || len(commit.Short()) != 8 {
+		t.Errorf("commit = %+v", commit)
+	}
+	if resolved, _ := filepath.EvalSymlinks(repo); commit.Repo != resolved && commit.Repo != repo {
+		t.Errorf("repo = %s, want %s", commit.Repo, repo)
+	}
+	if !slices.Equal(References(message), []int{12}) {
+		t.Errorf("message = %q", message)
+	}
+
+	if _, _, err := HeadCommit(t.TempDir()); err == nil {
+		t.Error("reading HEAD outside a repository succeeded")
+	}
+}
+
+func TestHooksDir(t *testing.T) {
+	repo := newRepo(t, "Initial commit")
+	dir, err := HooksDir(repo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dir != filepath.Join(repo, ".git", "hooks") {
+		t.Errorf("hooks dir = %s", dir)
+	}
+
+	cmd := exec.Command("git", "config", "core.hooksPath", ".githooks")
+	cmd.Dir = repo
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git config: %v\n%s", err, out)
+	}
+	if dir, err = HooksDir(repo); err != nil || dir != filepath.Join(repo, ".githooks") {
+		t.Errorf("hooks dir = %s, %v, want .githooks", dir, err)
+	}
+}
+
+func TestInstallAndUninstall(t *testing.T) {
+	tests := []struct {
+		name     string
+		existing string
+		force    bool
+		wantErr  bool
+	}{
+		{name: "no hook yet"},
+		{name: "replaces its own hook", existing: "#!/bin/sh\n" + hookMarker + "\nexec todo git-hook commit-msg\n"},
+		{name: "keeps another hook", existing: "#!/bin/sh\nlint\n", wantErr: true},
+		{name: "replaces another hook when forced", existing: "#!/bin/sh\nlint\n", force: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hooks := filepath.Join(t.TempDir(), "hooks")
+			path := filepath.Join(hooks, "commit-msg")
+			if tt.existing != "" {
+				if err := os.MkdirAll(hooks, 0755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(path, []byte(tt.existing), 0755); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			err := Install(hooks, "commit-msg", []string{"/opt/my todo/todo", "--list", "it's work"}, tt.force)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("the hook was replaced")
+				}
+				if data, _ := os.ReadFile(path); string(data) != tt.existing {
+					t.Errorf("the hook changed to %q", data)
+				}
+				if _, err := Uninstall(hooks, "commit-msg"); err == nil {
+					t.Error("uninstalling another hook succeeded")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := `exec '/opt/my todo/todo' '--list' 'it'\''s work' git-hook commit-msg "$@"`
+			if !strings.Contains(string(data), want) {
+				t.Errorf("hook script:\n%s\nwant a line\n%s", data, want)
+			}
+
+			removed, err := Uninstall(hooks, "commit-msg")
+			if err != nil || !removed {
+				t.Fatalf("Uninstall = %v, %v", removed, err)
+			}
+			if _, err := os.Stat(path); !os.IsNotExist(err) {
+				t.Error("the hook is still there")
+			}
+			if removed, err := Uninstall(hooks, "commit-msg"); err != nil || removed {
+				t.Errorf("second Uninstall = %v, %v", removed, err)
+			}
+		})
+	}
+}
+
+func TestShellQuote(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh is not installed")
+	}
+	for _, arg := range []string{"plain", "with space", "it's", `"double" $HOME \back`, ""} {
+		out, err := exec.Command("sh", "-c", "printf %s "+shellQuote(arg)).Output()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(out) != arg {
+			t.Errorf("sh read %q as %q", arg, out)
+		}
+	}
+}
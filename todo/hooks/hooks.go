@@ -0,0 +1,273 @@
+// Package hooks runs the user's shell commands and webhooks when tasks are
+// added, modified, completed or deleted. Pre-hooks run before the change is
+// saved and can veto or rewrite it; post-hooks ("on-") run after the save.
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"todo/taskdata"
+)
+
+// Event is something that happens to a task
+type Event string
+
+const (
+	Add      Event = "add"
+	Modify   Event = "modify"
+	Complete Event = "complete"
+	Delete   Event = "delete"
+)
+
+// Events lists every event in the order hooks are documented
+var Events = []Event{Add, Modify, Complete, Delete}
+
+// Timeout bounds how long a single hook may run
+const Timeout = 10 * time.Second
+
+// Change is a task change hooks are run for
+type Change struct {
+	Event Event
+	Task  taskdata.Task  // The task as it will be saved; the removed task for Delete
+	Old   *taskdata.Task // The task before the change, nil for Add
+}
+
+// EventOf classifies a change from the task before and after it; either may
+// be nil for an added or removed task
+func EventOf(old, task *taskdata.Task) Event {
+	switch {
+	case task == nil:
+		return Delete
+	case old == nil:
+		return Add
+	case task.Completed && !old.Completed:
+		return Complete
+	}
+	return Modify
+}
+
+// Payload is the JSON hooks receive on stdin or as the request body
+type Payload struct {
+	Event string         `json:"event"` // e.g. "pre-add" or "on-complete"
+	Task  taskdata.Task  `json:"task"`
+	Old   *taskdata.Task `json:"old,omitempty"`
+}
+
+// VetoError reports a change a pre-hook refused
+type VetoError struct {
+	Hook   string // e.g. "pre-delete"
+	Reason string
+}
+
+func (e *VetoError) Error() string {
+	if e.Reason == "" {
+		return fmt.Sprintf("%s hook refused the change", e.Hook)
+	}
+	return fmt.Sprintf("%s hook refused the change: %s", e.Hook, e.Reason)
+}
+
+// Config maps events to hooks. A hook starting with http:// or https:// is
+// a URL the payload is posted to; anything else is run with /bin/sh -c.
+type Config struct {
+	Pre map[Event]string
+	On  map[Event]string
+
+	// Output receives what hooks print, both output and errors; nil shows
+	// it on stdout and stderr
+	Output io.Writer
+}
+
+// Enabled reports whether any hook is configured
+func (c Config) Enabled() bool {
+	for _, event := range Events {
+		if c.Pre[event] != "" || c.On[event] != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// RunPre runs the pre-hook for a change and returns the task to save. A
+// shell hook vetoes by exiting non-zero and a webhook by answering with an
+// error status; the output or response body is the reason. Printing a JSON
+// object instead rewrites the task: its fields replace the task's, except
+// the ID and UID. Deletions cannot be rewritten.
+func (c Config) RunPre(change Change) (taskdata.Task, error) {
+	hook := c.Pre[change.Event]
+	if hook == "" {
+		return change.Task, nil
+	}
+	name := "pre-" + string(change.Event)
+
+	output, err := c.run(hook, name, change, true)
+	if err != nil {
+		if reason, ok := err.(refusal); ok {
+			return change.Task, &VetoError{Hook: name, Reason: string(reason)}
+		}
+		return change.Task, fmt.Errorf("%s hook failed: %v", name, err)
+	}
+
+	output = bytes.TrimSpace(output)
+	if !bytes.HasPrefix(output, []byte("{")) {
+		if len(output) > 0 {
+			fmt.Fprintln(c.stdout(), string(output))
+		}
+		return change.Task, nil
+	}
+	if change.Event == Delete {
+		return change.Task, nil
+	}
+	return rewrite(change.Task, output, name)
+}
+
+// RunOn runs the post-hook for a change that was saved
+func (c Config) RunOn(change Change) error {
+	hook := c.On[change.Event]
+	if hook == "" {
+		return nil
+	}
+	name := "on-" + string(change.Event)
+	if _, err := c.run(hook, name, change, false); err != nil {
+		return fmt.Errorf("%s hook failed: %v", name, err)
+	}
+	return nil
+}
+
+// rewrite applies the fields of a JSON object printed by a pre-hook
+func rewrite(task taskdata.Task, output []byte, name string) (taskdata.Task, error) {
+	rewritten := task
+	if err := json.Unmarshal(output, &rewritten); err != nil {
+		return task, fmt.Errorf("%s hook printed invalid JSON: %v", name, err)
+	}
+	rewritten.ID = task.ID
+	rewritten.UID = task.UID
+
+	priority, err := taskdata.NormalizePriority(rewritten.Priority)
+	if err == nil {
+		rewritten.Priority = priority
+		err = taskdata.ValidateDate(rewritten.DueDate)
+	}
+	if err == nil {
+		err = taskdata.ValidateDate(rewritten.WaitUntil)
+	}
+	if err == nil {
+		err = taskdata.ValidateTime(rewritten.DueTime)
+	}
+	if err == nil && strings.TrimSpace(rewritten.Description) == "" {
+		err = fmt.Errorf("empty description")
+	}
+	if err != nil {
+		return task, fmt.Errorf("%s hook returned an invalid task: %v", name, err)
+	}
+	return rewritten, nil
+}
+
+// refusal is a hook's non-zero exit or error status, with its explanation
+type refusal string
+
+func (r refusal) Error() string {
+	return string(r)
+}
+
+func (c Config) stdout() io.Writer {
+	if c.Output != nil {
+		return c.Output
+	}
+	return os.Stdout
+}
+
+func (c Config) stderr() io.Writer {
+	if c.Output != nil {
+		return c.Output
+	}
+	return os.Stderr
+}
+
+// run runs a shell hook or posts to a webhook. With capture the output is
+// returned instead of shown, as pre-hooks may print a rewritten task.
+func (c Config) run(hook, name string, change Change, capture bool) ([]byte, error) {
+	data, err := json.Marshal(Payload{Event: name, Task: change.Task, Old: change.Old})
+	if err != nil {
+		return nil, err
+	}
+	if strings.HasPrefix(hook, "http://") || strings.HasPrefix(hook, "https://") {
+		return post(hook, name, data)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), Timeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "/bin/sh", "-c", hook)
+	cmd.Stdin = bytes.NewReader(data)
+	cmd.Stdout = c.stdout()
+	cmd.Stderr = c.stderr()
+	if capture {
+		cmd.Stdout = &stdout
+		cmd.Stderr = &stderr
+	}
+	cmd.Env = append(os.Environ(),
+		"TODO_EVENT="+name,
+		"TODO_ID="+strconv.Itoa(change.Task.ID),
+		"TODO_UID="+change.Task.UID,
+		"TODO_DESCRIPTION="+change.Task.Description,
+	)
+
+	err = cmd.Run()
+	if ctx.Err() != nil {
+		return nil, fmt.Errorf("timed out after %s", Timeout)
+	}
+	if exitErr, ok := err.(*exec.ExitError); ok && capture {
+		reason := strings.TrimSpace(stderr.String())
+		if reason == "" {
+			reason = strings.TrimSpace(stdout.String())
+		}
+		if reason == "" {
+			reason = exitErr.Error()
+		}
+		return nil, refusal(reason)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if stderr.Len() > 0 {
+		c.stderr().Write(stderr.Bytes())
+	}
+	return stdout.Bytes(), nil
+}
+
+// post sends the payload to a webhook and returns the response body
+func post(url, name string, data []byte) ([]byte, error) {
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Todo-Event", name)
+
+	client := &http.Client{Timeout: Timeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		reason := strings.TrimSpace(string(body))
+		if reason == "" {
+			reason = "webhook returned " + resp.Status
+		}
+		return nil, refusal(reason)
+	}
+	return body, nil
+}
@@ -0,0 +1,247 @@
+package hooks
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"todo/taskdata"
+)
+
+// needShell skips tests of shell hooks where there is no /bin/sh
+func needShell(t *testing.T) {
+	t.Helper()
+	if _, err := os.Stat("/bin/sh"); err != nil {
+		t.Skip("no /bin/sh")
+	}
+}
+
+func testTask() taskdata.Task {
+	return taskdata.Task{ID: 4, UID: "uid-4", Description: "Write report", Priority: "normal", Tags: []string{"work"}}
+}
+
+func TestEventOf(t *testing.T) {
+	open := &taskdata.Task{ID: 1}
+	done := &taskdata.Task{ID: 1, Completed: true}
+	tests := []struct {
+		name     string
+		old, new *taskdata.Task
+		want     Event
+	}{
+		{"added", nil, open, Add},
+		{"removed", open, nil, Delete},
+		{"completed", open, done, Complete},
+		{"edited", open, open, Modify},
+		{"edited while completed", done, done, Modify},
+		{"reopened", done, open, Modify},
+	}
+	for _, tt := range tests {
+		if got := EventOf(tt.old, tt.new); got != tt.want {
+			t.Errorf("%s: EventOf = %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestEnabled(t *testing.T) {
+	tests := []struct {
+		config Config
+		want   bool
+	}{
+		{Config{}, false},
+		{Config{Pre: map[Event]string{Add: ""}}, false},
+		{Config{Pre: map[Event]string{Add: "true"}}, true},
+		{Config{On: map[Event]string{Delete: "true"}}, true},
+		{Config{On: map[Event]string{"rename": "true"}}, false},
+	}
+	for _, tt := range tests {
+		if got := tt.config.Enabled(); got != tt.want {
+			t.Errorf("Enabled(%+v) = %v, want %v", tt.config, got, tt.want)
+		}
+	}
+}
+
+func TestRunPre(t *testing.T) {
+	needShell(t)
+	tests := []struct {
+		name    string
+		event   Event
+		hook    string
+		want    func(*taskdata.Task) // Changes expected on the task
+		output  string
+		veto    string
+		wantErr string
+	}{
+		{name: "no hook", event: Add},
+		{name: "silent hook", event: Add, hook: "true"},
+		{name: "printed text is shown", event: Add, hook: "echo looks fine", output: "looks fine\n"},
+		{name: "veto with reason on stderr", event: Add, hook: "echo ignored; echo no work on weekends >&2; exit 1",
+			veto: "no work on weekends"},
+		{name: "veto with reason on stdout", event: Delete, hook: "echo keep it; exit 2", veto: "keep it"},
+		{name: "veto without reason", event: Complete, hook: "exit 1", veto: "exit status 1"},
+		{name: "rewrite", event: Add,
+			hook: `echo '{"id": 99, "uid": "other", "description": "Write the report", "priority": "h", "tags": ["work", "q3"]}'`,
+			want: func(task *taskdata.Task) {
+				task.Description = "Write the report"
+				task.Priority = "high"
+				task.Tags = []string{"work", "q3"}
+			}},
+		{name: "the payload is on stdin", event: Modify,
+			hook: `grep -q '"event":"pre-modify","task":{"id":4,' && echo '{"description": "Read report"}'`,
+			want: func(task *taskdata.Task) { task.Description = "Read report" }},
+		{name: "deletions are not rewritten", event: Delete, hook: `echo '{"description": "other"}'`},
+		{name: "invalid JSON", event: Add, hook: `echo '{"description": '`, wantErr: "pre-add hook printed invalid JSON"},
+		{name: "invalid priority", event: Add, hook: `echo '{"priority": "urgent"}'`,
+			wantErr: "pre-add hook returned an invalid task"},
+		{name: "invalid due date", event: Modify, hook: `echo '{"due_date": "2025-13-40"}'`,
+			wantErr: "pre-modify hook returned an invalid task"},
+		{name: "empty description", event: Add, hook: `echo '{"description": " "}'`,
+			wantErr: "pre-add hook returned an invalid task: empty description"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var output bytes.Buffer
+			config := Config{Pre: map[Event]string{tt.event: tt.hook}, Output: &output}
+			task := testTask()
+			got, err := config.RunPre(Change{Event: tt.event, Task: task})
+
+			var veto *VetoError
+			switch {
+			case tt.veto != "":
+				if !errors.As(err, &veto) || veto.Hook != "pre-"+string(tt.event) || veto.Reason != tt.veto {
+					t.Fatalf("error %v, want a veto with reason %q", err, tt.veto)
+				}
+			case tt.wantErr != "":
+				if err == nil || !strings.HasPrefix(err.Error(), tt.wantErr) || errors.As(err, &veto) {
+					t.Fatalf("error %v, want %q", err, tt.wantErr)
+				}
+			case err != nil:
+				t.Fatal(err)
+			}
+
+			want := testTask()
+			if tt.want != nil {
+				tt.want(&want)
+			}
+			gotJSON, _ := json.Marshal(got)
+			wantJSON, _ := json.Marshal(want)
+			if !bytes.Equal(gotJSON, wantJSON) {
+				t.Errorf("task %s, want %s", gotJSON, wantJSON)
+			}
+			if output.String() != tt.output {
+				t.Errorf("output %q, want %q", output.String(), tt.output)
+			}
+		})
+	}
+}
+
+func TestHookEnvironment(t *testing.T) {
+	needShell(t)
+	var output bytes.Buffer
+	config := Config{
+		On:     map[Event]string{Complete: `printf '%s|%s|%s|%s' "$TODO_EVENT" "$TODO_ID" "$TODO_UID" "$TODO_DESCRIPTION"`},
+		Output: &output,
+	}
+	if err := config.RunOn(Change{Event: Complete, Task: testTask()}); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := output.String(), "on-complete|4|uid-4|Write report"; got != want {
+		t.Errorf("environment %q, want %q", got, want)
+	}
+}
+
+func TestRunOn(t *testing.T) {
+	needShell(t)
+	tests := []struct {
+		name    string
+		hook    string
+		output  string
+		wantErr string
+	}{
+		{name: "no hook"},
+		{name: "output is shown", hook: "echo saved; echo warning >&2", output: "saved\nwarning\n"},
+		{name: "JSON output is not a rewrite", hook: `echo '{"description": "other"}'`, output: "{\"description\": \"other\"}\n"},
+		{name: "failure", hook: "exit 3", wantErr: "on-add hook failed: exit status 3"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var output bytes.Buffer
+			config := Config{On: map[Event]string{Add: tt.hook}, Output: &output}
+			err := config.RunOn(Change{Event: Add, Task: testTask()})
+			if tt.wantErr == "" && err != nil || tt.wantErr != "" && (err == nil || err.Error() != tt.wantErr) {
+				t.Fatalf("error %v, want %q", err, tt.wantErr)
+			}
+			if output.String() != tt.output {
+				t.Errorf("output %q, want %q", output.String(), tt.output)
+			}
+		})
+	}
+}
+
+func TestWebhooks(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   string
+		want   string // Description after the pre-hook
+		veto   string
+	}{
+		{"accepted", http.StatusOK, "", "Write report", ""},
+		{"accepted without content", http.StatusNoContent, "", "Write report", ""},
+		{"rewritten", http.StatusOK, `{"description": "Write the report"}`, "Write the report", ""},
+		{"refused with reason", http.StatusForbidden, "not today\n", "Write report", "not today"},
+		{"refused without reason", http.StatusInternalServerError, "", "Write report", "webhook returned 500 Internal Server Error"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var payload Payload
+			var event, contentType string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				event = r.Header.Get("X-Todo-Event")
+				contentType = r.Header.Get("Content-Type")
+				body, _ := io.ReadAll(r.Body)
+				json.Unmarshal(body, &payload)
+				w.WriteHeader(tt.status)
+				io.WriteString(w, tt.body)
+			}))
+			defer server.Close()
+
+			old := testTask()
+			task := testTask()
+			task.Priority = "high"
+			config := Config{Pre: map[Event]string{Modify: server.URL}, On: map[Event]string{Modify: server.URL}}
+			got, err := config.RunPre(Change{Event: Modify, Task: task, Old: &old})
+
+			var veto *VetoError
+			if tt.veto != "" {
+				if !errors.As(err, &veto) || veto.Reason != tt.veto {
+					t.Fatalf("error %v, want a veto with reason %q", err, tt.veto)
+				}
+			} else if err != nil {
+				t.Fatal(err)
+			}
+			if got.Description != tt.want || got.Priority != "high" {
+				t.Errorf("task %+v, want description %q", got, tt.want)
+			}
+			if event != "pre-modify" || contentType != "application/json" {
+				t.Errorf("posted event %q as %q", event, contentType)
+			}
+			if payload.Event != "pre-modify" || payload.Task.Priority != "high" || payload.Old == nil || payload.Old.Priority != "normal" {
+				t.Errorf("payload %+v", payload)
+			}
+
+			err = config.RunOn(Change{Event: Modify, Task: task, Old: &old})
+			if (err != nil) != (tt.veto != "") {
+				t.Errorf("on-modify error %v", err)
+			}
+			if event != "on-modify" {
+				t.Errorf("posted event %q, want on-modify", event)
+			}
+		})
+	}
+}
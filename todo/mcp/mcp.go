@@ -0,0 +1,193 @@
+// Package mcp implements a Model Context Protocol server over stdio that
+// exposes tools to local assistants (https://modelcontextprotocol.io).
+package mcp
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"sync"
+)
+
+// Tool is a typed operation the client can call
+type Tool struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	InputSchema map[string]any `json:"inputSchema"`
+
+	// Handler runs the tool with the client's arguments. An error is
+	// reported to the client as a failed tool call, not a protocol error, so
+	// the assistant can see it and correct its arguments.
+	Handler func(args json.RawMessage) (any, error) `json:"-"`
+}
+
+// Server answers MCP requests with a fixed set of tools
+type Server struct {
+	Name    string
+	Version string
+	Tools   []Tool
+
+	out sync.Mutex
+}
+
+// protocolVersions are the supported revisions, newest first
+var protocolVersions = []string{"2025-06-18", "2025-03-26", "2024-11-05"}
+
+// JSON-RPC error codes
+const (
+	codeParseError     = -32700
+	codeInvalidRequest = -32600
+	codeMethodNotFound = -32601
+	codeInvalidParams  = -32602
+)
+
+type request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  any             `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// content is a block of a tool result
+type content struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// Serve reads newline-delimited JSON-RPC messages from r and writes the
+// responses to w until r is closed
+func (s *Server) Serve(r io.Reader, w io.Writer) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+
+		var req request
+		if err := json.Unmarshal(line, &req); err != nil {
+			s.write(w, response{ID: json.RawMessage("null"), Error: &rpcError{codeParseError, "parse error: " + err.Error()}})
+			continue
+		}
+		if req.JSONRPC != "2.0" || req.Method == "" {
+			s.write(w, response{ID: idOrNull(req.ID), Error: &rpcError{codeInvalidRequest, "invalid JSON-RPC 2.0 request"}})
+			continue
+		}
+
+		result, rpcErr := s.handle(req)
+		// Notifications get no response
+		if req.ID == nil {
+			continue
+		}
+		s.write(w, response{ID: req.ID, Result: result, Error: rpcErr})
+	}
+	return scanner.Err()
+}
+
+func (s *Server) handle(req request) (any, *rpcError) {
+	switch req.Method {
+	case "initialize":
+		var params struct {
+			ProtocolVersion string `json:"protocolVersion"`
+		}
+		json.Unmarshal(req.Params, &params)
+		version := protocolVersions[0]
+		if slices.Contains(protocolVersions, params.ProtocolVersion) {
+			version = params.ProtocolVersion
+		}
+		return map[string]any{
+			"protocolVersion": version,
+			"capabilities":    map[string]any{"tools": map[string]any{"listChanged": false}},
+			"serverInfo":      map[string]any{"name": s.Name, "version": s.Version},
+		}, nil
+
+	case "ping":
+		return map[string]any{}, nil
+
+	case "tools/list":
+		return map[string]any{"tools": s.Tools}, nil
+
+	case "tools/call":
+		var params struct {
+			Name      string          `json:"name"`
+			Arguments json.RawMessage `json:"arguments"`
+		}
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return nil, &rpcError{codeInvalidParams, "invalid params: " + err.Error()}
+		}
+		for _, tool := range s.Tools {
+			if tool.Name == params.Name {
+				return callTool(tool, params.Arguments), nil
+			}
+		}
+		return nil, &rpcError{codeInvalidParams, fmt.Sprintf("unknown tool '%s'", params.Name)}
+	}
+
+	if req.ID == nil {
+		// Notifications such as notifications/initialized need no handling
+		return nil, nil
+	}
+	return nil, &rpcError{codeMethodNotFound, fmt.Sprintf("method '%s' not found", req.Method)}
+}
+
+// callTool runs a tool and wraps its result or error in a tool result
+func callTool(tool Tool, args json.RawMessage) map[string]any {
+	if len(args) == 0 || string(args) == "null" {
+		args = json.RawMessage("{}")
+	}
+
+	result, err := tool.Handler(args)
+	if err != nil {
+		return map[string]any{
+			"content": []content{{Type: "text", Text: err.Error()}},
+			"isError": true,
+		}
+	}
+
+	text, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return map[string]any{
+			"content": []content{{Type: "text", Text: err.Error()}},
+			"isError": true,
+		}
+	}
+	return map[string]any{
+		"content":           []content{{Type: "text", Text: string(text)}},
+		"structuredContent": result,
+	}
+}
+
+func (s *Server) write(w io.Writer, resp response) {
+	resp.JSONRPC = "2.0"
+	data, err := json.Marshal(resp)
+	if err != nil {
+		data, _ = json.Marshal(response{JSONRPC: "2.0", ID: resp.ID, Error: &rpcError{codeInvalidRequest, err.Error()}})
+	}
+
+	s.out.Lock()
+	defer s.out.Unlock()
+	w.Write(append(data, '\n'))
+}
+
+func idOrNull(id json.RawMessage) json.RawMessage {
+	if id == nil {
+		return json.RawMessage("null")
+	}
+	return id
+}
@@ -0,0 +1,164 @@
+package mcp
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// testServer has an "echo" tool returning its arguments and a "fail" tool
+func testServer() *Server {
+	return &Server{
+		Name:    "todo",
+		Version: "1.2.3",
+		Tools: []Tool{
+			{
+				Name:        "echo",
+				Description: "Echo the arguments",
+				InputSchema: map[string]any{"type": "object"},
+				Handler: func(args json.RawMessage) (any, error) {
+					var v map[string]any
+					err := json.Unmarshal(args, &v)
+					return v, err
+				},
+			},
+			{
+				Name:        "fail",
+				Description: "Always fails",
+				InputSchema: map[string]any{"type": "object"},
+				Handler: func(args json.RawMessage) (any, error) {
+					return nil, errors.New("task #9 not found")
+				},
+			},
+		},
+	}
+}
+
+// serve runs the server over input lines and decodes each response line
+func serve(t *testing.T, lines ...string) []map[string]any {
+	t.Helper()
+	var out strings.Builder
+	if err := testServer().Serve(strings.NewReader(strings.Join(lines, "\n")+"\n"), &out); err != nil {
+		t.Fatal(err)
+	}
+
+	var responses []map[string]any
+	for _, line := range strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n") {
+		if line == "" {
+			continue
+		}
+		var resp map[string]any
+		if err := json.Unmarshal([]byte(line), &resp); err != nil {
+			t.Fatalf("response is not JSON: %v\n%s", err, line)
+		}
+		if resp["jsonrpc"] != "2.0" {
+			t.Errorf("response without jsonrpc 2.0: %s", line)
+		}
+		responses = append(responses, resp)
+	}
+	return responses
+}
+
+// errorCode returns the JSON-RPC error code of a response, or 0
+func errorCode(resp map[string]any) int {
+	rpcErr, _ := resp["error"].(map[string]any)
+	code, _ := rpcErr["code"].(float64)
+	return int(code)
+}
+
+func TestServeErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+		id   any
+		code int
+	}{
+		{"not JSON", `{"jsonrpc":"2.0",`, nil, codeParseError},
+		{"not JSON-RPC 2.0", `{"jsonrpc":"1.0","id":1,"method":"ping"}`, 1.0, codeInvalidRequest},
+		{"no method", `{"jsonrpc":"2.0","id":"a"}`, "a", codeInvalidRequest},
+		{"unknown method", `{"jsonrpc":"2.0","id":2,"method":"resources/list"}`, 2.0, codeMethodNotFound},
+		{"unknown tool", `{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"nope"}}`, 3.0, codeInvalidParams},
+		{"invalid params", `{"jsonrpc":"2.0","id":4,"method":"tools/call","params":[1]}`, 4.0, codeInvalidParams},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			responses := serve(t, tt.line)
+			if len(responses) != 1 {
+				t.Fatalf("%d responses, want 1", len(responses))
+			}
+			resp := responses[0]
+			if code := errorCode(resp); code != tt.code {
+				t.Errorf("error code %d, want %d: %v", code, tt.code, resp)
+			}
+			if resp["id"] != tt.id {
+				t.Errorf("id %v, want %v", resp["id"], tt.id)
+			}
+			if _, exists := resp["result"]; exists {
+				t.Errorf("an error response has a result: %v", resp)
+			}
+		})
+	}
+}
+
+func TestServeSession(t *testing.T) {
+	responses := serve(t,
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-03-26","capabilities":{}}}`,
+		`{"jsonrpc":"2.0","method":"notifications/initialized"}`,
+		``,
+		`{"jsonrpc":"2.0","method":"notifications/unknown"}`,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/list"}`,
+		`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"echo","arguments":{"text":"hi"}}}`,
+		`{"jsonrpc":"2.0","id":4,"method":"tools/call","params":{"name":"echo"}}`,
+		`{"jsonrpc":"2.0","id":5,"method":"tools/call","params":{"name":"fail","arguments":{}}}`,
+		`{"jsonrpc":"2.0","id":6,"method":"ping"}`,
+	)
+	if len(responses) != 6 {
+		t.Fatalf("%d responses, want 6 (notifications get none): %v", len(responses), responses)
+	}
+	for i, resp := range responses {
+		if resp["id"] != float64(i+1) || resp["error"] != nil {
+			t.Errorf("response %d: %v", i+1, resp)
+		}
+	}
+
+	initialize := responses[0]["result"].(map[string]any)
+	if initialize["protocolVersion"] != "2025-03-26" {
+		t.Errorf("protocol version %v, want the client's", initialize["protocolVersion"])
+	}
+	if info := initialize["serverInfo"].(map[string]any); info["name"] != "todo" || info["version"] != "1.2.3" {
+		t.Errorf("server info %v", info)
+	}
+
+	tools := responses[1]["result"].(map[string]any)["tools"].([]any)
+	if len(tools) != 2 || tools[0].(map[string]any)["name"] != "echo" || tools[0].(map[string]any)["inputSchema"] == nil {
+		t.Errorf("tools %v", tools)
+	}
+
+	echo := responses[2]["result"].(map[string]any)
+	if structured := echo["structuredContent"].(map[string]any); structured["text"] != "hi" {
+		t.Errorf("structured content %v", structured)
+	}
+	text := echo["content"].([]any)[0].(map[string]any)["text"].(string)
+	if !strings.Contains(text, `"text": "hi"`) {
+		t.Errorf("text content %q", text)
+	}
+
+	if empty := responses[3]["result"].(map[string]any)["structuredContent"]; len(empty.(map[string]any)) != 0 {
+		t.Errorf("missing arguments were not passed as {}: %v", empty)
+	}
+
+	failed := responses[4]["result"].(map[string]any)
+	if failed["isError"] != true || !strings.Contains(failed["content"].([]any)[0].(map[string]any)["text"].(string), "task #9 not found") {
+		t.Errorf("failed tool call %v", failed)
+	}
+}
+
+func TestInitializeFallsBackToTheNewestVersion(t *testing.T) {
+	for _, params := range []string{`{"protocolVersion":"1999-01-01"}`, `{}`} {
+		responses := serve(t, `{"jsonrpc":"2.0","id":1,"method":"initialize","params":`+params+`}`)
+		if got := responses[0]["result"].(map[string]any)["protocolVersion"]; got != protocolVersions[0] {
+			t.Errorf("params %s: protocol version %v, want %s", params, got, protocolVersions[0])
+		}
+	}
+}
@@ -0,0 +1,143 @@
+// Package plugin finds todo-<name> executables on PATH, which todo runs as
+// 'todo <name>' in the style of git subcommands, and asks plugins for smart
+// suggestions.
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"todo/taskdata"
+)
+
+// Prefix starts the file name of every plugin executable
+const Prefix = "todo-"
+
+// Timeout bounds how long a plugin may take to make suggestions
+const Timeout = 10 * time.Second
+
+// Plugin is an executable on PATH
+type Plugin struct {
+	Name string // Command name, e.g. "foo" for todo-foo
+	Path string
+}
+
+// Discover returns the plugins on PATH sorted by name. When several
+// directories hold the same plugin, the first one on PATH wins.
+func Discover() []Plugin {
+	seen := map[string]bool{}
+	var plugins []Plugin
+	for _, dir := range filepath.SplitList(os.Getenv("PATH")) {
+		if dir == "" {
+			dir = "."
+		}
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			name, ok := strings.CutPrefix(entry.Name(), Prefix)
+			if !ok || name == "" || seen[name] {
+				continue
+			}
+			path := filepath.Join(dir, entry.Name())
+			if !isExecutable(path) {
+				continue
+			}
+			seen[name] = true
+			plugins = append(plugins, Plugin{Name: name, Path: path})
+		}
+	}
+	sort.Slice(plugins, func(i, j int) bool { return plugins[i].Name < plugins[j].Name })
+	return plugins
+}
+
+// Find returns the named plugin if it is on PATH
+func Find(name string) (Plugin, bool) {
+	path, err := exec.LookPath(Prefix + name)
+	if err != nil {
+		return Plugin{}, false
+	}
+	return Plugin{Name: name, Path: path}, true
+}
+
+func isExecutable(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.Mode().IsRegular() && info.Mode().Perm()&0111 != 0
+}
+
+// Run runs the plugin with the user's arguments, passing the terminal
+// through; env is added to the plugin's environment
+func (p Plugin) Run(args []string, env []string) error {
+	cmd := exec.Command(p.Path, args...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.Env = append(os.Environ(), env...)
+	return cmd.Run()
+}
+
+// Suggestion is a group of tasks a plugin suggests acting on, shown by
+// 'todo delete' next to the built-in suggestions
+type Suggestion struct {
+	Category string `json:"category"`
+	Score    int    `json:"score"` // 0-100 how beneficial deleting the tasks would be
+	Reason   string `json:"reason"`
+	Impact   string `json:"impact"` // "high", "medium" or "low"; defaults to medium
+	TaskIDs  []int  `json:"task_ids"`
+}
+
+// Suggest runs 'todo-<name> suggest' with the store as JSON on stdin and
+// reads a JSON array of suggestions from its output
+func (p Plugin) Suggest(store *taskdata.TaskStore, env []string) ([]Suggestion, error) {
+	data, err := json.Marshal(store)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), Timeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, p.Path, "suggest")
+	cmd.Stdin = bytes.NewReader(data)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	cmd.Env = append(os.Environ(), env...)
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("timed out after %s", Timeout)
+		}
+		if reason := strings.TrimSpace(stderr.String()); reason != "" {
+			return nil, fmt.Errorf("%v: %s", err, reason)
+		}
+		return nil, err
+	}
+
+	var suggestions []Suggestion
+	if err := json.Unmarshal(stdout.Bytes(), &suggestions); err != nil {
+		return nil, fmt.Errorf("invalid suggestions: %v", err)
+	}
+	for i, s := range suggestions {
+		if strings.TrimSpace(s.Category) == "" {
+			return nil, fmt.Errorf("suggestion %d has no category", i+1)
+		}
+		switch s.Impact {
+		case "high", "medium", "low":
+		case "":
+			suggestions[i].Impact = "medium"
+		default:
+			return nil, fmt.Errorf("suggestion '%s' has invalid impact '%s' (use high, medium or low)", s.Category, s.Impact)
+		}
+		suggestions[i].Score = max(0, min(100, s.Score))
+	}
+	return suggestions, nil
+}
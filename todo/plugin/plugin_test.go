@@ -0,0 +1,131 @@
+package plugin
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"todo/taskdata"
+)
+
+// writePlugin writes a shell script plugin into dir
+func writePlugin(t *testing.T, dir, name, script string) string {
+	t.Helper()
+	if _, err := os.Stat("/bin/sh"); err != nil {
+		t.Skip("no /bin/sh")
+	}
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"+script+"\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestDiscover(t *testing.T) {
+	first, second := t.TempDir(), t.TempDir()
+	writePlugin(t, first, "todo-sync", "true")
+	writePlugin(t, first, "todo-", "true")
+	writePlugin(t, first, "other", "true")
+	if err := os.WriteFile(filepath.Join(first, "todo-notes"), []byte("not executable"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	writePlugin(t, second, "todo-sync", "true")
+	writePlugin(t, second, "todo-notes", "true")
+	writePlugin(t, second, "todo-archive", "true")
+	if err := os.Mkdir(filepath.Join(second, "todo-dir"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", strings.Join([]string{first, filepath.Join(first, "missing"), second}, string(os.PathListSeparator)))
+
+	want := []Plugin{
+		{Name: "archive", Path: filepath.Join(second, "todo-archive")},
+		{Name: "notes", Path: filepath.Join(second, "todo-notes")},
+		{Name: "sync", Path: filepath.Join(first, "todo-sync")},
+	}
+	if got := Discover(); !slices.Equal(got, want) {
+		t.Errorf("Discover = %+v, want %+v", got, want)
+	}
+
+	tests := []struct {
+		name string
+		path string
+	}{
+		{"sync", filepath.Join(first, "todo-sync")},
+		{"notes", filepath.Join(second, "todo-notes")},
+		{"other", ""},
+		{"dir", ""},
+	}
+	for _, tt := range tests {
+		p, ok := Find(tt.name)
+		if ok != (tt.path != "") || p.Path != tt.path || ok && p.Name != tt.name {
+			t.Errorf("Find(%s) = %+v, %v, want %q", tt.name, p, ok, tt.path)
+		}
+	}
+}
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	out := filepath.Join(dir, "out")
+	p := Plugin{Name: "echo", Path: writePlugin(t, dir, "todo-echo", `echo "$* $TODO_TEST" > "`+out+`"; exit $1`)}
+
+	if err := p.Run([]string{"0", "list"}, []string{"TODO_TEST=set"}); err != nil {
+		t.Fatal(err)
+	}
+	if data, err := os.ReadFile(out); err != nil || string(data) != "0 list set\n" {
+		t.Errorf("plugin wrote %q, %v", data, err)
+	}
+	if err := p.Run([]string{"4"}, nil); err == nil {
+		t.Error("a failing plugin returned no error")
+	}
+}
+
+func TestSuggest(t *testing.T) {
+	tests := []struct {
+		name    string
+		script  string
+		want    []Suggestion
+		wantErr string
+	}{
+		{name: "no suggestions", script: `echo '[]'`, want: []Suggestion{}},
+		{name: "suggestions are normalized",
+			script: `echo '[{"category": "Stale", "score": 140, "reason": "old", "task_ids": [1, 2]}, {"category": "Done", "score": -5, "impact": "low", "task_ids": [3]}]'`,
+			want: []Suggestion{
+				{Category: "Stale", Score: 100, Reason: "old", Impact: "medium", TaskIDs: []int{1, 2}},
+				{Category: "Done", Score: 0, Impact: "low", TaskIDs: []int{3}},
+			}},
+		{name: "the store is on stdin",
+			script: `grep -q '"description":"Old task"' && echo '[{"category": "Seen", "impact": "high"}]'`,
+			want:   []Suggestion{{Category: "Seen", Impact: "high"}}},
+		{name: "the command is suggest", script: `[ "$1" = suggest ] && echo '[]'`, want: []Suggestion{}},
+		{name: "failure with reason", script: "echo broken >&2; exit 1", wantErr: "exit status 1: broken"},
+		{name: "failure", script: "exit 2", wantErr: "exit status 2"},
+		{name: "invalid JSON", script: "echo nothing to suggest", wantErr: "invalid suggestions"},
+		{name: "no category", script: `echo '[{"category": "A"}, {"category": " "}]'`, wantErr: "suggestion 2 has no category"},
+		{name: "invalid impact", script: `echo '[{"category": "A", "impact": "huge"}]'`,
+			wantErr: "suggestion 'A' has invalid impact 'huge' (use high, medium or low)"},
+	}
+	store := &taskdata.TaskStore{Tasks: []taskdata.Task{{ID: 1, UID: "a", Description: "Old task"}}, NextID: 2}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := Plugin{Name: "test", Path: writePlugin(t, t.TempDir(), "todo-test", tt.script)}
+			got, err := p.Suggest(store, nil)
+			if tt.wantErr != "" {
+				if err == nil || !strings.HasPrefix(err.Error(), tt.wantErr) {
+					t.Fatalf("error %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.EqualFunc(got, tt.want, func(a, b Suggestion) bool {
+				return a.Category == b.Category && a.Score == b.Score && a.Reason == b.Reason &&
+					a.Impact == b.Impact && slices.Equal(a.TaskIDs, b.TaskIDs)
+			}) {
+				t.Errorf("suggestions %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,157 @@
+package reminder
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"strconv"
+	"time"
+
+	"todo/taskdata"
+)
+
+// Notifier delivers reminders
+type Notifier interface {
+	Notify(r Reminder, now time.Time) error
+}
+
+// Notifiers lists the supported notifier names
+var Notifiers = []string{"desktop", "bell", "command", "webhook"}
+
+// Options configure the notifiers that need more than a name
+type Options struct {
+	Command  string    // Shell command run by the command notifier
+	Webhook  string    // URL the webhook notifier posts to
+	Terminal io.Writer // Where the bell notifier writes
+}
+
+// NewNotifier returns the named notifier
+func NewNotifier(name string, opts Options) (Notifier, error) {
+	switch name {
+	case "desktop":
+		path, err := exec.LookPath("notify-send")
+		if err != nil {
+			return nil, fmt.Errorf("desktop notifications need notify-send (libnotify)")
+		}
+		return desktopNotifier{path: path}, nil
+	case "bell":
+		terminal := opts.Terminal
+		if terminal == nil {
+			terminal = os.Stdout
+		}
+		return bellNotifier{w: terminal}, nil
+	case "command":
+		if opts.Command == "" {
+			return nil, fmt.Errorf("the command notifier needs reminders.command")
+		}
+		return commandNotifier{command: opts.Command}, nil
+	case "webhook":
+		if opts.Webhook == "" {
+			return nil, fmt.Errorf("the webhook notifier needs reminders.webhook")
+		}
+		return webhookNotifier{url: opts.Webhook, client: &http.Client{Timeout: 5 * time.Second}}, nil
+	}
+	return nil, fmt.Errorf("unknown notifier '%s' (use desktop, bell, command or webhook)", name)
+}
+
+// Payload is the JSON sent to commands and webhooks
+type Payload struct {
+	Message string        `json:"message"`
+	Due     string        `json:"due"`    // RFC 3339
+	Offset  string        `json:"offset"` // e.g. "1h", empty for a snoozed reminder
+	Snoozed bool          `json:"snoozed,omitempty"`
+	Task    taskdata.Task `json:"task"`
+}
+
+// NewPayload describes a reminder for commands and webhooks
+func NewPayload(r Reminder, now time.Time) Payload {
+	payload := Payload{
+		Message: r.Message(now),
+		Due:     r.Due.Format(taskdata.TimestampFormat),
+		Snoozed: r.Snoozed,
+		Task:    r.Task,
+	}
+	if !r.Snoozed {
+		payload.Offset = FormatOffset(r.Offset)
+	}
+	return payload
+}
+
+// desktopNotifier shows a desktop notification through notify-send (D-Bus)
+type desktopNotifier struct {
+	path string
+}
+
+func (n desktopNotifier) Notify(r Reminder, now time.Time) error {
+	urgency := "normal"
+	if taskdata.PriorityRank(r.Task.Priority) == len(taskdata.Priorities()) {
+		urgency = "critical"
+	}
+	return exec.Command(n.path, "--app-name=todo", "--urgency="+urgency, r.Title(), r.Message(now)).Run()
+}
+
+// bellNotifier rings the terminal bell and prints the reminder
+type bellNotifier struct {
+	w io.Writer
+}
+
+func (n bellNotifier) Notify(r Reminder, now time.Time) error {
+	_, err := fmt.Fprintf(n.w, "\a🔔 %s %s\n", now.Format("15:04"), r.Message(now))
+	return err
+}
+
+// commandNotifier runs a shell command with the reminder as JSON on stdin
+// and its main fields in TODO_* environment variables
+type commandNotifier struct {
+	command string
+}
+
+func (n commandNotifier) Notify(r Reminder, now time.Time) error {
+	data, err := json.Marshal(NewPayload(r, now))
+	if err != nil {
+		return err
+	}
+
+	cmd := exec.Command("/bin/sh", "-c", n.command)
+	cmd.Stdin = bytes.NewReader(data)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.Env = append(os.Environ(),
+		"TODO_ID="+strconv.Itoa(r.Task.ID),
+		"TODO_DESCRIPTION="+r.Task.Description,
+		"TODO_DUE="+r.Due.Format(taskdata.TimestampFormat),
+		"TODO_MESSAGE="+r.Message(now),
+	)
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("reminder command failed: %v", err)
+	}
+	return nil
+}
+
+// webhookNotifier posts the reminder as JSON
+type webhookNotifier struct {
+	url    string
+	client *http.Client
+}
+
+func (n webhookNotifier) Notify(r Reminder, now time.Time) error {
+	data, err := json.Marshal(NewPayload(r, now))
+	if err != nil {
+		return err
+	}
+
+	resp, err := n.client.Post(n.url, "application/json", bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("webhook failed: %v", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
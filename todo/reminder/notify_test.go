@@ -0,0 +1,160 @@
+package reminder
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"todo/taskdata"
+)
+
+func testReminder() (Reminder, time.Time) {
+	due := time.Date(2025, time.July, 16, 10, 0, 0, 0, time.Local)
+	r := Reminder{
+		Task:   taskdata.Task{ID: 3, UID: "a", Description: "Dentist", DueDate: "2025-07-16", DueTime: "10:00"},
+		Due:    due,
+		Offset: time.Hour,
+	}
+	return r, due.Add(-time.Hour)
+}
+
+func TestNewNotifier(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    Options
+		wantErr string
+	}{
+		{"bell", Options{}, ""},
+		{"command", Options{Command: "true"}, ""},
+		{"command", Options{}, "needs reminders.command"},
+		{"webhook", Options{Webhook: "http://localhost/"}, ""},
+		{"webhook", Options{}, "needs reminders.webhook"},
+		{"pager", Options{}, "unknown notifier 'pager'"},
+	}
+	for _, tt := range tests {
+		_, err := NewNotifier(tt.name, tt.opts)
+		switch {
+		case tt.wantErr == "" && err != nil:
+			t.Errorf("NewNotifier(%s): %v", tt.name, err)
+		case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+			t.Errorf("NewNotifier(%s) error = %v, want %q", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestNewPayload(t *testing.T) {
+	r, now := testReminder()
+	payload := NewPayload(r, now)
+	if payload.Message != "#3 Dentist is due in 1h" || payload.Offset != "1h" || payload.Snoozed {
+		t.Errorf("payload %+v", payload)
+	}
+	if payload.Due != r.Due.Format(taskdata.TimestampFormat) {
+		t.Errorf("due %q", payload.Due)
+	}
+
+	r.Snoozed = true
+	if payload := NewPayload(r, now); payload.Offset != "" || !payload.Snoozed {
+		t.Errorf("snoozed payload %+v", payload)
+	}
+}
+
+func TestBellNotifier(t *testing.T) {
+	var terminal bytes.Buffer
+	notifier, err := NewNotifier("bell", Options{Terminal: &terminal})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, now := testReminder()
+	if err := notifier.Notify(r, now); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := terminal.String(), "\a🔔 09:00 #3 Dentist is due in 1h\n"; got != want {
+		t.Errorf("bell wrote %q, want %q", got, want)
+	}
+}
+
+func TestCommandNotifier(t *testing.T) {
+	if _, err := os.Stat("/bin/sh"); err != nil {
+		t.Skip("no /bin/sh")
+	}
+	dir := t.TempDir()
+	stdin, env := filepath.Join(dir, "stdin"), filepath.Join(dir, "env")
+	r, now := testReminder()
+
+	command := `cat > "` + stdin + `"; printf '%s|%s|%s|%s' "$TODO_ID" "$TODO_DESCRIPTION" "$TODO_DUE" "$TODO_MESSAGE" > "` + env + `"`
+	notifier, err := NewNotifier("command", Options{Command: command})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := notifier.Notify(r, now); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(stdin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var payload Payload
+	if err := json.Unmarshal(data, &payload); err != nil {
+		t.Fatalf("stdin %q: %v", data, err)
+	}
+	if payload.Task.ID != 3 || payload.Offset != "1h" {
+		t.Errorf("payload %+v", payload)
+	}
+	vars, err := os.ReadFile(env)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "3|Dentist|" + r.Due.Format(taskdata.TimestampFormat) + "|#3 Dentist is due in 1h"
+	if string(vars) != want {
+		t.Errorf("environment %q, want %q", vars, want)
+	}
+
+	notifier, _ = NewNotifier("command", Options{Command: "exit 3"})
+	if err := notifier.Notify(r, now); err == nil || !strings.Contains(err.Error(), "reminder command failed") {
+		t.Errorf("failing command returned %v", err)
+	}
+}
+
+func TestWebhookNotifier(t *testing.T) {
+	tests := []struct {
+		status  int
+		wantErr bool
+	}{
+		{http.StatusOK, false},
+		{http.StatusNoContent, false},
+		{http.StatusNotFound, true},
+		{http.StatusInternalServerError, true},
+	}
+	r, now := testReminder()
+	for _, tt := range tests {
+		var got Payload
+		var contentType string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			contentType = req.Header.Get("Content-Type")
+			body, _ := io.ReadAll(req.Body)
+			json.Unmarshal(body, &got)
+			w.WriteHeader(tt.status)
+		}))
+
+		notifier, err := NewNotifier("webhook", Options{Webhook: server.URL})
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = notifier.Notify(r, now)
+		server.Close()
+		if (err != nil) != tt.wantErr {
+			t.Errorf("status %d: error %v, want error %v", tt.status, err, tt.wantErr)
+		}
+		if contentType != "application/json" || got.Task.ID != 3 || got.Message != "#3 Dentist is due in 1h" {
+			t.Errorf("status %d: posted %s %+v", tt.status, contentType, got)
+		}
+	}
+}
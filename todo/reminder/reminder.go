@@ -0,0 +1,160 @@
+// Package reminder decides when to remind about tasks before they are due
+// and delivers the reminders through notifiers.
+package reminder
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"todo/taskdata"
+)
+
+// MissedWindow is how late a reminder may still fire, e.g. after the daemon
+// was not running; older reminders are skipped
+const MissedWindow = 12 * time.Hour
+
+// Reminder is a notification about a task
+type Reminder struct {
+	Task    taskdata.Task
+	Due     time.Time
+	Offset  time.Duration // How long before Due the reminder was set for
+	Snoozed bool          // Fired again after a snooze ran out
+}
+
+// ParseOffset parses a reminder offset such as "1d", "2h" or "30m"
+func ParseOffset(offset string) (time.Duration, error) {
+	offset = strings.TrimSpace(offset)
+	if days, ok := strings.CutSuffix(offset, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid offset '%s'", offset)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	d, err := time.ParseDuration(offset)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid offset '%s'. Use e.g. 1d, 2h or 30m", offset)
+	}
+	return d, nil
+}
+
+// FormatOffset shows an offset the way ParseOffset reads it
+func FormatOffset(offset time.Duration) string {
+	switch {
+	case offset == 0:
+		return "0m"
+	case offset%(24*time.Hour) == 0:
+		return fmt.Sprintf("%dd", offset/(24*time.Hour))
+	case offset%time.Hour == 0:
+		return fmt.Sprintf("%dh", offset/time.Hour)
+	case offset%time.Minute == 0:
+		return fmt.Sprintf("%dm", offset/time.Minute)
+	}
+	return offset.String()
+}
+
+// DueAt returns when a task is due in local time. Tasks due on a day
+// without a time are due at allDayTime (HH:MM) that day.
+func DueAt(task taskdata.Task, allDayTime string) (time.Time, bool) {
+	if task.DueDate == "" {
+		return time.Time{}, false
+	}
+	clock := task.DueTime
+	if clock == "" {
+		clock = allDayTime
+	}
+	due, err := time.ParseInLocation("2006-01-02 15:04", task.DueDate+" "+clock, time.Local)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return due, true
+}
+
+// Check returns the reminder to fire for a task now, if any, and records
+// it in the states so it fires only once. When several offsets have passed
+// since the last check, only the latest one fires. While the task is
+// snoozed nothing fires; when the snooze runs out the reminder fires again.
+func (states *States) Check(task taskdata.Task, offsets []time.Duration, allDayTime string, now time.Time) *Reminder {
+	s := states.get(task.UID)
+	due, ok := DueAt(task, allDayTime)
+	if task.Completed || !ok {
+		states.set(task.UID, state{})
+		return nil
+	}
+
+	// A new due date re-arms every offset
+	dueKey := due.Format(taskdata.TimestampFormat)
+	if s.Due != dueKey {
+		s = state{Due: dueKey, SnoozedUntil: s.SnoozedUntil}
+	}
+
+	var fire *Reminder
+	for _, offset := range offsets {
+		key := FormatOffset(offset)
+		at := due.Add(-offset)
+		if at.After(now) || slices.Contains(s.Sent, key) {
+			continue
+		}
+		s.Sent = append(s.Sent, key)
+		if now.Sub(at) > MissedWindow {
+			continue
+		}
+		if fire == nil || offset < fire.Offset {
+			fire = &Reminder{Task: task, Due: due, Offset: offset}
+		}
+	}
+
+	if s.SnoozedUntil != "" {
+		until, err := time.Parse(taskdata.TimestampFormat, s.SnoozedUntil)
+		switch {
+		case err == nil && until.After(now):
+			fire = nil // Still snoozed; what passed in the meantime stays sent
+		default:
+			s.SnoozedUntil = ""
+			fire = &Reminder{Task: task, Due: due, Snoozed: true}
+		}
+	}
+
+	states.set(task.UID, s)
+	return fire
+}
+
+// Snooze holds back a task's reminders until the given time, then reminds
+// again
+func (states *States) Snooze(task taskdata.Task, until time.Time) {
+	s := states.get(task.UID)
+	s.SnoozedUntil = until.Format(taskdata.TimestampFormat)
+	states.set(task.UID, s)
+}
+
+// SnoozedUntil returns when a task's snooze runs out, if it is snoozed
+func (states *States) SnoozedUntil(task taskdata.Task) (time.Time, bool) {
+	until, err := time.Parse(taskdata.TimestampFormat, states.get(task.UID).SnoozedUntil)
+	return until, err == nil
+}
+
+// Title is a short summary for notification headings
+func (r Reminder) Title() string {
+	return fmt.Sprintf("Task #%d due %s", r.Task.ID, r.Due.Format("Mon 15:04"))
+}
+
+// Message describes the reminder relative to now
+func (r Reminder) Message(now time.Time) string {
+	left := r.Due.Sub(now).Round(time.Minute)
+	var when string
+	switch {
+	case left > 0:
+		when = "is due in " + FormatOffset(left)
+	case left > -time.Minute:
+		when = "is due now"
+	default:
+		when = "is overdue by " + FormatOffset(-left)
+	}
+	if r.Snoozed {
+		when += " (snoozed)"
+	}
+	return fmt.Sprintf("#%d %s %s", r.Task.ID, r.Task.Description, when)
+}
@@ -0,0 +1,186 @@
+package reminder
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"todo/taskdata"
+)
+
+// newStates returns empty states kept in a temporary directory
+func newStates(t *testing.T) *States {
+	t.Helper()
+	states, err := LoadStates(filepath.Join(t.TempDir(), "tasks.reminders.json"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return states
+}
+
+func TestParseOffset(t *testing.T) {
+	tests := []struct {
+		offset string
+		want   time.Duration
+		format string
+	}{
+		{"1d", 24 * time.Hour, "1d"},
+		{" 2d ", 48 * time.Hour, "2d"},
+		{"0d", 0, "0m"},
+		{"2h", 2 * time.Hour, "2h"},
+		{"90m", 90 * time.Minute, "90m"},
+		{"1h30m", 90 * time.Minute, "90m"},
+		{"48h", 48 * time.Hour, "2d"},
+		{"0m", 0, "0m"},
+		{"45s", 45 * time.Second, "45s"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.offset, func(t *testing.T) {
+			got, err := ParseOffset(tt.offset)
+			if err != nil || got != tt.want {
+				t.Fatalf("ParseOffset = %v, %v, want %v", got, err, tt.want)
+			}
+			if format := FormatOffset(got); format != tt.format {
+				t.Errorf("FormatOffset = %q, want %q", format, tt.format)
+			}
+		})
+	}
+
+	for _, offset := range []string{"", "d", "-1d", "-2h", "1w", "soon", "1.5d"} {
+		if _, err := ParseOffset(offset); err == nil {
+			t.Errorf("ParseOffset(%q) succeeded", offset)
+		}
+	}
+}
+
+func TestDueAt(t *testing.T) {
+	tests := []struct {
+		task taskdata.Task
+		want string
+	}{
+		{taskdata.Task{DueDate: "2025-07-16", DueTime: "14:30"}, "2025-07-16 14:30"},
+		{taskdata.Task{DueDate: "2025-07-16"}, "2025-07-16 09:00"},
+		{taskdata.Task{}, ""},
+		{taskdata.Task{DueDate: "someday"}, ""},
+	}
+	for _, tt := range tests {
+		due, ok := DueAt(tt.task, "09:00")
+		if got := due.Format("2006-01-02 15:04"); ok != (tt.want != "") || ok && got != tt.want {
+			t.Errorf("DueAt(%s %s) = %s, %v, want %q", tt.task.DueDate, tt.task.DueTime, got, ok, tt.want)
+		}
+		if ok && due.Location() != time.Local {
+			t.Errorf("due in %v, want local time", due.Location())
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	task := taskdata.Task{ID: 1, UID: "a", Description: "Dentist", DueDate: "2025-07-16", DueTime: "10:00"}
+	due := time.Date(2025, time.July, 16, 10, 0, 0, 0, time.Local)
+	offsets := []time.Duration{24 * time.Hour, time.Hour, 0}
+
+	// Each step checks at a time and expects the offset that fires, or -1
+	tests := []struct {
+		name  string
+		steps []time.Duration // Time relative to due
+		want  []time.Duration
+	}{
+		{"each offset fires once", []time.Duration{-25 * time.Hour, -24 * time.Hour, -23 * time.Hour, -time.Hour, -time.Minute, 0, time.Minute},
+			[]time.Duration{-1, 24 * time.Hour, -1, time.Hour, -1, 0, -1}},
+		{"only the latest of several passed offsets fires", []time.Duration{-30 * time.Minute, 0},
+			[]time.Duration{time.Hour, 0}},
+		{"reminders older than the missed window are skipped", []time.Duration{-time.Hour + MissedWindow + time.Minute},
+			[]time.Duration{0}},
+		{"long missed reminders are all skipped", []time.Duration{MissedWindow + time.Hour},
+			[]time.Duration{-1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			states := newStates(t)
+			for i, step := range tt.steps {
+				r := states.Check(task, offsets, "09:00", due.Add(step))
+				switch {
+				case tt.want[i] < 0 && r != nil:
+					t.Errorf("at %v: fired %v, want nothing", step, r.Offset)
+				case tt.want[i] >= 0 && (r == nil || r.Offset != tt.want[i]):
+					t.Errorf("at %v: fired %+v, want offset %v", step, r, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestCheckRearmsOnNewDueDate(t *testing.T) {
+	states := newStates(t)
+	task := taskdata.Task{ID: 1, UID: "a", Description: "Dentist", DueDate: "2025-07-16", DueTime: "10:00"}
+	now := time.Date(2025, time.July, 16, 9, 30, 0, 0, time.Local)
+	offsets := []time.Duration{time.Hour}
+
+	if r := states.Check(task, offsets, "09:00", now); r == nil {
+		t.Fatal("the reminder did not fire")
+	}
+	task.DueTime = "10:15"
+	if r := states.Check(task, offsets, "09:00", now); r == nil {
+		t.Error("the reminder did not fire again for the new due time")
+	}
+
+	task.Completed = true
+	if r := states.Check(task, offsets, "09:00", now); r != nil || len(states.tasks) != 0 {
+		t.Errorf("a completed task fired %+v or kept state %v", r, states.tasks)
+	}
+}
+
+func TestSnooze(t *testing.T) {
+	states := newStates(t)
+	task := taskdata.Task{ID: 1, UID: "a", Description: "Dentist", DueDate: "2025-07-16", DueTime: "10:00"}
+	due := time.Date(2025, time.July, 16, 10, 0, 0, 0, time.Local)
+	offsets := []time.Duration{time.Hour, 0}
+
+	if r := states.Check(task, offsets, "09:00", due.Add(-time.Hour)); r == nil {
+		t.Fatal("the reminder did not fire")
+	}
+	states.Snooze(task, due.Add(30*time.Minute))
+	if until, ok := states.SnoozedUntil(task); !ok || !until.Equal(due.Add(30*time.Minute)) {
+		t.Errorf("snoozed until %v, %v", until, ok)
+	}
+
+	if r := states.Check(task, offsets, "09:00", due); r != nil {
+		t.Errorf("fired %+v while snoozed", r)
+	}
+	r := states.Check(task, offsets, "09:00", due.Add(30*time.Minute))
+	if r == nil || !r.Snoozed {
+		t.Fatalf("fired %+v when the snooze ran out, want a snoozed reminder", r)
+	}
+	if r := states.Check(task, offsets, "09:00", due.Add(31*time.Minute)); r != nil {
+		t.Errorf("fired %+v again after the snooze", r)
+	}
+	if _, ok := states.SnoozedUntil(task); ok {
+		t.Error("the task is still snoozed")
+	}
+}
+
+func TestMessage(t *testing.T) {
+	due := time.Date(2025, time.July, 16, 10, 0, 0, 0, time.Local)
+	r := Reminder{Task: taskdata.Task{ID: 3, Description: "Dentist"}, Due: due}
+
+	tests := []struct {
+		now     time.Time
+		snoozed bool
+		want    string
+	}{
+		{due.Add(-time.Hour), false, "#3 Dentist is due in 1h"},
+		{due.Add(-24 * time.Hour), false, "#3 Dentist is due in 1d"},
+		{due.Add(-20 * time.Second), false, "#3 Dentist is due now"},
+		{due.Add(15 * time.Minute), false, "#3 Dentist is overdue by 15m"},
+		{due.Add(-time.Hour), true, "#3 Dentist is due in 1h (snoozed)"},
+	}
+	for _, tt := range tests {
+		r.Snoozed = tt.snoozed
+		if got := r.Message(tt.now); got != tt.want {
+			t.Errorf("Message = %q, want %q", got, tt.want)
+		}
+	}
+	if got := r.Title(); got != "Task #3 due Wed 10:00" {
+		t.Errorf("Title = %q", got)
+	}
+}
@@ -0,0 +1,123 @@
+package reminder
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+
+	"todo/taskdata"
+)
+
+// legacyExtraKey is where older versions kept reminder state, in
+// Task.Extra; it is read once to seed a new state file
+const legacyExtraKey = "reminder"
+
+// state is what has been sent for one task
+type state struct {
+	Due          string   `json:"due,omitempty"`  // Due time the sent offsets belong to
+	Sent         []string `json:"sent,omitempty"` // Offsets already fired for Due
+	SnoozedUntil string   `json:"snoozed_until,omitempty"`
+}
+
+func (s state) empty() bool {
+	return len(s.Sent) == 0 && s.SnoozedUntil == ""
+}
+
+// States is the reminder state of a task list, by task UID. It is kept in
+// its own file next to the tasks, so sending reminders never changes the
+// tasks themselves. Hold the file's lock (taskdata.LockFile) from Load to
+// Save.
+type States struct {
+	path    string
+	tasks   map[string]state
+	changed bool
+}
+
+// StatePath returns the state file for a tasks file, e.g.
+// ~/.todo/tasks.reminders.json for ~/.todo/tasks.json
+func StatePath(dataFile string) string {
+	return strings.TrimSuffix(dataFile, ".json") + ".reminders.json"
+}
+
+// LoadStates reads a state file. When there is none yet, the state older
+// versions kept in the tasks is taken over.
+func LoadStates(path string, tasks []taskdata.Task) (*States, error) {
+	states := &States{path: path, tasks: map[string]state{}}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		for _, task := range tasks {
+			var s state
+			if raw, ok := task.Extra[legacyExtraKey]; ok && json.Unmarshal(raw, &s) == nil && !s.empty() {
+				states.tasks[task.UID] = s
+				states.changed = true
+			}
+		}
+		return states, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read reminder state: %v", err)
+	}
+	if err := json.Unmarshal(data, &states.tasks); err != nil {
+		return nil, fmt.Errorf("failed to parse reminder state %s: %v", path, err)
+	}
+	return states, nil
+}
+
+// Save writes the state file if anything changed since it was loaded
+func (states *States) Save() error {
+	if !states.changed {
+		return nil
+	}
+	data, err := json.MarshalIndent(states.tasks, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal reminder state: %v", err)
+	}
+	// Replace the file through a temporary one, so a crash never leaves
+	// it half written
+	temp := states.path + ".tmp"
+	if err := os.WriteFile(temp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write reminder state: %v", err)
+	}
+	if err := os.Rename(temp, states.path); err != nil {
+		os.Remove(temp)
+		return fmt.Errorf("failed to write reminder state: %v", err)
+	}
+	states.changed = false
+	return nil
+}
+
+// Prune forgets tasks that are no longer in the list
+func (states *States) Prune(tasks []taskdata.Task) {
+	uids := make(map[string]bool, len(tasks))
+	for _, task := range tasks {
+		uids[task.UID] = true
+	}
+	for uid := range states.tasks {
+		if !uids[uid] {
+			delete(states.tasks, uid)
+			states.changed = true
+		}
+	}
+}
+
+func (states *States) get(uid string) state {
+	return states.tasks[uid]
+}
+
+// set stores s unless nothing changed, so checks that fire nothing leave
+// the file alone
+func (states *States) set(uid string, s state) {
+	_, kept := states.tasks[uid]
+	switch {
+	case s.empty():
+		if kept {
+			delete(states.tasks, uid)
+			states.changed = true
+		}
+	case !kept || !reflect.DeepEqual(s, states.tasks[uid]):
+		states.tasks[uid] = s
+		states.changed = true
+	}
+}
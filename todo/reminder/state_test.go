@@ -0,0 +1,144 @@
+package reminder
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"todo/taskdata"
+)
+
+func TestStatePath(t *testing.T) {
+	tests := []struct{ dataFile, want string }{
+		{"/home/a/.todo/tasks.json", "/home/a/.todo/tasks.reminders.json"},
+		{"/home/a/.todo/lists/work.json", "/home/a/.todo/lists/work.reminders.json"},
+		{"tasks", "tasks.reminders.json"},
+	}
+	for _, tt := range tests {
+		if got := StatePath(tt.dataFile); got != tt.want {
+			t.Errorf("StatePath(%q) = %q, want %q", tt.dataFile, got, tt.want)
+		}
+	}
+}
+
+func TestSaveAndLoadStates(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tasks.reminders.json")
+	task := taskdata.Task{ID: 1, UID: "a", Description: "Dentist", DueDate: "2025-07-16", DueTime: "10:00"}
+	due := time.Date(2025, time.July, 16, 10, 0, 0, 0, time.Local)
+	offsets := []time.Duration{time.Hour, 0}
+
+	states, err := LoadStates(path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Nothing fires yet, so nothing is written
+	states.Check(task, offsets, "09:00", due.Add(-2*time.Hour))
+	if err := states.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("a check that fired nothing wrote the state file (%v)", err)
+	}
+
+	if r := states.Check(task, offsets, "09:00", due.Add(-time.Hour)); r == nil {
+		t.Fatal("the reminder did not fire")
+	}
+	if err := states.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path + ".tmp"); !os.IsNotExist(err) {
+		t.Errorf("the temporary file was left behind (%v)", err)
+	}
+
+	states, err = LoadStates(path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r := states.Check(task, offsets, "09:00", due.Add(-time.Hour)); r != nil {
+		t.Errorf("fired %+v again after reloading", r)
+	}
+	if r := states.Check(task, offsets, "09:00", due); r == nil || r.Offset != 0 {
+		t.Errorf("fired %+v at the due time, want offset 0", r)
+	}
+}
+
+func TestLoadStatesErrors(t *testing.T) {
+	dir := t.TempDir()
+	broken := filepath.Join(dir, "broken.reminders.json")
+	if err := os.WriteFile(broken, []byte("{"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadStates(broken, nil); err == nil {
+		t.Error("loaded a broken state file")
+	}
+	if _, err := LoadStates(dir, nil); err == nil {
+		t.Error("loaded a directory as a state file")
+	}
+}
+
+func TestLoadStatesMigratesLegacyState(t *testing.T) {
+	legacy := func(s string) map[string]json.RawMessage {
+		return map[string]json.RawMessage{legacyExtraKey: json.RawMessage(s)}
+	}
+	tasks := []taskdata.Task{
+		{ID: 1, UID: "a", DueDate: "2025-07-16", DueTime: "10:00", Extra: legacy(`{"due":"2025-07-16T10:00:00Z","sent":["1h"]}`)},
+		{ID: 2, UID: "b", DueDate: "2025-07-16", Extra: legacy(`{}`)},
+		{ID: 3, UID: "c", DueDate: "2025-07-16", Extra: legacy(`"broken"`)},
+		{ID: 4, UID: "d", DueDate: "2025-07-16"},
+	}
+	path := filepath.Join(t.TempDir(), "tasks.reminders.json")
+
+	states, err := LoadStates(path, tasks)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(states.tasks) != 1 || len(states.get("a").Sent) != 1 {
+		t.Fatalf("migrated %+v, want the state of task a only", states.tasks)
+	}
+	if err := states.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Once the file exists, the tasks are no longer read
+	tasks[1].Extra = legacy(`{"sent":["0m"]}`)
+	states, err = LoadStates(path, tasks)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := states.tasks["b"]; ok || len(states.tasks) != 1 {
+		t.Errorf("state %+v, want the saved state only", states.tasks)
+	}
+}
+
+func TestPrune(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tasks.reminders.json")
+	states, err := LoadStates(path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := taskdata.Task{ID: 1, UID: "a", DueDate: "2025-07-16"}
+	b := taskdata.Task{ID: 2, UID: "b", DueDate: "2025-07-16"}
+	until := time.Date(2025, time.July, 16, 12, 0, 0, 0, time.Local)
+	states.Snooze(a, until)
+	states.Snooze(b, until)
+	if err := states.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	states.Prune([]taskdata.Task{a, b})
+	if states.changed {
+		t.Error("pruning known tasks changed the state")
+	}
+	states.Prune([]taskdata.Task{b})
+	if _, ok := states.SnoozedUntil(a); ok {
+		t.Error("a removed task is still snoozed")
+	}
+	if _, ok := states.SnoozedUntil(b); !ok {
+		t.Error("a kept task is no longer snoozed")
+	}
+	if !states.changed {
+		t.Error("pruning a task left the state unchanged")
+	}
+}
@@ -0,0 +1,60 @@
+package search
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"todo/taskdata"
+)
+
+// maxCandidates limits how many ambiguous matches are offered for disambiguation
+const maxCandidates = 5
+
+// Resolve finds the tasks identified by an ID, a UID or a name.
+// A single Exact result means the identifier is unambiguous. Anything
+// else, including a single fuzzy match, is a ranked list of candidates the
+// caller should ask the user to choose from or confirm.
+func Resolve(tasks []taskdata.Task, identifier string) ([]Result, error) {
+	identifier = strings.TrimSpace(identifier)
+	if identifier == "" {
+		return nil, fmt.Errorf("task ID or name cannot be empty")
+	}
+
+	// Try to parse as ID first; a number that is no task's ID may still
+	// name one, such as "2025" in "File 2025 taxes"
+	id, idErr := strconv.Atoi(identifier)
+	if idErr == nil {
+		for _, task := range tasks {
+			if task.ID == id {
+				return []Result{{Task: task, Score: 1.0, Field: "id"}}, nil
+			}
+		}
+	}
+	for _, task := range tasks {
+		if task.UID != "" && strings.EqualFold(task.UID, identifier) {
+			return []Result{{Task: task, Score: 1.0, Field: "uid"}}, nil
+		}
+	}
+
+	results, err := Search(tasks, identifier, Options{
+		IncludeCompleted: true,
+		Limit:            maxCandidates,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(results) == 0 {
+		if idErr == nil {
+			return nil, fmt.Errorf("task with ID %d not found", id)
+		}
+		return nil, fmt.Errorf("no tasks found matching '%s'", identifier)
+	}
+
+	// An exact description match wins outright unless it is itself ambiguous
+	if results[0].Exact() && (len(results) == 1 || !results[1].Exact()) {
+		return results[:1], nil
+	}
+
+	return results, nil
+}
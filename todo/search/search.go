@@ -0,0 +1,221 @@
+package search
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"todo/taskdata"
+)
+
+const (
+	// DefaultThreshold is the minimum score a task needs to be returned
+	DefaultThreshold = 0.5
+
+	// notesWeight scales matches found in notes so description matches rank first
+	notesWeight = 0.8
+)
+
+// Options controls how a search is performed
+type Options struct {
+	Regex            bool    // Treat the query as a regular expression
+	IncludeCompleted bool    // Also search completed tasks
+	Threshold        float64 // Minimum score (0 uses DefaultThreshold)
+	Limit            int     // Maximum number of results (0 means unlimited)
+}
+
+// Result is a single ranked search hit
+type Result struct {
+	Task  taskdata.Task
+	Score float64 // 0.0-1.0, higher is better
+	Field string  // "description" or "notes"; "id" or "uid" from Resolve
+}
+
+// Exact reports whether the result names its task outright: by ID, by UID
+// or by a description equal to the query, rather than by a fuzzy match
+func (r Result) Exact() bool {
+	return r.Field == "id" || r.Field == "uid" || (r.Field == "description" && r.Score == 1.0)
+}
+
+// Search ranks tasks against the query using fuzzy matching over descriptions and notes
+func Search(tasks []taskdata.Task, query string, opts Options) ([]Result, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, fmt.Errorf("search query cannot be empty")
+	}
+
+	var re *regexp.Regexp
+	if opts.Regex {
+		var err error
+		re, err = regexp.Compile("(?i)" + query)
+		if err != nil {
+			return nil, fmt.Errorf("invalid regular expression '%s': %v", query, err)
+		}
+	}
+
+	threshold := opts.Threshold
+	if threshold <= 0 {
+		threshold = DefaultThreshold
+	}
+
+	var results []Result
+	for _, task := range tasks {
+		if task.Completed && !opts.IncludeCompleted {
+			continue
+		}
+
+		var descScore, notesScore float64
+		if re != nil {
+			if re.MatchString(task.Description) {
+				descScore = 1.0
+			}
+			if task.Notes != "" && re.MatchString(task.Notes) {
+				notesScore = notesWeight
+			}
+		} else {
+			descScore = Score(task.Description, query)
+			if task.Notes != "" {
+				notesScore = Score(task.Notes, query) * notesWeight
+			}
+		}
+
+		result := Result{Task: task, Score: descScore, Field: "description"}
+		if notesScore > descScore {
+			result.Score = notesScore
+			result.Field = "notes"
+		}
+
+		if result.Score >= threshold {
+			results = append(results, result)
+		}
+	}
+
+	// Sort by score (highest first), then by ID for stable output
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].Task.ID < results[j].Task.ID
+	})
+
+	if opts.Limit > 0 && len(results) > opts.Limit {
+		results = results[:opts.Limit]
+	}
+
+	return results, nil
+}
+
+// Score returns how well text matches the query, from 0.0 (no match) to 1.0 (exact)
+func Score(text, query string) float64 {
+	text = normalize(text)
+	query = normalize(query)
+
+	if text == "" || query == "" {
+		return 0.0
+	}
+
+	// Exact match
+	if text == query {
+		return 1.0
+	}
+
+	// Whole phrase found in the text, scored by how much of the text it
+	// covers so that "tax" ranks "Pay taxes" above a long note mentioning it
+	if strings.Contains(text, query) {
+		coverage := float64(len([]rune(query))) / float64(len([]rune(text)))
+		if strings.HasPrefix(text, query) {
+			return 0.75 + 0.2*coverage
+		}
+		return 0.65 + 0.25*coverage
+	}
+
+	// Word-by-word matching with prefix and typo tolerance
+	textWords := strings.Fields(text)
+	queryWords := strings.Fields(query)
+
+	total := 0.0
+	for _, qw := range queryWords {
+		best := 0.0
+		for _, tw := range textWords {
+			if s := wordScore(tw, qw); s > best {
+				best = s
+			}
+		}
+		total += best
+	}
+
+	return total / float64(len(queryWords)) * 0.85
+}
+
+// wordScore compares a single word of text against a single query word
+func wordScore(word, query string) float64 {
+	switch {
+	case word == query:
+		return 1.0
+	case strings.HasPrefix(word, query):
+		return 0.9
+	case len(query) >= 3 && strings.Contains(word, query):
+		return 0.7
+	}
+
+	// Typo tolerance: allow one edit for short words, two for longer ones
+	if len(query) < 3 {
+		return 0.0
+	}
+	maxEdits := 1
+	if len(query) > 5 {
+		maxEdits = 2
+	}
+
+	// Compare against the word and against its prefix of the same length
+	// so that "grocer" still matches "groceries" with a typo
+	distance := levenshtein(word, query)
+	if rw, rq := []rune(word), []rune(query); len(rw) > len(rq) {
+		if d := levenshtein(string(rw[:len(rq)]), query); d < distance {
+			distance = d
+		}
+	}
+
+	if distance <= maxEdits {
+		return 0.8 - 0.1*float64(distance)
+	}
+	return 0.0
+}
+
+// normalize lowercases text and collapses punctuation and whitespace
+func normalize(text string) string {
+	text = strings.ToLower(text)
+	text = strings.Map(func(r rune) rune {
+		switch r {
+		case ',', '.', ';', ':', '!', '?', '"', '\'', '(', ')', '[', ']':
+			return ' '
+		}
+		return r
+	}, text)
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// levenshtein returns the edit distance between two strings
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+
+	return prev[len(rb)]
+}
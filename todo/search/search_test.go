@@ -0,0 +1,143 @@
+package search
+
+import (
+	"testing"
+
+	"todo/taskdata"
+)
+
+var testTasks = []taskdata.Task{
+	{ID: 1, UID: "a1b2c3d4-0000-4000-8000-000000000001", Description: "Buy groceries", Notes: "milk and eggs"},
+	{ID: 2, UID: "a1b2c3d4-0000-4000-8000-000000000002", Description: "Pay taxes"},
+	{ID: 3, UID: "a1b2c3d4-0000-4000-8000-000000000003", Description: "File 2025 taxes"},
+	{ID: 4, UID: "a1b2c3d4-0000-4000-8000-000000000004", Description: "Write report", Completed: true},
+	{ID: 5, UID: "a1b2c3d4-0000-4000-8000-000000000005", Description: "Call mom"},
+	{ID: 6, UID: "a1b2c3d4-0000-4000-8000-000000000006", Description: "Call mom"},
+}
+
+func TestScore(t *testing.T) {
+	tests := []struct {
+		text, query string
+		min, max    float64
+	}{
+		{"Buy groceries", "buy groceries", 1, 1},
+		{"Buy groceries!", "Buy, groceries", 1, 1},
+		{"Buy groceries", "buy", 0.75, 0.95},
+		{"Pay taxes", "taxes", 0.65, 0.9},
+		{"Buy groceries", "grocer", 0.75, 0.95},
+		{"Buy groceries", "grocries", 0.5, 0.85},
+		{"Buy groceries", "gorceries buy", 0.5, 0.85},
+		{"Buy groceries", "xyz", 0, 0},
+		{"Buy groceries", "by", 0, 0.5},
+		{"", "buy", 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text+"/"+tt.query, func(t *testing.T) {
+			if got := Score(tt.text, tt.query); got < tt.min || got > tt.max {
+				t.Errorf("Score(%q, %q) = %.2f, want %.2f-%.2f", tt.text, tt.query, got, tt.min, tt.max)
+			}
+		})
+	}
+}
+
+func TestScoreRanksShorterTextsFirst(t *testing.T) {
+	if Score("Pay taxes", "tax") <= Score("Remember to look up the tax rules for next year", "tax") {
+		t.Error("a short description does not rank above a long one")
+	}
+	if Score("Taxes for 2025", "tax") <= Score("Pay taxes", "tax") {
+		t.Error("a prefix match does not rank above a match inside the text")
+	}
+}
+
+func TestSearch(t *testing.T) {
+	tests := []struct {
+		query string
+		opts  Options
+		want  []int
+	}{
+		{"taxes", Options{}, []int{2, 3}},
+		{"report", Options{}, nil},
+		{"report", Options{IncludeCompleted: true}, []int{4}},
+		{"eggs", Options{}, []int{1}},
+		{"^call", Options{Regex: true}, []int{5, 6}},
+		{"taxes", Options{Limit: 1}, []int{2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			results, err := Search(testTasks, tt.query, tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var ids []int
+			for _, result := range results {
+				ids = append(ids, result.Task.ID)
+			}
+			if len(ids) != len(tt.want) {
+				t.Fatalf("found %v, want %v", ids, tt.want)
+			}
+			for i := range ids {
+				if ids[i] != tt.want[i] {
+					t.Fatalf("found %v, want %v", ids, tt.want)
+				}
+			}
+		})
+	}
+
+	if results, _ := Search(testTasks, "eggs", Options{}); len(results) == 1 && results[0].Field != "notes" {
+		t.Errorf("eggs matched in %s, want notes", results[0].Field)
+	}
+	for _, query := range []string{"", "  "} {
+		if _, err := Search(testTasks, query, Options{}); err == nil {
+			t.Errorf("empty query %q was accepted", query)
+		}
+	}
+	if _, err := Search(testTasks, "(", Options{Regex: true}); err == nil {
+		t.Error("an invalid expression was accepted")
+	}
+}
+
+func TestResolve(t *testing.T) {
+	tests := []struct {
+		identifier string
+		want       []int
+		exact      bool
+	}{
+		{"2", []int{2}, true},
+		{" 5 ", []int{5}, true},
+		{"A1B2C3D4-0000-4000-8000-000000000003", []int{3}, true},
+		{"pay taxes", []int{2}, true},
+		{"Write report", []int{4}, true},
+		{"2025", []int{3}, false},     // No task #2025, but it names one
+		{"grocries", []int{1}, false}, // A typo is only a candidate
+		{"buy", []int{1}, false},
+		{"taxes", []int{2, 3}, false},
+		{"call mom", []int{5, 6}, true}, // Exact, but twice
+	}
+	for _, tt := range tests {
+		t.Run(tt.identifier, func(t *testing.T) {
+			results, err := Resolve(testTasks, tt.identifier)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(results) != len(tt.want) {
+				t.Fatalf("resolved to %d tasks, want %v", len(results), tt.want)
+			}
+			for i, result := range results {
+				if result.Task.ID != tt.want[i] {
+					t.Errorf("result %d is #%d, want #%d", i, result.Task.ID, tt.want[i])
+				}
+				if result.Exact() != tt.exact {
+					t.Errorf("result #%d exact = %v, want %v", result.Task.ID, result.Exact(), tt.exact)
+				}
+			}
+		})
+	}
+}
+
+func TestResolveErrors(t *testing.T) {
+	for _, identifier := range []string{"", "99", "xyz"} {
+		if results, err := Resolve(testTasks, identifier); err == nil {
+			t.Errorf("Resolve(%q) = %v, want an error", identifier, results)
+		}
+	}
+}
@@ -0,0 +1,255 @@
+// Package shell reads command lines for 'todo shell': line editing with
+// history kept in a file and tab completion on a terminal, plain lines
+// otherwise.
+package shell
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// Completer returns the candidates for the word that ends at the cursor,
+// given the words before it
+type Completer func(words []string, word string) []string
+
+// Reader reads command lines
+type Reader struct {
+	term     *term.Terminal // Nil when stdin is not a terminal
+	history  *fileHistory
+	complete Completer
+	prompt   string
+}
+
+// NewReader reads lines from stdin. On a terminal, lines are edited with
+// history (saved to historyFile) and tab completion.
+func NewReader(prompt, historyFile string, complete Completer) *Reader {
+	r := &Reader{prompt: prompt, complete: complete}
+	if !term.IsTerminal(int(os.Stdin.Fd())) || !term.IsTerminal(int(os.Stdout.Fd())) {
+		return r
+	}
+
+	screen := struct {
+		io.Reader
+		io.Writer
+	}{os.Stdin, os.Stdout}
+	r.term = term.NewTerminal(screen, prompt)
+	r.history = loadHistory(historyFile)
+	r.term.History = r.history
+	r.term.AutoCompleteCallback = r.autoComplete
+	return r
+}
+
+// SetPrompt changes the prompt for the next line
+func (r *Reader) SetPrompt(prompt string) {
+	r.prompt = prompt
+	if r.term != nil {
+		r.term.SetPrompt(prompt)
+	}
+}
+
+// ReadLine reads the next line; io.EOF means the input ended (Ctrl+D)
+func (r *Reader) ReadLine() (string, error) {
+	if r.term == nil {
+		fmt.Print(r.prompt)
+		return readPlainLine(os.Stdin)
+	}
+
+	// Raw mode only while editing, so commands print and prompt as usual
+	state, err := term.MakeRaw(int(os.Stdin.Fd()))
+	if err != nil {
+		return "", err
+	}
+	defer term.Restore(int(os.Stdin.Fd()), state)
+
+	if width, height, err := term.GetSize(int(os.Stdout.Fd())); err == nil {
+		r.term.SetSize(width, height)
+	}
+	return r.term.ReadLine()
+}
+
+// readPlainLine reads up to a newline a byte at a time, so nothing past
+// the line is consumed before commands that read stdin themselves
+func readPlainLine(in io.Reader) (string, error) {
+	var line []byte
+	buf := make([]byte, 1)
+	for {
+		n, err := in.Read(buf)
+		if n == 1 {
+			if buf[0] == '\n' {
+				return strings.TrimSuffix(string(line), "\r"), nil
+			}
+			line = append(line, buf[0])
+		}
+		if err != nil {
+			if err == io.EOF && len(line) > 0 {
+				return string(line), nil
+			}
+			return "", err
+		}
+	}
+}
+
+// autoComplete completes the word before the cursor on Tab. A single
+// candidate is filled in; with several the common prefix is filled in and
+// the candidates are listed.
+func (r *Reader) autoComplete(line string, pos int, key rune) (string, int, bool) {
+	if key != '\t' || r.complete == nil {
+		return "", 0, false
+	}
+
+	before := line[:pos]
+	words, _ := Split(before)
+	word := ""
+	if len(words) > 0 && !strings.HasSuffix(before, " ") {
+		word = words[len(words)-1]
+		words = words[:len(words)-1]
+	}
+
+	var candidates []string
+	for _, candidate := range r.complete(words, word) {
+		if strings.HasPrefix(candidate, word) {
+			candidates = append(candidates, candidate)
+		}
+	}
+	if len(candidates) == 0 {
+		return line, pos, true
+	}
+
+	completion := commonPrefix(candidates)
+	if len(candidates) == 1 {
+		completion += " "
+	} else if completion == word {
+		r.listCandidates(candidates)
+	}
+	start := pos - len(word)
+	newLine := line[:start] + completion + line[pos:]
+	return newLine, start + len(completion), true
+}
+
+func (r *Reader) listCandidates(candidates []string) {
+	sort.Strings(candidates)
+	fmt.Fprintf(r.term, "%s\n", strings.Join(candidates, "  "))
+}
+
+func commonPrefix(words []string) string {
+	prefix := words[0]
+	for _, word := range words[1:] {
+		for !strings.HasPrefix(word, prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+	return prefix
+}
+
+// Split splits a command line into words like a POSIX shell does, without
+// expansions: single and double quotes group words and a backslash escapes
+// the next character
+func Split(line string) ([]string, error) {
+	var words []string
+	var word strings.Builder
+	inWord := false
+	var quote rune
+	escaped := false
+
+	for _, c := range line {
+		switch {
+		case escaped:
+			word.WriteRune(c)
+			escaped = false
+		case c == '\\' && quote != '\'':
+			escaped = true
+			inWord = true
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			} else {
+				word.WriteRune(c)
+			}
+		case c == '\'' || c == '"':
+			quote = c
+			inWord = true
+		case c == ' ' || c == '\t':
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		default:
+			word.WriteRune(c)
+			inWord = true
+		}
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+	if quote != 0 {
+		return words, fmt.Errorf("unterminated %c quote", quote)
+	}
+	return words, nil
+}
+
+// historyLimit is how many lines the history file keeps
+const historyLimit = 1000
+
+// fileHistory is the line history, also appended to a file so it survives
+// between shells
+type fileHistory struct {
+	path    string
+	entries []string // Oldest first
+}
+
+func loadHistory(path string) *fileHistory {
+	history := &fileHistory{path: path}
+	file, err := os.Open(path)
+	if err != nil {
+		return history
+	}
+	defer file.Close()
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if line := scanner.Text(); line != "" {
+			history.entries = append(history.entries, line)
+		}
+	}
+	if len(history.entries) > historyLimit {
+		history.entries = history.entries[len(history.entries)-historyLimit:]
+		os.WriteFile(path, []byte(strings.Join(history.entries, "\n")+"\n"), 0600)
+	}
+	return history
+}
+
+// Add records a line unless it repeats the previous one
+func (h *fileHistory) Add(entry string) {
+	if entry == "" || (len(h.entries) > 0 && h.entries[len(h.entries)-1] == entry) {
+		return
+	}
+	h.entries = append(h.entries, entry)
+	if len(h.entries) > historyLimit {
+		h.entries = h.entries[1:]
+	}
+
+	if h.path == "" {
+		return
+	}
+	os.MkdirAll(filepath.Dir(h.path), 0755)
+	if file, err := os.OpenFile(h.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600); err == nil {
+		fmt.Fprintln(file, entry)
+		file.Close()
+	}
+}
+
+func (h *fileHistory) Len() int {
+	return len(h.entries)
+}
+
+// At returns the entry idx lines back, 0 being the most recent
+func (h *fileHistory) At(idx int) string {
+	return h.entries[len(h.entries)-1-idx]
+}
@@ -0,0 +1,169 @@
+package shell
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"golang.org/x/term"
+)
+
+func TestSplit(t *testing.T) {
+	tests := []struct {
+		line    string
+		want    []string
+		wantErr string
+	}{
+		{"", nil, ""},
+		{"   ", nil, ""},
+		{"add Buy milk", []string{"add", "Buy", "milk"}, ""},
+		{"  list \t --all  ", []string{"list", "--all"}, ""},
+		{`add "Buy milk" -p high`, []string{"add", "Buy milk", "-p", "high"}, ""},
+		{`add 'it''s'`, []string{"add", "its"}, ""},
+		{`add "say \"hi\""`, []string{"add", `say "hi"`}, ""},
+		{`add 'back\slash'`, []string{"add", `back\slash`}, ""},
+		{`add Buy\ milk`, []string{"add", "Buy milk"}, ""},
+		{`add ""`, []string{"add", ""}, ""},
+		{`add pre"mid"post`, []string{"add", "premidpost"}, ""},
+		{`add "Buy milk`, []string{"add", "Buy milk"}, `unterminated " quote`},
+		{`add 'Buy`, []string{"add", "Buy"}, "unterminated ' quote"},
+	}
+	for _, tt := range tests {
+		got, err := Split(tt.line)
+		if tt.wantErr == "" && err != nil || tt.wantErr != "" && (err == nil || err.Error() != tt.wantErr) {
+			t.Errorf("Split(%q) error = %v, want %q", tt.line, err, tt.wantErr)
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("Split(%q) = %q, want %q", tt.line, got, tt.want)
+		}
+	}
+}
+
+func TestReadPlainLine(t *testing.T) {
+	in := strings.NewReader("list\r\nadd Buy milk\n\ndone 3")
+	for _, want := range []string{"list", "add Buy milk", "", "done 3"} {
+		got, err := readPlainLine(in)
+		if err != nil || got != want {
+			t.Fatalf("readPlainLine = %q, %v, want %q", got, err, want)
+		}
+	}
+	if _, err := readPlainLine(in); err != io.EOF {
+		t.Errorf("error at the end = %v, want EOF", err)
+	}
+
+	// Nothing past the line is read
+	in = strings.NewReader("first\nsecond\n")
+	readPlainLine(in)
+	if rest, _ := io.ReadAll(in); string(rest) != "second\n" {
+		t.Errorf("left %q unread, want the second line", rest)
+	}
+}
+
+func TestAutoComplete(t *testing.T) {
+	commands := []string{"add", "archive", "assign", "delete", "done"}
+	complete := func(words []string, word string) []string {
+		if len(words) == 0 {
+			return commands
+		}
+		if words[0] == "tag" {
+			return []string{"+home", "+work"}
+		}
+		return nil
+	}
+
+	tests := []struct {
+		line    string
+		pos     int
+		want    string
+		wantPos int
+		listed  string
+	}{
+		{"de", 2, "delete ", 7, ""},
+		{"a", 1, "a", 1, "add  archive  assign\n"},
+		{"ar", 2, "archive ", 8, ""},
+		{"d", 1, "d", 1, "delete  done\n"},
+		{"", 0, "", 0, "add  archive  assign  delete  done\n"},
+		{"x", 1, "x", 1, ""},
+		{"tag 3 +w", 8, "tag 3 +work ", 12, ""},
+		{"tag 3 +", 7, "tag 3 +", 7, "+home  +work\n"},
+		{"list ", 5, "list ", 5, ""},
+		{"de 3", 2, "delete  3", 7, ""},
+	}
+	for _, tt := range tests {
+		var screen bytes.Buffer
+		r := &Reader{complete: complete, term: term.NewTerminal(struct {
+			io.Reader
+			io.Writer
+		}{strings.NewReader(""), &screen}, "")}
+
+		line, pos, ok := r.autoComplete(tt.line, tt.pos, '\t')
+		if !ok || line != tt.want || pos != tt.wantPos {
+			t.Errorf("complete %q at %d = %q at %d, %v, want %q at %d", tt.line, tt.pos, line, pos, ok, tt.want, tt.wantPos)
+		}
+		if listed := strings.ReplaceAll(screen.String(), "\r", ""); listed != tt.listed {
+			t.Errorf("complete %q listed %q, want %q", tt.line, listed, tt.listed)
+		}
+	}
+
+	r := &Reader{complete: complete}
+	if _, _, ok := r.autoComplete("de", 2, 'x'); ok {
+		t.Error("a key other than Tab was handled")
+	}
+	if _, _, ok := (&Reader{}).autoComplete("de", 2, '\t'); ok {
+		t.Error("Tab was handled without a completer")
+	}
+}
+
+func TestHistory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "shell", "history")
+	history := loadHistory(path)
+	for _, line := range []string{"list", "add Buy milk", "add Buy milk", "", "done 1"} {
+		history.Add(line)
+	}
+	if history.Len() != 3 || history.At(0) != "done 1" || history.At(2) != "list" {
+		t.Errorf("history %q", history.entries)
+	}
+
+	// A new shell reads the lines back
+	history = loadHistory(path)
+	if !slices.Equal(history.entries, []string{"list", "add Buy milk", "done 1"}) {
+		t.Errorf("loaded history %q", history.entries)
+	}
+
+	if history := loadHistory(filepath.Join(t.TempDir(), "missing")); history.Len() != 0 {
+		t.Errorf("missing history file loaded %q", history.entries)
+	}
+}
+
+func TestHistoryLimit(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history")
+	var lines []string
+	for i := range historyLimit + 10 {
+		lines = append(lines, "show "+strconv.Itoa(i))
+	}
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	history := loadHistory(path)
+	if history.Len() != historyLimit || history.At(historyLimit-1) != "show 10" {
+		t.Fatalf("loaded %d lines, oldest %q", history.Len(), history.At(history.Len()-1))
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Count(string(data), "\n"); got != historyLimit {
+		t.Errorf("history file has %d lines, want it trimmed to %d", got, historyLimit)
+	}
+
+	history.Add("list")
+	if history.Len() != historyLimit || history.At(0) != "list" || history.At(historyLimit-1) != "show 11" {
+		t.Errorf("after adding: %d lines, oldest %q", history.Len(), history.At(history.Len()-1))
+	}
+}
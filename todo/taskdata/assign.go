@@ -0,0 +1,58 @@
+package taskdata
+
+import (
+	"strings"
+	"time"
+)
+
+// Change is an entry in a task's history, such as a reassignment
+type Change struct {
+	At    string `json:"at"` // RFC 3339 timestamp
+	By    string `json:"by,omitempty"`
+	Field string `json:"field"`
+	From  string `json:"from,omitempty"`
+	To    string `json:"to,omitempty"`
+}
+
+// identity is the local user, set from the config file's user setting
+var identity string
+
+// SetIdentity sets the user recorded as the creator of new tasks and as the
+// author of changes
+func SetIdentity(name string) {
+	identity = strings.TrimSpace(name)
+}
+
+// Identity returns the local user
+func Identity() string {
+	return identity
+}
+
+// Assign sets the task's assignee ("" unassigns it) and records the change
+// in its history. It reports whether the assignee changed.
+func (task *Task) Assign(assignee string, now time.Time) bool {
+	assignee = strings.TrimSpace(assignee)
+	if strings.EqualFold(task.Assignee, assignee) {
+		return false
+	}
+	task.History = append(task.History, Change{
+		At:    now.Format(TimestampFormat),
+		By:    identity,
+		Field: "assignee",
+		From:  task.Assignee,
+		To:    assignee,
+	})
+	task.Assignee = assignee
+	return true
+}
+
+// IsAssignedTo reports whether the task is assigned to the user, ignoring case
+func (task Task) IsAssignedTo(user string) bool {
+	return task.Assignee != "" && strings.EqualFold(task.Assignee, user)
+}
+
+// IsMine reports whether the task is for the local user: assigned to them
+// or not assigned to anyone
+func (task Task) IsMine() bool {
+	return task.Assignee == "" || task.IsAssignedTo(identity)
+}
@@ -0,0 +1,109 @@
+package taskdata
+
+import (
+	"testing"
+	"time"
+)
+
+// useIdentity sets the local user until the test ends
+func useIdentity(t *testing.T, name string) {
+	t.Helper()
+	SetIdentity(name)
+	t.Cleanup(func() { SetIdentity("") })
+}
+
+func TestAssign(t *testing.T) {
+	useIdentity(t, " alice ")
+	now := time.Date(2025, time.July, 16, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		assignee string
+		assign   string
+		want     string
+		changed  bool
+	}{
+		{"assign", "", "bob", "bob", true},
+		{"reassign", "bob", " carol ", "carol", true},
+		{"unassign", "bob", "", "", true},
+		{"same assignee", "bob", "bob", "bob", false},
+		{"same assignee in another case", "Bob", "bob", "Bob", false},
+		{"already unassigned", "", " ", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := Task{Description: "Write report", Assignee: tt.assignee}
+			if changed := task.Assign(tt.assign, now); changed != tt.changed {
+				t.Errorf("Assign = %v, want %v", changed, tt.changed)
+			}
+			if task.Assignee != tt.want {
+				t.Errorf("assignee %q, want %q", task.Assignee, tt.want)
+			}
+			if !tt.changed {
+				if len(task.History) != 0 {
+					t.Errorf("history %+v, want none", task.History)
+				}
+				return
+			}
+			want := Change{At: now.Format(TimestampFormat), By: "alice", Field: "assignee", From: tt.assignee, To: tt.want}
+			if len(task.History) != 1 || task.History[0] != want {
+				t.Errorf("history %+v, want %+v", task.History, want)
+			}
+		})
+	}
+}
+
+func TestAssignKeepsHistoryInOrder(t *testing.T) {
+	useIdentity(t, "alice")
+	task := Task{Description: "Write report"}
+	start := time.Date(2025, time.July, 16, 12, 0, 0, 0, time.UTC)
+	for i, assignee := range []string{"bob", "carol", ""} {
+		task.Assign(assignee, start.Add(time.Duration(i)*time.Hour))
+	}
+
+	var moves []string
+	for _, change := range task.History {
+		moves = append(moves, change.From+">"+change.To)
+	}
+	if got := len(moves); got != 3 || moves[0] != ">bob" || moves[1] != "bob>carol" || moves[2] != "carol>" {
+		t.Errorf("history %v", moves)
+	}
+}
+
+func TestIsMine(t *testing.T) {
+	useIdentity(t, "Alice")
+	tests := []struct {
+		assignee   string
+		assignedTo bool
+		mine       bool
+	}{
+		{"", false, true},
+		{"alice", true, true},
+		{"ALICE", true, true},
+		{"bob", false, false},
+	}
+	for _, tt := range tests {
+		task := Task{Assignee: tt.assignee}
+		if got := task.IsAssignedTo("alice"); got != tt.assignedTo {
+			t.Errorf("IsAssignedTo(%q) = %v, want %v", tt.assignee, got, tt.assignedTo)
+		}
+		if got := task.IsMine(); got != tt.mine {
+			t.Errorf("IsMine with assignee %q = %v, want %v", tt.assignee, got, tt.mine)
+		}
+	}
+	if (Task{}).IsAssignedTo("") {
+		t.Error("an unassigned task is assigned to the empty user")
+	}
+}
+
+func TestAddTaskRecordsCreator(t *testing.T) {
+	useIdentity(t, "alice")
+	store := &TaskStore{NextID: 1}
+	task, err := store.AddTask("Write report", "", "normal")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if task.Creator != "alice" || task.Assignee != "" {
+		t.Errorf("creator %q, assignee %q", task.Creator, task.Assignee)
+	}
+}
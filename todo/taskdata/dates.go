@@ -0,0 +1,93 @@
+package taskdata
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DateKeyword is a relative date accepted wherever a date is given
+type DateKeyword struct {
+	Name        string
+	Description string
+}
+
+// DateKeywords are the relative dates ResolveDate understands, besides
+// offsets such as +3d and +2w
+var DateKeywords = []DateKeyword{
+	{"today", "Today"},
+	{"tomorrow", "Tomorrow"},
+	{"monday", "Next Monday"},
+	{"tuesday", "Next Tuesday"},
+	{"wednesday", "Next Wednesday"},
+	{"thursday", "Next Thursday"},
+	{"friday", "Next Friday"},
+	{"saturday", "Next Saturday"},
+	{"sunday", "Next Sunday"},
+	{"next-week", "Monday next week"},
+	{"end-of-month", "Last day of this month"},
+	{"next-month", "First day of next month"},
+}
+
+// ResolveDate turns a relative date such as "tomorrow", "friday" or "+3d"
+// into YYYY-MM-DD as seen from now. Other values are returned unchanged
+// once ValidateDate accepts them; an empty date stays empty.
+func ResolveDate(date string, now time.Time) (string, error) {
+	keyword := strings.ToLower(strings.TrimSpace(date))
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	resolved, ok := resolveKeyword(keyword, today)
+	if !ok {
+		if err := ValidateDate(date); err != nil {
+			return "", fmt.Errorf("invalid date '%s'. Use YYYY-MM-DD, a keyword such as today, tomorrow or friday, or an offset such as +3d", date)
+		}
+		return date, nil
+	}
+	return resolved.Format(dateFormat), nil
+}
+
+func resolveKeyword(keyword string, today time.Time) (time.Time, bool) {
+	switch keyword {
+	case "today":
+		return today, true
+	case "tomorrow":
+		return today.AddDate(0, 0, 1), true
+	case "next-week":
+		return nextWeekday(today, time.Monday), true
+	case "end-of-month":
+		return time.Date(today.Year(), today.Month()+1, 0, 0, 0, 0, 0, today.Location()), true
+	case "next-month":
+		return time.Date(today.Year(), today.Month()+1, 1, 0, 0, 0, 0, today.Location()), true
+	}
+
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		if keyword == strings.ToLower(day.String()) {
+			return nextWeekday(today, day), true
+		}
+	}
+
+	// Offsets: +Nd days, +Nw weeks
+	if offset, ok := strings.CutPrefix(keyword, "+"); ok && len(offset) > 1 {
+		n, err := strconv.Atoi(offset[:len(offset)-1])
+		if err != nil || n < 0 {
+			return time.Time{}, false
+		}
+		switch offset[len(offset)-1] {
+		case 'd':
+			return today.AddDate(0, 0, n), true
+		case 'w':
+			return today.AddDate(0, 0, 7*n), true
+		}
+	}
+	return time.Time{}, false
+}
+
+// nextWeekday is the first day after today falling on day
+func nextWeekday(today time.Time, day time.Weekday) time.Time {
+	days := (int(day) - int(today.Weekday()) + 7) % 7
+	if days == 0 {
+		days = 7
+	}
+	return today.AddDate(0, 0, days)
+}
@@ -0,0 +1,68 @@
+package taskdata
+
+import (
+	"testing"
+	"time"
+)
+
+func TestResolveDate(t *testing.T) {
+	// A Wednesday
+	now := time.Date(2025, time.July, 16, 15, 30, 0, 0, time.Local)
+
+	tests := []struct {
+		date string
+		want string
+	}{
+		{"", ""},
+		{"2025-08-01", "2025-08-01"},
+		{"today", "2025-07-16"},
+		{"Tomorrow", "2025-07-17"},
+		{" friday ", "2025-07-18"},
+		{"wednesday", "2025-07-23"},
+		{"monday", "2025-07-21"},
+		{"next-week", "2025-07-21"},
+		{"end-of-month", "2025-07-31"},
+		{"next-month", "2025-08-01"},
+		{"+0d", "2025-07-16"},
+		{"+3d", "2025-07-19"},
+		{"+2w", "2025-07-30"},
+		{"+20d", "2025-08-05"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.date, func(t *testing.T) {
+			got, err := ResolveDate(tt.date, now)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("ResolveDate(%q) = %q, want %q", tt.date, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveDateAtYearEnd(t *testing.T) {
+	now := time.Date(2025, time.December, 31, 9, 0, 0, 0, time.Local)
+	for date, want := range map[string]string{"tomorrow": "2026-01-01", "next-month": "2026-01-01", "end-of-month": "2025-12-31"} {
+		if got, _ := ResolveDate(date, now); got != want {
+			t.Errorf("ResolveDate(%q) = %q, want %q", date, got, want)
+		}
+	}
+}
+
+func TestResolveDateRejectsUnknownValues(t *testing.T) {
+	now := time.Now()
+	for _, date := range []string{"someday", "+3", "+d", "+-1d", "+3m", "2025-13-01", "07/16/2025"} {
+		if got, err := ResolveDate(date, now); err == nil {
+			t.Errorf("ResolveDate(%q) = %q, want an error", date, got)
+		}
+	}
+}
+
+func TestDateKeywordsResolve(t *testing.T) {
+	for _, keyword := range DateKeywords {
+		if _, err := ResolveDate(keyword.Name, time.Now()); err != nil {
+			t.Errorf("keyword %s: %v", keyword.Name, err)
+		}
+	}
+}
@@ -0,0 +1,186 @@
+package taskdata
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const (
+	// DefaultList is the list kept in the data file itself
+	DefaultList = "default"
+
+	listsDirName = "lists"
+)
+
+var listNameRegex = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// currentList is the list GetDataFilePath resolves to
+var currentList = DefaultList
+
+// SetList makes the named list current, so loading and saving use its file.
+// An empty name selects the default list.
+func SetList(name string) {
+	if name == "" {
+		name = DefaultList
+	}
+	currentList = name
+}
+
+// CurrentList returns the name of the current list
+func CurrentList() string {
+	return currentList
+}
+
+// ValidateListName checks that a list name is usable as a file name
+func ValidateListName(name string) error {
+	if !listNameRegex.MatchString(name) {
+		return fmt.Errorf("invalid list name '%s'. Use lowercase letters, digits, '-' and '_'", name)
+	}
+	return nil
+}
+
+// ListFilePath returns the tasks file of a list. The default list is the
+// data file; other lists live in a lists directory next to it, e.g.
+// ~/.todo/lists/work.json.
+func ListFilePath(name string) string {
+	dataFile := baseDataFilePath()
+	if name == "" || name == DefaultList {
+		return dataFile
+	}
+	return filepath.Join(filepath.Dir(dataFile), listsDirName, name+".json")
+}
+
+// ListExists reports whether a list has been created. The default list
+// always exists.
+func ListExists(name string) bool {
+	if name == "" || name == DefaultList {
+		return true
+	}
+	_, err := os.Stat(ListFilePath(name))
+	return err == nil
+}
+
+// ListNames returns the default list followed by the other lists by name
+func ListNames() ([]string, error) {
+	entries, err := os.ReadDir(filepath.Join(filepath.Dir(baseDataFilePath()), listsDirName))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read lists: %v", err)
+	}
+
+	var names []string
+	for _, entry := range entries {
+		name, ok := strings.CutSuffix(entry.Name(), ".json")
+		if !ok || entry.IsDir() || ValidateListName(name) != nil {
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return append([]string{DefaultList}, names...), nil
+}
+
+// CreateList creates an empty list
+func CreateList(name string) error {
+	if err := ValidateListName(name); err != nil {
+		return err
+	}
+	if ListExists(name) {
+		return fmt.Errorf("list '%s' already exists", name)
+	}
+
+	filePath := ListFilePath(name)
+	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+		return fmt.Errorf("failed to create lists directory: %v", err)
+	}
+	store := &TaskStore{Tasks: []Task{}, NextID: 1}
+	_, err := store.write(filePath)
+	return err
+}
+
+// LoadList loads the tasks of a list other than the current one
+func LoadList(name string) (*TaskStore, error) {
+	return load(ListFilePath(name))
+}
+
+// UpdateList is UpdateTasks for a list other than the current one
+func UpdateList(name string, update func(store *TaskStore) error) (*TaskStore, error) {
+	return updateTasksAt(ListFilePath(name), update)
+}
+
+// UpdateListPair is UpdateTasks for moving tasks between two lists. Both
+// locks are taken in path order, so moves in opposite directions cannot
+// deadlock. The source is saved first, and restored if the target then
+// fails to save, so a failed move never loses tasks.
+func UpdateListPair(source, target string, update func(source, target *TaskStore) error) error {
+	sourcePath, targetPath := ListFilePath(source), ListFilePath(target)
+	if sourcePath == targetPath {
+		return fmt.Errorf("cannot move tasks within list '%s'", source)
+	}
+
+	if session != nil {
+		sourceStore, err := session.checkout(sourcePath)
+		if err != nil {
+			return err
+		}
+		targetStore, err := session.checkout(targetPath)
+		if err != nil {
+			return err
+		}
+		if err := update(sourceStore, targetStore); err != nil {
+			return err
+		}
+		return session.commitAll(map[string]*TaskStore{sourcePath: sourceStore, targetPath: targetStore})
+	}
+
+	paths := []string{sourcePath, targetPath}
+	sort.Strings(paths)
+	for _, filePath := range paths {
+		if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+			return fmt.Errorf("failed to create data directory: %v", err)
+		}
+		unlock, err := lockFile(filePath)
+		if err != nil {
+			return err
+		}
+		defer unlock()
+	}
+
+	sourceStore, err := LoadTasksFrom(sourcePath)
+	if err != nil {
+		return err
+	}
+	targetStore, err := LoadTasksFrom(targetPath)
+	if err != nil {
+		return err
+	}
+	if err := update(sourceStore, targetStore); err != nil {
+		return err
+	}
+
+	previous, err := os.ReadFile(sourcePath)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read tasks file: %v", err)
+	}
+	existed := err == nil
+
+	if err := sourceStore.save(sourcePath); err != nil {
+		return err
+	}
+	if err := targetStore.save(targetPath); err != nil {
+		var restoreErr error
+		if existed {
+			restoreErr = writeFileAtomic(sourcePath, previous)
+		} else {
+			restoreErr = os.Remove(sourcePath)
+		}
+		if restoreErr != nil {
+			return fmt.Errorf("%v; restoring %s also failed: %v", err, sourcePath, restoreErr)
+		}
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,275 @@
+package taskdata
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+// useList makes name the current list until the test ends
+func useList(t *testing.T, name string) {
+	t.Helper()
+	SetList(name)
+	t.Cleanup(func() { SetList("") })
+}
+
+// addToList adds tasks with the given descriptions to a list
+func addToList(t *testing.T, list string, descriptions ...string) {
+	t.Helper()
+	_, err := UpdateList(list, func(store *TaskStore) error {
+		for _, description := range descriptions {
+			if _, err := store.AddTask(description, "", "normal"); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// descriptionsIn returns the descriptions of a list's tasks
+func descriptionsIn(t *testing.T, list string) []string {
+	t.Helper()
+	store, err := LoadList(list)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var descriptions []string
+	for _, task := range store.Tasks {
+		descriptions = append(descriptions, task.Description)
+	}
+	return descriptions
+}
+
+func TestValidateListName(t *testing.T) {
+	tests := map[string]bool{
+		"work": true, "home-2": true, "q3_goals": true, "0day": true,
+		"": false, "Work": false, "-work": false, "my list": false, "../etc": false, "work.json": false,
+	}
+	for name, valid := range tests {
+		if err := ValidateListName(name); (err == nil) != valid {
+			t.Errorf("ValidateListName(%q) = %v, want valid %v", name, err, valid)
+		}
+	}
+}
+
+func TestListFiles(t *testing.T) {
+	dataFile := useDataFile(t, "")
+	dir := filepath.Dir(dataFile)
+
+	for name, want := range map[string]string{
+		"":        dataFile,
+		"default": dataFile,
+		"work":    filepath.Join(dir, "lists", "work.json"),
+	} {
+		if got := ListFilePath(name); got != want {
+			t.Errorf("ListFilePath(%q) = %s, want %s", name, got, want)
+		}
+	}
+
+	useList(t, "work")
+	if GetDataFilePath() != filepath.Join(dir, "lists", "work.json") || CurrentList() != "work" {
+		t.Errorf("current list %s at %s", CurrentList(), GetDataFilePath())
+	}
+	SetList("")
+	if CurrentList() != DefaultList || GetDataFilePath() != dataFile {
+		t.Errorf("an empty name selects %s at %s", CurrentList(), GetDataFilePath())
+	}
+}
+
+func TestCreateList(t *testing.T) {
+	dataFile := useDataFile(t, "")
+
+	names, err := ListNames()
+	if err != nil || !slices.Equal(names, []string{"default"}) {
+		t.Fatalf("ListNames = %v, %v before any list was created", names, err)
+	}
+
+	for _, name := range []string{"work", "home"} {
+		if err := CreateList(name); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Stray files in the lists directory are not lists
+	for _, name := range []string{"notes.txt", "Bad Name.json", "work.json.lock"} {
+		if err := os.WriteFile(filepath.Join(filepath.Dir(dataFile), "lists", name), nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	names, err = ListNames()
+	if err != nil || !slices.Equal(names, []string{"default", "home", "work"}) {
+		t.Errorf("ListNames = %v, %v", names, err)
+	}
+	if !ListExists("work") || !ListExists("default") || ListExists("school") {
+		t.Error("ListExists does not match the created lists")
+	}
+	if store, err := LoadList("work"); err != nil || len(store.Tasks) != 0 {
+		t.Errorf("new list holds %v, %v", store, err)
+	}
+
+	for _, name := range []string{"work", "default", "Bad"} {
+		if err := CreateList(name); err == nil {
+			t.Errorf("CreateList(%q) succeeded", name)
+		}
+	}
+}
+
+func TestListsAreSeparate(t *testing.T) {
+	useDataFile(t, "")
+	if err := CreateList("work"); err != nil {
+		t.Fatal(err)
+	}
+	addToList(t, DefaultList, "Buy milk")
+	addToList(t, "work", "Write report", "Send report")
+
+	useList(t, "work")
+	store, err := LoadTasks()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(store.Tasks) != 2 || store.Tasks[0].ID != 1 {
+		t.Errorf("work list holds %+v", store.Tasks)
+	}
+	if got := descriptionsIn(t, DefaultList); !slices.Equal(got, []string{"Buy milk"}) {
+		t.Errorf("default list holds %v", got)
+	}
+}
+
+func TestUpdateListPairMovesTasks(t *testing.T) {
+	useDataFile(t, "")
+	addToList(t, DefaultList, "Buy milk", "Write report")
+	if err := CreateList("work"); err != nil {
+		t.Fatal(err)
+	}
+
+	err := UpdateListPair(DefaultList, "work", func(source, target *TaskStore) error {
+		task := source.Tasks[1]
+		source.Tasks = source.Tasks[:1]
+		_, err := target.AddTask(task.Description, task.DueDate, task.Priority)
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := descriptionsIn(t, DefaultList); !slices.Equal(got, []string{"Buy milk"}) {
+		t.Errorf("source holds %v", got)
+	}
+	if got := descriptionsIn(t, "work"); !slices.Equal(got, []string{"Write report"}) {
+		t.Errorf("target holds %v", got)
+	}
+
+	if err := UpdateListPair("work", "work", func(source, target *TaskStore) error { return nil }); err == nil {
+		t.Error("moving within one list succeeded")
+	}
+}
+
+func TestUpdateListPairRestoresSourceWhenTargetFails(t *testing.T) {
+	tests := []struct {
+		name         string
+		sourceExists bool
+	}{
+		{"existing source", true},
+		{"new source", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useDataFile(t, "")
+			if err := CreateList("work"); err != nil {
+				t.Fatal(err)
+			}
+			addToList(t, "work", "Write report")
+			if tt.sourceExists {
+				addToList(t, DefaultList, "Buy milk")
+			}
+			sourcePath := ListFilePath(DefaultList)
+			before, _ := os.ReadFile(sourcePath)
+
+			// The target file turns into a directory, so saving it fails
+			targetPath := ListFilePath("work")
+			err := UpdateListPair(DefaultList, "work", func(source, target *TaskStore) error {
+				if _, err := source.AddTask("Moved here", "", "normal"); err != nil {
+					return err
+				}
+				target.Tasks = nil
+				if err := os.Remove(targetPath); err != nil {
+					return err
+				}
+				if err := os.MkdirAll(filepath.Join(targetPath, "blocker"), 0755); err != nil {
+					return err
+				}
+				return nil
+			})
+			if err == nil {
+				t.Fatal("the move succeeded")
+			}
+
+			after, readErr := os.ReadFile(sourcePath)
+			if tt.sourceExists && string(after) != string(before) {
+				t.Errorf("source is\n%s\nwant\n%s", after, before)
+			}
+			if !tt.sourceExists && !os.IsNotExist(readErr) {
+				t.Errorf("the source file was left behind: %s", after)
+			}
+		})
+	}
+}
+
+func TestUpdateListPairInOppositeDirections(t *testing.T) {
+	useDataFile(t, "")
+	if err := CreateList("work"); err != nil {
+		t.Fatal(err)
+	}
+	addToList(t, DefaultList, "Buy milk")
+	addToList(t, "work", "Write report")
+
+	// Each move swaps the first task of one list onto the other
+	move := func(source, target string) error {
+		return UpdateListPair(source, target, func(from, to *TaskStore) error {
+			if len(from.Tasks) == 0 {
+				return nil
+			}
+			task := from.Tasks[0]
+			from.Tasks = from.Tasks[1:]
+			_, err := to.AddTask(task.Description, "", "normal")
+			return err
+		})
+	}
+
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	errs := make(chan error, 40)
+	for _, pair := range [][2]string{{DefaultList, "work"}, {"work", DefaultList}} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 20 {
+				if err := move(pair[0], pair[1]); err != nil {
+					errs <- err
+				}
+			}
+		}()
+	}
+	go func() { wg.Wait(); close(done) }()
+
+	select {
+	case <-done:
+	case <-time.After(lockTimeout):
+		t.Fatal("moves in opposite directions deadlocked")
+	}
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+
+	all := append(descriptionsIn(t, DefaultList), descriptionsIn(t, "work")...)
+	slices.Sort(all)
+	if !slices.Equal(all, []string{"Buy milk", "Write report"}) {
+		t.Errorf("after the moves the lists hold %v", all)
+	}
+}
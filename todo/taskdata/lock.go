@@ -0,0 +1,228 @@
+package taskdata
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrStoreChanged is returned by SaveTasks when another process wrote the
+// tasks file after this store was loaded
+var ErrStoreChanged = errors.New("the tasks file was changed by another process since it was loaded; run the command again")
+
+const (
+	// lockTimeout is how long to wait for another process to release the lock
+	lockTimeout = 10 * time.Second
+	// staleLockAge is when a lock left behind by a crashed process is
+	// removed. Holders touch the lock every lockRefresh, so a lock held by
+	// a slow but living process never gets this old.
+	staleLockAge = 30 * time.Second
+	lockRefresh  = staleLockAge / 3
+)
+
+// lockFile takes the store's lock file, waiting for other processes to
+// release it. The lock holds a random token, and the returned function
+// only removes it while it still holds that token, so a holder whose lock
+// was taken over cannot release the new holder's lock.
+func lockFile(path string) (func(), error) {
+	lockPath := path + ".lock"
+	deadline := time.Now().Add(lockTimeout)
+	delay := 5 * time.Millisecond
+	token := lockToken()
+
+	for {
+		file, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+		if err == nil {
+			_, err = fmt.Fprintf(file, "%d %s\n", os.Getpid(), token)
+			file.Close()
+			if err != nil {
+				os.Remove(lockPath)
+				return nil, fmt.Errorf("failed to lock tasks file: %v", err)
+			}
+			return holdLock(lockPath, token), nil
+		}
+		if !os.IsExist(err) {
+			return nil, fmt.Errorf("failed to lock tasks file: %v", err)
+		}
+
+		if info, statErr := os.Stat(lockPath); statErr == nil && time.Since(info.ModTime()) > staleLockAge {
+			// Only remove the lock that was found stale, not one another
+			// process took since
+			if stale, readErr := readLockToken(lockPath); readErr == nil {
+				removeLock(lockPath, stale)
+			}
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("tasks file is locked by another process (remove %s if it is stale)", lockPath)
+		}
+		time.Sleep(delay)
+		delay = min(delay*2, 200*time.Millisecond)
+	}
+}
+
+// holdLock keeps the lock's modification time fresh until the returned
+// function releases it
+func holdLock(lockPath, token string) func() {
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(lockRefresh)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				if current, err := readLockToken(lockPath); err == nil && current == token {
+					now := time.Now()
+					os.Chtimes(lockPath, now, now)
+				}
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			close(done)
+			removeLock(lockPath, token)
+		})
+	}
+}
+
+// removeLock removes the lock file if it holds token
+func removeLock(lockPath, token string) {
+	if current, err := readLockToken(lockPath); err == nil && current == token {
+		os.Remove(lockPath)
+	}
+}
+
+// readLockToken returns the token written by the lock's holder; lock files
+// of older versions hold only a PID, which then serves as the token
+func readLockToken(lockPath string) (string, error) {
+	data, err := os.ReadFile(lockPath)
+	if err != nil {
+		return "", err
+	}
+	fields := strings.Fields(string(data))
+	if len(fields) == 0 {
+		return "", nil
+	}
+	return fields[len(fields)-1], nil
+}
+
+func lockToken() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		panic(fmt.Sprintf("failed to generate lock token: %v", err))
+	}
+	return hex.EncodeToString(b[:])
+}
+
+// LockFile takes the lock of another tasks file, such as a copy shared with
+// other machines, for reading and writing it with LoadTasksFrom and
+// SaveTasksTo. The returned function releases it.
+func LockFile(path string) (func(), error) {
+	return lockFile(path)
+}
+
+// writeFileAtomic replaces path with data through a temporary file, so
+// readers never see a partly written file
+func writeFileAtomic(path string, data []byte) error {
+	temp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(temp.Name())
+
+	if _, err := temp.Write(data); err != nil {
+		temp.Close()
+		return err
+	}
+	if err := temp.Sync(); err != nil {
+		temp.Close()
+		return err
+	}
+	if err := temp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(temp.Name(), 0644); err != nil {
+		return err
+	}
+	return os.Rename(temp.Name(), path)
+}
+
+// fileRevision returns a hash of the file's contents, "" if it does not exist
+func fileRevision(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return revisionOf(data), nil
+}
+
+func revisionOf(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:8])
+}
+
+// Revision identifies the contents of the tasks file this store was loaded
+// from or last saved to; it changes whenever the file does
+func (store *TaskStore) Revision() string {
+	return store.revision
+}
+
+// UpdateTasks loads the store, applies update and saves the result while
+// holding the lock, so concurrent writers cannot interleave. Nothing is
+// saved if update returns an error.
+func UpdateTasks(update func(store *TaskStore) error) (*TaskStore, error) {
+	return updateTasksAt(GetDataFilePath(), update)
+}
+
+func updateTasksAt(filePath string, update func(store *TaskStore) error) (*TaskStore, error) {
+	if session != nil {
+		store, err := session.checkout(filePath)
+		if err != nil {
+			return nil, err
+		}
+		if err := update(store); err != nil {
+			return store, err
+		}
+		return store, session.commit(filePath, store)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create data directory: %v", err)
+	}
+
+	unlock, err := lockFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	store, err := LoadTasksFrom(filePath)
+	if err != nil {
+		return nil, err
+	}
+	if err := update(store); err != nil {
+		return store, err
+	}
+	return store, store.save(filePath)
+}
+
+// CurrentRevision returns the revision of the tasks file on disk, for
+// noticing changes made by other processes
+func CurrentRevision() (string, error) {
+	return fileRevision(GetDataFilePath())
+}
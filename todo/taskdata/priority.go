@@ -0,0 +1,152 @@
+package taskdata
+
+import (
+	"fmt"
+	"strings"
+)
+
+// PriorityScale is an ordered list of priority levels, most important first
+type PriorityScale struct {
+	levels  []string
+	aliases map[string]string // lowercase alias -> level
+}
+
+// DefaultPriorityLevels is the scale used when none is configured
+var DefaultPriorityLevels = []string{"high", "normal", "low"}
+
+// priorityScale is the scale used by ValidatePriority, NormalizePriority and PriorityRank
+var priorityScale, _ = NewPriorityScale(DefaultPriorityLevels, nil)
+
+// NewPriorityScale builds a scale from levels ordered most important first.
+// Aliases map alternative spellings ("urgent") to levels ("p0"); in addition,
+// the first letter of each level is an alias when it is unambiguous.
+func NewPriorityScale(levels []string, aliases map[string]string) (*PriorityScale, error) {
+	if len(levels) < 2 {
+		return nil, fmt.Errorf("a priority scale needs at least two levels")
+	}
+
+	scale := &PriorityScale{aliases: map[string]string{}}
+	seen := map[string]bool{}
+	for _, level := range levels {
+		level = strings.TrimSpace(level)
+		key := strings.ToLower(level)
+		if level == "" {
+			return nil, fmt.Errorf("priority levels cannot be empty")
+		}
+		if seen[key] {
+			return nil, fmt.Errorf("duplicate priority level '%s'", level)
+		}
+		seen[key] = true
+		scale.levels = append(scale.levels, level)
+	}
+
+	// First-letter shortcuts (h, n, l for the default scale)
+	initials := map[string][]string{}
+	for _, level := range scale.levels {
+		initial := strings.ToLower(string([]rune(level)[:1]))
+		initials[initial] = append(initials[initial], level)
+	}
+	for initial, matches := range initials {
+		if len(matches) == 1 && !seen[initial] {
+			scale.aliases[initial] = matches[0]
+		}
+	}
+
+	for alias, level := range aliases {
+		canonical, ok := scale.lookup(level)
+		if !ok {
+			return nil, fmt.Errorf("priority alias '%s' refers to unknown level '%s'", alias, level)
+		}
+		scale.aliases[strings.ToLower(strings.TrimSpace(alias))] = canonical
+	}
+
+	// Tasks created before a custom scale was configured keep the default
+	// levels; map them onto the top, middle and bottom of the new scale
+	// unless the scale or an alias already gives those names a meaning
+	last := len(scale.levels) - 1
+	for name, level := range map[string]string{
+		"high":   scale.levels[0],
+		"normal": scale.levels[len(scale.levels)/2],
+		"low":    scale.levels[last],
+	} {
+		if _, taken := scale.aliases[name]; !taken && !seen[name] {
+			scale.aliases[name] = level
+		}
+	}
+
+	return scale, nil
+}
+
+// lookup finds the canonical level for a name, ignoring case
+func (scale *PriorityScale) lookup(name string) (string, bool) {
+	for _, level := range scale.levels {
+		if strings.EqualFold(level, name) {
+			return level, true
+		}
+	}
+	return "", false
+}
+
+// Normalize returns the canonical level for a priority or alias, ignoring case
+func (scale *PriorityScale) Normalize(priority string) (string, error) {
+	name := strings.TrimSpace(priority)
+	if level, ok := scale.lookup(name); ok {
+		return level, nil
+	}
+	if level, ok := scale.aliases[strings.ToLower(name)]; ok {
+		return level, nil
+	}
+	return "", fmt.Errorf("invalid priority '%s'. Valid priorities are: %s",
+		priority, strings.Join(scale.levels, ", "))
+}
+
+// Levels returns the levels, most important first
+func (scale *PriorityScale) Levels() []string {
+	return append([]string(nil), scale.levels...)
+}
+
+// Rank returns a priority's position counted from the least important level
+// (1 for the lowest level, len(levels) for the highest, 0 if unknown).
+// Aliases rank as the level they stand for.
+func (scale *PriorityScale) Rank(priority string) int {
+	level, err := scale.Normalize(priority)
+	if err != nil {
+		return 0
+	}
+	for i, l := range scale.levels {
+		if l == level {
+			return len(scale.levels) - i
+		}
+	}
+	return 0
+}
+
+// SetPriorityScale replaces the scale used for validation and ordering
+func SetPriorityScale(scale *PriorityScale) {
+	priorityScale = scale
+}
+
+// Priorities returns the configured priority levels, most important first
+func Priorities() []string {
+	return priorityScale.Levels()
+}
+
+// HighestPriority returns the most important priority level
+func HighestPriority() string {
+	return priorityScale.levels[0]
+}
+
+// LowestPriority returns the least important priority level
+func LowestPriority() string {
+	return priorityScale.levels[len(priorityScale.levels)-1]
+}
+
+// NormalizePriority returns the canonical level for a priority or alias
+func NormalizePriority(priority string) (string, error) {
+	return priorityScale.Normalize(priority)
+}
+
+// PriorityRank orders priorities: higher is more important, 0 means unknown
+func PriorityRank(priority string) int {
+	return priorityScale.Rank(priority)
+}
@@ -0,0 +1,106 @@
+package taskdata
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestNormalizePriority(t *testing.T) {
+	scale, err := NewPriorityScale([]string{"P0", "P1", "P2", "P3", "Later"}, map[string]string{"urgent": "p0", "Someday": "later"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		priority string
+		want     string
+	}{
+		{"P0", "P0"},
+		{"p2", "P2"},
+		{" p3 ", "P3"},
+		{"urgent", "P0"},
+		{"SOMEDAY", "Later"},
+		{"l", "Later"},
+		{"high", "P0"},
+		{"normal", "P2"},
+		{"low", "Later"},
+		{"p", ""},
+		{"p9", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.priority, func(t *testing.T) {
+			got, err := scale.Normalize(tt.priority)
+			if tt.want == "" {
+				if err == nil {
+					t.Fatalf("Normalize(%q) = %q, want an error", tt.priority, got)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("Normalize(%q) = %q, %v, want %q", tt.priority, got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestDefaultScale(t *testing.T) {
+	scale, err := NewPriorityScale(DefaultPriorityLevels, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for priority, want := range map[string]string{"h": "high", "N": "normal", "Low": "low"} {
+		if got, err := scale.Normalize(priority); err != nil || got != want {
+			t.Errorf("Normalize(%q) = %q, %v, want %q", priority, got, err, want)
+		}
+	}
+	if got := scale.Levels(); !slices.Equal(got, DefaultPriorityLevels) {
+		t.Errorf("Levels = %v", got)
+	}
+}
+
+func TestScaleKeepsItsOwnMeaningOfDefaultNames(t *testing.T) {
+	// "low" is a level here and "high" an alias, so neither is remapped
+	scale, err := NewPriorityScale([]string{"critical", "medium", "low", "none"}, map[string]string{"high": "medium"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for priority, want := range map[string]string{"low": "low", "high": "medium", "normal": "low"} {
+		if got, _ := scale.Normalize(priority); got != want {
+			t.Errorf("Normalize(%q) = %q, want %q", priority, got, want)
+		}
+	}
+}
+
+func TestNewPriorityScaleErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		levels  []string
+		aliases map[string]string
+	}{
+		{"one level", []string{"only"}, nil},
+		{"empty level", []string{"high", " ", "low"}, nil},
+		{"duplicate level", []string{"high", "High"}, nil},
+		{"alias to unknown level", []string{"p0", "p1"}, map[string]string{"urgent": "p9"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewPriorityScale(tt.levels, tt.aliases); err == nil {
+				t.Error("the scale was accepted")
+			}
+		})
+	}
+}
+
+func TestPriorityRank(t *testing.T) {
+	scale, err := NewPriorityScale([]string{"p0", "p1", "p2", "p3"}, map[string]string{"urgent": "p0"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := map[string]int{"p0": 4, "urgent": 4, "P1": 3, "p3": 1, "normal": 2, "unknown": 0, "": 0}
+	for priority, want := range tests {
+		if got := scale.Rank(priority); got != want {
+			t.Errorf("Rank(%q) = %d, want %d", priority, got, want)
+		}
+	}
+}
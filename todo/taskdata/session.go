@@ -0,0 +1,250 @@
+package taskdata
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Session keeps task lists in memory between commands, for 'todo shell'.
+// While a session is active, LoadTasks, UpdateTasks and the list functions
+// hand out copies of the kept lists and SaveTasks keeps the result in
+// memory; Flush writes the lists with unsaved changes to disk.
+type Session struct {
+	// Merge combines changes another process saved to disk with unsaved
+	// changes, both made since base, writing the result into local.
+	// Without it such lists are not reloaded or written (ErrStoreChanged).
+	Merge func(base, local, disk *TaskStore)
+
+	mu    sync.Mutex
+	lists map[string]*keptList // By file path
+}
+
+// keptList is a task list held by a session
+type keptList struct {
+	base    *TaskStore // As last read from or written to disk
+	store   *TaskStore // Including unsaved changes
+	version int        // Counts changes, to refuse saving outdated copies
+	dirty   bool
+}
+
+// session is the active session, if any
+var session *Session
+
+// StartSession starts keeping task lists in memory
+func StartSession() *Session {
+	session = &Session{lists: map[string]*keptList{}}
+	return session
+}
+
+// End writes unsaved changes and stops keeping lists in memory
+func (s *Session) End() error {
+	err := s.Flush()
+	session = nil
+	return err
+}
+
+// Unsaved reports whether any list has changes not yet written to disk
+func (s *Session) Unsaved() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, list := range s.lists {
+		if list.dirty {
+			return true
+		}
+	}
+	return false
+}
+
+// list returns the kept list for a file, reading it on first use. The
+// caller holds s.mu.
+func (s *Session) list(filePath string) (*keptList, error) {
+	if list, ok := s.lists[filePath]; ok {
+		return list, nil
+	}
+	store, err := LoadTasksFrom(filePath)
+	if err != nil {
+		return nil, err
+	}
+	list := &keptList{base: store, store: store.clone()}
+	s.lists[filePath] = list
+	return list, nil
+}
+
+// checkout returns a copy of a kept list for a command to change
+func (s *Session) checkout(filePath string) (*TaskStore, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	list, err := s.list(filePath)
+	if err != nil {
+		return nil, err
+	}
+	store := list.store.clone()
+	store.kept = list
+	store.version = list.version
+	return store, nil
+}
+
+// commit keeps a changed copy handed out by checkout
+func (s *Session) commit(filePath string, store *TaskStore) error {
+	return s.commitAll(map[string]*TaskStore{filePath: store})
+}
+
+// commitAll keeps changed copies of several lists, or none of them if any
+// is out of date
+func (s *Session) commitAll(stores map[string]*TaskStore) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	lists := make(map[string]*keptList, len(stores))
+	for filePath, store := range stores {
+		list, err := s.list(filePath)
+		if err != nil {
+			return err
+		}
+		if store.kept != list || store.version != list.version {
+			return ErrStoreChanged
+		}
+		lists[filePath] = list
+	}
+
+	now := time.Now()
+	for filePath, store := range stores {
+		list := lists[filePath]
+		store.stampModified(now)
+		list.store = store.clone()
+		list.version++
+		list.dirty = true
+		store.version = list.version
+		store.snapshot()
+	}
+	return nil
+}
+
+// Refresh picks up lists another process changed on disk: lists without
+// unsaved changes are read again and the others are merged. It returns the
+// files that changed.
+func (s *Session) Refresh() ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var changed []string
+	for _, filePath := range s.paths() {
+		list := s.lists[filePath]
+		revision, err := fileRevision(filePath)
+		if err != nil {
+			return changed, err
+		}
+		if revision == list.base.revision {
+			continue
+		}
+		if err := s.reload(filePath, list); err != nil {
+			return changed, err
+		}
+		changed = append(changed, filePath)
+	}
+	return changed, nil
+}
+
+// reload replaces a list's base with the file on disk, merging in unsaved
+// changes. The caller holds s.mu.
+func (s *Session) reload(filePath string, list *keptList) error {
+	disk, err := LoadTasksFrom(filePath)
+	if err != nil {
+		return err
+	}
+	if list.dirty {
+		if s.Merge == nil {
+			return ErrStoreChanged
+		}
+		local := list.store.clone()
+		s.Merge(list.base, local, disk)
+		local.revision = disk.revision
+		list.store = local
+	} else {
+		list.store = disk.clone()
+	}
+	list.base = disk
+	list.version++
+	return nil
+}
+
+// Flush writes the lists with unsaved changes to disk, first merging in
+// changes other processes saved there
+func (s *Session) Flush() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, filePath := range s.paths() {
+		list := s.lists[filePath]
+		if !list.dirty {
+			continue
+		}
+		if err := s.flush(filePath, list); err != nil {
+			return fmt.Errorf("%s: %w", filePath, err)
+		}
+	}
+	return nil
+}
+
+// flush writes one list while holding its lock. The caller holds s.mu.
+func (s *Session) flush(filePath string, list *keptList) error {
+	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+		return fmt.Errorf("failed to create data directory: %v", err)
+	}
+	unlock, err := lockFile(filePath)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	revision, err := fileRevision(filePath)
+	if err != nil {
+		return err
+	}
+	if revision != list.base.revision {
+		if err := s.reload(filePath, list); err != nil {
+			return err
+		}
+	}
+
+	data, err := list.store.write(filePath)
+	if err != nil {
+		return err
+	}
+	list.store.revision = revisionOf(data)
+	list.store.snapshot()
+	list.base = list.store.clone()
+	list.dirty = false
+	return nil
+}
+
+// paths returns the kept files in a stable order. The caller holds s.mu.
+func (s *Session) paths() []string {
+	var paths []string
+	for filePath := range s.lists {
+		paths = append(paths, filePath)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// clone returns a copy of the store that shares nothing with it
+func (store *TaskStore) clone() *TaskStore {
+	copied := &TaskStore{Tasks: make([]Task, len(store.Tasks)), NextID: store.NextID, revision: store.revision}
+	for i, task := range store.Tasks {
+		copied.Tasks[i] = copyTask(task)
+	}
+	copied.snapshot()
+	return copied
+}
+
+// load reads a tasks file, or checks out the kept copy during a session
+func load(filePath string) (*TaskStore, error) {
+	if session != nil {
+		return session.checkout(filePath)
+	}
+	return LoadTasksFrom(filePath)
+}
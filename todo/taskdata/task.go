@@ -6,6 +6,7 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strings"
 	"time"
 )
@@ -13,33 +14,59 @@ import (
 const (
 	dataFileName = "tasks.json"
 	dateFormat   = "2006-01-02"
+	timeFormat   = "15:04"
+
+	// TimestampFormat is used for CreatedAt and CompletedAt
+	TimestampFormat = time.RFC3339
 )
 
 type Task struct {
-	ID          int    `json:"id"`
-	Description string `json:"description"`
-	DueDate     string `json:"due_date"`
-	Priority    string `json:"priority"`
-	Completed   bool   `json:"completed"`
+	ID          int      `json:"id"`
+	UID         string   `json:"uid,omitempty"` // Stable identity shared with other tools
+	Description string   `json:"description"`
+	DueDate     string   `json:"due_date"`
+	DueTime     string   `json:"due_time,omitempty"` // HH:MM on the due date, empty for all day
+	Priority    string   `json:"priority"`
+	Completed   bool     `json:"completed"`
+	Notes       string   `json:"notes,omitempty"`
+	WaitUntil   string   `json:"wait_until,omitempty"`   // Hidden from the next report until this date
+	CreatedAt   string   `json:"created_at,omitempty"`   // RFC 3339 timestamp
+	CompletedAt string   `json:"completed_at,omitempty"` // RFC 3339 timestamp, empty while pending
+	ModifiedAt  string   `json:"modified_at,omitempty"`  // RFC 3339 timestamp of the last saved change
+	Tags        []string `json:"tags,omitempty"`
+	Project     string   `json:"project,omitempty"`
+	Contexts    []string `json:"contexts,omitempty"`   // Where the task can be done, e.g. "phone"
+	Source      string   `json:"source,omitempty"`     // Where the task came from, e.g. "cmd/add.go:42"
+	DependsOn   []int    `json:"depends_on,omitempty"` // IDs of tasks that must be completed first
+	Assignee    string   `json:"assignee,omitempty"`   // Who the task is assigned to, see Assign
+	Creator     string   `json:"creator,omitempty"`    // Who added the task
+	History     []Change `json:"history,omitempty"`    // Recorded changes, oldest first
+
+	// Extra keeps attributes from other tools that have no Task field, keyed
+	// by format name, so exporting back to that tool does not lose them
+	Extra map[string]json.RawMessage `json:"extra,omitempty"`
 }
 
 type TaskStore struct {
 	Tasks  []Task `json:"tasks"`
 	NextID int    `json:"next_id"`
-}
 
-// ValidatePriority checks if the priority is valid
-func ValidatePriority(priority string) error {
-	validPriorities := []string{"low", "L", "N", "normal", "H", "high"}
-	priority = strings.ToLower(priority)
+	revision string                // See Revision
+	loaded   map[string]loadedTask // Tasks as loaded or last saved, by UID; see stampModified
+	kept     *keptList             // Session list this store is a copy of, see Session
+	version  int                   // Version of kept this copy was made from
+}
 
-	for _, valid := range validPriorities {
-		if priority == valid {
-			return nil
-		}
-	}
+type loadedTask struct {
+	hash       string
+	modifiedAt string
+	task       Task // Copy of the task, see Changes
+}
 
-	return fmt.Errorf("invalid priority '%s'. Valid priorities are: low, normal, high", priority)
+// ValidatePriority checks if the priority is valid on the configured scale
+func ValidatePriority(priority string) error {
+	_, err := NormalizePriority(priority)
+	return err
 }
 
 // ValidateDate checks if the date format is valid (YYYY-MM-DD)
@@ -63,8 +90,43 @@ func ValidateDate(date string) error {
 	return nil
 }
 
-// GetDataFilePath returns the path to the tasks data file
+// ValidateTime checks if a time of day is valid (HH:MM, 24-hour)
+func ValidateTime(clock string) error {
+	if clock == "" {
+		return nil
+	}
+	if _, err := time.Parse(timeFormat, clock); err != nil || len(clock) != len(timeFormat) {
+		return fmt.Errorf("invalid time '%s'. Please use HH:MM (24-hour) format", clock)
+	}
+	return nil
+}
+
+// dataFileOverride is set from the config file's data_file setting
+var dataFileOverride string
+
+// SetDataFilePath overrides the tasks data file location.
+// A leading ~ is expanded to the home directory; an empty path restores the default.
+func SetDataFilePath(path string) {
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		if homeDir, err := os.UserHomeDir(); err == nil {
+			path = filepath.Join(homeDir, path[1:])
+		}
+	}
+	dataFileOverride = path
+}
+
+// GetDataFilePath returns the path to the tasks file of the current list
 func GetDataFilePath() string {
+	return ListFilePath(currentList)
+}
+
+// baseDataFilePath returns the path to the data file, which holds the
+// default list
+func baseDataFilePath() string {
+	if dataFileOverride != "" {
+		return dataFileOverride
+	}
+
 	homeDir, err := os.UserHomeDir()
 	if err != nil {
 		// Fallback to current directory if home directory is not accessible
@@ -83,6 +145,12 @@ func LoadTasks() (*TaskStore, error) {
 		return nil, fmt.Errorf("failed to create data directory: %v", err)
 	}
 
+	return load(filePath)
+}
+
+// LoadTasksFrom loads tasks from another tasks file, such as a copy shared
+// with other machines. A missing file gives an empty store.
+func LoadTasksFrom(filePath string) (*TaskStore, error) {
 	// Check if file exists
 	if _, err := os.Stat(filePath); os.IsNotExist(err) {
 		// Create new empty task store
@@ -103,13 +171,29 @@ func LoadTasks() (*TaskStore, error) {
 	if err := json.Unmarshal(data, &store); err != nil {
 		return nil, fmt.Errorf("failed to parse tasks file: %v", err)
 	}
+	store.revision = revisionOf(data)
+
+	// Give tasks from older files a UID; it is written with the next save.
+	// It is derived from the task, so every reader sees the same one until
+	// then without writing the file.
+	for i := range store.Tasks {
+		if task := &store.Tasks[i]; task.UID == "" {
+			task.UID = NameUID(fmt.Sprintf("task:%d:%s:%s", task.ID, task.CreatedAt, task.Description))
+		}
+	}
+	store.snapshot()
 
 	return &store, nil
 }
 
-// SaveTasks saves tasks to the data file
+// SaveTasks saves tasks to the data file. It fails with ErrStoreChanged
+// rather than overwrite changes another process saved after the store was
+// loaded.
 func (store *TaskStore) SaveTasks() error {
 	filePath := GetDataFilePath()
+	if session != nil {
+		return session.commit(filePath, store)
+	}
 
 	// Create directory if it doesn't exist
 	dir := filepath.Dir(filePath)
@@ -117,18 +201,130 @@ func (store *TaskStore) SaveTasks() error {
 		return fmt.Errorf("failed to create data directory: %v", err)
 	}
 
+	unlock, err := lockFile(filePath)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	return store.save(filePath)
+}
+
+// save writes the store while the caller holds the lock
+func (store *TaskStore) save(filePath string) error {
+	current, err := fileRevision(filePath)
+	if err != nil {
+		return fmt.Errorf("failed to read tasks file: %v", err)
+	}
+	if current != store.revision {
+		return ErrStoreChanged
+	}
+
+	store.stampModified(time.Now())
+	data, err := store.write(filePath)
+	if err != nil {
+		return err
+	}
+	store.revision = revisionOf(data)
+	store.snapshot()
+
+	return nil
+}
+
+// SaveTasksTo writes the store to another tasks file, such as a copy shared
+// with other machines, while the caller holds that file's lock (see
+// LockFile). Unlike SaveTasks it keeps every ModifiedAt as it is.
+func (store *TaskStore) SaveTasksTo(filePath string) error {
+	_, err := store.write(filePath)
+	return err
+}
+
+// write marshals the store and replaces the file with it
+func (store *TaskStore) write(filePath string) ([]byte, error) {
 	// Marshal to JSON
 	data, err := json.MarshalIndent(store, "", "  ")
 	if err != nil {
-		return fmt.Errorf("failed to marshal tasks: %v", err)
+		return nil, fmt.Errorf("failed to marshal tasks: %v", err)
 	}
 
-	// Write to file
-	if err := os.WriteFile(filePath, data, 0644); err != nil {
-		return fmt.Errorf("failed to write tasks file: %v", err)
+	// Write to a temporary file and rename it over the old one
+	if err := writeFileAtomic(filePath, data); err != nil {
+		return nil, fmt.Errorf("failed to write tasks file: %v", err)
 	}
+	return data, nil
+}
 
-	return nil
+// snapshot remembers the tasks as they are in the file
+func (store *TaskStore) snapshot() {
+	store.loaded = make(map[string]loadedTask, len(store.Tasks))
+	for _, task := range store.Tasks {
+		store.loaded[task.UID] = loadedTask{hash: taskHash(task), modifiedAt: task.ModifiedAt, task: copyTask(task)}
+	}
+}
+
+// copyTask returns a task that shares no slices or maps with the original
+func copyTask(task Task) Task {
+	var copied Task
+	data, _ := json.Marshal(task)
+	json.Unmarshal(data, &copied)
+	return copied
+}
+
+// TaskChange is a task added, changed or removed since the store was loaded
+// or last saved. Old is nil for an added task and New for a removed one.
+type TaskChange struct {
+	Old *Task
+	New *Task
+}
+
+// Changes lists what was added, changed or removed since the store was
+// loaded or last saved, in store order followed by removed tasks by ID
+func (store *TaskStore) Changes() []TaskChange {
+	var changes, removed []TaskChange
+	current := make(map[string]bool, len(store.Tasks))
+	for _, task := range store.Tasks {
+		current[task.UID] = true
+		loaded, existed := store.loaded[task.UID]
+		switch {
+		case !existed:
+			changes = append(changes, TaskChange{New: &task})
+		case loaded.hash != taskHash(task):
+			old := loaded.task
+			changes = append(changes, TaskChange{Old: &old, New: &task})
+		}
+	}
+	for uid, loaded := range store.loaded {
+		if !current[uid] {
+			old := loaded.task
+			removed = append(removed, TaskChange{Old: &old})
+		}
+	}
+	sort.Slice(removed, func(i, j int) bool { return removed[i].Old.ID < removed[j].Old.ID })
+	return append(changes, removed...)
+}
+
+// taskHash identifies a task's contents, ignoring its local ID
+func taskHash(task Task) string {
+	task.ID = 0
+	data, _ := json.Marshal(task)
+	return revisionOf(data)
+}
+
+// stampModified sets ModifiedAt on tasks that are new or changed since the
+// store was loaded, unless the caller already set it (as sync does when it
+// takes a change made elsewhere)
+func (store *TaskStore) stampModified(now time.Time) {
+	for i := range store.Tasks {
+		task := &store.Tasks[i]
+		loaded, existed := store.loaded[task.UID]
+		if existed && loaded.hash == taskHash(*task) {
+			continue
+		}
+		if task.ModifiedAt != loaded.modifiedAt {
+			continue
+		}
+		task.ModifiedAt = now.Format(TimestampFormat)
+	}
 }
 
 // AddTask adds a new task to the store
@@ -138,17 +334,21 @@ func (store *TaskStore) AddTask(description, dueDate, priority string) (*Task, e
 		return nil, err
 	}
 
-	if err := ValidatePriority(priority); err != nil {
+	priority, err := NormalizePriority(priority)
+	if err != nil {
 		return nil, err
 	}
 
 	// Create new task
 	task := Task{
 		ID:          store.NextID,
+		UID:         NewUID(),
 		Description: description,
 		DueDate:     dueDate,
-		Priority:    strings.ToLower(priority),
+		Priority:    priority,
 		Completed:   false,
+		CreatedAt:   time.Now().Format(TimestampFormat),
+		Creator:     identity,
 	}
 
 	// Add to store
@@ -158,13 +358,71 @@ func (store *TaskStore) AddTask(description, dueDate, priority string) (*Task, e
 	return &task, nil
 }
 
+// ImportTask adds a task read from another tool. The task is validated and
+// created through AddTask; the remaining fields are copied over, keeping the
+// original UID and creation and completion timestamps when present.
+func (store *TaskStore) ImportTask(imported Task) (*Task, error) {
+	if err := ValidateDate(imported.WaitUntil); err != nil {
+		return nil, err
+	}
+	if err := ValidateTime(imported.DueTime); err != nil {
+		return nil, err
+	}
+	if imported.DueTime != "" && imported.DueDate == "" {
+		return nil, fmt.Errorf("a due time needs a due date")
+	}
+
+	task, err := store.AddTask(imported.Description, imported.DueDate, imported.Priority)
+	if err != nil {
+		return nil, err
+	}
+
+	stored := &store.Tasks[len(store.Tasks)-1]
+	if imported.UID != "" {
+		stored.UID = imported.UID
+	}
+	stored.DueTime = imported.DueTime
+	stored.Completed = imported.Completed
+	stored.Notes = imported.Notes
+	stored.WaitUntil = imported.WaitUntil
+	stored.CompletedAt = imported.CompletedAt
+	stored.Tags = imported.Tags
+	stored.Project = imported.Project
+	stored.Contexts = imported.Contexts
+	stored.Source = imported.Source
+	stored.Extra = imported.Extra
+	stored.Assignee = imported.Assignee
+	stored.History = imported.History
+	if imported.Creator != "" {
+		stored.Creator = imported.Creator
+	}
+	if imported.CreatedAt != "" {
+		stored.CreatedAt = imported.CreatedAt
+	}
+	if stored.Completed && stored.CompletedAt == "" {
+		stored.CompletedAt = time.Now().Format(TimestampFormat)
+	}
+
+	*task = *stored
+	return task, nil
+}
+
 // CompleteTask marks a task as completed
 func (store *TaskStore) CompleteTask(id int) error {
 	for i, task := range store.Tasks {
 		if task.ID == id {
 			store.Tasks[i].Completed = true
+			store.Tasks[i].CompletedAt = time.Now().Format(TimestampFormat)
 			return nil
 		}
 	}
 	return fmt.Errorf("task with ID %d not found", id)
 }
+
+// IsWaiting reports whether a pending task is deferred until a future date
+func (task Task) IsWaiting(now time.Time) bool {
+	if task.Completed || task.WaitUntil == "" {
+		return false
+	}
+	return task.WaitUntil > now.Format(dateFormat)
+}
@@ -0,0 +1,66 @@
+package taskdata
+
+import (
+	"crypto/rand"
+	"crypto/sha1"
+	"fmt"
+	"strings"
+)
+
+// NewUID returns a random RFC 4122 version 4 UUID identifying a task
+// across stores and external tools
+func NewUID() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		panic(fmt.Sprintf("failed to generate task UID: %v", err))
+	}
+	b[6] = (b[6] & 0x0f) | 0x40 // Version 4
+	b[8] = (b[8] & 0x3f) | 0x80 // RFC 4122 variant
+	return formatUUID(b)
+}
+
+// NameUID derives a UID from a name, so the same name always gives the same UID.
+// Importers use it for sources that have no identity of their own.
+func NameUID(name string) string {
+	sum := sha1.Sum([]byte(name))
+	var b [16]byte
+	copy(b[:], sum[:16])
+	b[6] = (b[6] & 0x0f) | 0x50 // Version 5 (name-based, SHA-1)
+	b[8] = (b[8] & 0x3f) | 0x80
+	return formatUUID(b)
+}
+
+func formatUUID(b [16]byte) string {
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
+
+// NormalizeDescription reduces a description to the form used to spot
+// duplicates: lowercase, trimmed, with runs of whitespace collapsed
+func NormalizeDescription(description string) string {
+	return strings.Join(strings.Fields(strings.ToLower(description)), " ")
+}
+
+// FindByDescription returns the first task whose normalized description
+// matches, or nil
+func (store *TaskStore) FindByDescription(description string) *Task {
+	normalized := NormalizeDescription(description)
+	for i := range store.Tasks {
+		if NormalizeDescription(store.Tasks[i].Description) == normalized {
+			return &store.Tasks[i]
+		}
+	}
+	return nil
+}
+
+// FindByUID returns the task with the given UID, or nil
+func (store *TaskStore) FindByUID(uid string) *Task {
+	if uid == "" {
+		return nil
+	}
+	for i := range store.Tasks {
+		if store.Tasks[i].UID == uid {
+			return &store.Tasks[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package taskdata
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+)
+
+// useDataFile points the store at a file in a temporary directory holding
+// data, or at a missing file when data is empty
+func useDataFile(t *testing.T, data string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "tasks.json")
+	if data != "" {
+		if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	SetDataFilePath(path)
+	t.Cleanup(func() { SetDataFilePath("") })
+	return path
+}
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-[45][0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+func TestUIDs(t *testing.T) {
+	for _, uid := range []string{NewUID(), NameUID("scan:main.go:add tests")} {
+		if !uuidPattern.MatchString(uid) {
+			t.Errorf("%s is not a UUID", uid)
+		}
+	}
+	if NewUID() == NewUID() {
+		t.Error("NewUID returned the same UID twice")
+	}
+	if NameUID("a") != NameUID("a") || NameUID("a") == NameUID("b") {
+		t.Error("NameUID does not depend on the name alone")
+	}
+}
+
+const legacyTasks = `{
+  "tasks": [
+    {"id": 1, "description": "Buy milk", "priority": "normal", "created_at": "2024-01-01T09:00:00Z"},
+    {"id": 2, "description": "Buy milk", "priority": "normal", "created_at": "2024-01-02T09:00:00Z"}
+  ],
+  "next_id": 3
+}`
+
+func TestLoadingOlderFilesDoesNotWrite(t *testing.T) {
+	path := useDataFile(t, legacyTasks)
+
+	first, err := LoadTasks()
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := LoadTasks()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if data, _ := os.ReadFile(path); string(data) != legacyTasks {
+		t.Errorf("loading wrote the file:\n%s", data)
+	}
+	for i := range first.Tasks {
+		if first.Tasks[i].UID == "" || first.Tasks[i].UID != second.Tasks[i].UID {
+			t.Errorf("task #%d has UIDs %q and %q", first.Tasks[i].ID, first.Tasks[i].UID, second.Tasks[i].UID)
+		}
+	}
+	if first.Tasks[0].UID == first.Tasks[1].UID {
+		t.Error("two tasks got the same UID")
+	}
+}
+
+func TestNextSavePersistsUIDs(t *testing.T) {
+	path := useDataFile(t, legacyTasks)
+	loaded, err := LoadTasks()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = UpdateTasks(func(store *TaskStore) error {
+		return store.CompleteTask(1)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, task := range loaded.Tasks {
+		if !bytes.Contains(data, []byte(task.UID)) {
+			t.Errorf("UID %s of #%d was not saved", task.UID, task.ID)
+		}
+	}
+}
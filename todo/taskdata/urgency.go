@@ -0,0 +1,151 @@
+package taskdata
+
+import (
+	"math"
+	"time"
+)
+
+// UrgencyWeights are the coefficients combined into a task's urgency score
+type UrgencyWeights struct {
+	Priority float64 // Applied to the priority's position on the scale (0.0-1.0)
+	Due      float64 // Applied to due date proximity (0.0-1.0)
+	Age      float64 // Applied to the task's age, saturating after a year (0.0-1.0)
+	Tags     float64 // Applied when the task has tags (0.8-1.0)
+	Blocking float64 // Added when other pending tasks depend on this one
+	Blocked  float64 // Added (usually negative) when this task waits on pending tasks
+}
+
+// DefaultUrgencyWeights returns the weights used when none are configured
+func DefaultUrgencyWeights() UrgencyWeights {
+	return UrgencyWeights{
+		Priority: 6.0,
+		Due:      12.0,
+		Age:      2.0,
+		Tags:     1.0,
+		Blocking: 8.0,
+		Blocked:  -5.0,
+	}
+}
+
+// urgencyWeights are the weights used by Urgency
+var urgencyWeights = DefaultUrgencyWeights()
+
+// SetUrgencyWeights replaces the weights used by Urgency
+func SetUrgencyWeights(weights UrgencyWeights) {
+	urgencyWeights = weights
+}
+
+// Urgency computes a score combining priority, due proximity, age, tags and
+// blocking status. Higher scores are more urgent; completed tasks score 0.
+func (store *TaskStore) Urgency(task Task, now time.Time) float64 {
+	if task.Completed {
+		return 0
+	}
+
+	w := urgencyWeights
+	score := w.Priority*priorityFactor(task.Priority) +
+		w.Due*dueFactor(task.DueDate, now) +
+		w.Age*ageFactor(task.CreatedAt, now) +
+		w.Tags*tagsFactor(task.Tags)
+
+	if store.IsBlocking(task) {
+		score += w.Blocking
+	}
+	if store.IsBlocked(task) {
+		score += w.Blocked
+	}
+
+	// Round to two decimals so scores display and compare consistently
+	return math.Round(score*100) / 100
+}
+
+// IsBlocking reports whether another pending task depends on this task
+func (store *TaskStore) IsBlocking(task Task) bool {
+	if task.Completed {
+		return false
+	}
+	for _, other := range store.Tasks {
+		if other.Completed {
+			continue
+		}
+		for _, id := range other.DependsOn {
+			if id == task.ID {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// IsBlocked reports whether this task depends on a task that is still pending
+func (store *TaskStore) IsBlocked(task Task) bool {
+	for _, id := range task.DependsOn {
+		for _, other := range store.Tasks {
+			if other.ID == id && !other.Completed {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// priorityFactor maps the priority onto 0.0 (lowest level) to 1.0 (highest level)
+func priorityFactor(priority string) float64 {
+	rank := PriorityRank(priority)
+	levels := len(priorityScale.levels)
+	if rank == 0 || levels < 2 {
+		return 0
+	}
+	return float64(rank-1) / float64(levels-1)
+}
+
+// dueFactor rises from 0.2 two weeks out to 1.0 a week overdue
+func dueFactor(dueDate string, now time.Time) float64 {
+	if dueDate == "" {
+		return 0
+	}
+	due, err := time.Parse(dateFormat, dueDate)
+	if err != nil {
+		return 0
+	}
+
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	daysUntil := due.Sub(today).Hours() / 24
+
+	switch {
+	case daysUntil <= -7:
+		return 1.0
+	case daysUntil >= 14:
+		return 0.2
+	}
+	// Linear between -7 days (1.0) and +14 days (0.2)
+	return 1.0 - (daysUntil+7)*0.8/21
+}
+
+// ageFactor grows linearly with age and saturates after a year
+func ageFactor(createdAt string, now time.Time) float64 {
+	if createdAt == "" {
+		return 0
+	}
+	created, err := time.Parse(TimestampFormat, createdAt)
+	if err != nil {
+		return 0
+	}
+	days := now.Sub(created).Hours() / 24
+	if days <= 0 {
+		return 0
+	}
+	return math.Min(days/365, 1.0)
+}
+
+func tagsFactor(tags []string) float64 {
+	switch len(tags) {
+	case 0:
+		return 0
+	case 1:
+		return 0.8
+	case 2:
+		return 0.9
+	}
+	return 1.0
+}
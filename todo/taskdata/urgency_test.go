@@ -0,0 +1,105 @@
+package taskdata
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestUrgencyFactors(t *testing.T) {
+	now := time.Date(2025, time.July, 16, 15, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		got  float64
+		want float64
+	}{
+		{"highest priority", priorityFactor("high"), 1.0},
+		{"middle priority", priorityFactor("normal"), 0.5},
+		{"lowest priority", priorityFactor("low"), 0},
+		{"unknown priority", priorityFactor("someday"), 0},
+		{"no due date", dueFactor("", now), 0},
+		{"due in two weeks", dueFactor("2025-07-30", now), 0.2},
+		{"due in a month", dueFactor("2025-08-16", now), 0.2},
+		{"due today", dueFactor("2025-07-16", now), 1.0 - 7*0.8/21},
+		{"a week overdue", dueFactor("2025-07-09", now), 1.0},
+		{"long overdue", dueFactor("2025-01-01", now), 1.0},
+		{"invalid due date", dueFactor("soon", now), 0},
+		{"just created", ageFactor(now.Format(TimestampFormat), now), 0},
+		{"half a year old", ageFactor(now.AddDate(0, 0, -365/2).Format(TimestampFormat), now), float64(365/2) / 365},
+		{"two years old", ageFactor(now.AddDate(-2, 0, 0).Format(TimestampFormat), now), 1.0},
+		{"no tags", tagsFactor(nil), 0},
+		{"one tag", tagsFactor([]string{"a"}), 0.8},
+		{"two tags", tagsFactor([]string{"a", "b"}), 0.9},
+		{"many tags", tagsFactor([]string{"a", "b", "c", "d"}), 1.0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if math.Abs(tt.got-tt.want) > 1e-9 {
+				t.Errorf("got %v, want %v", tt.got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBlockingAndBlocked(t *testing.T) {
+	store := &TaskStore{Tasks: []Task{
+		{ID: 1, Description: "Collect numbers"},
+		{ID: 2, Description: "Write report", DependsOn: []int{1}},
+		{ID: 3, Description: "Book room", Completed: true},
+		{ID: 4, Description: "Hold meeting", DependsOn: []int{3}},
+		{ID: 5, Description: "Old plan", Completed: true, DependsOn: []int{4}},
+		{ID: 6, Description: "Orphan", DependsOn: []int{99}},
+	}}
+
+	tests := []struct {
+		id       int
+		blocking bool
+		blocked  bool
+	}{
+		{1, true, false},
+		{2, false, true},
+		{3, false, false}, // Completed tasks block nothing
+		{4, false, false}, // Only completed tasks depend on it, and it waits on none
+		{6, false, false}, // Missing dependencies do not block
+	}
+	for _, tt := range tests {
+		task := store.Tasks[tt.id-1]
+		if got := store.IsBlocking(task); got != tt.blocking {
+			t.Errorf("IsBlocking(#%d) = %v, want %v", tt.id, got, tt.blocking)
+		}
+		if got := store.IsBlocked(task); got != tt.blocked {
+			t.Errorf("IsBlocked(#%d) = %v, want %v", tt.id, got, tt.blocked)
+		}
+	}
+}
+
+func TestUrgency(t *testing.T) {
+	now := time.Date(2025, time.July, 16, 15, 30, 0, 0, time.UTC)
+	store := &TaskStore{Tasks: []Task{
+		{ID: 1, Description: "Collect numbers", Priority: "low"},
+		{ID: 2, Description: "Write report", Priority: "high", DueDate: "2025-07-09", Tags: []string{"work"}, DependsOn: []int{1}},
+		{ID: 3, Description: "Done", Priority: "high", Completed: true},
+	}}
+
+	weights := DefaultUrgencyWeights()
+	tests := []struct {
+		id   int
+		want float64
+	}{
+		{1, weights.Blocking},
+		{2, weights.Priority + weights.Due + weights.Tags*0.8 + weights.Blocked},
+		{3, 0},
+	}
+	for _, tt := range tests {
+		if got := store.Urgency(store.Tasks[tt.id-1], now); math.Abs(got-tt.want) > 0.005 {
+			t.Errorf("Urgency(#%d) = %v, want %v", tt.id, got, tt.want)
+		}
+	}
+
+	SetUrgencyWeights(UrgencyWeights{Blocking: 1})
+	t.Cleanup(func() { SetUrgencyWeights(DefaultUrgencyWeights()) })
+	if got := store.Urgency(store.Tasks[0], now); got != 1 {
+		t.Errorf("Urgency with custom weights = %v, want 1", got)
+	}
+}
@@ -0,0 +1,431 @@
+// Package tasksync merges two copies of a task store that were changed
+// independently. Tasks are matched by UID and merged field by field against
+// the copy both sides had after the last sync, so edits to different fields
+// of the same task combine and only edits to the same field conflict.
+package tasksync
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"todo/taskdata"
+)
+
+// Side is one of the two copies being merged
+type Side int
+
+const (
+	Local Side = iota
+	Remote
+)
+
+func (side Side) String() string {
+	if side == Remote {
+		return "remote"
+	}
+	return "local"
+}
+
+// Deleted is shown as the value of a task deleted on one side
+const Deleted = "(deleted)"
+
+// Conflict is a task changed differently on both sides since the last sync.
+// Set Choice before calling Result.Apply; it defaults to Local.
+type Conflict struct {
+	UID         string
+	Description string
+	Field       string // Empty when the task was deleted on one side and changed on the other
+
+	Base, Local, Remote string // Field values as shown to the user
+
+	LocalModified, RemoteModified string // ModifiedAt of each side's task
+
+	Choice Side
+
+	useRemote func() // Applies the remote side to the merged entry
+}
+
+// Newest is the side whose task was modified last, Local when unknown or equal
+func (conflict *Conflict) Newest() Side {
+	local, localErr := time.Parse(taskdata.TimestampFormat, conflict.LocalModified)
+	remote, remoteErr := time.Parse(taskdata.TimestampFormat, conflict.RemoteModified)
+	if remoteErr == nil && (localErr != nil || remote.After(local)) {
+		return Remote
+	}
+	return Local
+}
+
+// Result is a merge waiting for its conflicts to be resolved
+type Result struct {
+	Conflicts []*Conflict
+
+	Incoming int // Tasks added, changed or deleted on the remote side
+	Outgoing int // Tasks added, changed or deleted on the local side
+
+	entries []*entry
+}
+
+// entry is the merged state of one task
+type entry struct {
+	uid     string
+	merged  *record // nil when the task is deleted
+	localID int     // 0 for tasks new to the local store
+}
+
+// record is a task with its dependencies identified by UID, since IDs are
+// local to each store
+type record struct {
+	task taskdata.Task
+	deps []string
+}
+
+// field is a part of a task merged as a unit
+type field struct {
+	name  string
+	value func(r *record) string // Compared between sides and shown in conflicts
+	copy  func(dst, src *record)
+}
+
+var fields = []field{
+	{"description",
+		func(r *record) string { return r.task.Description },
+		func(dst, src *record) { dst.task.Description = src.task.Description }},
+	{"due",
+		func(r *record) string { return strings.TrimSpace(r.task.DueDate + " " + r.task.DueTime) },
+		func(dst, src *record) { dst.task.DueDate, dst.task.DueTime = src.task.DueDate, src.task.DueTime }},
+	{"priority",
+		func(r *record) string { return r.task.Priority },
+		func(dst, src *record) { dst.task.Priority = src.task.Priority }},
+	{"status",
+		func(r *record) string {
+			if r.task.Completed {
+				return "completed"
+			}
+			return "pending"
+		},
+		func(dst, src *record) {
+			dst.task.Completed, dst.task.CompletedAt = src.task.Completed, src.task.CompletedAt
+		}},
+	{"notes",
+		func(r *record) string { return r.task.Notes },
+		func(dst, src *record) { dst.task.Notes = src.task.Notes }},
+	{"wait",
+		func(r *record) string { return r.task.WaitUntil },
+		func(dst, src *record) { dst.task.WaitUntil = src.task.WaitUntil }},
+	{"tags",
+		func(r *record) string { return strings.Join(r.task.Tags, ", ") },
+		func(dst, src *record) { dst.task.Tags = src.task.Tags }},
+	{"project",
+		func(r *record) string { return r.task.Project },
+		func(dst, src *record) { dst.task.Project = src.task.Project }},
+	{"contexts",
+		func(r *record) string { return strings.Join(r.task.Contexts, ", ") },
+		func(dst, src *record) { dst.task.Contexts = src.task.Contexts }},
+	{"source",
+		func(r *record) string { return r.task.Source },
+		func(dst, src *record) { dst.task.Source = src.task.Source }},
+	{"assignee",
+		func(r *record) string { return r.task.Assignee },
+		func(dst, src *record) { dst.task.Assignee, dst.task.History = src.task.Assignee, src.task.History }},
+	{"depends",
+		func(r *record) string { return strings.Join(r.deps, ", ") },
+		func(dst, src *record) { dst.deps = src.deps }},
+}
+
+// fieldsOf returns the fields to merge between versions of a task: the
+// fixed ones, then one for each key of Extra any version has, so that
+// attributes kept for different tools merge independently
+func fieldsOf(versions ...*record) []field {
+	var keys []string
+	for _, r := range versions {
+		if r == nil {
+			continue
+		}
+		for key := range r.task.Extra {
+			if !slices.Contains(keys, key) {
+				keys = append(keys, key)
+			}
+		}
+	}
+	sort.Strings(keys)
+
+	merged := slices.Clone(fields)
+	for _, key := range keys {
+		merged = append(merged, extraField(key))
+	}
+	return merged
+}
+
+// extraField merges one key of Task.Extra; a missing key is an empty value
+func extraField(key string) field {
+	return field{"extra." + key,
+		func(r *record) string {
+			raw, exists := r.task.Extra[key]
+			if !exists {
+				return ""
+			}
+			var compact bytes.Buffer
+			if json.Compact(&compact, raw) != nil {
+				return string(raw)
+			}
+			return compact.String()
+		},
+		func(dst, src *record) {
+			// The map may be shared with the version dst was copied from
+			extra := maps.Clone(dst.task.Extra)
+			if raw, exists := src.task.Extra[key]; exists {
+				if extra == nil {
+					extra = map[string]json.RawMessage{}
+				}
+				extra[key] = raw
+			} else {
+				delete(extra, key)
+			}
+			if len(extra) == 0 {
+				extra = nil
+			}
+			dst.task.Extra = extra
+		}}
+}
+
+// records indexes a store's tasks by UID, keeping their order
+func records(store *taskdata.TaskStore) (map[string]*record, []string) {
+	uids := make(map[int]string, len(store.Tasks))
+	for _, task := range store.Tasks {
+		uids[task.ID] = task.UID
+	}
+
+	byUID := make(map[string]*record, len(store.Tasks))
+	var order []string
+	for _, task := range store.Tasks {
+		if _, duplicate := byUID[task.UID]; duplicate {
+			continue
+		}
+		r := &record{task: task}
+		for _, id := range task.DependsOn {
+			if uid, ok := uids[id]; ok {
+				r.deps = append(r.deps, uid)
+			}
+		}
+		byUID[task.UID] = r
+		order = append(order, task.UID)
+	}
+	return byUID, order
+}
+
+// changed reports whether any field differs between two versions of a task
+func changed(a, b *record) bool {
+	for _, f := range fieldsOf(a, b) {
+		if f.value(a) != f.value(b) {
+			return true
+		}
+	}
+	return false
+}
+
+// Merge combines the local and remote stores, given the store both had after
+// the last sync (empty before the first one). Tasks present on both sides
+// without a common base, as on a first sync, conflict on every field where
+// they differ.
+func Merge(base, local, remote *taskdata.TaskStore) *Result {
+	baseRecords, _ := records(base)
+	localRecords, localOrder := records(local)
+	remoteRecords, remoteOrder := records(remote)
+
+	localIDs := make(map[string]int, len(local.Tasks))
+	for _, task := range local.Tasks {
+		if _, seen := localIDs[task.UID]; !seen {
+			localIDs[task.UID] = task.ID
+		}
+	}
+
+	result := &Result{}
+	seen := map[string]bool{}
+	for _, uid := range append(localOrder, remoteOrder...) {
+		if seen[uid] {
+			continue
+		}
+		seen[uid] = true
+		e := &entry{uid: uid, localID: localIDs[uid]}
+		result.entries = append(result.entries, e)
+		result.mergeTask(e, baseRecords[uid], localRecords[uid], remoteRecords[uid])
+	}
+	return result
+}
+
+func (result *Result) mergeTask(e *entry, b, l, r *record) {
+	switch {
+	case l != nil && r != nil:
+		e.merged = result.mergeFields(b, l, r)
+
+	case l != nil: // Missing remotely
+		switch {
+		case b == nil:
+			e.merged = l
+			result.Outgoing++
+		case !changed(b, l):
+			result.Incoming++ // Deleted remotely
+		default:
+			e.merged = l
+			result.Conflicts = append(result.Conflicts, &Conflict{
+				UID: e.uid, Description: l.task.Description,
+				Base: "", Local: "changed", Remote: Deleted,
+				LocalModified: l.task.ModifiedAt,
+				useRemote:     func() { e.merged = nil },
+			})
+		}
+
+	case r != nil: // Missing locally
+		switch {
+		case b == nil:
+			e.merged = r
+			result.Incoming++
+		case !changed(b, r):
+			result.Outgoing++ // Deleted locally
+		default:
+			result.Conflicts = append(result.Conflicts, &Conflict{
+				UID: e.uid, Description: r.task.Description,
+				Base: "", Local: Deleted, Remote: "changed",
+				RemoteModified: r.task.ModifiedAt,
+				useRemote:      func() { e.merged = r },
+			})
+		}
+	}
+}
+
+// mergeFields merges a task present on both sides, starting from the local
+// version and taking each field the remote side changed
+func (result *Result) mergeFields(b, l, r *record) *record {
+	merged := &record{task: l.task, deps: l.deps}
+	merged.task.ModifiedAt = later(l.task.ModifiedAt, r.task.ModifiedAt)
+
+	var incoming, outgoing bool
+	for _, f := range fieldsOf(b, l, r) {
+		localValue, remoteValue := f.value(l), f.value(r)
+		if localValue == remoteValue {
+			continue
+		}
+
+		if b != nil {
+			baseValue := f.value(b)
+			if localValue == baseValue {
+				f.copy(merged, r)
+				incoming = true
+				continue
+			}
+			if remoteValue == baseValue {
+				outgoing = true
+				continue
+			}
+		}
+
+		conflict := &Conflict{
+			UID: l.task.UID, Description: l.task.Description, Field: f.name,
+			Local: localValue, Remote: remoteValue,
+			LocalModified: l.task.ModifiedAt, RemoteModified: r.task.ModifiedAt,
+		}
+		if b != nil {
+			conflict.Base = f.value(b)
+		}
+		copyField := f.copy
+		conflict.useRemote = func() { copyField(merged, r) }
+		result.Conflicts = append(result.Conflicts, conflict)
+	}
+
+	if incoming {
+		result.Incoming++
+	}
+	if outgoing {
+		result.Outgoing++
+	}
+	return merged
+}
+
+// later returns whichever of two timestamps is later, either if one is unset
+func later(a, b string) string {
+	ta, errA := time.Parse(taskdata.TimestampFormat, a)
+	tb, errB := time.Parse(taskdata.TimestampFormat, b)
+	switch {
+	case errA != nil:
+		return b
+	case errB != nil:
+		return a
+	case tb.After(ta):
+		return b
+	}
+	return a
+}
+
+// Apply resolves the conflicts as chosen and writes the merged tasks into
+// the local store. Tasks keep their local IDs; tasks new to it get the next
+// free ones.
+func (result *Result) Apply(store *taskdata.TaskStore) {
+	for _, conflict := range result.Conflicts {
+		if conflict.Choice == Remote {
+			conflict.useRemote()
+		}
+	}
+
+	nextID := store.NextID
+	for _, e := range result.entries {
+		if e.localID >= nextID {
+			nextID = e.localID + 1
+		}
+	}
+
+	ids := map[string]int{}
+	var tasks []taskdata.Task
+	var deps [][]string
+	for _, e := range result.entries {
+		if e.merged == nil {
+			continue
+		}
+		task := e.merged.task
+		if e.localID != 0 {
+			task.ID = e.localID
+		} else {
+			task.ID = nextID
+			nextID++
+		}
+		ids[e.uid] = task.ID
+		tasks = append(tasks, task)
+		deps = append(deps, e.merged.deps)
+	}
+
+	for i := range tasks {
+		tasks[i].DependsOn = nil
+		for _, uid := range deps[i] {
+			if id, ok := ids[uid]; ok {
+				tasks[i].DependsOn = append(tasks[i].DependsOn, id)
+			}
+		}
+	}
+
+	if tasks == nil {
+		tasks = []taskdata.Task{}
+	}
+	store.Tasks = tasks
+	store.NextID = nextID
+}
+
+// String describes a conflict on one line
+func (conflict *Conflict) String() string {
+	if conflict.Field == "" {
+		return fmt.Sprintf("%q: local %s, remote %s", conflict.Description, conflict.Local, conflict.Remote)
+	}
+	return fmt.Sprintf("%q %s: local %s, remote %s", conflict.Description, conflict.Field,
+		quoteValue(conflict.Local), quoteValue(conflict.Remote))
+}
+
+func quoteValue(value string) string {
+	if value == "" {
+		return "(none)"
+	}
+	return fmt.Sprintf("%q", value)
+}
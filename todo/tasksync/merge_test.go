@@ -0,0 +1,96 @@
+package tasksync
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"todo/taskdata"
+)
+
+func storeWithExtra(extra map[string]string) *taskdata.TaskStore {
+	task := taskdata.Task{ID: 1, UID: "task-1", Description: "Buy milk", Priority: "normal"}
+	if extra != nil {
+		task.Extra = map[string]json.RawMessage{}
+		for key, value := range extra {
+			task.Extra[key] = json.RawMessage(value)
+		}
+	}
+	return &taskdata.TaskStore{Tasks: []taskdata.Task{task}, NextID: 2}
+}
+
+func TestMergeExtraPerKey(t *testing.T) {
+	base := storeWithExtra(map[string]string{"color": `"red"`, "estimate": `3`})
+	local := storeWithExtra(map[string]string{"color": `"red"`, "estimate": `3`, "links": `["a"]`})
+	remote := storeWithExtra(map[string]string{"color": `"blue"`})
+
+	result := Merge(base, local, remote)
+	if len(result.Conflicts) != 0 {
+		t.Fatalf("conflicts: %v", result.Conflicts)
+	}
+	result.Apply(local)
+
+	extra := local.Tasks[0].Extra
+	if len(extra) != 2 || string(extra["color"]) != `"blue"` || string(extra["links"]) != `["a"]` {
+		t.Errorf("merged extra = %s", extra)
+	}
+}
+
+func TestMergeExtraConflictsOnSameKey(t *testing.T) {
+	base := storeWithExtra(map[string]string{"color": `"red"`})
+	local := storeWithExtra(map[string]string{"color": `"green"`, "estimate": `3`})
+	remote := storeWithExtra(map[string]string{"color": `"blue"`})
+
+	result := Merge(base, local, remote)
+	if len(result.Conflicts) != 1 || result.Conflicts[0].Field != "extra.color" {
+		t.Fatalf("conflicts = %v, want one on extra.color", result.Conflicts)
+	}
+	result.Conflicts[0].Choice = Remote
+	result.Apply(local)
+
+	extra := local.Tasks[0].Extra
+	if string(extra["color"]) != `"blue"` || string(extra["estimate"]) != `3` {
+		t.Errorf("merged extra = %s", extra)
+	}
+}
+
+func TestMergeAssigneeKeepsHistory(t *testing.T) {
+	now := time.Date(2025, time.July, 16, 12, 0, 0, 0, time.UTC)
+	assigned := func(assignees ...string) *taskdata.TaskStore {
+		store := storeWithExtra(nil)
+		for i, assignee := range assignees {
+			store.Tasks[0].Assign(assignee, now.Add(time.Duration(i)*time.Hour))
+		}
+		return store
+	}
+
+	tests := []struct {
+		name      string
+		local     *taskdata.TaskStore
+		remote    *taskdata.TaskStore
+		want      string
+		history   int
+		conflicts int
+	}{
+		{"reassigned remotely", assigned("bob"), assigned("bob", "carol"), "carol", 2, 0},
+		{"reassigned locally", assigned("bob", "dave"), assigned("bob"), "dave", 2, 0},
+		{"reassigned on both sides", assigned("bob", "dave"), assigned("bob", "carol"), "dave", 2, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := Merge(assigned("bob"), tt.local, tt.remote)
+			if len(result.Conflicts) != tt.conflicts {
+				t.Fatalf("conflicts = %v, want %d", result.Conflicts, tt.conflicts)
+			}
+			for i := range result.Conflicts {
+				result.Conflicts[i].Choice = Local
+			}
+			result.Apply(tt.local)
+
+			task := tt.local.Tasks[0]
+			if task.Assignee != tt.want || len(task.History) != tt.history || task.History[len(task.History)-1].To != tt.want {
+				t.Errorf("assignee %q with history %+v, want %q", task.Assignee, task.History, tt.want)
+			}
+		})
+	}
+}
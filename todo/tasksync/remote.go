@@ -0,0 +1,224 @@
+package tasksync
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"todo/taskdata"
+)
+
+// remoteFileName is the tasks file kept in a shared folder or repository
+const remoteFileName = "tasks.json"
+
+// ErrRemoteChanged is returned by Push when another machine pushed after
+// the Pull the merge was made from
+var ErrRemoteChanged = errors.New("the shared tasks changed during the sync; run 'todo sync' again")
+
+// Shared is a copy of the store shared with other machines
+type Shared interface {
+	// Pull reads the shared store, empty if nothing was pushed yet
+	Pull() (*taskdata.TaskStore, error)
+	// Push replaces the shared store with the merged one. It fails with
+	// ErrRemoteChanged if the shared store changed since Pull.
+	Push(store *taskdata.TaskStore) error
+	// Close releases the remote
+	Close() error
+}
+
+// Open connects to a shared folder or a git repository. Git URLs, paths
+// ending in .git and git repositories on disk (bare or not) are synced
+// through a clone kept in workDir; any other path is a shared folder, such
+// as one synced by Dropbox or mounted over the network.
+func Open(location, workDir string) (Shared, error) {
+	if isGitLocation(location) {
+		return openGit(location, workDir)
+	}
+	return openFolder(location)
+}
+
+func isGitLocation(location string) bool {
+	if strings.Contains(location, "://") || strings.HasPrefix(location, "git@") || strings.HasSuffix(location, ".git") {
+		return true
+	}
+	if _, err := os.Stat(filepath.Join(location, ".git")); err == nil {
+		return true
+	}
+	_, headErr := os.Stat(filepath.Join(location, "HEAD"))
+	_, objectsErr := os.Stat(filepath.Join(location, "objects"))
+	return headErr == nil && objectsErr == nil
+}
+
+// folderRemote is a tasks file in a shared folder. Its lock is only held
+// while reading and while writing it, not while conflicts are settled.
+type folderRemote struct {
+	path     string
+	revision string // Revision of the file as pulled
+}
+
+func openFolder(dir string) (Shared, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create sync folder: %v", err)
+	}
+	return &folderRemote{path: filepath.Join(dir, remoteFileName)}, nil
+}
+
+func (remote *folderRemote) Pull() (*taskdata.TaskStore, error) {
+	unlock, err := taskdata.LockFile(remote.path)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	store, err := taskdata.LoadTasksFrom(remote.path)
+	if err != nil {
+		return nil, err
+	}
+	remote.revision = store.Revision()
+	return store, nil
+}
+
+func (remote *folderRemote) Push(store *taskdata.TaskStore) error {
+	unlock, err := taskdata.LockFile(remote.path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	current, err := taskdata.LoadTasksFrom(remote.path)
+	if err != nil {
+		return err
+	}
+	if current.Revision() != remote.revision {
+		return ErrRemoteChanged
+	}
+	return store.SaveTasksTo(remote.path)
+}
+
+func (remote *folderRemote) Close() error {
+	return nil
+}
+
+// gitRemote is a tasks file in a git repository, reached through a local
+// clone that is reset to the remote branch on every sync
+type gitRemote struct {
+	dir    string
+	branch string
+	pulled string // Commit of the remote branch as pulled, empty if it has none
+}
+
+func openGit(url, workDir string) (Shared, error) {
+	remote := &gitRemote{dir: workDir}
+
+	if _, err := os.Stat(filepath.Join(workDir, ".git")); err != nil {
+		if err := os.MkdirAll(filepath.Dir(workDir), 0755); err != nil {
+			return nil, fmt.Errorf("failed to create sync directory: %v", err)
+		}
+		if _, err := git("", "clone", "--quiet", url, workDir); err != nil {
+			return nil, err
+		}
+	} else {
+		if _, err := remote.git("remote", "set-url", "origin", url); err != nil {
+			return nil, err
+		}
+		if _, err := remote.git("fetch", "--quiet", "origin"); err != nil {
+			return nil, err
+		}
+	}
+
+	// Follow the remote's default branch, or the clone's for an empty repository
+	if head, err := remote.git("symbolic-ref", "--short", "refs/remotes/origin/HEAD"); err == nil {
+		remote.branch = strings.TrimPrefix(head, "origin/")
+	} else if head, err := remote.git("symbolic-ref", "--short", "HEAD"); err == nil {
+		remote.branch = head
+	} else {
+		return nil, err
+	}
+
+	if _, err := remote.git("rev-parse", "--verify", "--quiet", "refs/remotes/origin/"+remote.branch); err == nil {
+		if _, err := remote.git("checkout", "--quiet", "--force", "-B", remote.branch, "origin/"+remote.branch); err != nil {
+			return nil, err
+		}
+	}
+	return remote, nil
+}
+
+func (remote *gitRemote) Pull() (*taskdata.TaskStore, error) {
+	remote.pulled = remote.head()
+	return taskdata.LoadTasksFrom(filepath.Join(remote.dir, remoteFileName))
+}
+
+func (remote *gitRemote) Push(store *taskdata.TaskStore) error {
+	// Check the remote branch is still where it was pulled from; the push
+	// below would be rejected otherwise, after the commit was made
+	if _, err := remote.git("fetch", "--quiet", "origin"); err != nil {
+		return err
+	}
+	if remote.head() != remote.pulled {
+		return ErrRemoteChanged
+	}
+
+	if err := store.SaveTasksTo(filepath.Join(remote.dir, remoteFileName)); err != nil {
+		return err
+	}
+	if _, err := remote.git("add", remoteFileName); err != nil {
+		return err
+	}
+	if _, err := remote.git("diff", "--cached", "--quiet"); err == nil {
+		return nil // Nothing changed
+	}
+
+	commit := []string{"commit", "--quiet", "-m", "Sync tasks from " + hostname()}
+	if _, err := remote.git("config", "user.email"); err != nil {
+		// Commit without an identity configured for git
+		commit = append([]string{"-c", "user.name=todo", "-c", "user.email=todo@" + hostname()}, commit...)
+	}
+	if _, err := remote.git(commit...); err != nil {
+		return err
+	}
+
+	if _, err := remote.git("push", "--quiet", "origin", "HEAD:refs/heads/"+remote.branch); err != nil {
+		return fmt.Errorf("push failed, the repository may have changed during the sync; run 'todo sync' again: %v", err)
+	}
+	return nil
+}
+
+func (remote *gitRemote) Close() error {
+	return nil
+}
+
+// head returns the commit of the remote branch as last fetched, empty if
+// the branch does not exist yet
+func (remote *gitRemote) head() string {
+	commit, _ := remote.git("rev-parse", "--verify", "--quiet", "refs/remotes/origin/"+remote.branch)
+	return commit
+}
+
+func (remote *gitRemote) git(args ...string) (string, error) {
+	return git(remote.dir, args...)
+}
+
+// git runs a git command and returns its output
+func git(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok && len(exitErr.Stderr) > 0 {
+			return "", fmt.Errorf("git: %s", strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return "", fmt.Errorf("git: %v", err)
+	}
+	return strings.TrimSuffix(string(out), "\n"), nil
+}
+
+func hostname() string {
+	name, err := os.Hostname()
+	if err != nil || name == "" {
+		return "localhost"
+	}
+	return name
+}
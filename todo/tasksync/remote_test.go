@@ -0,0 +1,266 @@
+package tasksync
+
+import (
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"todo/taskdata"
+)
+
+// machine is one copy of the tasks, synced the way 'todo sync' does: the
+// local file, the store both sides had after the last sync and a clone of
+// the shared repository
+type machine struct {
+	dir string
+}
+
+func newMachine(t *testing.T) *machine {
+	t.Helper()
+	return &machine{dir: t.TempDir()}
+}
+
+func (m *machine) path(name string) string {
+	return filepath.Join(m.dir, name)
+}
+
+func (m *machine) load(t *testing.T) *taskdata.TaskStore {
+	t.Helper()
+	store, err := taskdata.LoadTasksFrom(m.path("tasks.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return store
+}
+
+// update changes the local tasks as a command would
+func (m *machine) update(t *testing.T, change func(store *taskdata.TaskStore)) {
+	t.Helper()
+	store := m.load(t)
+	change(store)
+	if err := store.SaveTasksTo(m.path("tasks.json")); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// sync merges with the shared repository, lets choose resolve each
+// conflict, and pushes the result
+func (m *machine) sync(t *testing.T, url string, choose func(*Conflict)) *Result {
+	t.Helper()
+	shared, err := Open(url, m.path("clone"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer shared.Close()
+
+	local := m.load(t)
+	base, err := taskdata.LoadTasksFrom(m.path("base.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	remote, err := shared.Pull()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result := Merge(base, local, remote)
+	for _, conflict := range result.Conflicts {
+		if choose == nil {
+			t.Fatalf("unexpected conflict: %s", conflict)
+		}
+		choose(conflict)
+	}
+	result.Apply(local)
+
+	if err := shared.Push(local); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"tasks.json", "base.json"} {
+		if err := local.SaveTasksTo(m.path(name)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return result
+}
+
+// newSharedRepo creates an empty bare repository for machines to sync through
+func newSharedRepo(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	// Keep the user's git configuration out of the test
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+
+	url := filepath.Join(t.TempDir(), "shared.git")
+	if _, err := git("", "init", "--quiet", "--bare", url); err != nil {
+		t.Fatal(err)
+	}
+	return url
+}
+
+func addTask(t *testing.T, store *taskdata.TaskStore, description string) {
+	t.Helper()
+	if _, err := store.AddTask(description, "", "normal"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// onlyTask returns the single task of a machine's store
+func onlyTask(t *testing.T, m *machine) taskdata.Task {
+	t.Helper()
+	store := m.load(t)
+	if len(store.Tasks) != 1 {
+		t.Fatalf("%d tasks, want 1: %+v", len(store.Tasks), store.Tasks)
+	}
+	return store.Tasks[0]
+}
+
+// sharedTask sets up two machines sharing one task through a repository
+func sharedTask(t *testing.T) (url string, laptop, desktop *machine) {
+	t.Helper()
+	url = newSharedRepo(t)
+	laptop, desktop = newMachine(t), newMachine(t)
+
+	laptop.update(t, func(store *taskdata.TaskStore) { addTask(t, store, "Buy milk") })
+	laptop.sync(t, url, nil)
+	desktop.sync(t, url, nil)
+
+	if task := onlyTask(t, desktop); task.Description != "Buy milk" {
+		t.Fatalf("desktop has %q after the first sync", task.Description)
+	}
+	return url, laptop, desktop
+}
+
+func TestSyncMergesChangesToDifferentFields(t *testing.T) {
+	url, laptop, desktop := sharedTask(t)
+
+	laptop.update(t, func(store *taskdata.TaskStore) { store.Tasks[0].Priority = "high" })
+	desktop.update(t, func(store *taskdata.TaskStore) { store.Tasks[0].Notes = "Semi-skimmed" })
+
+	laptop.sync(t, url, nil)
+	result := desktop.sync(t, url, nil)
+	if result.Incoming != 1 || result.Outgoing != 1 {
+		t.Errorf("incoming %d, outgoing %d; want 1 and 1", result.Incoming, result.Outgoing)
+	}
+	laptop.sync(t, url, nil)
+
+	for name, m := range map[string]*machine{"laptop": laptop, "desktop": desktop} {
+		task := onlyTask(t, m)
+		if task.Priority != "high" || task.Notes != "Semi-skimmed" {
+			t.Errorf("%s: priority %q, notes %q; want both changes", name, task.Priority, task.Notes)
+		}
+	}
+}
+
+func TestSyncConflictingEdits(t *testing.T) {
+	url, laptop, desktop := sharedTask(t)
+
+	laptop.update(t, func(store *taskdata.TaskStore) { store.Tasks[0].Description = "Buy oat milk" })
+	desktop.update(t, func(store *taskdata.TaskStore) { store.Tasks[0].Description = "Buy milk and eggs" })
+
+	laptop.sync(t, url, nil)
+	var conflicts []*Conflict
+	desktop.sync(t, url, func(conflict *Conflict) {
+		conflicts = append(conflicts, conflict)
+		conflict.Choice = Remote
+	})
+
+	if len(conflicts) != 1 {
+		t.Fatalf("%d conflicts, want 1", len(conflicts))
+	}
+	conflict := conflicts[0]
+	if conflict.Field != "description" || conflict.Base != "Buy milk" ||
+		conflict.Local != "Buy milk and eggs" || conflict.Remote != "Buy oat milk" {
+		t.Errorf("conflict = %+v", *conflict)
+	}
+
+	laptop.sync(t, url, nil)
+	for name, m := range map[string]*machine{"laptop": laptop, "desktop": desktop} {
+		if task := onlyTask(t, m); task.Description != "Buy oat milk" {
+			t.Errorf("%s: description %q, want the remote choice", name, task.Description)
+		}
+	}
+}
+
+func TestSyncDeleteEditConflict(t *testing.T) {
+	url, laptop, desktop := sharedTask(t)
+
+	laptop.update(t, func(store *taskdata.TaskStore) { store.Tasks = []taskdata.Task{} })
+	desktop.update(t, func(store *taskdata.TaskStore) { store.Tasks[0].Notes = "From the corner shop" })
+
+	laptop.sync(t, url, nil)
+	var conflicts []*Conflict
+	desktop.sync(t, url, func(conflict *Conflict) {
+		conflicts = append(conflicts, conflict)
+		conflict.Choice = Local
+	})
+
+	if len(conflicts) != 1 {
+		t.Fatalf("%d conflicts, want 1", len(conflicts))
+	}
+	if conflicts[0].Field != "" || conflicts[0].Remote != Deleted {
+		t.Errorf("conflict = %+v, want a remote deletion", *conflicts[0])
+	}
+
+	// Keeping the edited task brings it back on the machine that deleted it
+	laptop.sync(t, url, nil)
+	for name, m := range map[string]*machine{"laptop": laptop, "desktop": desktop} {
+		if task := onlyTask(t, m); task.Notes != "From the corner shop" {
+			t.Errorf("%s: notes %q, want the kept edit", name, task.Notes)
+		}
+	}
+}
+
+// pushAfterOtherPush pulls from location, lets another machine sync in
+// between, and returns the error of pushing afterwards
+func pushAfterOtherPush(t *testing.T, location string) error {
+	t.Helper()
+	laptop, desktop := newMachine(t), newMachine(t)
+
+	shared, err := Open(location, laptop.path("clone"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer shared.Close()
+	remote, err := shared.Pull()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Between the pull and the push, e.g. while conflicts are settled
+	desktop.update(t, func(store *taskdata.TaskStore) { addTask(t, store, "Buy bread") })
+	desktop.sync(t, location, nil)
+
+	addTask(t, remote, "Buy milk")
+	return shared.Push(remote)
+}
+
+func TestFolderPushFailsWhenChangedSincePull(t *testing.T) {
+	if err := pushAfterOtherPush(t, t.TempDir()); err != ErrRemoteChanged {
+		t.Errorf("push = %v, want ErrRemoteChanged", err)
+	}
+}
+
+func TestGitPushFailsWhenChangedSincePull(t *testing.T) {
+	if err := pushAfterOtherPush(t, newSharedRepo(t)); err != ErrRemoteChanged {
+		t.Errorf("push = %v, want ErrRemoteChanged", err)
+	}
+}
+
+func TestFolderSync(t *testing.T) {
+	folder := t.TempDir()
+	laptop, desktop := newMachine(t), newMachine(t)
+
+	laptop.update(t, func(store *taskdata.TaskStore) { addTask(t, store, "Buy milk") })
+	laptop.sync(t, folder, nil)
+	desktop.sync(t, folder, nil)
+	desktop.update(t, func(store *taskdata.TaskStore) { store.Tasks[0].Completed = true })
+	desktop.sync(t, folder, nil)
+	laptop.sync(t, folder, nil)
+
+	if task := onlyTask(t, laptop); !task.Completed {
+		t.Error("the completion did not reach the laptop")
+	}
+}
@@ -0,0 +1,267 @@
+package transfer
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"todo/taskdata"
+)
+
+// csvFormat reads and writes comma-separated files with a header row.
+//
+// Columns are named after the task's JSON fields (description, due_date,
+// priority...). On import, columns with other names can be mapped onto
+// fields with Options.Mapping; unknown columns are ignored.
+type csvFormat struct{}
+
+func init() {
+	register(csvFormat{})
+}
+
+func (csvFormat) Name() string { return "csv" }
+
+func (csvFormat) Extensions() []string { return []string{".csv"} }
+
+// csvFields are the task fields in export column order
+var csvFields = []string{
+	"id", "uid", "description", "due_date", "due_time", "priority", "completed", "notes", "wait_until",
+	"created_at", "completed_at", "tags", "project", "contexts", "depends_on",
+}
+
+// ParseMapping parses "Title=description,Deadline=due_date" into a
+// column -> field map, checking that every field exists
+func ParseMapping(spec string) (map[string]string, error) {
+	mapping := map[string]string{}
+	for _, pair := range strings.Split(spec, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		column, field, found := strings.Cut(pair, "=")
+		column, field = strings.TrimSpace(column), strings.ToLower(strings.TrimSpace(field))
+		if !found || column == "" || field == "" {
+			return nil, fmt.Errorf("invalid mapping '%s' (use Column=field)", pair)
+		}
+		if !isCSVField(field) {
+			return nil, fmt.Errorf("unknown field '%s' in mapping. Fields are: %s", field, strings.Join(csvFields, ", "))
+		}
+		mapping[strings.ToLower(column)] = field
+	}
+	return mapping, nil
+}
+
+func isCSVField(name string) bool {
+	for _, field := range csvFields {
+		if field == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (csvFormat) Export(w io.Writer, tasks []taskdata.Task, opts Options) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(csvFields); err != nil {
+		return err
+	}
+
+	for _, task := range tasks {
+		depends := make([]string, len(task.DependsOn))
+		for i, id := range task.DependsOn {
+			depends[i] = strconv.Itoa(id)
+		}
+
+		record := []string{
+			strconv.Itoa(task.ID),
+			task.UID,
+			task.Description,
+			task.DueDate,
+			task.DueTime,
+			task.Priority,
+			strconv.FormatBool(task.Completed),
+			task.Notes,
+			task.WaitUntil,
+			task.CreatedAt,
+			task.CompletedAt,
+			strings.Join(task.Tags, ","),
+			task.Project,
+			strings.Join(task.Contexts, ","),
+			strings.Join(depends, ","),
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+func (csvFormat) Import(r io.Reader, opts Options) (*ImportResult, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return &ImportResult{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV header: %v", err)
+	}
+
+	// Work out which field each column feeds
+	fields := make([]string, len(header))
+	hasDescription := false
+	for i, column := range header {
+		name := strings.ToLower(strings.TrimSpace(strings.TrimPrefix(column, "\ufeff")))
+		if field, mapped := opts.Mapping[name]; mapped {
+			fields[i] = field
+		} else if isCSVField(name) {
+			fields[i] = name
+		}
+		if fields[i] == "description" {
+			hasDescription = true
+		}
+	}
+	if !hasDescription {
+		return nil, fmt.Errorf("no column maps to description; use --map \"<Column>=description\"")
+	}
+
+	result := &ImportResult{}
+	row := 1
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		row++
+		if err != nil {
+			result.Errors = append(result.Errors, ImportError{Line: row, Err: err})
+			continue
+		}
+
+		task, err := parseCSVRecord(fields, record)
+		if err != nil {
+			result.Errors = append(result.Errors, ImportError{Line: row, Text: strings.Join(record, ","), Err: err})
+			continue
+		}
+		result.Tasks = append(result.Tasks, task)
+	}
+
+	return result, nil
+}
+
+// parseCSVRecord builds a task from one row, collecting every invalid value
+func parseCSVRecord(fields, record []string) (taskdata.Task, error) {
+	var task taskdata.Task
+	var problems []string
+
+	for i, value := range record {
+		if i >= len(fields) || fields[i] == "" {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+
+		switch fields[i] {
+		case "description":
+			task.Description = value
+		case "uid":
+			task.UID = value
+		case "due_date":
+			if err := taskdata.ValidateDate(value); err != nil {
+				problems = append(problems, err.Error())
+			}
+			task.DueDate = value
+		case "due_time":
+			if err := taskdata.ValidateTime(value); err != nil {
+				problems = append(problems, err.Error())
+			}
+			task.DueTime = value
+		case "priority":
+			priority, err := taskdata.NormalizePriority(value)
+			if err != nil {
+				problems = append(problems, err.Error())
+			}
+			task.Priority = priority
+		case "completed":
+			completed, err := parseCompleted(value)
+			if err != nil {
+				problems = append(problems, err.Error())
+			}
+			task.Completed = completed
+		case "notes":
+			task.Notes = value
+		case "wait_until":
+			if err := taskdata.ValidateDate(value); err != nil {
+				problems = append(problems, err.Error())
+			}
+			task.WaitUntil = value
+		case "created_at", "completed_at":
+			timestamp, err := parseTimestamp(value)
+			if err != nil {
+				problems = append(problems, fmt.Sprintf("invalid %s '%s'", fields[i], value))
+			}
+			if fields[i] == "created_at" {
+				task.CreatedAt = timestamp
+			} else {
+				task.CompletedAt = timestamp
+			}
+		case "tags":
+			for _, tag := range splitCSVList(value) {
+				task.Tags = append(task.Tags, strings.ToLower(strings.TrimPrefix(tag, "+")))
+			}
+		case "project":
+			task.Project = value
+		case "contexts":
+			for _, context := range splitCSVList(value) {
+				task.Contexts = append(task.Contexts, strings.TrimPrefix(context, "@"))
+			}
+		}
+		// id and depends_on refer to the source's numbering and are not imported
+	}
+
+	if task.Description == "" {
+		problems = append(problems, "missing description")
+	}
+	if task.CompletedAt != "" {
+		task.Completed = true
+	}
+
+	if len(problems) > 0 {
+		return task, fmt.Errorf("%s", strings.Join(problems, "; "))
+	}
+	return task, nil
+}
+
+// parseCompleted accepts the usual spreadsheet spellings of a checkbox
+func parseCompleted(value string) (bool, error) {
+	switch strings.ToLower(value) {
+	case "true", "yes", "y", "x", "1", "done", "completed":
+		return true, nil
+	case "false", "no", "n", "0", "pending", "todo":
+		return false, nil
+	}
+	return false, fmt.Errorf("invalid completed value '%s'", value)
+}
+
+// parseTimestamp accepts RFC 3339 timestamps or plain YYYY-MM-DD dates
+func parseTimestamp(value string) (string, error) {
+	if _, err := time.Parse(taskdata.TimestampFormat, value); err == nil {
+		return value, nil
+	}
+	if err := taskdata.ValidateDate(value); err != nil {
+		return "", err
+	}
+	return timestampOf(value), nil
+}
+
+// splitCSVList splits a list cell on commas, semicolons or spaces
+func splitCSVList(value string) []string {
+	return strings.FieldsFunc(value, func(r rune) bool { return r == ',' || r == ';' || r == ' ' })
+}
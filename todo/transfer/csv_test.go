@@ -0,0 +1,172 @@
+package transfer
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"todo/taskdata"
+)
+
+func TestParseMapping(t *testing.T) {
+	tests := []struct {
+		spec    string
+		want    map[string]string
+		wantErr bool
+	}{
+		{spec: "", want: map[string]string{}},
+		{spec: "Title=description", want: map[string]string{"title": "description"}},
+		{spec: " Title = Description , Deadline=due_date,", want: map[string]string{"title": "description", "deadline": "due_date"}},
+		{spec: "Title", wantErr: true},
+		{spec: "=description", wantErr: true},
+		{spec: "Title=", wantErr: true},
+		{spec: "Title=summary", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			got, err := ParseMapping(tt.spec)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("accepted as %v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCSVImport(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		mapping map[string]string
+		want    []taskdata.Task
+	}{
+		{
+			name: "task field columns",
+			data: "description,due_date,priority,tags,contexts,completed\n" +
+				"Call mom,2025-05-01,H,\"Family, +Phone\",@home;@car,no\n",
+			want: []taskdata.Task{{Description: "Call mom", DueDate: "2025-05-01", Priority: "high",
+				Tags: []string{"family", "phone"}, Contexts: []string{"home", "car"}}},
+		},
+		{
+			name:    "mapped columns, unknown columns ignored",
+			data:    "\ufeffTitle,Deadline,Owner,Done\nPay rent,2025-06-01,Sam,x\n",
+			mapping: map[string]string{"title": "description", "deadline": "due_date", "done": "completed"},
+			want:    []taskdata.Task{{Description: "Pay rent", DueDate: "2025-06-01", Completed: true}},
+		},
+		{
+			name: "dates become timestamps and completed_at completes",
+			data: "description,created_at,completed_at\nWater plants,2025-01-02,2025-01-03T10:00:00Z\n",
+			want: []taskdata.Task{{Description: "Water plants", Completed: true,
+				CreatedAt: timestampOf("2025-01-02"), CompletedAt: "2025-01-03T10:00:00Z"}},
+		},
+		{
+			name: "id and depends_on are not imported",
+			data: "id,description,depends_on\n7,Write report,\"3,4\"\n",
+			want: []taskdata.Task{{Description: "Write report"}},
+		},
+		{
+			name: "short rows and blank cells",
+			data: "description,notes,project\nRead,,\nWrite\n",
+			want: []taskdata.Task{{Description: "Read"}, {Description: "Write"}},
+		},
+		{
+			name: "header only",
+			data: "description\n",
+		},
+		{
+			name: "empty file",
+			data: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := (csvFormat{}).Import(strings.NewReader(tt.data), Options{Mapping: tt.mapping})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(result.Errors) > 0 {
+				t.Fatalf("errors: %v", result.Errors)
+			}
+			if !reflect.DeepEqual(result.Tasks, tt.want) {
+				t.Errorf("got  %+v\nwant %+v", result.Tasks, tt.want)
+			}
+		})
+	}
+}
+
+func TestCSVImportErrors(t *testing.T) {
+	data := "description,due_date,priority,completed,created_at\n" +
+		"Fine,2025-01-01,low,yes,\n" +
+		",2025-01-01,,,\n" +
+		"Bad date,2025-13-01,,,\n" +
+		"Bad everything,tomorrow,extreme,maybe,last week\n"
+
+	result, err := (csvFormat{}).Import(strings.NewReader(data), Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Tasks) != 1 || result.Tasks[0].Description != "Fine" {
+		t.Errorf("tasks = %+v", result.Tasks)
+	}
+
+	lines := []int{3, 4, 5}
+	if len(result.Errors) != len(lines) {
+		t.Fatalf("errors = %v, want %d", result.Errors, len(lines))
+	}
+	for i, line := range lines {
+		if result.Errors[i].Line != line {
+			t.Errorf("error %d on line %d, want %d", i, result.Errors[i].Line, line)
+		}
+	}
+	// Every problem in a row is reported at once
+	if problems := strings.Count(result.Errors[2].Error(), ";"); problems != 3 {
+		t.Errorf("%q reports %d problems, want 4", result.Errors[2].Error(), problems+1)
+	}
+}
+
+func TestCSVImportWithoutDescription(t *testing.T) {
+	if _, err := (csvFormat{}).Import(strings.NewReader("Title,Deadline\nPay rent,2025-06-01\n"), Options{}); err == nil {
+		t.Error("a file without a description column was accepted")
+	}
+}
+
+func TestCSVRoundTrip(t *testing.T) {
+	tasks := []taskdata.Task{
+		{ID: 1, UID: "a", Description: "Write report, final", DueDate: "2025-05-01", DueTime: "14:30",
+			Priority: "high", Notes: "line one\nline \"two\"", WaitUntil: "2025-04-20",
+			CreatedAt: "2025-04-01T09:00:00Z", Tags: []string{"work", "q2"}, Project: "reports",
+			Contexts: []string{"office"}, DependsOn: []int{2}},
+		{ID: 2, UID: "b", Description: "Collect numbers", Priority: "low", Completed: true,
+			CreatedAt: "2025-04-01T09:00:00Z", CompletedAt: "2025-04-02T09:00:00Z"},
+	}
+
+	var out bytes.Buffer
+	if err := (csvFormat{}).Export(&out, tasks, Options{}); err != nil {
+		t.Fatal(err)
+	}
+	result, err := (csvFormat{}).Import(&out, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Errors) > 0 {
+		t.Fatalf("errors: %v", result.Errors)
+	}
+
+	// IDs and dependencies belong to the exporting store
+	for i := range tasks {
+		tasks[i].ID, tasks[i].DependsOn = 0, nil
+	}
+	if !reflect.DeepEqual(result.Tasks, tasks) {
+		t.Errorf("got  %+v\nwant %+v", result.Tasks, tasks)
+	}
+}
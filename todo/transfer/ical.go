@@ -0,0 +1,448 @@
+package transfer
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"todo/taskdata"
+)
+
+// iCalendar implements RFC 5545 calendars.
+//
+// Tasks with a due time are exported as VEVENTs so they show up at that
+// time in calendar apps; all other tasks become VTODOs with an all-day DUE.
+// Every entry carries the task's UID so re-exports update instead of
+// duplicating. Import reads VTODOs from any tool, plus the VEVENTs this
+// format wrote itself (marked with X-TODO-TASK).
+type iCalendar struct{}
+
+func init() {
+	register(iCalendar{})
+}
+
+func (iCalendar) Name() string { return "ics" }
+
+func (iCalendar) Extensions() []string { return []string{".ics", ".ical", ".ifb"} }
+
+const (
+	icalDate     = "20060102"
+	icalDateTime = "20060102T150405"
+	icalUTC      = "20060102T150405Z"
+
+	// icalLineLimit is the maximum line length in octets before folding
+	icalLineLimit = 75
+)
+
+func (iCalendar) Export(w io.Writer, tasks []taskdata.Task, opts Options) error {
+	out := &icalWriter{w: bufio.NewWriter(w)}
+	stamp := time.Now().UTC().Format(icalUTC)
+
+	out.line("BEGIN:VCALENDAR")
+	out.line("VERSION:2.0")
+	out.line("PRODID:-//Smart Todo CLI//todo//EN")
+	out.line("CALSCALE:GREGORIAN")
+
+	for _, task := range tasks {
+		component := "VTODO"
+		if task.DueTime != "" && !opts.TodosOnly {
+			component = "VEVENT"
+		}
+
+		out.line("BEGIN:" + component)
+		out.line("UID:" + icalEscape(task.UID))
+		out.line("DTSTAMP:" + stamp)
+		if created := icalTimestamp(task.CreatedAt); created != "" {
+			out.line("CREATED:" + created)
+		}
+		out.line("SUMMARY:" + icalEscape(task.Description))
+		if task.Notes != "" {
+			out.line("DESCRIPTION:" + icalEscape(task.Notes))
+		}
+		if priority := icalPriority(task.Priority); priority > 0 {
+			out.line("PRIORITY:" + strconv.Itoa(priority))
+		}
+		if len(task.Tags) > 0 {
+			out.line("CATEGORIES:" + icalEscapeList(task.Tags))
+		}
+		if task.Project != "" {
+			out.line("X-TODO-PROJECT:" + icalEscape(task.Project))
+		}
+		if len(task.Contexts) > 0 {
+			out.line("X-TODO-CONTEXTS:" + icalEscapeList(task.Contexts))
+		}
+
+		if component == "VEVENT" {
+			// Floating local time: the event stays at the same wall-clock time
+			start, _ := time.Parse("2006-01-02 15:04", task.DueDate+" "+task.DueTime)
+			out.line("DTSTART:" + start.Format(icalDateTime))
+			out.line("X-TODO-TASK:TRUE")
+			if task.Completed {
+				out.line("X-TODO-COMPLETED:" + icalCompleted(task))
+			}
+		} else {
+			// DTSTART and DUE must have the same value type
+			if task.DueTime != "" {
+				due, _ := time.Parse("2006-01-02 15:04", task.DueDate+" "+task.DueTime)
+				if task.WaitUntil != "" {
+					out.line("DTSTART:" + strings.ReplaceAll(task.WaitUntil, "-", "") + "T000000")
+				}
+				out.line("DUE:" + due.Format(icalDateTime))
+			} else {
+				if task.WaitUntil != "" {
+					out.line("DTSTART;VALUE=DATE:" + strings.ReplaceAll(task.WaitUntil, "-", ""))
+				}
+				if task.DueDate != "" {
+					out.line("DUE;VALUE=DATE:" + strings.ReplaceAll(task.DueDate, "-", ""))
+				}
+			}
+			if task.Completed {
+				out.line("STATUS:COMPLETED")
+				out.line("COMPLETED:" + icalCompleted(task))
+				out.line("PERCENT-COMPLETE:100")
+			} else {
+				out.line("STATUS:NEEDS-ACTION")
+			}
+		}
+		out.line("END:" + component)
+	}
+
+	out.line("END:VCALENDAR")
+	if out.err != nil {
+		return out.err
+	}
+	return out.w.Flush()
+}
+
+// icalWriter writes folded CRLF-terminated content lines
+type icalWriter struct {
+	w   *bufio.Writer
+	err error
+}
+
+func (out *icalWriter) line(text string) {
+	if out.err != nil {
+		return
+	}
+	// Fold long lines without splitting UTF-8 sequences
+	limit := icalLineLimit
+	for len(text) > limit {
+		cut := limit
+		for cut > 0 && text[cut]&0xC0 == 0x80 {
+			cut--
+		}
+		_, out.err = out.w.WriteString(text[:cut] + "\r\n ")
+		text = text[cut:]
+		limit = icalLineLimit - 1 // Continuation lines start with a space
+	}
+	_, out.err = out.w.WriteString(text + "\r\n")
+}
+
+func (iCalendar) Import(r io.Reader, opts Options) (*ImportResult, error) {
+	lines, err := icalUnfold(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read calendar: %v", err)
+	}
+
+	result := &ImportResult{}
+	var current *icalComponent
+	depth := 0 // Nesting inside the current component (e.g. VALARM)
+
+	for number, text := range lines {
+		name, params, value := icalParseLine(text)
+
+		switch {
+		case current == nil && name == "BEGIN" && (value == "VTODO" || value == "VEVENT"):
+			current = &icalComponent{kind: value, line: number + 1, props: map[string]icalProperty{}}
+		case current == nil:
+			continue
+		case name == "BEGIN":
+			depth++
+		case name == "END" && depth > 0:
+			depth--
+		case name == "END":
+			task, keep, err := current.task()
+			if err != nil {
+				result.Errors = append(result.Errors, ImportError{Line: current.line, Text: current.summary(), Err: err})
+			} else if keep {
+				result.Tasks = append(result.Tasks, task)
+			}
+			current = nil
+		case depth == 0:
+			current.props[name] = icalProperty{params: params, value: value}
+		}
+	}
+
+	return result, nil
+}
+
+// icalProperty is one content line of a component
+type icalProperty struct {
+	params map[string]string
+	value  string
+}
+
+// icalComponent collects the properties of one VTODO or VEVENT
+type icalComponent struct {
+	kind  string
+	line  int
+	props map[string]icalProperty
+}
+
+func (c *icalComponent) summary() string {
+	return icalUnescape(c.props["SUMMARY"].value)
+}
+
+// task converts the component; keep is false for events that are not tasks
+func (c *icalComponent) task() (taskdata.Task, bool, error) {
+	var task taskdata.Task
+
+	if c.kind == "VEVENT" && !strings.EqualFold(c.props["X-TODO-TASK"].value, "TRUE") {
+		return task, false, nil
+	}
+
+	task.UID = icalUnescape(c.props["UID"].value)
+	task.Description = strings.TrimSpace(c.summary())
+	if task.Description == "" {
+		return task, false, fmt.Errorf("%s has no SUMMARY", c.kind)
+	}
+	task.Notes = icalUnescape(c.props["DESCRIPTION"].value)
+	task.Project = icalUnescape(c.props["X-TODO-PROJECT"].value)
+	task.Tags = icalSplitList(c.props["CATEGORIES"].value)
+	for i, tag := range task.Tags {
+		task.Tags[i] = strings.ToLower(tag)
+	}
+	task.Contexts = icalSplitList(c.props["X-TODO-CONTEXTS"].value)
+
+	if p, exists := c.props["PRIORITY"]; exists {
+		value, err := strconv.Atoi(p.value)
+		if err != nil || value < 0 || value > 9 {
+			return task, false, fmt.Errorf("invalid PRIORITY '%s'", p.value)
+		}
+		task.Priority = priorityFromICal(value)
+	}
+
+	if created, exists := c.props["CREATED"]; exists {
+		t, _, err := icalParseTime(created)
+		if err != nil {
+			return task, false, err
+		}
+		task.CreatedAt = t.Format(taskdata.TimestampFormat)
+	}
+
+	// Due date (VTODO) or start time (our VEVENTs)
+	dueProp, hasDue := c.props["DUE"]
+	if c.kind == "VEVENT" {
+		dueProp, hasDue = c.props["DTSTART"]
+	}
+	if hasDue {
+		t, allDay, err := icalParseTime(dueProp)
+		if err != nil {
+			return task, false, err
+		}
+		task.DueDate = t.Format("2006-01-02")
+		if !allDay {
+			task.DueTime = t.Format("15:04")
+		}
+	}
+
+	// A VTODO's start date is when work can begin
+	if start, exists := c.props["DTSTART"]; exists && c.kind == "VTODO" {
+		t, _, err := icalParseTime(start)
+		if err != nil {
+			return task, false, err
+		}
+		task.WaitUntil = t.Format("2006-01-02")
+	}
+
+	completedProp := c.props["COMPLETED"]
+	if c.kind == "VEVENT" {
+		completedProp = c.props["X-TODO-COMPLETED"]
+	}
+	status := strings.ToUpper(c.props["STATUS"].value)
+	if completedProp.value != "" || status == "COMPLETED" || status == "CANCELLED" {
+		task.Completed = true
+		if completedProp.value != "" {
+			t, _, err := icalParseTime(completedProp)
+			if err != nil {
+				return task, false, err
+			}
+			task.CompletedAt = t.Format(taskdata.TimestampFormat)
+		}
+	}
+
+	return task, true, nil
+}
+
+// icalUnfold reads content lines, joining folded continuation lines
+func icalUnfold(r io.Reader) ([]string, error) {
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	for scanner.Scan() {
+		text := strings.TrimRight(scanner.Text(), "\r")
+		if (strings.HasPrefix(text, " ") || strings.HasPrefix(text, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += text[1:]
+			continue
+		}
+		if text != "" {
+			lines = append(lines, text)
+		}
+	}
+	return lines, scanner.Err()
+}
+
+// icalParseLine splits "NAME;PARAM=x:value" into its parts
+func icalParseLine(text string) (string, map[string]string, string) {
+	// The value starts at the first colon outside a quoted parameter
+	colon := -1
+	quoted := false
+	for i, r := range text {
+		if r == '"' {
+			quoted = !quoted
+		} else if r == ':' && !quoted {
+			colon = i
+			break
+		}
+	}
+	if colon < 0 {
+		return strings.ToUpper(text), nil, ""
+	}
+
+	parts := strings.Split(text[:colon], ";")
+	params := map[string]string{}
+	for _, param := range parts[1:] {
+		key, value, _ := strings.Cut(param, "=")
+		params[strings.ToUpper(key)] = strings.Trim(value, `"`)
+	}
+	return strings.ToUpper(parts[0]), params, text[colon+1:]
+}
+
+// icalParseTime parses DATE and DATE-TIME values; allDay is true for DATE values
+func icalParseTime(prop icalProperty) (time.Time, bool, error) {
+	value := prop.value
+	if prop.params["VALUE"] == "DATE" || len(value) == len(icalDate) {
+		t, err := time.ParseInLocation(icalDate, value, time.Local)
+		if err != nil {
+			return t, true, fmt.Errorf("invalid date '%s'", value)
+		}
+		return t, true, nil
+	}
+
+	if strings.HasSuffix(value, "Z") {
+		t, err := time.Parse(icalUTC, value)
+		if err != nil {
+			return t, false, fmt.Errorf("invalid date-time '%s'", value)
+		}
+		return t.Local(), false, nil
+	}
+
+	location := time.Local
+	if tzid := prop.params["TZID"]; tzid != "" {
+		if loc, err := time.LoadLocation(tzid); err == nil {
+			location = loc
+		}
+	}
+	t, err := time.ParseInLocation(icalDateTime, value, location)
+	if err != nil {
+		return t, false, fmt.Errorf("invalid date-time '%s'", value)
+	}
+	return t.In(time.Local), false, nil
+}
+
+// icalTimestamp converts an RFC 3339 timestamp to a UTC DATE-TIME
+func icalTimestamp(timestamp string) string {
+	t, err := time.Parse(taskdata.TimestampFormat, timestamp)
+	if err != nil {
+		return ""
+	}
+	return t.UTC().Format(icalUTC)
+}
+
+// icalCompleted returns the completion time, falling back to now for old tasks
+func icalCompleted(task taskdata.Task) string {
+	if completed := icalTimestamp(task.CompletedAt); completed != "" {
+		return completed
+	}
+	return time.Now().UTC().Format(icalUTC)
+}
+
+// icalPriority spreads the scale over iCalendar's 1 (highest) to 9 (lowest)
+func icalPriority(priority string) int {
+	levels := taskdata.Priorities()
+	for i, level := range levels {
+		if strings.EqualFold(level, priority) {
+			return 1 + int(math.Round(float64(i)*8/float64(len(levels)-1)))
+		}
+	}
+	return 0
+}
+
+// priorityFromICal maps 1-9 back onto the scale; 0 (undefined) returns ""
+func priorityFromICal(value int) string {
+	if value == 0 {
+		return ""
+	}
+	levels := taskdata.Priorities()
+	i := int(math.Round(float64(value-1) * float64(len(levels)-1) / 8))
+	return levels[i]
+}
+
+var icalEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`)
+
+func icalEscape(text string) string {
+	return icalEscaper.Replace(text)
+}
+
+func icalEscapeList(items []string) string {
+	escaped := make([]string, len(items))
+	for i, item := range items {
+		escaped[i] = icalEscape(item)
+	}
+	return strings.Join(escaped, ",")
+}
+
+func icalUnescape(text string) string {
+	var b strings.Builder
+	escaped := false
+	for _, r := range text {
+		switch {
+		case escaped && (r == 'n' || r == 'N'):
+			b.WriteRune('\n')
+			escaped = false
+		case escaped:
+			b.WriteRune(r)
+			escaped = false
+		case r == '\\':
+			escaped = true
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// icalSplitList splits a comma-separated value, honouring escaped commas
+func icalSplitList(value string) []string {
+	var items []string
+	start := 0
+	for i := 0; i < len(value); i++ {
+		switch value[i] {
+		case '\\':
+			i++
+		case ',':
+			if item := strings.TrimSpace(icalUnescape(value[start:i])); item != "" {
+				items = append(items, item)
+			}
+			start = i + 1
+		}
+	}
+	if item := strings.TrimSpace(icalUnescape(value[start:])); item != "" {
+		items = append(items, item)
+	}
+	return items
+}
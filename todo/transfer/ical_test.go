@@ -0,0 +1,309 @@
+package transfer
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"todo/taskdata"
+)
+
+// icalExport exports tasks and returns the unfolded content lines
+func icalExport(t *testing.T, tasks []taskdata.Task, opts Options) []string {
+	t.Helper()
+	var out bytes.Buffer
+	if err := (iCalendar{}).Export(&out, tasks, opts); err != nil {
+		t.Fatal(err)
+	}
+	lines, err := icalUnfold(&out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return lines
+}
+
+// icalImport wraps components in a calendar and imports it
+func icalImport(t *testing.T, components string) *ImportResult {
+	t.Helper()
+	data := "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n" + strings.ReplaceAll(components, "\n", "\r\n") + "END:VCALENDAR\r\n"
+	result, err := (iCalendar{}).Import(strings.NewReader(data), Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return result
+}
+
+func TestICalExportComponents(t *testing.T) {
+	tests := []struct {
+		name  string
+		task  taskdata.Task
+		opts  Options
+		want  []string
+		avoid []string
+	}{
+		{
+			name:  "all-day task is a VTODO",
+			task:  taskdata.Task{UID: "a", Description: "Pay rent", DueDate: "2025-06-01", WaitUntil: "2025-05-25"},
+			want:  []string{"BEGIN:VTODO", "DUE;VALUE=DATE:20250601", "DTSTART;VALUE=DATE:20250525", "STATUS:NEEDS-ACTION"},
+			avoid: []string{"BEGIN:VEVENT"},
+		},
+		{
+			name:  "timed task is a VEVENT",
+			task:  taskdata.Task{UID: "b", Description: "Dentist", DueDate: "2025-06-01", DueTime: "14:30"},
+			want:  []string{"BEGIN:VEVENT", "DTSTART:20250601T143000", "X-TODO-TASK:TRUE"},
+			avoid: []string{"BEGIN:VTODO", "DUE"},
+		},
+		{
+			name:  "timed task as a VTODO",
+			task:  taskdata.Task{UID: "c", Description: "Dentist", DueDate: "2025-06-01", DueTime: "14:30", WaitUntil: "2025-05-30"},
+			opts:  Options{TodosOnly: true},
+			want:  []string{"BEGIN:VTODO", "DUE:20250601T143000", "DTSTART:20250530T000000"},
+			avoid: []string{"BEGIN:VEVENT"},
+		},
+		{
+			name: "completed task",
+			task: taskdata.Task{UID: "d", Description: "Call", Completed: true, CompletedAt: "2025-06-02T10:00:00Z"},
+			want: []string{"STATUS:COMPLETED", "COMPLETED:20250602T100000Z", "PERCENT-COMPLETE:100"},
+		},
+		{
+			name: "text is escaped",
+			task: taskdata.Task{UID: "e", Description: "Buy milk, eggs; bread", Notes: "two\nlines \\ here",
+				Tags: []string{"a,b", "c"}, Project: "home"},
+			want: []string{`SUMMARY:Buy milk\, eggs\; bread`, `DESCRIPTION:two\nlines \\ here`,
+				`CATEGORIES:a\,b,c`, "X-TODO-PROJECT:home"},
+		},
+		{
+			name: "priority",
+			task: taskdata.Task{UID: "f", Description: "Urgent", Priority: "high"},
+			want: []string{"PRIORITY:1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lines := icalExport(t, []taskdata.Task{tt.task}, tt.opts)
+			text := strings.Join(lines, "\n") + "\n"
+			for _, want := range tt.want {
+				if !strings.Contains(text, want+"\n") {
+					t.Errorf("missing %s in\n%s", want, text)
+				}
+			}
+			for _, avoid := range tt.avoid {
+				if strings.Contains(text, avoid) {
+					t.Errorf("unexpected %s in\n%s", avoid, text)
+				}
+			}
+		})
+	}
+}
+
+func TestICalExportFoldsLongLines(t *testing.T) {
+	task := taskdata.Task{UID: "a", Description: strings.Repeat("Überprüfung der Steuererklärung ", 10)}
+	var out bytes.Buffer
+	if err := (iCalendar{}).Export(&out, []taskdata.Task{task}, Options{}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, line := range strings.Split(strings.TrimSuffix(out.String(), "\r\n"), "\r\n") {
+		if len(line) > icalLineLimit {
+			t.Errorf("line of %d octets: %q", len(line), line)
+		}
+		if !utf8.ValidString(line) {
+			t.Errorf("line splits a character: %q", line)
+		}
+	}
+
+	result, err := (iCalendar{}).Import(&out, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Tasks) != 1 || result.Tasks[0].Description != strings.TrimSpace(task.Description) {
+		t.Errorf("tasks = %+v", result.Tasks)
+	}
+}
+
+func TestICalImport(t *testing.T) {
+	tests := []struct {
+		name      string
+		component string
+		want      []taskdata.Task
+	}{
+		{
+			name: "VTODO from another client",
+			component: `BEGIN:VTODO
+UID:abc@example.com
+SUMMARY:Renew passport
+DESCRIPTION:Bring the old one\, and photos
+CATEGORIES:Errands,Travel
+PRIORITY:9
+DUE;VALUE=DATE:20250701
+DTSTART;VALUE=DATE:20250620
+BEGIN:VALARM
+ACTION:DISPLAY
+DESCRIPTION:Reminder
+END:VALARM
+END:VTODO
+`,
+			want: []taskdata.Task{{UID: "abc@example.com", Description: "Renew passport", Notes: "Bring the old one, and photos",
+				Tags: []string{"errands", "travel"}, Priority: "low", DueDate: "2025-07-01", WaitUntil: "2025-06-20"}},
+		},
+		{
+			name:      "folded lines",
+			component: "BEGIN:VTODO\nUID:a\nSUMMARY:Write the quarterly\n  report\nEND:VTODO\n",
+			want:      []taskdata.Task{{UID: "a", Description: "Write the quarterly report"}},
+		},
+		{
+			name:      "ordinary VEVENTs are not tasks",
+			component: "BEGIN:VEVENT\nUID:a\nSUMMARY:Lunch\nDTSTART:20250601T120000\nEND:VEVENT\n",
+		},
+		{
+			name:      "our VEVENTs are",
+			component: "BEGIN:VEVENT\nUID:a\nSUMMARY:Dentist\nDTSTART:20250601T143000\nX-TODO-TASK:TRUE\nEND:VEVENT\n",
+			want:      []taskdata.Task{{UID: "a", Description: "Dentist", DueDate: "2025-06-01", DueTime: "14:30"}},
+		},
+		{
+			name:      "cancelled counts as completed",
+			component: "BEGIN:VTODO\nUID:a\nSUMMARY:Old plan\nSTATUS:CANCELLED\nEND:VTODO\n",
+			want:      []taskdata.Task{{UID: "a", Description: "Old plan", Completed: true}},
+		},
+		{
+			name:      "undefined priority",
+			component: "BEGIN:VTODO\nUID:a\nSUMMARY:Someday\nPRIORITY:0\nEND:VTODO\n",
+			want:      []taskdata.Task{{UID: "a", Description: "Someday"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := icalImport(t, tt.component)
+			if len(result.Errors) > 0 {
+				t.Fatalf("errors: %v", result.Errors)
+			}
+			if !reflect.DeepEqual(result.Tasks, tt.want) {
+				t.Errorf("got  %+v\nwant %+v", result.Tasks, tt.want)
+			}
+		})
+	}
+}
+
+func TestICalImportTimeZones(t *testing.T) {
+	if _, err := time.LoadLocation("America/New_York"); err != nil {
+		t.Skip("time zone database not available")
+	}
+	result := icalImport(t, `BEGIN:VTODO
+UID:a
+SUMMARY:Call New York
+DUE;TZID=America/New_York:20250601T090000
+END:VTODO
+BEGIN:VTODO
+UID:b
+SUMMARY:Call London
+DUE:20250601T080000Z
+END:VTODO
+`)
+	if len(result.Tasks) != 2 {
+		t.Fatalf("tasks = %+v, errors %v", result.Tasks, result.Errors)
+	}
+	for i, utc := range []time.Time{
+		time.Date(2025, time.June, 1, 13, 0, 0, 0, time.UTC),
+		time.Date(2025, time.June, 1, 8, 0, 0, 0, time.UTC),
+	} {
+		local := utc.Local()
+		task := result.Tasks[i]
+		if task.DueDate != local.Format("2006-01-02") || task.DueTime != local.Format("15:04") {
+			t.Errorf("%s due %s %s, want %s", task.Description, task.DueDate, task.DueTime, local.Format("2006-01-02 15:04"))
+		}
+	}
+}
+
+func TestICalImportErrors(t *testing.T) {
+	result := icalImport(t, `BEGIN:VTODO
+UID:a
+SUMMARY:Fine
+END:VTODO
+BEGIN:VTODO
+UID:b
+END:VTODO
+BEGIN:VTODO
+UID:c
+SUMMARY:Bad priority
+PRIORITY:high
+END:VTODO
+BEGIN:VTODO
+UID:d
+SUMMARY:Bad date
+DUE;VALUE=DATE:2025-06-01
+END:VTODO
+`)
+	if len(result.Tasks) != 1 || result.Tasks[0].UID != "a" {
+		t.Errorf("tasks = %+v", result.Tasks)
+	}
+	if len(result.Errors) != 3 {
+		t.Fatalf("errors = %v, want 3", result.Errors)
+	}
+	if result.Errors[1].Text != "Bad priority" || result.Errors[1].Line != 10 {
+		t.Errorf("error %q on line %d, want the Bad priority component on line 10", result.Errors[1].Text, result.Errors[1].Line)
+	}
+}
+
+func TestICalPriority(t *testing.T) {
+	tests := []struct {
+		priority string
+		ical     int
+		back     string
+	}{
+		{"high", 1, "high"},
+		{"normal", 5, "normal"},
+		{"low", 9, "low"},
+		{"", 0, ""},
+	}
+	for _, tt := range tests {
+		if got := icalPriority(tt.priority); got != tt.ical {
+			t.Errorf("icalPriority(%q) = %d, want %d", tt.priority, got, tt.ical)
+		}
+		if got := priorityFromICal(tt.ical); got != tt.back {
+			t.Errorf("priorityFromICal(%d) = %q, want %q", tt.ical, got, tt.back)
+		}
+	}
+	// Other clients use the whole range
+	for value, want := range map[int]string{2: "high", 4: "normal", 6: "normal", 8: "low"} {
+		if got := priorityFromICal(value); got != want {
+			t.Errorf("priorityFromICal(%d) = %q, want %q", value, got, want)
+		}
+	}
+}
+
+func TestICalRoundTrip(t *testing.T) {
+	tasks := []taskdata.Task{
+		{UID: "a", Description: "Pay rent; twice", DueDate: "2025-06-01", WaitUntil: "2025-05-25", Priority: "high",
+			Notes: "line one\nline two", Tags: []string{"home", "money"}, Project: "flat", Contexts: []string{"online"}},
+		{UID: "b", Description: "Dentist", DueDate: "2025-06-03", DueTime: "14:30", Priority: "low"},
+		{UID: "c", Description: "Call mom", Completed: true},
+	}
+
+	for _, opts := range []Options{{}, {TodosOnly: true}} {
+		var out bytes.Buffer
+		if err := (iCalendar{}).Export(&out, tasks, opts); err != nil {
+			t.Fatal(err)
+		}
+		result, err := (iCalendar{}).Import(&out, Options{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(result.Errors) > 0 {
+			t.Fatalf("errors: %v", result.Errors)
+		}
+
+		// The export stamps a completion time on tasks completed without one
+		got := result.Tasks
+		if len(got) == 3 {
+			got[2].CompletedAt = ""
+		}
+		if !reflect.DeepEqual(got, tasks) {
+			t.Errorf("todos only %v:\ngot  %+v\nwant %+v", opts.TodosOnly, got, tasks)
+		}
+	}
+}
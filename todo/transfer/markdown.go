@@ -0,0 +1,237 @@
+package transfer
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"todo/taskdata"
+)
+
+// markdown implements GitHub-style checklists: "- [ ] task" and "- [x] task".
+//
+// Items carry inline metadata: (due 2026-11-02), (due 2026-11-02 14:30),
+// (wait 2026-11-01), !priority and #tag. Export adds the task's UID as an
+// HTML comment, which renders invisibly; items without one get a UID derived
+// from their description and how often it came up before, so importing the
+// same file twice finds the same tasks.
+type markdown struct{}
+
+func init() {
+	register(markdown{})
+}
+
+func (markdown) Name() string { return "markdown" }
+
+func (markdown) Extensions() []string { return []string{".md", ".markdown"} }
+
+var (
+	mdItem     = regexp.MustCompile(`^\s*[-*+]\s+\[([ xX])\]\s+(.*)$`)
+	mdUID      = regexp.MustCompile(`<!--\s*todo:(\S+)\s*-->`)
+	mdDate     = regexp.MustCompile(`\((due|wait):?\s+(\d{4}-\d{2}-\d{2})(?:\s+(\d{2}:\d{2}))?\)`)
+	mdPriority = regexp.MustCompile(`(^|\s)!(\S+)`)
+	mdTag      = regexp.MustCompile(`(^|\s)#([\p{L}_][\p{L}\p{N}_-]*)`)
+)
+
+func (markdown) Export(w io.Writer, tasks []taskdata.Task, opts Options) error {
+	out := bufio.NewWriter(w)
+	now := time.Now()
+
+	groups, order := groupMarkdown(tasks, opts.Group, now)
+	first := true
+	for _, title := range order {
+		if len(groups[title]) == 0 {
+			continue
+		}
+		if !first {
+			fmt.Fprintln(out)
+		}
+		first = false
+
+		fmt.Fprintf(out, "## %s\n\n", title)
+		for _, task := range groups[title] {
+			fmt.Fprintln(out, formatMarkdownItem(task))
+		}
+	}
+
+	return out.Flush()
+}
+
+// groupMarkdown sorts tasks into sections by due bucket (the default) or
+// priority; completed tasks always go into a final Done section
+func groupMarkdown(tasks []taskdata.Task, group string, now time.Time) (map[string][]taskdata.Task, []string) {
+	groups := map[string][]taskdata.Task{}
+	var order []string
+
+	if group == "priority" {
+		for _, level := range taskdata.Priorities() {
+			runes := []rune(level)
+			order = append(order, strings.ToUpper(string(runes[:1]))+string(runes[1:])+" priority")
+		}
+		order = append(order, "Other")
+	} else {
+		order = []string{"Overdue", "Today", "This week", "Later", "No due date"}
+	}
+	order = append(order, "Done")
+
+	today := now.Format("2006-01-02")
+	weekEnd := now.AddDate(0, 0, 7).Format("2006-01-02")
+
+	for _, task := range tasks {
+		var title string
+		switch {
+		case task.Completed:
+			title = "Done"
+		case group == "priority":
+			title = "Other"
+			if rank := taskdata.PriorityRank(task.Priority); rank > 0 {
+				title = order[len(taskdata.Priorities())-rank]
+			}
+		case task.DueDate == "":
+			title = "No due date"
+		case task.DueDate < today:
+			title = "Overdue"
+		case task.DueDate == today:
+			title = "Today"
+		case task.DueDate <= weekEnd:
+			title = "This week"
+		default:
+			title = "Later"
+		}
+		groups[title] = append(groups[title], task)
+	}
+
+	return groups, order
+}
+
+// formatMarkdownItem renders a task as a checklist item with inline metadata
+func formatMarkdownItem(task taskdata.Task) string {
+	box := "[ ]"
+	if task.Completed {
+		box = "[x]"
+	}
+
+	parts := []string{"-", box, strings.ReplaceAll(task.Description, "\n", " ")}
+	if task.DueDate != "" {
+		parts = append(parts, "(due "+strings.TrimSpace(task.DueDate+" "+task.DueTime)+")")
+	}
+	if task.WaitUntil != "" {
+		parts = append(parts, "(wait "+task.WaitUntil+")")
+	}
+	if task.Priority != "" {
+		parts = append(parts, "!"+task.Priority)
+	}
+	for _, tag := range task.Tags {
+		parts = append(parts, "#"+tag)
+	}
+	if task.UID != "" {
+		parts = append(parts, "<!-- todo:"+task.UID+" -->")
+	}
+	return strings.Join(parts, " ")
+}
+
+func (markdown) Import(r io.Reader, opts Options) (*ImportResult, error) {
+	result := &ImportResult{}
+	scanner := bufio.NewScanner(r)
+
+	line := 0
+	inCode := false
+	seen := map[string]int{} // Items without a UID comment, by description
+	for scanner.Scan() {
+		line++
+		text := scanner.Text()
+
+		// Checklists inside fenced code blocks are examples, not tasks
+		if strings.HasPrefix(strings.TrimSpace(text), "```") {
+			inCode = !inCode
+			continue
+		}
+		if inCode {
+			continue
+		}
+
+		m := mdItem.FindStringSubmatch(text)
+		if m == nil {
+			continue
+		}
+
+		task, err := parseMarkdownItem(m[2], m[1] != " ")
+		if err != nil {
+			result.Errors = append(result.Errors, ImportError{Line: line, Text: strings.TrimSpace(text), Err: err})
+			continue
+		}
+
+		// Items without a UID comment are known by their description; a
+		// repeated item is told apart by how many came before it, so
+		// importing the file again still finds each one
+		if task.UID == "" {
+			key := taskdata.NormalizeDescription(task.Description)
+			occurrence := seen[key]
+			seen[key]++
+			if occurrence > 0 {
+				key += ":" + strconv.Itoa(occurrence)
+			}
+			task.UID = taskdata.NameUID("markdown:" + key)
+		}
+		result.Tasks = append(result.Tasks, task)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read markdown: %v", err)
+	}
+	return result, nil
+}
+
+// parseMarkdownItem extracts metadata from the text of a checklist item
+func parseMarkdownItem(text string, checked bool) (taskdata.Task, error) {
+	task := taskdata.Task{Completed: checked}
+
+	if m := mdUID.FindStringSubmatch(text); m != nil {
+		task.UID = m[1]
+		text = mdUID.ReplaceAllString(text, "")
+	}
+
+	for _, m := range mdDate.FindAllStringSubmatch(text, -1) {
+		if err := taskdata.ValidateDate(m[2]); err != nil {
+			return task, err
+		}
+		if m[1] == "wait" {
+			task.WaitUntil = m[2]
+			continue
+		}
+		if err := taskdata.ValidateTime(m[3]); err != nil {
+			return task, err
+		}
+		task.DueDate, task.DueTime = m[2], m[3]
+	}
+	text = mdDate.ReplaceAllString(text, "")
+
+	// Only !words that name a priority are metadata; "!!" or "!important" stay
+	text = mdPriority.ReplaceAllStringFunc(text, func(match string) string {
+		word := strings.TrimPrefix(strings.TrimSpace(match), "!")
+		level, err := taskdata.NormalizePriority(word)
+		if err != nil {
+			return match
+		}
+		task.Priority = level
+		return ""
+	})
+
+	// #tags, but not issue references such as #12
+	for _, m := range mdTag.FindAllStringSubmatch(text, -1) {
+		if tag := strings.ToLower(m[2]); !slices.Contains(task.Tags, tag) {
+			task.Tags = append(task.Tags, tag)
+		}
+	}
+	text = mdTag.ReplaceAllString(text, "$1")
+
+	task.Description = strings.Join(strings.Fields(text), " ")
+	if task.Description == "" {
+		return task, fmt.Errorf("checklist item has no description")
+	}
+	return task, nil
+}
@@ -0,0 +1,121 @@
+package transfer
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"todo/taskdata"
+)
+
+func importMarkdown(t *testing.T, text string) *ImportResult {
+	t.Helper()
+	result, err := (markdown{}).Import(strings.NewReader(text), Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return result
+}
+
+func TestParseMarkdownItem(t *testing.T) {
+	tests := []struct {
+		text    string
+		checked bool
+		want    taskdata.Task
+	}{
+		{"Buy milk", false, taskdata.Task{Description: "Buy milk"}},
+		{"Ship it", true, taskdata.Task{Description: "Ship it", Completed: true}},
+		{"Call Bob (due 2026-11-02 14:30) !high #work #Work", false,
+			taskdata.Task{Description: "Call Bob", DueDate: "2026-11-02", DueTime: "14:30", Priority: "high", Tags: []string{"work"}}},
+		{"Renew passport (wait 2026-11-01) (due: 2026-12-01)", false,
+			taskdata.Task{Description: "Renew passport", WaitUntil: "2026-11-01", DueDate: "2026-12-01"}},
+		{"Fix #12 !!important", false, taskdata.Task{Description: "Fix #12 !!important"}},
+		{"Review <!-- todo:abc-1 -->", false, taskdata.Task{Description: "Review", UID: "abc-1"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			got, err := parseMarkdownItem(tt.text, tt.checked)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got  %+v\nwant %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMarkdownImport(t *testing.T) {
+	result := importMarkdown(t, "# Release\n\n"+
+		"- [ ] Write changelog\n"+
+		"* [x] Tag the release\n"+
+		"Some text\n"+
+		"```\n- [ ] Example in a code block\n```\n"+
+		"  - [ ] (due 2026-13-01)\n"+
+		"+ [X] Publish #docs\n")
+
+	var descriptions []string
+	for _, task := range result.Tasks {
+		descriptions = append(descriptions, task.Description)
+	}
+	if want := []string{"Write changelog", "Tag the release", "Publish"}; !reflect.DeepEqual(descriptions, want) {
+		t.Errorf("imported %q, want %q", descriptions, want)
+	}
+	if len(result.Errors) != 1 || result.Errors[0].Line != 9 {
+		t.Errorf("errors = %v, want one on line 9", result.Errors)
+	}
+}
+
+func TestMarkdownDuplicateItems(t *testing.T) {
+	text := "## Monday\n- [ ] Water plants\n## Thursday\n- [ ] water  plants\n- [ ] Water plants\n- [ ] Buy milk\n"
+	first, again := importMarkdown(t, text), importMarkdown(t, text)
+
+	if len(first.Tasks) != 4 {
+		t.Fatalf("imported %d tasks, want 4", len(first.Tasks))
+	}
+	uids := map[string]bool{}
+	for i, task := range first.Tasks {
+		if uids[task.UID] {
+			t.Errorf("item %d %q has the UID of an earlier item", i+1, task.Description)
+		}
+		uids[task.UID] = true
+		if again.Tasks[i].UID != task.UID {
+			t.Errorf("item %d %q got another UID when imported again", i+1, task.Description)
+		}
+	}
+
+	// The first of the repeated items keeps the UID a single item gets
+	single := importMarkdown(t, "- [ ] Water plants\n")
+	if single.Tasks[0].UID != first.Tasks[0].UID {
+		t.Error("the first repeated item has another UID than a single one")
+	}
+}
+
+func TestMarkdownRoundTrip(t *testing.T) {
+	tasks := []taskdata.Task{
+		{ID: 1, UID: "uid-1", Description: "Call Bob", DueDate: "2026-11-02", DueTime: "14:30", Priority: "high", Tags: []string{"work"}},
+		{ID: 2, UID: "uid-2", Description: "Fix #12", Priority: "low", Completed: true},
+		{ID: 3, UID: "uid-3", Description: "Water plants", Priority: "normal", WaitUntil: "2026-11-01"},
+		{ID: 4, UID: "uid-4", Description: "Water plants", Priority: "normal"},
+	}
+	var out bytes.Buffer
+	if err := (markdown{}).Export(&out, tasks, Options{Group: "priority"}); err != nil {
+		t.Fatal(err)
+	}
+
+	result := importMarkdown(t, out.String())
+	if len(result.Tasks) != len(tasks) {
+		t.Fatalf("imported %d tasks from:\n%s", len(result.Tasks), out.String())
+	}
+	byUID := map[string]taskdata.Task{}
+	for _, task := range result.Tasks {
+		byUID[task.UID] = task
+	}
+	for _, want := range tasks {
+		want.ID = 0
+		if got := byUID[want.UID]; !reflect.DeepEqual(got, want) {
+			t.Errorf("got  %+v\nwant %+v", got, want)
+		}
+	}
+}
@@ -0,0 +1,435 @@
+package transfer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+	"todo/taskdata"
+)
+
+// taskwarrior implements the JSON written by 'task export' and read by
+// 'task import' (https://taskwarrior.org/docs/design/task/).
+//
+// description, uuid, status, due, wait, entry, end, priority (H/M/L),
+// project, tags, annotations and depends map onto Task fields. Every other
+// attribute (recur, scheduled, UDAs...) is kept in Task.Extra and written
+// back on export, as are depends UUIDs of tasks that were not imported. A
+// task without a priority gets Options.DefaultPriority, and export leaves
+// the priority out again while the task still has that level.
+type taskwarrior struct{}
+
+func init() {
+	register(taskwarrior{})
+}
+
+func (taskwarrior) Name() string { return "taskwarrior" }
+
+func (taskwarrior) Extensions() []string { return []string{".json"} }
+
+const twDateTime = "20060102T150405Z"
+
+// twMapped lists the attributes converted to Task fields; id and urgency are
+// computed by Taskwarrior and dropped
+var twMapped = map[string]bool{
+	"id": true, "urgency": true, "description": true, "uuid": true, "status": true,
+	"due": true, "wait": true, "entry": true, "end": true, "priority": true,
+	"project": true, "tags": true, "annotations": true, "depends": true,
+}
+
+// twAnnotation is a timestamped note
+type twAnnotation struct {
+	Entry       string `json:"entry"`
+	Description string `json:"description"`
+}
+
+func (taskwarrior) Export(w io.Writer, tasks []taskdata.Task, opts Options) error {
+	records := make([]map[string]any, 0, len(tasks))
+	now := time.Now().UTC().Format(twDateTime)
+
+	for _, task := range tasks {
+		record := map[string]any{}
+
+		// Unmapped attributes first, so mapped fields win
+		if raw, exists := task.Extra["taskwarrior"]; exists {
+			if err := json.Unmarshal(raw, &record); err != nil {
+				return fmt.Errorf("task #%d: invalid taskwarrior attributes: %v", task.ID, err)
+			}
+			// Kept only for their timestamps, dependencies and missing
+			// priority; rebuilt below
+			delete(record, "annotations")
+			delete(record, "depends")
+			delete(record, "priority")
+		}
+
+		record["uuid"] = task.UID
+		record["description"] = task.Description
+		record["status"] = "pending"
+		if task.Completed {
+			record["status"] = "completed"
+			record["end"] = twTimestamp(task.CompletedAt, now)
+		}
+		record["entry"] = twTimestamp(task.CreatedAt, now)
+		if _, exists := record["modified"]; !exists {
+			record["modified"] = now
+		}
+
+		if task.DueDate != "" {
+			clock := task.DueTime
+			if clock == "" {
+				clock = "00:00"
+			}
+			if due, err := time.ParseInLocation("2006-01-02 15:04", task.DueDate+" "+clock, time.Local); err == nil {
+				record["due"] = due.UTC().Format(twDateTime)
+			}
+		}
+		if task.WaitUntil != "" {
+			if wait, err := time.ParseInLocation("2006-01-02", task.WaitUntil, time.Local); err == nil {
+				record["wait"] = wait.UTC().Format(twDateTime)
+			}
+		}
+		if priority := twPriority(task.Priority); priority != "" && !(twHadNoPriority(task) && task.Priority == opts.DefaultPriority) {
+			record["priority"] = priority
+		}
+		if task.Project != "" {
+			record["project"] = task.Project
+		}
+		if len(task.Tags) > 0 {
+			record["tags"] = task.Tags
+		}
+		if annotations := twAnnotations(task, now); len(annotations) > 0 {
+			record["annotations"] = annotations
+		}
+		if depends := twDepends(task, tasks, opts.Store); len(depends) > 0 {
+			record["depends"] = depends
+		}
+
+		records = append(records, record)
+	}
+
+	// One task per line inside an array, like 'task export'
+	if _, err := io.WriteString(w, "[\n"); err != nil {
+		return err
+	}
+	for i, record := range records {
+		data, err := json.Marshal(record)
+		if err != nil {
+			return err
+		}
+		separator := ",\n"
+		if i == len(records)-1 {
+			separator = "\n"
+		}
+		if _, err := fmt.Fprintf(w, "%s%s", data, separator); err != nil {
+			return err
+		}
+	}
+	_, err := io.WriteString(w, "]\n")
+	return err
+}
+
+func (taskwarrior) Import(r io.Reader, opts Options) (*ImportResult, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read taskwarrior export: %v", err)
+	}
+
+	// Accept a JSON array ('task export') or one object per line (older versions)
+	var records []json.RawMessage
+	trimmed := bytes.TrimSpace(data)
+	if bytes.HasPrefix(trimmed, []byte("[")) {
+		if err := json.Unmarshal(trimmed, &records); err != nil {
+			return nil, fmt.Errorf("failed to parse taskwarrior export: %v", err)
+		}
+	} else {
+		decoder := json.NewDecoder(bytes.NewReader(trimmed))
+		for decoder.More() {
+			var record json.RawMessage
+			if err := decoder.Decode(&record); err != nil {
+				return nil, fmt.Errorf("failed to parse taskwarrior export: %v", err)
+			}
+			records = append(records, record)
+		}
+	}
+
+	result := &ImportResult{Dependencies: map[string][]string{}}
+	for i, raw := range records {
+		task, depends, keep, err := parseTaskwarrior(raw, opts)
+		if err != nil {
+			result.Errors = append(result.Errors, ImportError{Line: i + 1, Text: task.Description, Err: err})
+			continue
+		}
+		if !keep {
+			continue
+		}
+		if len(depends) > 0 {
+			result.Dependencies[task.UID] = depends
+		}
+		result.Tasks = append(result.Tasks, task)
+	}
+
+	return result, nil
+}
+
+// parseTaskwarrior converts one exported task. Deleted tasks and recurring
+// templates (whose instances are exported separately) are not kept.
+func parseTaskwarrior(raw json.RawMessage, opts Options) (taskdata.Task, []string, bool, error) {
+	var task taskdata.Task
+	var record map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &record); err != nil {
+		return task, nil, false, fmt.Errorf("entry is not a JSON object")
+	}
+
+	var attrs struct {
+		Description string         `json:"description"`
+		UUID        string         `json:"uuid"`
+		Status      string         `json:"status"`
+		Due         string         `json:"due"`
+		Wait        string         `json:"wait"`
+		Entry       string         `json:"entry"`
+		End         string         `json:"end"`
+		Priority    string         `json:"priority"`
+		Project     string         `json:"project"`
+		Tags        []string       `json:"tags"`
+		Annotations []twAnnotation `json:"annotations"`
+	}
+	if err := json.Unmarshal(raw, &attrs); err != nil {
+		return task, nil, false, fmt.Errorf("unexpected attribute type: %v", err)
+	}
+
+	task.Description = attrs.Description
+	task.UID = attrs.UUID
+	if task.Description == "" {
+		return task, nil, false, fmt.Errorf("task has no description")
+	}
+
+	switch attrs.Status {
+	case "deleted", "recurring":
+		return task, nil, false, nil
+	case "completed":
+		task.Completed = true
+	}
+
+	var err error
+	if attrs.Due != "" {
+		due, err := twParseTime(attrs.Due)
+		if err != nil {
+			return task, nil, false, err
+		}
+		task.DueDate = due.Format("2006-01-02")
+		if clock := due.Format("15:04"); clock != "00:00" {
+			task.DueTime = clock
+		}
+	}
+	if attrs.Wait != "" {
+		wait, err := twParseTime(attrs.Wait)
+		if err != nil {
+			return task, nil, false, err
+		}
+		task.WaitUntil = wait.Format("2006-01-02")
+	}
+	if task.CreatedAt, err = twRFC3339(attrs.Entry); err != nil {
+		return task, nil, false, err
+	}
+	if task.CompletedAt, err = twRFC3339(attrs.End); err != nil {
+		return task, nil, false, err
+	}
+	if !task.Completed {
+		task.CompletedAt = "" // end is also set on deleted tasks
+	}
+
+	levels := taskdata.Priorities()
+	switch strings.ToUpper(attrs.Priority) {
+	case "H":
+		task.Priority = levels[0]
+	case "M":
+		task.Priority = levels[len(levels)/2]
+	case "L":
+		task.Priority = levels[len(levels)-1]
+	case "":
+		task.Priority = opts.DefaultPriority
+	default:
+		return task, nil, false, fmt.Errorf("invalid priority '%s' (use H, M or L)", attrs.Priority)
+	}
+
+	task.Project = attrs.Project
+	for _, tag := range attrs.Tags {
+		if !slices.Contains(task.Tags, tag) {
+			task.Tags = append(task.Tags, tag)
+		}
+	}
+
+	notes := make([]string, len(attrs.Annotations))
+	for i, annotation := range attrs.Annotations {
+		notes[i] = annotation.Description
+	}
+	task.Notes = strings.Join(notes, "\n")
+
+	depends := twParseDepends(record["depends"])
+
+	// Keep everything else, plus the original annotations for their
+	// timestamps, the dependencies for those that are not imported, and
+	// a null priority to tell a missing priority from the default level
+	extra := map[string]json.RawMessage{}
+	for key, value := range record {
+		if !twMapped[key] || (key == "annotations" && len(attrs.Annotations) > 0) {
+			extra[key] = value
+		}
+	}
+	if len(depends) > 0 {
+		data, err := json.Marshal(depends)
+		if err != nil {
+			return task, nil, false, err
+		}
+		extra["depends"] = data
+	}
+	if attrs.Priority == "" {
+		extra["priority"] = json.RawMessage("null")
+	}
+	if len(extra) > 0 {
+		data, err := json.Marshal(extra)
+		if err != nil {
+			return task, nil, false, err
+		}
+		task.Extra = map[string]json.RawMessage{"taskwarrior": data}
+	}
+
+	return task, depends, true, nil
+}
+
+// twParseDepends reads depends as an array (2.6+) or comma-separated string
+func twParseDepends(raw json.RawMessage) []string {
+	if len(raw) == 0 {
+		return nil
+	}
+	var list []string
+	if err := json.Unmarshal(raw, &list); err == nil {
+		return list
+	}
+	var joined string
+	if err := json.Unmarshal(raw, &joined); err == nil && joined != "" {
+		return strings.Split(joined, ",")
+	}
+	return nil
+}
+
+// twParseTime parses a Taskwarrior UTC timestamp into local time
+func twParseTime(value string) (time.Time, error) {
+	t, err := time.Parse(twDateTime, value)
+	if err != nil {
+		return t, fmt.Errorf("invalid date '%s'", value)
+	}
+	return t.Local(), nil
+}
+
+// twRFC3339 converts a Taskwarrior timestamp to TimestampFormat, "" if empty
+func twRFC3339(value string) (string, error) {
+	if value == "" {
+		return "", nil
+	}
+	t, err := twParseTime(value)
+	if err != nil {
+		return "", err
+	}
+	return t.Format(taskdata.TimestampFormat), nil
+}
+
+// twTimestamp converts an RFC 3339 timestamp, falling back to now
+func twTimestamp(timestamp, now string) string {
+	t, err := time.Parse(taskdata.TimestampFormat, timestamp)
+	if err != nil {
+		return now
+	}
+	return t.UTC().Format(twDateTime)
+}
+
+// twPriority maps the highest level to H, the lowest to L and the rest to M
+func twPriority(priority string) string {
+	levels := taskdata.Priorities()
+	switch taskdata.PriorityRank(priority) {
+	case 0:
+		return ""
+	case len(levels):
+		return "H"
+	case 1:
+		return "L"
+	}
+	return "M"
+}
+
+// twAnnotations turns each line of the notes into an annotation, reusing the
+// original timestamp of annotations that came from Taskwarrior
+func twAnnotations(task taskdata.Task, now string) []twAnnotation {
+	if task.Notes == "" {
+		return nil
+	}
+
+	var original []twAnnotation
+	json.Unmarshal(twExtra(task)["annotations"], &original)
+
+	var annotations []twAnnotation
+	for _, line := range strings.Split(task.Notes, "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		entry := twTimestamp(task.CreatedAt, now)
+		for _, a := range original {
+			if a.Description == line {
+				entry = a.Entry
+				break
+			}
+		}
+		annotations = append(annotations, twAnnotation{Entry: entry, Description: line})
+	}
+	return annotations
+}
+
+// twDepends returns the UIDs of the tasks this task depends on, including
+// those kept from Taskwarrior for tasks that were never imported
+func twDepends(task taskdata.Task, tasks []taskdata.Task, store *taskdata.TaskStore) []string {
+	if store != nil {
+		tasks = store.Tasks
+	}
+
+	var uids []string
+	for _, id := range task.DependsOn {
+		for _, other := range tasks {
+			if other.ID == id && other.UID != "" {
+				uids = append(uids, other.UID)
+			}
+		}
+	}
+	// Dependencies on known tasks are in DependsOn, unless they were removed
+	for _, uid := range twExtraDepends(task) {
+		known := slices.ContainsFunc(tasks, func(other taskdata.Task) bool { return other.UID == uid })
+		if !known && !slices.Contains(uids, uid) {
+			uids = append(uids, uid)
+		}
+	}
+	sort.Strings(uids)
+	return uids
+}
+
+// twExtra returns the Taskwarrior attributes kept in Task.Extra
+func twExtra(task taskdata.Task) map[string]json.RawMessage {
+	var extra map[string]json.RawMessage
+	if raw, exists := task.Extra["taskwarrior"]; exists {
+		json.Unmarshal(raw, &extra)
+	}
+	return extra
+}
+
+// twExtraDepends returns the depends UUIDs kept on import
+func twExtraDepends(task taskdata.Task) []string {
+	return twParseDepends(twExtra(task)["depends"])
+}
+
+// twHadNoPriority reports whether the task had no priority in Taskwarrior
+func twHadNoPriority(task taskdata.Task) bool {
+	priority, exists := twExtra(task)["priority"]
+	return exists && string(priority) == "null"
+}
@@ -0,0 +1,198 @@
+package transfer
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"todo/taskdata"
+)
+
+// twImport imports a Taskwarrior export and numbers the tasks from 1, with
+// their dependencies linked as 'todo import' does
+func twImport(t *testing.T, data string) *ImportResult {
+	t.Helper()
+	format, err := Lookup("taskwarrior")
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := format.Import(strings.NewReader(data), Options{DefaultPriority: "normal"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Errors) > 0 {
+		t.Fatalf("import errors: %v", result.Errors)
+	}
+	for i := range result.Tasks {
+		result.Tasks[i].ID = i + 1
+	}
+	for i, task := range result.Tasks {
+		for _, uid := range result.Dependencies[task.UID] {
+			for _, other := range result.Tasks {
+				if other.UID == uid {
+					result.Tasks[i].DependsOn = append(result.Tasks[i].DependsOn, other.ID)
+				}
+			}
+		}
+	}
+	return result
+}
+
+// twExport exports tasks and returns the records by uuid
+func twExport(t *testing.T, tasks []taskdata.Task) map[string]map[string]any {
+	t.Helper()
+	var out bytes.Buffer
+	if err := (taskwarrior{}).Export(&out, tasks, Options{DefaultPriority: "normal"}); err != nil {
+		t.Fatal(err)
+	}
+	var records []map[string]any
+	if err := json.Unmarshal(out.Bytes(), &records); err != nil {
+		t.Fatalf("export is not JSON: %v\n%s", err, out.String())
+	}
+	byUUID := map[string]map[string]any{}
+	for _, record := range records {
+		byUUID[record["uuid"].(string)] = record
+	}
+	return byUUID
+}
+
+func TestTaskwarriorImport(t *testing.T) {
+	tests := []struct {
+		name   string
+		record string
+		check  func(t *testing.T, task taskdata.Task)
+	}{
+		{
+			name:   "tags keep their case",
+			record: `{"uuid":"a","description":"Call","status":"pending","tags":["Home","home","URGENT"]}`,
+			check: func(t *testing.T, task taskdata.Task) {
+				if !reflect.DeepEqual(task.Tags, []string{"Home", "home", "URGENT"}) {
+					t.Errorf("tags = %v", task.Tags)
+				}
+			},
+		},
+		{
+			name:   "priorities map onto the scale",
+			record: `{"uuid":"a","description":"Call","status":"pending","priority":"L"}`,
+			check: func(t *testing.T, task taskdata.Task) {
+				if task.Priority != "low" {
+					t.Errorf("priority = %q, want low", task.Priority)
+				}
+			},
+		},
+		{
+			name:   "a missing priority is the default",
+			record: `{"uuid":"a","description":"Call","status":"pending"}`,
+			check: func(t *testing.T, task taskdata.Task) {
+				if task.Priority != "normal" || !twHadNoPriority(task) {
+					t.Errorf("priority = %q, had none = %v", task.Priority, twHadNoPriority(task))
+				}
+			},
+		},
+		{
+			name:   "due time and dates",
+			record: `{"uuid":"a","description":"Call","status":"completed","entry":"20250101T090000Z","end":"20250102T090000Z","due":"20250103T143000Z"}`,
+			check: func(t *testing.T, task taskdata.Task) {
+				if !task.Completed || task.CompletedAt == "" || task.CreatedAt == "" || task.DueDate == "" || task.DueTime == "" {
+					t.Errorf("task = %+v", task)
+				}
+			},
+		},
+		{
+			name:   "annotations become notes",
+			record: `{"uuid":"a","description":"Call","status":"pending","annotations":[{"entry":"20250101T090000Z","description":"first"},{"entry":"20250102T090000Z","description":"second"}]}`,
+			check: func(t *testing.T, task taskdata.Task) {
+				if task.Notes != "first\nsecond" {
+					t.Errorf("notes = %q", task.Notes)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := twImport(t, "["+tt.record+"]")
+			if len(result.Tasks) != 1 {
+				t.Fatalf("imported %d tasks", len(result.Tasks))
+			}
+			tt.check(t, result.Tasks[0])
+		})
+	}
+}
+
+func TestTaskwarriorImportSkipsAndRejects(t *testing.T) {
+	data := `{"uuid":"a","description":"Gone","status":"deleted"}
+{"uuid":"b","description":"Template","status":"recurring"}
+{"uuid":"c","description":"","status":"pending"}
+{"uuid":"d","description":"Odd","status":"pending","priority":"X"}
+{"uuid":"e","description":"Fine","status":"pending"}`
+
+	result, err := (taskwarrior{}).Import(strings.NewReader(data), Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Tasks) != 1 || result.Tasks[0].UID != "e" {
+		t.Errorf("tasks = %+v", result.Tasks)
+	}
+	if len(result.Errors) != 2 {
+		t.Errorf("errors = %v, want 2", result.Errors)
+	}
+}
+
+func TestTaskwarriorRoundTrip(t *testing.T) {
+	result := twImport(t, `[
+{"uuid":"a","description":"Write report","status":"pending","entry":"20250101T090000Z","priority":"H","tags":["Work"],"depends":["b","elsewhere"],"scheduled":"20250105T090000Z"},
+{"uuid":"b","description":"Collect numbers","status":"pending","entry":"20250101T090000Z","depends":"elsewhere-too"}
+]`)
+	records := twExport(t, result.Tasks)
+
+	report, numbers := records["a"], records["b"]
+	if report["priority"] != "H" {
+		t.Errorf("priority = %v, want H", report["priority"])
+	}
+	if _, exists := numbers["priority"]; exists {
+		t.Errorf("a task without priority was exported with %v", numbers["priority"])
+	}
+	if !reflect.DeepEqual(report["tags"], []any{"Work"}) {
+		t.Errorf("tags = %v", report["tags"])
+	}
+	if report["scheduled"] != "20250105T090000Z" || report["entry"] != "20250101T090000Z" {
+		t.Errorf("attributes not kept: %v", report)
+	}
+	if !reflect.DeepEqual(report["depends"], []any{"b", "elsewhere"}) {
+		t.Errorf("depends = %v, want b and elsewhere", report["depends"])
+	}
+	if !reflect.DeepEqual(numbers["depends"], []any{"elsewhere-too"}) {
+		t.Errorf("depends = %v, want elsewhere-too", numbers["depends"])
+	}
+}
+
+func TestTaskwarriorExportAfterChanges(t *testing.T) {
+	result := twImport(t, `[
+{"uuid":"a","description":"Write report","status":"pending","depends":["b","elsewhere"]},
+{"uuid":"b","description":"Collect numbers","status":"pending"}
+]`)
+	// The priority was set and the dependency on b removed here
+	result.Tasks[0].Priority = "normal"
+	result.Tasks[1].Priority = "high"
+	result.Tasks[0].DependsOn = nil
+
+	records := twExport(t, result.Tasks)
+	if _, exists := records["a"]["priority"]; exists {
+		t.Errorf("priority = %v, want none while it is the default", records["a"]["priority"])
+	}
+	if records["b"]["priority"] != "H" {
+		t.Errorf("priority = %v, want H", records["b"]["priority"])
+	}
+	if !reflect.DeepEqual(records["a"]["depends"], []any{"elsewhere"}) {
+		t.Errorf("depends = %v, want only the task that was not imported", records["a"]["depends"])
+	}
+
+	// Tasks created here always carry their priority
+	created := taskdata.Task{ID: 3, UID: "c", Description: "New", Priority: "normal"}
+	if record := twExport(t, []taskdata.Task{created})["c"]; record["priority"] != "M" {
+		t.Errorf("priority = %v, want M", record["priority"])
+	}
+}
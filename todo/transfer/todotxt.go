@@ -0,0 +1,258 @@
+package transfer
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strings"
+	"todo/taskdata"
+)
+
+// todoTxt implements the todo.txt format (https://github.com/todotxt/todo.txt).
+//
+// Priorities (A), (B), (C)... map onto the configured scale, most important
+// first. The last +project and every @context become Project and Contexts;
+// due:, t: (wait until), tags: and uid: are read as key:value extensions.
+// Other key:value pairs stay in the description so nothing is lost.
+//
+// Description words that would otherwise be read as markup (+word, @word,
+// due:... and the like, or a leading x, (A) or date) are written with a
+// backslash in front, which import removes again.
+type todoTxt struct{}
+
+func init() {
+	register(todoTxt{})
+}
+
+func (todoTxt) Name() string { return "todotxt" }
+
+func (todoTxt) Extensions() []string { return []string{".txt"} }
+
+var (
+	todoTxtPriority = regexp.MustCompile(`^\(([A-Z])\)$`)
+	todoTxtDate     = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}$`)
+)
+
+func (todoTxt) Export(w io.Writer, tasks []taskdata.Task, opts Options) error {
+	for _, task := range tasks {
+		if _, err := fmt.Fprintln(w, formatTodoTxt(task)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// formatTodoTxt renders a task as a single todo.txt line
+func formatTodoTxt(task taskdata.Task) string {
+	var parts []string
+	letter := priorityLetter(task.Priority)
+
+	if task.Completed {
+		parts = append(parts, "x")
+		// A creation date is only allowed after a completion date
+		if completed := dateOf(task.CompletedAt); completed != "" {
+			parts = append(parts, completed)
+			if created := dateOf(task.CreatedAt); created != "" {
+				parts = append(parts, created)
+			}
+		}
+	} else {
+		if letter != "" {
+			parts = append(parts, "("+letter+")")
+		}
+		if created := dateOf(task.CreatedAt); created != "" {
+			parts = append(parts, created)
+		}
+	}
+
+	for i, word := range strings.Fields(task.Description) {
+		parts = append(parts, escapeTodoTxt(word, i == 0))
+	}
+	if task.Project != "" {
+		parts = append(parts, "+"+strings.ReplaceAll(task.Project, " ", "-"))
+	}
+	for _, context := range task.Contexts {
+		parts = append(parts, "@"+context)
+	}
+	if task.DueDate != "" {
+		parts = append(parts, "due:"+task.DueDate)
+	}
+	if task.WaitUntil != "" {
+		parts = append(parts, "t:"+task.WaitUntil)
+	}
+	if len(task.Tags) > 0 {
+		parts = append(parts, "tags:"+strings.Join(task.Tags, ","))
+	}
+	// Completed tasks drop the leading priority, so keep it as pri:
+	if task.Completed && letter != "" {
+		parts = append(parts, "pri:"+letter)
+	}
+	if task.UID != "" {
+		parts = append(parts, "uid:"+task.UID)
+	}
+
+	return strings.Join(parts, " ")
+}
+
+func (todoTxt) Import(r io.Reader, opts Options) (*ImportResult, error) {
+	result := &ImportResult{}
+	scanner := bufio.NewScanner(r)
+
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+
+		task, err := parseTodoTxt(text)
+		if err != nil {
+			result.Errors = append(result.Errors, ImportError{Line: line, Text: text, Err: err})
+			continue
+		}
+		result.Tasks = append(result.Tasks, task)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read todo.txt: %v", err)
+	}
+	return result, nil
+}
+
+// todoTxtKeys are the key:value extensions read into task fields
+var todoTxtKeys = []string{"due", "t", "tags", "pri", "uid"}
+
+// escapeTodoTxt protects a description word that import would otherwise
+// read as markup; first is the word that starts the description
+func escapeTodoTxt(word string, first bool) string {
+	key, value, isPair := strings.Cut(word, ":")
+	switch {
+	case strings.HasPrefix(word, `\`),
+		len(word) > 1 && (word[0] == '+' || word[0] == '@'),
+		isPair && value != "" && slices.Contains(todoTxtKeys, key),
+		first && (word == "x" || todoTxtPriority.MatchString(word) || todoTxtDate.MatchString(word)):
+		return `\` + word
+	}
+	return word
+}
+
+// parseTodoTxt converts one todo.txt line into a task
+func parseTodoTxt(line string) (taskdata.Task, error) {
+	var task taskdata.Task
+	fields := strings.Fields(line)
+
+	// Completion marker, priority, then completion and creation dates.
+	// Completed lines should not keep their priority, but many clients
+	// leave it in place, so it is accepted after the x as well.
+	completed := fields[0] == "x"
+	if completed {
+		task.Completed = true
+		fields = fields[1:]
+	}
+	if len(fields) > 0 {
+		if m := todoTxtPriority.FindStringSubmatch(fields[0]); m != nil {
+			task.Priority = letterPriority(m[1])
+			fields = fields[1:]
+		}
+	}
+	if completed && len(fields) > 0 && todoTxtDate.MatchString(fields[0]) {
+		task.CompletedAt = timestampOf(fields[0])
+		fields = fields[1:]
+	}
+	if len(fields) > 0 && todoTxtDate.MatchString(fields[0]) {
+		task.CreatedAt = timestampOf(fields[0])
+		fields = fields[1:]
+	}
+
+	// The last +project is the task's project; export appends it at the end,
+	// so any others stay in the description in their original order
+	projectIndex := -1
+	for i, field := range fields {
+		if len(field) > 1 && field[0] == '+' {
+			projectIndex = i
+		}
+	}
+
+	var words []string
+	for i, field := range fields {
+		switch {
+		case strings.HasPrefix(field, `\`):
+			words = append(words, field[1:])
+			continue
+		case i == projectIndex:
+			task.Project = field[1:]
+			continue
+		case len(field) > 1 && field[0] == '@':
+			context := field[1:]
+			if !slices.Contains(task.Contexts, context) {
+				task.Contexts = append(task.Contexts, context)
+			}
+			continue
+		default:
+			key, value, found := strings.Cut(field, ":")
+			if !found || key == "" || value == "" || strings.HasPrefix(value, "//") {
+				break
+			}
+			switch key {
+			case "due":
+				if err := taskdata.ValidateDate(value); err != nil {
+					return task, err
+				}
+				task.DueDate = value
+				continue
+			case "t":
+				if err := taskdata.ValidateDate(value); err != nil {
+					return task, err
+				}
+				task.WaitUntil = value
+				continue
+			case "tags":
+				for _, tag := range strings.Split(value, ",") {
+					if tag = strings.ToLower(tag); tag != "" && !slices.Contains(task.Tags, tag) {
+						task.Tags = append(task.Tags, tag)
+					}
+				}
+				continue
+			case "pri":
+				if len(value) == 1 && value[0] >= 'A' && value[0] <= 'Z' {
+					task.Priority = letterPriority(value)
+					continue
+				}
+			case "uid":
+				task.UID = value
+				continue
+			}
+		}
+		words = append(words, field)
+	}
+
+	task.Description = strings.Join(words, " ")
+	if task.Description == "" {
+		return task, fmt.Errorf("task has no description")
+	}
+	return task, nil
+}
+
+// priorityLetter maps a priority onto A (most important), B, C...
+func priorityLetter(priority string) string {
+	levels := taskdata.Priorities()
+	for i, level := range levels {
+		if strings.EqualFold(level, priority) && i < 26 {
+			return string(rune('A' + i))
+		}
+	}
+	return ""
+}
+
+// letterPriority maps A, B, C... onto the scale; letters past its end get the lowest level
+func letterPriority(letter string) string {
+	levels := taskdata.Priorities()
+	i := int(letter[0] - 'A')
+	if i >= len(levels) {
+		return taskdata.LowestPriority()
+	}
+	return levels[i]
+}
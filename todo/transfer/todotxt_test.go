@@ -0,0 +1,132 @@
+package transfer
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"todo/taskdata"
+)
+
+func TestParseTodoTxt(t *testing.T) {
+	tests := []struct {
+		line string
+		want taskdata.Task
+	}{
+		{
+			line: "Buy milk",
+			want: taskdata.Task{Description: "Buy milk"},
+		},
+		{
+			line: "(A) 2016-04-30 Call mom +family @phone due:2016-05-01",
+			want: taskdata.Task{Description: "Call mom", Priority: "high", CreatedAt: timestampOf("2016-04-30"),
+				Project: "family", Contexts: []string{"phone"}, DueDate: "2016-05-01"},
+		},
+		{
+			line: "x 2016-05-20 2016-04-30 measure space for +chapelShelving",
+			want: taskdata.Task{Description: "measure space for", Completed: true, Project: "chapelShelving",
+				CompletedAt: timestampOf("2016-05-20"), CreatedAt: timestampOf("2016-04-30")},
+		},
+		{
+			line: "x (A) 2016-05-20 2016-04-30 measure space for +chapelShelving @chapel due:2016-05-30",
+			want: taskdata.Task{Description: "measure space for", Completed: true, Priority: "high",
+				CompletedAt: timestampOf("2016-05-20"), CreatedAt: timestampOf("2016-04-30"),
+				Project: "chapelShelving", Contexts: []string{"chapel"}, DueDate: "2016-05-30"},
+		},
+		{
+			line: "x (C) Water plants",
+			want: taskdata.Task{Description: "Water plants", Completed: true, Priority: "low"},
+		},
+		{
+			line: "x 2016-05-20 Water plants pri:B",
+			want: taskdata.Task{Description: "Water plants", Completed: true, Priority: "normal",
+				CompletedAt: timestampOf("2016-05-20")},
+		},
+		{
+			line: "(Z) Someday t:2030-01-01 tags:Home,errand uid:abc-1",
+			want: taskdata.Task{Description: "Someday", Priority: "low", WaitUntil: "2030-01-01",
+				Tags: []string{"home", "errand"}, UID: "abc-1"},
+		},
+		{
+			line: `\x \(A) marks the spot see:https://example.com \+plus \due:friday`,
+			want: taskdata.Task{Description: "x (A) marks the spot see:https://example.com +plus due:friday"},
+		},
+		{
+			line: "2016-04-30 Read +old +new",
+			want: taskdata.Task{Description: "Read +old", Project: "new", CreatedAt: timestampOf("2016-04-30")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			got, err := parseTodoTxt(tt.line)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got  %+v\nwant %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseTodoTxtErrors(t *testing.T) {
+	for _, line := range []string{"x", "(A) 2016-04-30", "Pay rent due:someday", "Pay rent t:2016-13-01"} {
+		if _, err := parseTodoTxt(line); err == nil {
+			t.Errorf("%q was accepted", line)
+		}
+	}
+}
+
+func TestTodoTxtRoundTrip(t *testing.T) {
+	tests := []struct {
+		line     string
+		exported string
+	}{
+		{"Buy milk", "Buy milk"},
+		{"(A) 2016-04-30 Call mom +family @phone due:2016-05-01",
+			"(A) 2016-04-30 Call mom +family @phone due:2016-05-01"},
+		{"x (A) 2016-05-20 2016-04-30 measure space for +chapelShelving @chapel due:2016-05-30",
+			"x 2016-05-20 2016-04-30 measure space for +chapelShelving @chapel due:2016-05-30 pri:A"},
+		{"x 2016-05-20 2016-04-30 Water plants pri:C",
+			"x 2016-05-20 2016-04-30 Water plants pri:C"},
+		{"(B) Pay rent t:2016-05-01 tags:home uid:rent-1",
+			"(B) Pay rent t:2016-05-01 tags:home uid:rent-1"},
+		{`\x \(A) \2016-01-01 marks the \+spot \due:friday`,
+			`\x (A) 2016-01-01 marks the \+spot \due:friday`},
+	}
+
+	format, err := Lookup("todotxt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			result, err := format.Import(strings.NewReader(tt.line+"\n"), Options{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(result.Errors) > 0 || len(result.Tasks) != 1 {
+				t.Fatalf("imported %d tasks, errors %v", len(result.Tasks), result.Errors)
+			}
+
+			var out bytes.Buffer
+			if err := format.Export(&out, result.Tasks, Options{}); err != nil {
+				t.Fatal(err)
+			}
+			exported := strings.TrimSuffix(out.String(), "\n")
+			if exported != tt.exported {
+				t.Errorf("exported %q, want %q", exported, tt.exported)
+			}
+
+			again, err := format.Import(strings.NewReader(exported), Options{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(again.Tasks, result.Tasks) {
+				t.Errorf("reimported %+v, want %+v", again.Tasks, result.Tasks)
+			}
+		})
+	}
+}
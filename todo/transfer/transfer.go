@@ -0,0 +1,116 @@
+// Package transfer converts tasks to and from the file formats of other tools.
+package transfer
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+	"todo/taskdata"
+)
+
+// Format reads and writes tasks in one file format
+type Format interface {
+	// Name is the value accepted by --format
+	Name() string
+	// Extensions are the file extensions that select this format, e.g. ".txt"
+	Extensions() []string
+	Export(w io.Writer, tasks []taskdata.Task, opts Options) error
+	Import(r io.Reader, opts Options) (*ImportResult, error)
+}
+
+// Options carries format-specific settings from the command line
+type Options struct {
+	// Mapping maps source column names to task fields (CSV)
+	Mapping map[string]string
+	// Group selects how exported tasks are sectioned: "due" or "priority" (Markdown)
+	Group string
+	// TodosOnly writes every task as a VTODO, with a date-time DUE for tasks
+	// with a due time, instead of turning those into VEVENTs (iCalendar)
+	TodosOnly bool
+	// Store is the whole task store, for resolving references such as
+	// dependencies to tasks that are not being exported
+	Store *taskdata.TaskStore
+	// DefaultPriority is the priority of tasks whose source has none
+	// (Taskwarrior)
+	DefaultPriority string
+}
+
+// ImportResult holds the tasks read from a file and the entries that failed
+type ImportResult struct {
+	Tasks  []taskdata.Task
+	Errors []ImportError
+	// Dependencies maps a task's UID to the UIDs of the tasks it depends on.
+	// They are resolved to IDs once every task has been added.
+	Dependencies map[string][]string
+}
+
+// ImportError describes an entry that could not be converted into a task
+type ImportError struct {
+	Line int // Line or row number in the source, 1-based
+	Text string
+	Err  error
+}
+
+func (e ImportError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+var formats = map[string]Format{}
+
+// register makes a format available by name
+func register(format Format) {
+	formats[format.Name()] = format
+}
+
+// Lookup returns the format with the given name
+func Lookup(name string) (Format, error) {
+	format, exists := formats[strings.ToLower(name)]
+	if !exists {
+		return nil, fmt.Errorf("unknown format '%s'. Supported formats: %s", name, strings.Join(Names(), ", "))
+	}
+	return format, nil
+}
+
+// Detect picks a format from a file name's extension
+func Detect(fileName string) (Format, error) {
+	ext := strings.ToLower(filepath.Ext(fileName))
+	for _, name := range Names() {
+		for _, candidate := range formats[name].Extensions() {
+			if candidate == ext {
+				return formats[name], nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("cannot tell the format of '%s'; use --format (%s)", fileName, strings.Join(Names(), ", "))
+}
+
+// Names returns the supported format names in sorted order
+func Names() []string {
+	names := make([]string, 0, len(formats))
+	for name := range formats {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// dateOf returns the YYYY-MM-DD part of an RFC 3339 timestamp
+func dateOf(timestamp string) string {
+	t, err := time.Parse(taskdata.TimestampFormat, timestamp)
+	if err != nil {
+		return ""
+	}
+	return t.Format("2006-01-02")
+}
+
+// timestampOf turns a YYYY-MM-DD date into an RFC 3339 timestamp at local midnight
+func timestampOf(date string) string {
+	t, err := time.ParseInLocation("2006-01-02", date, time.Local)
+	if err != nil {
+		return ""
+	}
+	return t.Format(taskdata.TimestampFormat)
+}
@@ -0,0 +1,609 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"todo/taskdata"
+)
+
+// View is one of the task lists the App switches between, such as today's
+// tasks
+type View struct {
+	Name   string
+	Groups func(store *taskdata.TaskStore, now time.Time) []Group
+}
+
+// Group is a titled part of a view; a single group may have no title
+type Group struct {
+	Title string
+	Tasks []taskdata.Task
+}
+
+// Options connect the App to the tasks
+type Options struct {
+	Views    []View
+	Load     func() (*taskdata.TaskStore, error)
+	Save     func(store *taskdata.TaskStore) (string, error) // Returns a message to show, if any
+	Revision func() (string, error)                          // Current revision of the tasks file
+}
+
+type mode int
+
+const (
+	modeList mode = iota
+	modeFilter
+	modeEdit
+	modeConfirm
+)
+
+// row is a line of the list: a group title or a task
+type row struct {
+	title string
+	task  *taskdata.Task
+}
+
+// App is the state of the full-screen interface
+type App struct {
+	opts  Options
+	store *taskdata.TaskStore
+
+	view     int
+	filter   string
+	rows     []row
+	cursor   int // Index into rows
+	offset   int // First row shown
+	selected map[string]bool
+
+	mode    mode
+	prompt  string
+	input   []rune
+	field   string // Field being edited in modeEdit
+	confirm func()
+
+	status string
+	width  int
+	height int
+	quit   bool
+}
+
+// New returns an App showing the first view
+func New(opts Options) *App {
+	return &App{opts: opts, selected: map[string]bool{}}
+}
+
+// SelectView makes the named view (case-insensitive) the one shown first
+func (a *App) SelectView(name string) error {
+	var names []string
+	for i, view := range a.opts.Views {
+		if strings.EqualFold(view.Name, name) {
+			a.view = i
+			return nil
+		}
+		names = append(names, strings.ToLower(view.Name))
+	}
+	return fmt.Errorf("unknown view '%s' (use %s)", name, strings.Join(names, ", "))
+}
+
+// Run loads the tasks and runs the interface on t until the user quits or
+// t runs out of keys. Changes made to the tasks file by other processes are
+// picked up while it runs.
+func (a *App) Run(t Terminal) error {
+	if err := a.reload(); err != nil {
+		return err
+	}
+
+	keys := make(chan Key)
+	readErr := make(chan error, 1)
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		for {
+			key, err := t.ReadKey()
+			if err != nil {
+				readErr <- err
+				return
+			}
+			select {
+			case keys <- key:
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	a.draw(t)
+	for !a.quit {
+		select {
+		case key := <-keys:
+			a.handleKey(key)
+		case err := <-readErr:
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		case <-ticker.C:
+			width, height := t.Size()
+			if !a.checkDisk() && width == a.width && height == a.height {
+				continue
+			}
+		}
+		a.draw(t)
+	}
+	return nil
+}
+
+// reload reads the tasks again, keeping the cursor on the same task
+func (a *App) reload() error {
+	store, err := a.opts.Load()
+	if err != nil {
+		return err
+	}
+	a.store = store
+	a.rebuild()
+	return nil
+}
+
+// checkDisk reloads the tasks when another process changed them
+func (a *App) checkDisk() bool {
+	if a.mode != modeList || a.opts.Revision == nil {
+		return false
+	}
+	revision, err := a.opts.Revision()
+	if err != nil || revision == a.store.Revision() {
+		return false
+	}
+	if err := a.reload(); err != nil {
+		a.status = "Error: " + err.Error()
+		return true
+	}
+	a.status = "Tasks changed on disk; reloaded"
+	return true
+}
+
+// rebuild recomputes the rows of the current view and filter
+func (a *App) rebuild() {
+	var current string
+	if task := a.current(); task != nil {
+		current = task.UID
+	}
+
+	a.rows = nil
+	for _, group := range a.opts.Views[a.view].Groups(a.store, time.Now()) {
+		var tasks []row
+		for i := range group.Tasks {
+			if matchesFilter(group.Tasks[i], a.filter) {
+				tasks = append(tasks, row{task: &group.Tasks[i]})
+			}
+		}
+		if len(tasks) == 0 {
+			continue
+		}
+		if group.Title != "" {
+			a.rows = append(a.rows, row{title: fmt.Sprintf("%s (%d)", group.Title, len(tasks))})
+		}
+		a.rows = append(a.rows, tasks...)
+	}
+
+	// Forget selections of tasks that are gone
+	present := map[string]bool{}
+	for _, task := range a.store.Tasks {
+		present[task.UID] = true
+	}
+	for uid := range a.selected {
+		if !present[uid] {
+			delete(a.selected, uid)
+		}
+	}
+
+	a.cursor = 0
+	for i, r := range a.rows {
+		if r.task != nil && r.task.UID == current {
+			a.cursor = i
+			return
+		}
+	}
+	a.moveCursor(0)
+}
+
+// matchesFilter reports whether every word of the filter appears in the
+// task's description, notes, tags, project or "#ID"
+func matchesFilter(task taskdata.Task, filter string) bool {
+	text := strings.ToLower(strings.Join([]string{
+		fmt.Sprintf("#%d", task.ID), task.Description, task.Notes, task.Project,
+		strings.Join(task.Tags, " "), task.Assignee,
+	}, " "))
+	for _, word := range strings.Fields(strings.ToLower(filter)) {
+		if !strings.Contains(text, word) {
+			return false
+		}
+	}
+	return true
+}
+
+// current returns the task under the cursor
+func (a *App) current() *taskdata.Task {
+	if a.cursor < 0 || a.cursor >= len(a.rows) {
+		return nil
+	}
+	return a.rows[a.cursor].task
+}
+
+// moveCursor moves by delta rows, skipping group titles
+func (a *App) moveCursor(delta int) {
+	if len(a.rows) == 0 {
+		a.cursor = 0
+		return
+	}
+	step := 1
+	if delta < 0 {
+		step = -1
+	}
+	target := max(0, min(len(a.rows)-1, a.cursor+delta))
+	for target >= 0 && target < len(a.rows) && a.rows[target].task == nil {
+		target += step
+	}
+	if target < 0 || target >= len(a.rows) {
+		// Ran past the end on a title; look the other way
+		target = max(0, min(len(a.rows)-1, a.cursor+delta))
+		for target >= 0 && target < len(a.rows) && a.rows[target].task == nil {
+			target -= step
+		}
+	}
+	if target >= 0 && target < len(a.rows) {
+		a.cursor = target
+	}
+}
+
+// targets returns the selected tasks, or the task under the cursor
+func (a *App) targets() []string {
+	var uids []string
+	for _, task := range a.store.Tasks {
+		if a.selected[task.UID] {
+			uids = append(uids, task.UID)
+		}
+	}
+	if len(uids) == 0 {
+		if task := a.current(); task != nil {
+			uids = append(uids, task.UID)
+		}
+	}
+	return uids
+}
+
+func (a *App) find(uid string) *taskdata.Task {
+	for i := range a.store.Tasks {
+		if a.store.Tasks[i].UID == uid {
+			return &a.store.Tasks[i]
+		}
+	}
+	return nil
+}
+
+// save stores the changes made to a.store and shows done, or what went
+// wrong. The tasks are reloaded either way so the screen shows what is on
+// disk.
+func (a *App) save(done string) {
+	message, err := a.opts.Save(a.store)
+	switch {
+	case err != nil && message != "":
+		a.status = message
+	case err != nil:
+		a.status = "Error: " + err.Error()
+	case message != "":
+		a.status = message
+	default:
+		a.status = done
+	}
+	if err := a.reload(); err != nil {
+		a.status = "Error: " + err.Error()
+	}
+}
+
+func (a *App) handleKey(key Key) {
+	switch a.mode {
+	case modeFilter:
+		a.handleFilterKey(key)
+	case modeEdit:
+		a.handleEditKey(key)
+	case modeConfirm:
+		a.mode = modeList
+		if key.Code == KeyRune && (key.Rune == 'y' || key.Rune == 'Y') {
+			a.confirm()
+		} else {
+			a.status = "Cancelled"
+		}
+	default:
+		a.handleListKey(key)
+	}
+}
+
+func (a *App) handleListKey(key Key) {
+	a.status = ""
+	page := max(1, a.listHeight()-1)
+
+	switch key.Code {
+	case KeyCtrlC:
+		a.quit = true
+	case KeyUp:
+		a.moveCursor(-1)
+	case KeyDown:
+		a.moveCursor(1)
+	case KeyPageUp:
+		a.moveCursor(-page)
+	case KeyPageDown:
+		a.moveCursor(page)
+	case KeyHome:
+		a.cursor = 0
+		a.moveCursor(0)
+	case KeyEnd:
+		a.cursor = len(a.rows) - 1
+		a.moveCursor(0)
+	case KeyTab, KeyRight:
+		a.switchView((a.view + 1) % len(a.opts.Views))
+	case KeyLeft:
+		a.switchView((a.view + len(a.opts.Views) - 1) % len(a.opts.Views))
+	case KeyEsc:
+		if len(a.selected) > 0 {
+			a.selected = map[string]bool{}
+		} else if a.filter != "" {
+			a.filter = ""
+			a.rebuild()
+		}
+	case KeyDelete:
+		a.confirmDelete()
+	case KeyEnter:
+		a.startEdit("description")
+	case KeyRune:
+		a.handleListRune(key.Rune)
+	}
+}
+
+func (a *App) handleListRune(r rune) {
+	switch {
+	case r == 'q':
+		a.quit = true
+	case r == 'j':
+		a.moveCursor(1)
+	case r == 'k':
+		a.moveCursor(-1)
+	case r == 'g':
+		a.cursor = 0
+		a.moveCursor(0)
+	case r == 'G':
+		a.cursor = len(a.rows) - 1
+		a.moveCursor(0)
+	case r >= '1' && r <= '9' && int(r-'1') < len(a.opts.Views):
+		a.switchView(int(r - '1'))
+	case r == '/':
+		a.mode = modeFilter
+		a.input = []rune(a.filter)
+	case r == ' ':
+		if task := a.current(); task != nil {
+			if a.selected[task.UID] {
+				delete(a.selected, task.UID)
+			} else {
+				a.selected[task.UID] = true
+			}
+			a.moveCursor(1)
+		}
+	case r == 'a':
+		a.toggleSelectAll()
+	case r == 'x':
+		a.toggleCompleted()
+	case r == 'D':
+		a.confirmDelete()
+	case r == 'e':
+		a.startEdit("description")
+	case r == 'd':
+		a.startEdit("due")
+	case r == 'p':
+		a.startEdit("priority")
+	case r == 'r':
+		if err := a.reload(); err != nil {
+			a.status = "Error: " + err.Error()
+		} else {
+			a.status = "Reloaded"
+		}
+	}
+}
+
+func (a *App) switchView(view int) {
+	a.view = view
+	a.rebuild()
+	a.cursor = 0
+	a.moveCursor(0)
+}
+
+// toggleSelectAll selects every task shown, or clears the selection when
+// they are all selected already
+func (a *App) toggleSelectAll() {
+	all := true
+	for _, r := range a.rows {
+		if r.task != nil && !a.selected[r.task.UID] {
+			all = false
+		}
+	}
+	a.selected = map[string]bool{}
+	if !all {
+		for _, r := range a.rows {
+			if r.task != nil {
+				a.selected[r.task.UID] = true
+			}
+		}
+	}
+}
+
+// toggleCompleted completes the target tasks, or reopens them when they are
+// all completed already
+func (a *App) toggleCompleted() {
+	uids := a.targets()
+	if len(uids) == 0 {
+		return
+	}
+	complete := false
+	for _, uid := range uids {
+		if task := a.find(uid); task != nil && !task.Completed {
+			complete = true
+		}
+	}
+
+	now := time.Now().Format(taskdata.TimestampFormat)
+	for _, uid := range uids {
+		task := a.find(uid)
+		if task == nil || task.Completed == complete {
+			continue
+		}
+		task.Completed = complete
+		task.CompletedAt = ""
+		if complete {
+			task.CompletedAt = now
+		}
+	}
+	a.selected = map[string]bool{}
+
+	verb := "Completed"
+	if !complete {
+		verb = "Reopened"
+	}
+	a.save(fmt.Sprintf("%s %d task(s)", verb, len(uids)))
+}
+
+func (a *App) confirmDelete() {
+	uids := a.targets()
+	if len(uids) == 0 {
+		return
+	}
+	a.mode = modeConfirm
+	if len(uids) == 1 {
+		task := a.find(uids[0])
+		a.prompt = fmt.Sprintf("Delete #%d %s? (y/n)", task.ID, task.Description)
+	} else {
+		a.prompt = fmt.Sprintf("Delete %d tasks? (y/n)", len(uids))
+	}
+	a.confirm = func() {
+		remove := map[string]bool{}
+		for _, uid := range uids {
+			remove[uid] = true
+		}
+		kept := a.store.Tasks[:0]
+		for _, task := range a.store.Tasks {
+			if !remove[task.UID] {
+				kept = append(kept, task)
+			}
+		}
+		a.store.Tasks = kept
+		a.selected = map[string]bool{}
+		a.save(fmt.Sprintf("Deleted %d task(s)", len(uids)))
+	}
+}
+
+// startEdit opens the prompt for a field of the task under the cursor
+func (a *App) startEdit(field string) {
+	task := a.current()
+	if task == nil {
+		return
+	}
+	a.mode = modeEdit
+	a.field = field
+	a.status = ""
+	switch field {
+	case "description":
+		a.prompt = fmt.Sprintf("Description of #%d: ", task.ID)
+		a.input = []rune(task.Description)
+	case "due":
+		a.prompt = fmt.Sprintf("Due date of #%d (YYYY-MM-DD, today, friday, +3d..., empty for none): ", task.ID)
+		a.input = []rune(task.DueDate)
+	case "priority":
+		a.prompt = fmt.Sprintf("Priority of #%d (%s): ", task.ID, strings.Join(taskdata.Priorities(), ", "))
+		a.input = []rune(task.Priority)
+	}
+}
+
+func (a *App) handleEditKey(key Key) {
+	switch key.Code {
+	case KeyEsc, KeyCtrlC:
+		a.mode = modeList
+		a.status = "Cancelled"
+	case KeyEnter:
+		a.applyEdit(strings.TrimSpace(string(a.input)))
+	default:
+		a.input = editInput(a.input, key)
+	}
+}
+
+// applyEdit validates and saves the edited field, keeping the prompt open
+// when the value is invalid
+func (a *App) applyEdit(value string) {
+	task := a.current()
+	if task == nil {
+		a.mode = modeList
+		return
+	}
+	task = a.find(task.UID)
+
+	switch a.field {
+	case "description":
+		if value == "" {
+			a.status = "The description cannot be empty"
+			return
+		}
+		task.Description = value
+	case "due":
+		if value == "none" {
+			value = ""
+		}
+		value, err := taskdata.ResolveDate(value, time.Now())
+		if err != nil {
+			a.status = err.Error()
+			return
+		}
+		task.DueDate = value
+		if value == "" {
+			task.DueTime = ""
+		}
+	case "priority":
+		priority, err := taskdata.NormalizePriority(value)
+		if err != nil {
+			a.status = err.Error()
+			return
+		}
+		task.Priority = priority
+	}
+	a.mode = modeList
+	a.save(fmt.Sprintf("Updated #%d", task.ID))
+}
+
+func (a *App) handleFilterKey(key Key) {
+	switch key.Code {
+	case KeyEsc, KeyCtrlC:
+		a.input = nil
+		a.mode = modeList
+	case KeyEnter:
+		a.mode = modeList
+	default:
+		a.input = editInput(a.input, key)
+	}
+	a.filter = string(a.input)
+	a.rebuild()
+}
+
+// editInput applies a key to a line of input: typing appends, Backspace
+// removes the last character and Ctrl+U clears the line
+func editInput(input []rune, key Key) []rune {
+	switch {
+	case key.Code == KeyBackspace && len(input) > 0:
+		return input[:len(input)-1]
+	case key.Code == KeyRune && key.Rune == 0x15:
+		return nil
+	case key.Code == KeyRune && key.Rune >= ' ':
+		return append(input, key.Rune)
+	}
+	return input
+}
@@ -0,0 +1,207 @@
+package tui
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"todo/taskdata"
+)
+
+// allView shows every task in one group
+var allView = View{Name: "All", Groups: func(store *taskdata.TaskStore, now time.Time) []Group {
+	return []Group{{Tasks: store.Tasks}}
+}}
+
+// pendingView shows the pending tasks
+var pendingView = View{Name: "Pending", Groups: func(store *taskdata.TaskStore, now time.Time) []Group {
+	var pending []taskdata.Task
+	for _, task := range store.Tasks {
+		if !task.Completed {
+			pending = append(pending, task)
+		}
+	}
+	return []Group{{Title: "Pending", Tasks: pending}}
+}}
+
+// newTestApp returns an App over a tasks file in a temporary directory
+// holding the given tasks
+func newTestApp(t *testing.T, descriptions ...string) *App {
+	t.Helper()
+	taskdata.SetDataFilePath(filepath.Join(t.TempDir(), "tasks.json"))
+	t.Cleanup(func() { taskdata.SetDataFilePath("") })
+
+	_, err := taskdata.UpdateTasks(func(store *taskdata.TaskStore) error {
+		for _, description := range descriptions {
+			if _, err := store.AddTask(description, "", "normal"); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return New(Options{
+		Views: []View{allView, pendingView},
+		Load:  taskdata.LoadTasks,
+		Save: func(store *taskdata.TaskStore) (string, error) {
+			return "", store.SaveTasks()
+		},
+		Revision: taskdata.CurrentRevision,
+	})
+}
+
+// run drives the App with the keys queued on a 80x24 screen
+func run(t *testing.T, app *App, keys func(term *VirtualTerminal)) *VirtualTerminal {
+	t.Helper()
+	term := NewVirtualTerminal(80, 24)
+	keys(term)
+	if err := app.Run(term); err != nil {
+		t.Fatal(err)
+	}
+	return term
+}
+
+func loadTask(t *testing.T, id int) taskdata.Task {
+	t.Helper()
+	store, err := taskdata.LoadTasks()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, task := range store.Tasks {
+		if task.ID == id {
+			return task
+		}
+	}
+	t.Fatalf("task #%d not found", id)
+	return taskdata.Task{}
+}
+
+// statusRow returns the status bar, the row above the help line
+func statusRow(term *VirtualTerminal) string {
+	screen := term.Screen()
+	for i := len(screen) - 1; i >= 0; i-- {
+		if term.Lines()[i].Style == StyleStatus {
+			return screen[i]
+		}
+	}
+	return ""
+}
+
+func TestShowsTasks(t *testing.T) {
+	app := newTestApp(t, "Buy milk", "Write report")
+	term := run(t, app, func(term *VirtualTerminal) {})
+
+	for _, want := range []string{"Buy milk", "Write report"} {
+		if !term.Contains(want) {
+			t.Errorf("screen does not show %q:\n%s", want, term)
+		}
+	}
+}
+
+func TestCompleteTaskUnderCursor(t *testing.T) {
+	app := newTestApp(t, "Buy milk", "Write report")
+	term := run(t, app, func(term *VirtualTerminal) {
+		term.Send(Key{Code: KeyDown})
+		term.Type("x")
+	})
+
+	if status := statusRow(term); !strings.Contains(status, "Completed 1 task(s)") {
+		t.Errorf("status = %q", status)
+	}
+	if !loadTask(t, 2).Completed || loadTask(t, 1).Completed {
+		t.Error("the wrong task was completed")
+	}
+}
+
+func TestSwitchViews(t *testing.T) {
+	app := newTestApp(t, "Buy milk", "Write report")
+	term := run(t, app, func(term *VirtualTerminal) {
+		term.Type("x2")
+	})
+
+	if term.Contains("Buy milk") {
+		t.Errorf("the pending view shows the completed task:\n%s", term)
+	}
+	if !term.Contains("Pending (1)") || !term.Contains("Write report") {
+		t.Errorf("the pending view does not show the pending task:\n%s", term)
+	}
+}
+
+func TestFilter(t *testing.T) {
+	app := newTestApp(t, "Buy milk", "Write report", "Buy bread")
+	term := run(t, app, func(term *VirtualTerminal) {
+		term.Type("/buy")
+		term.Send(Key{Code: KeyEnter})
+	})
+
+	if !term.Contains("Buy milk") || !term.Contains("Buy bread") || term.Contains("Write report") {
+		t.Errorf("filtered screen:\n%s", term)
+	}
+}
+
+func TestEditPriority(t *testing.T) {
+	app := newTestApp(t, "Buy milk")
+	term := run(t, app, func(term *VirtualTerminal) {
+		term.Type("p")
+		term.Send(Key{Code: KeyRune, Rune: 0x15}) // Ctrl+U clears the input
+		term.Type("urgent")
+		term.Send(Key{Code: KeyEnter})
+	})
+
+	// An invalid value keeps the prompt open
+	if status := statusRow(term); !strings.Contains(status, "Priority of #1") {
+		t.Fatalf("status = %q, want the priority prompt", status)
+	}
+
+	app = newTestApp(t, "Buy milk")
+	term = run(t, app, func(term *VirtualTerminal) {
+		term.Type("p")
+		term.Send(Key{Code: KeyRune, Rune: 0x15})
+		term.Type("high")
+		term.Send(Key{Code: KeyEnter})
+	})
+	if status := statusRow(term); !strings.Contains(status, "Updated #1") {
+		t.Errorf("status = %q", status)
+	}
+	if task := loadTask(t, 1); task.Priority != "high" {
+		t.Errorf("priority = %q, want high", task.Priority)
+	}
+}
+
+func TestDeleteAsksFirst(t *testing.T) {
+	app := newTestApp(t, "Buy milk", "Write report")
+	term := run(t, app, func(term *VirtualTerminal) {
+		term.Type("Dn")
+	})
+	if status := statusRow(term); !strings.Contains(status, "Cancelled") {
+		t.Errorf("status = %q", status)
+	}
+
+	term = run(t, app, func(term *VirtualTerminal) {
+		term.Type("Dy")
+	})
+	if status := statusRow(term); !strings.Contains(status, "Deleted 1 task(s)") {
+		t.Errorf("status = %q", status)
+	}
+	if term.Contains("Buy milk") || !term.Contains("Write report") {
+		t.Errorf("screen after deleting:\n%s", term)
+	}
+}
+
+func TestSaveMessageReplacesStatus(t *testing.T) {
+	app := newTestApp(t, "Buy milk")
+	app.opts.Save = func(store *taskdata.TaskStore) (string, error) {
+		return "on-complete: synced to the tracker", store.SaveTasks()
+	}
+	term := run(t, app, func(term *VirtualTerminal) {
+		term.Type("x")
+	})
+
+	if status := statusRow(term); !strings.Contains(status, "on-complete: synced to the tracker") {
+		t.Errorf("status = %q", status)
+	}
+}
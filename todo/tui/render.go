@@ -0,0 +1,225 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"todo/taskdata"
+)
+
+// detailHeight is the height of the detail pane, including its separator
+const detailHeight = 7
+
+const help = "j/k move  / filter  space select  x done  D delete  e/d/p edit  1-5 view  q quit"
+
+// draw renders the screen and sends it to t
+func (a *App) draw(t Terminal) {
+	a.width, a.height = t.Size()
+	t.Draw(a.render())
+}
+
+// listHeight is how many rows of the list fit on the screen
+func (a *App) listHeight() int {
+	height := a.height - 3 // Title, status and help lines
+	if a.showDetail() {
+		height -= detailHeight
+	}
+	return max(1, height)
+}
+
+// showDetail reports whether the screen is tall enough for the detail pane
+func (a *App) showDetail() bool {
+	return a.height >= detailHeight+8
+}
+
+func (a *App) render() []Line {
+	var lines []Line
+	add := func(text string, style Style) {
+		lines = append(lines, Line{Text: fit(text, a.width), Style: style})
+	}
+
+	add(a.titleBar(), StyleTitle)
+
+	// Keep the cursor in view
+	height := a.listHeight()
+	if a.cursor < a.offset {
+		a.offset = a.cursor
+	}
+	if a.cursor >= a.offset+height {
+		a.offset = a.cursor - height + 1
+	}
+	a.offset = max(0, min(a.offset, len(a.rows)-height))
+
+	now := time.Now()
+	for i := a.offset; i < a.offset+height; i++ {
+		switch {
+		case i >= len(a.rows):
+			if i == 0 {
+				add("  No tasks in this view", StyleDim)
+			} else {
+				add("", StyleNormal)
+			}
+		case a.rows[i].task == nil:
+			add("── "+a.rows[i].title, StyleTitle)
+		case i == a.cursor:
+			add(a.taskLine(*a.rows[i].task, now), StyleCursor)
+		default:
+			style := StyleNormal
+			if a.rows[i].task.Completed {
+				style = StyleDim
+			}
+			add(a.taskLine(*a.rows[i].task, now), style)
+		}
+	}
+
+	if a.showDetail() {
+		add(strings.Repeat("─", a.width), StyleDim)
+		detail := a.detailLines()
+		for i := 0; i < detailHeight-1; i++ {
+			text := ""
+			if i < len(detail) {
+				text = detail[i]
+			}
+			add(" "+text, StyleNormal)
+		}
+	}
+
+	add(a.statusLine(), StyleStatus)
+	add(help, StyleDim)
+
+	// Pad a screen taller than the content
+	for len(lines) < a.height {
+		add("", StyleNormal)
+	}
+	return lines[:min(len(lines), a.height)]
+}
+
+func (a *App) titleBar() string {
+	var b strings.Builder
+	b.WriteString(" todo ")
+	for i, view := range a.opts.Views {
+		if i == a.view {
+			fmt.Fprintf(&b, " [%d %s]", i+1, view.Name)
+		} else {
+			fmt.Fprintf(&b, "  %d %s ", i+1, view.Name)
+		}
+	}
+	if a.filter != "" {
+		fmt.Fprintf(&b, "   filter: %s", a.filter)
+	}
+	return b.String()
+}
+
+// taskLine shows a task as a row of the list
+func (a *App) taskLine(task taskdata.Task, now time.Time) string {
+	mark := " "
+	if a.selected[task.UID] {
+		mark = "*"
+	}
+	box := "[ ]"
+	switch {
+	case task.Completed:
+		box = "[x]"
+	case isOverdue(task, now):
+		box = "[!]"
+	}
+	due := strings.TrimSpace(task.DueDate + " " + task.DueTime)
+	text := task.Description
+	for _, tag := range task.Tags {
+		text += " +" + tag
+	}
+	return fmt.Sprintf("%s %s #%-4d %-8s %-16s %s", mark, box, task.ID, task.Priority, due, text)
+}
+
+func isOverdue(task taskdata.Task, now time.Time) bool {
+	if task.Completed || task.DueDate == "" {
+		return false
+	}
+	return task.DueDate < now.Format("2006-01-02")
+}
+
+// detailLines describe the task under the cursor
+func (a *App) detailLines() []string {
+	task := a.current()
+	if task == nil {
+		return nil
+	}
+
+	status := "pending"
+	if task.Completed {
+		status = "completed " + task.CompletedAt
+	} else if isOverdue(*task, time.Now()) {
+		status = "overdue"
+	}
+	due := strings.TrimSpace(task.DueDate + " " + task.DueTime)
+	if due == "" {
+		due = "none"
+	}
+
+	lines := []string{
+		fmt.Sprintf("#%d %s", task.ID, task.Description),
+		fmt.Sprintf("Priority: %s   Due: %s   Status: %s", task.Priority, due, status),
+	}
+	var meta []string
+	if len(task.Tags) > 0 {
+		meta = append(meta, "Tags: "+strings.Join(task.Tags, ", "))
+	}
+	if task.Project != "" {
+		meta = append(meta, "Project: "+task.Project)
+	}
+	if task.Assignee != "" {
+		meta = append(meta, "Assignee: "+task.Assignee)
+	}
+	if task.WaitUntil != "" {
+		meta = append(meta, "Waiting until: "+task.WaitUntil)
+	}
+	if len(meta) > 0 {
+		lines = append(lines, strings.Join(meta, "   "))
+	}
+	if len(task.DependsOn) > 0 {
+		var ids []string
+		for _, id := range task.DependsOn {
+			ids = append(ids, fmt.Sprintf("#%d", id))
+		}
+		lines = append(lines, "Depends on: "+strings.Join(ids, ", "))
+	}
+	if task.Notes != "" {
+		lines = append(lines, "Notes: "+strings.ReplaceAll(task.Notes, "\n", " "))
+	}
+	if task.CreatedAt != "" {
+		lines = append(lines, "Created: "+task.CreatedAt)
+	}
+	return lines
+}
+
+// statusLine shows the prompt being answered, the last message or counts
+func (a *App) statusLine() string {
+	switch a.mode {
+	case modeFilter:
+		return " Filter: " + string(a.input) + "_"
+	case modeEdit:
+		line := " " + a.prompt + string(a.input) + "_"
+		if a.status != "" {
+			line += "   " + a.status
+		}
+		return line
+	case modeConfirm:
+		return " " + a.prompt
+	}
+	if a.status != "" {
+		return " " + a.status
+	}
+
+	shown := 0
+	for _, r := range a.rows {
+		if r.task != nil {
+			shown++
+		}
+	}
+	line := fmt.Sprintf(" %d task(s)", shown)
+	if len(a.selected) > 0 {
+		line += fmt.Sprintf(", %d selected", len(a.selected))
+	}
+	return line
+}
@@ -0,0 +1,81 @@
+// Package tui is the full-screen task interface behind 'todo tui'. It draws
+// to a Terminal, which is either the real terminal or a VirtualTerminal
+// that scripts keys and records the screen, so the interface can be driven
+// and inspected without a tty.
+package tui
+
+// Terminal is a full-screen terminal the App draws to and reads keys from
+type Terminal interface {
+	Size() (width, height int)
+	ReadKey() (Key, error) // Blocks until a key is pressed; io.EOF ends the App
+	Draw(lines []Line) error
+	Close() error
+}
+
+// KeyCode identifies special keys; printable keys are KeyRune
+type KeyCode int
+
+const (
+	KeyRune KeyCode = iota
+	KeyEnter
+	KeyEsc
+	KeyBackspace
+	KeyTab
+	KeyUp
+	KeyDown
+	KeyLeft
+	KeyRight
+	KeyHome
+	KeyEnd
+	KeyPageUp
+	KeyPageDown
+	KeyDelete
+	KeyCtrlC
+)
+
+// Key is a key press
+type Key struct {
+	Code KeyCode
+	Rune rune // Set for KeyRune
+}
+
+// Runes returns the key presses that type s
+func Runes(s string) []Key {
+	var keys []Key
+	for _, r := range s {
+		keys = append(keys, Key{Code: KeyRune, Rune: r})
+	}
+	return keys
+}
+
+// Style is how a line is drawn
+type Style int
+
+const (
+	StyleNormal Style = iota
+	StyleTitle        // Bold
+	StyleCursor       // Reverse video
+	StyleDim          // Faint
+	StyleStatus       // Reverse video, for the status bar
+)
+
+// Line is one row of the screen
+type Line struct {
+	Text  string
+	Style Style
+}
+
+// fit truncates or pads s with spaces to exactly width runes
+func fit(s string, width int) string {
+	runes := []rune(s)
+	if len(runes) > width {
+		if width > 1 {
+			return string(runes[:width-1]) + "…"
+		}
+		return string(runes[:width])
+	}
+	for len(runes) < width {
+		runes = append(runes, ' ')
+	}
+	return string(runes)
+}
@@ -0,0 +1,142 @@
+package tui
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// TTY is the real terminal, switched to raw mode and the alternate screen
+// while the App runs
+type TTY struct {
+	in    *os.File
+	out   *os.File
+	state *term.State
+	keys  *bufio.Reader
+}
+
+// OpenTTY takes over the terminal on stdin and stdout
+func OpenTTY() (*TTY, error) {
+	in, out := os.Stdin, os.Stdout
+	if !term.IsTerminal(int(in.Fd())) || !term.IsTerminal(int(out.Fd())) {
+		return nil, fmt.Errorf("todo tui needs an interactive terminal")
+	}
+	state, err := term.MakeRaw(int(in.Fd()))
+	if err != nil {
+		return nil, err
+	}
+	// Alternate screen, hidden cursor
+	fmt.Fprint(out, "\x1b[?1049h\x1b[?25l")
+	return &TTY{in: in, out: out, state: state, keys: bufio.NewReader(in)}, nil
+}
+
+// Close restores the terminal
+func (t *TTY) Close() error {
+	fmt.Fprint(t.out, "\x1b[?25h\x1b[?1049l")
+	return term.Restore(int(t.in.Fd()), t.state)
+}
+
+// Size returns the terminal size, 80x24 if it cannot be read
+func (t *TTY) Size() (int, int) {
+	width, height, err := term.GetSize(int(t.out.Fd()))
+	if err != nil || width <= 0 || height <= 0 {
+		return 80, 24
+	}
+	return width, height
+}
+
+// Draw redraws the whole screen in one write
+func (t *TTY) Draw(lines []Line) error {
+	var b strings.Builder
+	b.WriteString("\x1b[H")
+	for i, line := range lines {
+		if i > 0 {
+			b.WriteString("\r\n")
+		}
+		switch line.Style {
+		case StyleTitle:
+			b.WriteString("\x1b[1m")
+		case StyleCursor, StyleStatus:
+			b.WriteString("\x1b[7m")
+		case StyleDim:
+			b.WriteString("\x1b[2m")
+		}
+		b.WriteString(line.Text)
+		b.WriteString("\x1b[0m\x1b[K")
+	}
+	b.WriteString("\x1b[J")
+	_, err := io.WriteString(t.out, b.String())
+	return err
+}
+
+// ReadKey reads a key press, decoding the escape sequences of special keys
+func (t *TTY) ReadKey() (Key, error) {
+	r, _, err := t.keys.ReadRune()
+	if err != nil {
+		return Key{}, err
+	}
+	switch r {
+	case '\r', '\n':
+		return Key{Code: KeyEnter}, nil
+	case '\t':
+		return Key{Code: KeyTab}, nil
+	case 0x7f, 0x08:
+		return Key{Code: KeyBackspace}, nil
+	case 0x03:
+		return Key{Code: KeyCtrlC}, nil
+	case 0x1b:
+		return t.readEscape()
+	}
+	return Key{Code: KeyRune, Rune: r}, nil
+}
+
+// readEscape decodes CSI and SS3 sequences such as "\x1b[A"; a lone Esc
+// has nothing buffered after it
+func (t *TTY) readEscape() (Key, error) {
+	if t.keys.Buffered() == 0 {
+		return Key{Code: KeyEsc}, nil
+	}
+	next, _, err := t.keys.ReadRune()
+	if err != nil || (next != '[' && next != 'O') {
+		return Key{Code: KeyEsc}, err
+	}
+
+	var seq strings.Builder
+	for {
+		r, _, err := t.keys.ReadRune()
+		if err != nil {
+			return Key{Code: KeyEsc}, err
+		}
+		seq.WriteRune(r)
+		if r >= 0x40 && r <= 0x7e {
+			break
+		}
+	}
+	switch seq.String() {
+	case "A":
+		return Key{Code: KeyUp}, nil
+	case "B":
+		return Key{Code: KeyDown}, nil
+	case "C":
+		return Key{Code: KeyRight}, nil
+	case "D":
+		return Key{Code: KeyLeft}, nil
+	case "H", "1~", "7~":
+		return Key{Code: KeyHome}, nil
+	case "F", "4~", "8~":
+		return Key{Code: KeyEnd}, nil
+	case "5~":
+		return Key{Code: KeyPageUp}, nil
+	case "6~":
+		return Key{Code: KeyPageDown}, nil
+	case "3~":
+		return Key{Code: KeyDelete}, nil
+	case "Z":
+		return Key{Code: KeyTab}, nil
+	}
+	return Key{Code: KeyEsc}, nil
+}
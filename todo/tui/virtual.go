@@ -0,0 +1,94 @@
+package tui
+
+import (
+	"io"
+	"strings"
+)
+
+// VirtualTerminal is an in-memory Terminal of a fixed size. Keys sent to it
+// are read in order; once they run out ReadKey returns io.EOF, which ends
+// the App, and Screen shows what was drawn last.
+type VirtualTerminal struct {
+	width, height int
+	keys          []Key
+	lines         []Line
+	frames        int
+}
+
+// NewVirtualTerminal returns an empty screen of the given size
+func NewVirtualTerminal(width, height int) *VirtualTerminal {
+	return &VirtualTerminal{width: width, height: height}
+}
+
+// Send queues key presses
+func (v *VirtualTerminal) Send(keys ...Key) {
+	v.keys = append(v.keys, keys...)
+}
+
+// Type queues the key presses that type s
+func (v *VirtualTerminal) Type(s string) {
+	v.Send(Runes(s)...)
+}
+
+// Resize changes the screen size for the next frame
+func (v *VirtualTerminal) Resize(width, height int) {
+	v.width, v.height = width, height
+}
+
+func (v *VirtualTerminal) Size() (int, int) {
+	return v.width, v.height
+}
+
+func (v *VirtualTerminal) ReadKey() (Key, error) {
+	if len(v.keys) == 0 {
+		return Key{}, io.EOF
+	}
+	key := v.keys[0]
+	v.keys = v.keys[1:]
+	return key, nil
+}
+
+func (v *VirtualTerminal) Draw(lines []Line) error {
+	v.lines = append([]Line(nil), lines...)
+	v.frames++
+	return nil
+}
+
+func (v *VirtualTerminal) Close() error {
+	return nil
+}
+
+// Lines returns the last frame with styles
+func (v *VirtualTerminal) Lines() []Line {
+	return v.lines
+}
+
+// Screen returns the text of the last frame, one string per row with
+// trailing spaces removed
+func (v *VirtualTerminal) Screen() []string {
+	rows := make([]string, len(v.lines))
+	for i, line := range v.lines {
+		rows[i] = strings.TrimRight(line.Text, " ")
+	}
+	return rows
+}
+
+// String returns the last frame as text
+func (v *VirtualTerminal) String() string {
+	return strings.Join(v.Screen(), "\n")
+}
+
+// Contains reports whether any row of the last frame contains s
+func (v *VirtualTerminal) Contains(s string) bool {
+	for _, row := range v.Screen() {
+		if strings.Contains(row, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// Frames counts the frames drawn so far
+func (v *VirtualTerminal) Frames() int {
+	return v.frames
+}